@@ -0,0 +1,168 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/luxfi/ids"
+)
+
+var ErrNotAllowed = errors.New("validator not allowed")
+
+// AccessManager restricts which nodes may validate a net. A net with an
+// allowlist is permissioned: only listed nodes may join. A denylisted node
+// may never join. Lists are enforced when validators are added and
+// retroactively when a list changes, in which case newly disallowed
+// validators are removed and OnValidatorRemoved is emitted.
+type AccessManager interface {
+	// SetAllowlist makes [netID] permissioned to [nodeIDs]
+	SetAllowlist(netID ids.ID, nodeIDs []ids.NodeID)
+	// ClearAllowlist makes [netID] permissionless
+	ClearAllowlist(netID ids.ID)
+	// GetAllowlist returns the allowlist of [netID] and whether it has one
+	GetAllowlist(netID ids.ID) ([]ids.NodeID, bool)
+	// Deny adds [nodeID] to the denylist of [netID]
+	Deny(netID ids.ID, nodeID ids.NodeID)
+	// Undeny removes [nodeID] from the denylist of [netID]
+	Undeny(netID ids.ID, nodeID ids.NodeID)
+	// GetDenylist returns the denylist of [netID]
+	GetDenylist(netID ids.ID) []ids.NodeID
+	// IsAllowed returns true if [nodeID] may validate [netID]
+	IsAllowed(netID ids.ID, nodeID ids.NodeID) bool
+}
+
+var _ AccessManager = (*manager)(nil)
+
+// SetAllowlist replaces the allowlist of a net
+func (m *manager) SetAllowlist(netID ids.ID, nodeIDs []ids.NodeID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	allowlist := make(map[ids.NodeID]struct{}, len(nodeIDs))
+	for _, nodeID := range nodeIDs {
+		allowlist[nodeID] = struct{}{}
+	}
+	m.allowlists[netID] = allowlist
+	m.enforceAccess(netID)
+}
+
+// ClearAllowlist removes the allowlist of a net
+func (m *manager) ClearAllowlist(netID ids.ID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.allowlists, netID)
+}
+
+// GetAllowlist returns the sorted allowlist of a net
+func (m *manager) GetAllowlist(netID ids.ID) ([]ids.NodeID, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	allowlist, ok := m.allowlists[netID]
+	if !ok {
+		return nil, false
+	}
+	return sortedNodeIDs(allowlist), true
+}
+
+// Deny adds a node to the denylist of a net
+func (m *manager) Deny(netID ids.ID, nodeID ids.NodeID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.denylists[netID] == nil {
+		m.denylists[netID] = make(map[ids.NodeID]struct{})
+	}
+	m.denylists[netID][nodeID] = struct{}{}
+	m.enforceAccess(netID)
+}
+
+// Undeny removes a node from the denylist of a net
+func (m *manager) Undeny(netID ids.ID, nodeID ids.NodeID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.denylists[netID], nodeID)
+	if len(m.denylists[netID]) == 0 {
+		delete(m.denylists, netID)
+	}
+}
+
+// GetDenylist returns the sorted denylist of a net
+func (m *manager) GetDenylist(netID ids.ID) []ids.NodeID {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return sortedNodeIDs(m.denylists[netID])
+}
+
+// IsAllowed returns true if a node may validate a net
+func (m *manager) IsAllowed(netID ids.ID, nodeID ids.NodeID) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.isAllowed(netID, nodeID)
+}
+
+// isAllowed assumes the lock is held
+func (m *manager) isAllowed(netID ids.ID, nodeID ids.NodeID) bool {
+	if _, denied := m.denylists[netID][nodeID]; denied {
+		return false
+	}
+	if allowlist, ok := m.allowlists[netID]; ok {
+		_, allowed := allowlist[nodeID]
+		return allowed
+	}
+	return true
+}
+
+// requireAllowed returns an error if [nodeID] may not validate [netID]. It
+// assumes the lock is held.
+func (m *manager) requireAllowed(netID ids.ID, nodeID ids.NodeID) error {
+	if !m.isAllowed(netID, nodeID) {
+		return fmt.Errorf("%w: %s in %s", ErrNotAllowed, nodeID, netID)
+	}
+	return nil
+}
+
+// enforceAccess removes every validator of [netID] that is no longer
+// allowed, including deactivated balance backed validators. Frozen nets are
+// enforced when they thaw. It assumes the lock is held.
+func (m *manager) enforceAccess(netID ids.ID) {
+	if m.frozen[netID] > 0 {
+		return
+	}
+	var notify []func(ManagerCallbackListener)
+	for nodeID, val := range m.validators[netID] {
+		if m.isAllowed(netID, nodeID) {
+			continue
+		}
+		m.evictValidator(netID, nodeID)
+		notify = append(notify, func(listener ManagerCallbackListener) {
+			listener.OnValidatorRemoved(netID, nodeID, val.Light)
+		})
+	}
+	m.notify(notifyAll(notify))
+	for key, entry := range m.balances {
+		if key.netID == netID && entry.inactive != nil && !m.isAllowed(netID, key.nodeID) {
+			m.evictValidator(netID, key.nodeID)
+		}
+	}
+}
+
+func sortedNodeIDs(nodeIDs map[ids.NodeID]struct{}) []ids.NodeID {
+	sorted := make([]ids.NodeID, 0, len(nodeIDs))
+	for nodeID := range nodeIDs {
+		sorted = append(sorted, nodeID)
+	}
+	slices.SortFunc(sorted, func(a, b ids.NodeID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	return sorted
+}
@@ -0,0 +1,80 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// TestManagerAllowlist tests permissioned nets
+func TestManagerAllowlist(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID0 := ids.GenerateTestNodeID()
+	nodeID1 := ids.GenerateTestNodeID()
+	listener := &testListener{}
+	m.RegisterCallbackListener(listener)
+
+	require.NoError(m.AddStaker(netID, nodeID0, nil, ids.Empty, 100))
+	require.NoError(m.AddStaker(netID, nodeID1, nil, ids.Empty, 200))
+	_, ok := m.GetAllowlist(netID)
+	require.False(ok)
+
+	// Setting an allowlist removes validators that aren't on it
+	m.SetAllowlist(netID, []ids.NodeID{nodeID0})
+	allowlist, ok := m.GetAllowlist(netID)
+	require.True(ok)
+	require.Equal([]ids.NodeID{nodeID0}, allowlist)
+	require.Equal(1, m.Count(netID))
+	require.Equal([]validatorEvent{{netID, nodeID1, 200}}, listener.removed)
+
+	err := m.AddStaker(netID, nodeID1, nil, ids.Empty, 200)
+	require.ErrorIs(err, ErrNotAllowed)
+	require.False(m.IsAllowed(netID, nodeID1))
+	require.True(m.IsAllowed(ids.GenerateTestID(), nodeID1))
+
+	m.ClearAllowlist(netID)
+	require.NoError(m.AddStaker(netID, nodeID1, nil, ids.Empty, 200))
+}
+
+// TestManagerDenylist tests denied nodes
+func TestManagerDenylist(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	delegatorID := ids.GenerateTestShortID()
+	listener := &testListener{}
+	m.RegisterCallbackListener(listener)
+
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+	require.NoError(m.AddDelegator(netID, nodeID, delegatorID, 50))
+
+	// Denying removes the validator along with its delegations
+	m.Deny(netID, nodeID)
+	require.Equal([]ids.NodeID{nodeID}, m.GetDenylist(netID))
+	_, ok := m.GetValidator(netID, nodeID)
+	require.False(ok)
+	require.Empty(m.GetDelegatorPositions(delegatorID))
+	require.Equal([]validatorEvent{{netID, nodeID, 150}}, listener.removed)
+
+	require.ErrorIs(m.AddStaker(netID, nodeID, nil, ids.Empty, 100), ErrNotAllowed)
+	require.NoError(m.SetWeightMode(netID, WeightModeBig))
+	require.ErrorIs(m.AddStakerBig(netID, nodeID, nil, ids.Empty, big.NewInt(1)), ErrNotAllowed)
+
+	// The denylist takes precedence over the allowlist
+	m.SetAllowlist(netID, []ids.NodeID{nodeID})
+	require.False(m.IsAllowed(netID, nodeID))
+
+	m.Undeny(netID, nodeID)
+	require.Empty(m.GetDenylist(netID))
+	require.True(m.IsAllowed(netID, nodeID))
+}
@@ -0,0 +1,294 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package alarm raises alarms when a net's validator set becomes unsafe: too
+// few validators, or too much light concentrated in its largest validators
+package alarm
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"math/bits"
+	"slices"
+	"sync"
+
+	"github.com/luxfi/ids"
+
+	validators "github.com/luxfi/validators"
+)
+
+var ErrInvalidConfig = errors.New("invalid alarm config")
+
+// Kind is the condition an alarm reports
+type Kind uint8
+
+const (
+	// ValidatorCountLow is raised while a net has fewer than MinValidators
+	ValidatorCountLow Kind = iota + 1
+	// ConcentrationHigh is raised while the TopK validators of a net hold more
+	// than MaxShareNumerator / MaxShareDenominator of its light. Nets with TopK
+	// or fewer validators never raise it, as their top K hold all the light by
+	// definition.
+	ConcentrationHigh
+)
+
+// String implements fmt.Stringer
+func (k Kind) String() string {
+	switch k {
+	case ValidatorCountLow:
+		return "validator-count-low"
+	case ConcentrationHigh:
+		return "concentration-high"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(k))
+	}
+}
+
+// Config is the thresholds of a net's alarms
+type Config struct {
+	// MinValidators is the validator count floor. Zero disables the alarm.
+	MinValidators int
+	// TopK is the number of largest validators whose share of the light is
+	// limited. Zero disables the alarm.
+	TopK                int
+	MaxShareNumerator   uint64
+	MaxShareDenominator uint64
+}
+
+// Verify returns an error if the config can't be evaluated
+func (c Config) Verify() error {
+	switch {
+	case c.MinValidators < 0:
+		return fmt.Errorf("%w: negative minimum validators", ErrInvalidConfig)
+	case c.TopK < 0:
+		return fmt.Errorf("%w: negative top k", ErrInvalidConfig)
+	case c.TopK == 0:
+		return nil
+	case c.MaxShareDenominator == 0:
+		return fmt.Errorf("%w: zero share denominator", ErrInvalidConfig)
+	case c.MaxShareNumerator > c.MaxShareDenominator:
+		return fmt.Errorf("%w: share %d/%d exceeds 1", ErrInvalidConfig, c.MaxShareNumerator, c.MaxShareDenominator)
+	}
+	return nil
+}
+
+// Alarm is the state of a net when an alarm changed
+type Alarm struct {
+	Kind       Kind
+	NetID      ids.ID
+	Count      int
+	TopKLight  uint64
+	TotalLight uint64
+}
+
+// Handler is notified when alarms are raised and cleared
+type Handler interface {
+	OnAlarmRaised(alarm Alarm)
+	OnAlarmCleared(alarm Alarm)
+}
+
+type alarmKey struct {
+	netID ids.ID
+	kind  Kind
+}
+
+// Monitor evaluates the alarms of every net on each validator change. It is a
+// validators.ManagerCallbackListener, so it tracks light from the manager's
+// notifications and never calls back into the manager.
+type Monitor struct {
+	mu            sync.Mutex
+	defaultConfig Config
+	configs       map[ids.ID]Config
+	handlers      []Handler
+	lights        map[ids.ID]map[ids.NodeID]uint64
+	raised        map[alarmKey]Alarm
+	// replaying is set while Watch registers the monitor, so the validators
+	// the manager replays one at a time don't raise alarms for the partial
+	// sets in between
+	replaying bool
+}
+
+var _ validators.ManagerCallbackListener = (*Monitor)(nil)
+
+// NewMonitor returns a monitor that applies [defaultConfig] to nets without a
+// config of their own. Register it with Watch.
+func NewMonitor(defaultConfig Config) (*Monitor, error) {
+	if err := defaultConfig.Verify(); err != nil {
+		return nil, err
+	}
+	return &Monitor{
+		defaultConfig: defaultConfig,
+		configs:       make(map[ids.ID]Config),
+		lights:        make(map[ids.ID]map[ids.NodeID]uint64),
+		raised:        make(map[alarmKey]Alarm),
+	}, nil
+}
+
+// Watch registers the monitor as a listener of [manager] and evaluates the
+// alarms of its current validators once they are all replayed. Registering
+// the monitor with Manager.RegisterCallbackListener directly evaluates them
+// after each replayed validator instead, raising alarms for partial sets.
+func (m *Monitor) Watch(manager validators.Manager) {
+	m.mu.Lock()
+	m.replaying = true
+	m.mu.Unlock()
+
+	manager.RegisterCallbackListener(m)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.replaying = false
+	for netID := range m.lights {
+		m.evaluate(netID)
+	}
+}
+
+// SetConfig sets the config of [netID] and re-evaluates its alarms
+func (m *Monitor) SetConfig(netID ids.ID, config Config) error {
+	if err := config.Verify(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.configs[netID] = config
+	m.evaluate(netID)
+	return nil
+}
+
+// RegisterHandler registers a handler of alarm changes
+func (m *Monitor) RegisterHandler(handler Handler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.handlers = append(m.handlers, handler)
+}
+
+// Active returns the raised alarms, ordered by net and kind
+func (m *Monitor) Active() []Alarm {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	alarms := make([]Alarm, 0, len(m.raised))
+	for _, alarm := range m.raised {
+		alarms = append(alarms, alarm)
+	}
+	slices.SortFunc(alarms, func(a, b Alarm) int {
+		if c := bytes.Compare(a.NetID[:], b.NetID[:]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Kind, b.Kind)
+	})
+	return alarms
+}
+
+// OnValidatorAdded implements validators.ManagerCallbackListener
+func (m *Monitor) OnValidatorAdded(netID ids.ID, nodeID ids.NodeID, light uint64) {
+	m.setLight(netID, nodeID, light)
+}
+
+// OnValidatorRemoved implements validators.ManagerCallbackListener
+func (m *Monitor) OnValidatorRemoved(netID ids.ID, nodeID ids.NodeID, _ uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.lights[netID], nodeID)
+	m.evaluate(netID)
+}
+
+// OnValidatorLightChanged implements validators.ManagerCallbackListener
+func (m *Monitor) OnValidatorLightChanged(netID ids.ID, nodeID ids.NodeID, _, newLight uint64) {
+	m.setLight(netID, nodeID, newLight)
+}
+
+func (m *Monitor) setLight(netID ids.ID, nodeID ids.NodeID, light uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.lights[netID] == nil {
+		m.lights[netID] = make(map[ids.NodeID]uint64)
+	}
+	m.lights[netID][nodeID] = light
+	m.evaluate(netID)
+}
+
+// evaluate raises and clears the alarms of [netID], unless a replay is in
+// progress. It assumes the lock is held.
+func (m *Monitor) evaluate(netID ids.ID) {
+	if m.replaying {
+		return
+	}
+	config, ok := m.configs[netID]
+	if !ok {
+		config = m.defaultConfig
+	}
+
+	lights := make([]uint64, 0, len(m.lights[netID]))
+	for _, light := range m.lights[netID] {
+		lights = append(lights, light)
+	}
+	slices.SortFunc(lights, func(a, b uint64) int {
+		return cmp.Compare(b, a)
+	})
+
+	state := Alarm{
+		NetID: netID,
+		Count: len(lights),
+	}
+	for i, light := range lights {
+		// Saturate rather than fail, so a net can't silence its alarms
+		state.TotalLight = saturatingAdd(state.TotalLight, light)
+		if i < config.TopK {
+			state.TopKLight = saturatingAdd(state.TopKLight, light)
+		}
+	}
+
+	m.set(ValidatorCountLow, state, state.Count < config.MinValidators)
+	m.set(ConcentrationHigh, state, config.TopK > 0 && state.Count > config.TopK && state.TotalLight > 0 &&
+		exceeds(state.TopKLight, state.TotalLight, config.MaxShareNumerator, config.MaxShareDenominator),
+	)
+}
+
+// set raises or clears the alarm [kind] of [state.NetID]. Handlers are only
+// notified of changes. It assumes the lock is held.
+func (m *Monitor) set(kind Kind, state Alarm, raise bool) {
+	state.Kind = kind
+	key := alarmKey{netID: state.NetID, kind: kind}
+	_, raised := m.raised[key]
+	switch {
+	case raise && !raised:
+		m.raised[key] = state
+		for _, handler := range m.handlers {
+			handler.OnAlarmRaised(state)
+		}
+	case raise:
+		m.raised[key] = state
+	case raised:
+		delete(m.raised, key)
+		for _, handler := range m.handlers {
+			handler.OnAlarmCleared(state)
+		}
+	}
+}
+
+// exceeds returns true if part/total > num/den, comparing 128 bit products
+func exceeds(part, total, num, den uint64) bool {
+	lhsHi, lhsLo := bits.Mul64(part, den)
+	rhsHi, rhsLo := bits.Mul64(total, num)
+	if lhsHi != rhsHi {
+		return lhsHi > rhsHi
+	}
+	return lhsLo > rhsLo
+}
+
+func saturatingAdd(a, b uint64) uint64 {
+	sum, carry := bits.Add64(a, b, 0)
+	if carry != 0 {
+		return ^uint64(0)
+	}
+	return sum
+}
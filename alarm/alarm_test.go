@@ -0,0 +1,114 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package alarm
+
+import (
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+type testHandler struct {
+	raised  []Alarm
+	cleared []Alarm
+}
+
+func (h *testHandler) OnAlarmRaised(alarm Alarm) {
+	h.raised = append(h.raised, alarm)
+}
+
+func (h *testHandler) OnAlarmCleared(alarm Alarm) {
+	h.cleared = append(h.cleared, alarm)
+}
+
+// TestMonitorValidatorCount tests the validator count floor
+func TestMonitorValidatorCount(t *testing.T) {
+	require := require.New(t)
+
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	nodeIDs := []ids.NodeID{ids.GenerateTestNodeID(), ids.GenerateTestNodeID(), ids.GenerateTestNodeID()}
+	for _, nodeID := range nodeIDs {
+		require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 10))
+	}
+
+	monitor, err := NewMonitor(Config{MinValidators: 3})
+	require.NoError(err)
+	handler := &testHandler{}
+	monitor.RegisterHandler(handler)
+	monitor.Watch(m)
+	require.Empty(monitor.Active())
+
+	require.NoError(m.RemoveWeight(netID, nodeIDs[0], 10))
+	expected := Alarm{Kind: ValidatorCountLow, NetID: netID, Count: 2, TotalLight: 20}
+	require.Equal([]Alarm{expected}, handler.raised)
+	require.Equal([]Alarm{expected}, monitor.Active())
+
+	// Further drops don't raise the alarm again
+	require.NoError(m.RemoveWeight(netID, nodeIDs[1], 10))
+	require.Len(handler.raised, 1)
+
+	require.NoError(m.AddStaker(netID, nodeIDs[0], nil, ids.Empty, 10))
+	require.NoError(m.AddStaker(netID, nodeIDs[1], nil, ids.Empty, 10))
+	require.Equal([]Alarm{{Kind: ValidatorCountLow, NetID: netID, Count: 3, TotalLight: 30}}, handler.cleared)
+	require.Empty(monitor.Active())
+}
+
+// TestMonitorConcentration tests the top-k share ceiling
+func TestMonitorConcentration(t *testing.T) {
+	require := require.New(t)
+
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	for _, light := range []uint64{10, 10, 10, 10} {
+		require.NoError(m.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, light))
+	}
+
+	// The two largest validators may hold at most half the light. The first
+	// validators replayed hold more, but the set is only evaluated once it is
+	// complete.
+	monitor, err := NewMonitor(Config{})
+	require.NoError(err)
+	require.NoError(monitor.SetConfig(netID, Config{TopK: 2, MaxShareNumerator: 1, MaxShareDenominator: 2}))
+	handler := &testHandler{}
+	monitor.RegisterHandler(handler)
+	monitor.Watch(m)
+	require.Empty(handler.raised)
+
+	whale := ids.GenerateTestNodeID()
+
+	require.NoError(m.AddStaker(netID, whale, nil, ids.Empty, 50))
+	require.Equal([]Alarm{{Kind: ConcentrationHigh, NetID: netID, Count: 5, TopKLight: 60, TotalLight: 90}}, handler.raised)
+
+	require.NoError(m.RemoveWeight(netID, whale, 40))
+	require.Equal([]Alarm{{Kind: ConcentrationHigh, NetID: netID, Count: 5, TopKLight: 20, TotalLight: 50}}, handler.cleared)
+
+	// Other nets use the default config
+	otherNetID := ids.GenerateTestID()
+	require.NoError(m.AddStaker(otherNetID, whale, nil, ids.Empty, 100))
+	require.Len(handler.raised, 1)
+
+	// A net with at most TopK validators can't be concentrated
+	require.NoError(monitor.SetConfig(otherNetID, Config{TopK: 2, MaxShareNumerator: 1, MaxShareDenominator: 2}))
+	require.NoError(m.AddStaker(otherNetID, ids.GenerateTestNodeID(), nil, ids.Empty, 1))
+	require.Len(handler.raised, 1)
+	require.NoError(m.AddStaker(otherNetID, ids.GenerateTestNodeID(), nil, ids.Empty, 1))
+	require.Len(handler.raised, 2)
+}
+
+// TestConfigVerify tests config validation
+func TestConfigVerify(t *testing.T) {
+	require := require.New(t)
+
+	require.NoError(Config{}.Verify())
+	require.ErrorIs(Config{MinValidators: -1}.Verify(), ErrInvalidConfig)
+	require.ErrorIs(Config{TopK: 1}.Verify(), ErrInvalidConfig)
+	require.ErrorIs(Config{TopK: 1, MaxShareNumerator: 2, MaxShareDenominator: 1}.Verify(), ErrInvalidConfig)
+
+	_, err := NewMonitor(Config{TopK: -1})
+	require.ErrorIs(err, ErrInvalidConfig)
+}
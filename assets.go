@@ -0,0 +1,296 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"fmt"
+	"maps"
+	"math/big"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math"
+)
+
+// AssetRateDenominator is the denominator of asset conversion rates, so an
+// asset with a rate of AssetRateDenominator converts 1:1 into weight
+const AssetRateDenominator = 1_000_000
+
+// AssetManager tracks stake held in multiple assets. Each net converts an
+// asset into weight with its own rate, and the converted amounts add to the
+// validator's light and weight alongside its self-stake and delegations.
+type AssetManager interface {
+	// SetAssetRate sets the conversion rate of [assetID] in [netID] and
+	// re-derives the weights of every validator of [netID] holding it. Either
+	// every validator is updated or none are.
+	SetAssetRate(netID ids.ID, assetID ids.ID, rate uint64) error
+	// GetAssetRate returns the conversion rate of [assetID] in [netID]
+	GetAssetRate(netID ids.ID, assetID ids.ID) uint64
+	// SetAssetStake sets the amount of [assetID] staked by [nodeID]
+	SetAssetStake(netID ids.ID, nodeID ids.NodeID, assetID ids.ID, amount uint64) error
+	// GetAssetStake returns the amount of [assetID] staked by [nodeID]
+	GetAssetStake(netID ids.ID, nodeID ids.NodeID, assetID ids.ID) uint64
+	// GetAssetStakes returns the amount of every asset staked by [nodeID]
+	GetAssetStakes(netID ids.ID, nodeID ids.NodeID) map[ids.ID]uint64
+	// GetAssetWeight returns the weight derived from the assets of [nodeID]
+	GetAssetWeight(netID ids.ID, nodeID ids.NodeID) uint64
+	// RegisterAssetListener registers a listener for asset rate changes
+	RegisterAssetListener(listener AssetListener)
+}
+
+// AssetListener listens to asset conversion rate changes. The resulting light
+// changes are reported to the ManagerCallbackListeners.
+type AssetListener interface {
+	OnAssetRateChanged(netID ids.ID, assetID ids.ID, oldRate, newRate uint64)
+}
+
+var _ AssetManager = (*manager)(nil)
+
+// assets tracks the asset stakes of every validator
+type assets struct {
+	rates   map[ids.ID]map[ids.ID]uint64
+	amounts map[validatorKey]map[ids.ID]uint64
+	derived map[validatorKey]uint64
+}
+
+func newAssets() *assets {
+	return &assets{
+		rates:   make(map[ids.ID]map[ids.ID]uint64),
+		amounts: make(map[validatorKey]map[ids.ID]uint64),
+		derived: make(map[validatorKey]uint64),
+	}
+}
+
+// clone returns a copy of [a] whose stakes can change without changing [a]
+func (a *assets) clone() *assets {
+	c := &assets{
+		rates:   make(map[ids.ID]map[ids.ID]uint64, len(a.rates)),
+		amounts: make(map[validatorKey]map[ids.ID]uint64, len(a.amounts)),
+		derived: maps.Clone(a.derived),
+	}
+	for netID, rates := range a.rates {
+		c.rates[netID] = maps.Clone(rates)
+	}
+	for key, amounts := range a.amounts {
+		c.amounts[key] = maps.Clone(amounts)
+	}
+	return c
+}
+
+// derive returns the weight of [amounts] at the rates of [netID], with
+// [assetID] priced at [rate] instead
+func (a *assets) derive(netID ids.ID, amounts map[ids.ID]uint64, assetID ids.ID, rate uint64) (uint64, error) {
+	total := new(big.Int)
+	for id, amount := range amounts {
+		r := a.rates[netID][id]
+		if id == assetID {
+			r = rate
+		}
+		total.Add(total, new(big.Int).Mul(new(big.Int).SetUint64(amount), new(big.Int).SetUint64(r)))
+	}
+	total.Quo(total, big.NewInt(AssetRateDenominator))
+	if !total.IsUint64() {
+		return 0, fmt.Errorf("%w: asset weight %s", ErrWeightOverflow, total)
+	}
+	return total.Uint64(), nil
+}
+
+// externalWeight returns the part of a validator's weight that isn't its
+// self-stake. It assumes the lock is held.
+func (m *manager) externalWeight(key validatorKey) uint64 {
+	return m.delegations.totals[key] + m.assets.derived[key]
+}
+
+// reweighAssets replaces a validator's derived asset weight with [derived].
+// It assumes the lock is held and returns the old and new light.
+func (m *manager) reweighAssets(key validatorKey, val *GetValidatorOutput, derived uint64) (uint64, uint64, error) {
+	old := m.assets.derived[key]
+	oldLight := val.Light
+	if derived >= old {
+		newLight, err := math.Add64(val.Light, derived-old)
+		if err != nil {
+			return 0, 0, fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+		}
+		newWeight, err := math.Add64(val.Weight, derived-old)
+		if err != nil {
+			return 0, 0, fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+		}
+		val.Light, val.Weight = newLight, newWeight
+	} else {
+		val.Light -= min(old-derived, val.Light)
+		val.Weight -= old - derived
+	}
+	m.bumpSequence(key.netID, val)
+
+	if derived == 0 {
+		delete(m.assets.derived, key)
+	} else {
+		m.assets.derived[key] = derived
+	}
+	return oldLight, val.Light, nil
+}
+
+// SetAssetRate sets an asset's conversion rate and re-derives weights
+func (m *manager) SetAssetRate(netID ids.ID, assetID ids.ID, rate uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.requireThawed(netID); err != nil {
+		return err
+	}
+	if err := m.requireWeightMode(netID, WeightModeUint64); err != nil {
+		return err
+	}
+
+	// Derive every new weight before applying any so the update is atomic
+	type reweigh struct {
+		key     validatorKey
+		val     *GetValidatorOutput
+		derived uint64
+	}
+	var reweighs []reweigh
+	for key, amounts := range m.assets.amounts {
+		if key.netID != netID || amounts[assetID] == 0 {
+			continue
+		}
+		val, exists := m.validators[netID][key.nodeID]
+		if !exists {
+			continue
+		}
+		derived, err := m.assets.derive(netID, amounts, assetID, rate)
+		if err != nil {
+			return err
+		}
+		old := m.assets.derived[key]
+		if derived > old {
+			if _, err := math.Add64(val.Weight, derived-old); err != nil {
+				return fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+			}
+			if _, err := math.Add64(val.Light, derived-old); err != nil {
+				return fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+			}
+		}
+		reweighs = append(reweighs, reweigh{key: key, val: val, derived: derived})
+	}
+
+	oldRate := m.assets.rates[netID][assetID]
+	if m.assets.rates[netID] == nil {
+		m.assets.rates[netID] = make(map[ids.ID]uint64)
+	}
+	m.assets.rates[netID][assetID] = rate
+
+	for _, listener := range m.assetListeners {
+		listener.OnAssetRateChanged(netID, assetID, oldRate, rate)
+	}
+	var notify []func(ManagerCallbackListener)
+	for _, r := range reweighs {
+		// Overflow was ruled out above
+		oldLight, newLight, _ := m.reweighAssets(r.key, r.val, r.derived)
+		if oldLight == newLight {
+			continue
+		}
+		nodeID := r.key.nodeID
+		notify = append(notify, func(listener ManagerCallbackListener) {
+			listener.OnValidatorLightChanged(netID, nodeID, oldLight, newLight)
+		})
+	}
+	m.notify(notifyAll(notify))
+	return nil
+}
+
+// GetAssetRate returns an asset's conversion rate
+func (m *manager) GetAssetRate(netID ids.ID, assetID ids.ID) uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.assets.rates[netID][assetID]
+}
+
+// SetAssetStake sets the amount of an asset staked by an existing validator
+func (m *manager) SetAssetStake(netID ids.ID, nodeID ids.NodeID, assetID ids.ID, amount uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.requireThawed(netID); err != nil {
+		return err
+	}
+
+	val, err := m.uint64Validator(netID, nodeID)
+	if val == nil {
+		return err // Validator doesn't exist, nothing to stake on
+	}
+
+	key := validatorKey{netID: netID, nodeID: nodeID}
+	amounts := maps.Clone(m.assets.amounts[key])
+	if amounts == nil {
+		amounts = make(map[ids.ID]uint64)
+	}
+	if amount == 0 {
+		delete(amounts, assetID)
+	} else {
+		amounts[assetID] = amount
+	}
+	derived, err := m.assets.derive(netID, amounts, assetID, m.assets.rates[netID][assetID])
+	if err != nil {
+		return err
+	}
+	oldLight, newLight, err := m.reweighAssets(key, val, derived)
+	if err != nil {
+		return err
+	}
+
+	if len(amounts) == 0 {
+		delete(m.assets.amounts, key)
+	} else {
+		m.assets.amounts[key] = amounts
+	}
+	if oldLight != newLight {
+		m.notify(func(listener ManagerCallbackListener) {
+			listener.OnValidatorLightChanged(netID, nodeID, oldLight, newLight)
+		})
+	}
+	return nil
+}
+
+// GetAssetStake returns the amount of an asset staked by a validator
+func (m *manager) GetAssetStake(netID ids.ID, nodeID ids.NodeID, assetID ids.ID) uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.assets.amounts[validatorKey{netID: netID, nodeID: nodeID}][assetID]
+}
+
+// GetAssetStakes returns a copy of the asset stakes of a validator
+func (m *manager) GetAssetStakes(netID ids.ID, nodeID ids.NodeID) map[ids.ID]uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	amounts := m.assets.amounts[validatorKey{netID: netID, nodeID: nodeID}]
+	if amounts == nil {
+		return make(map[ids.ID]uint64)
+	}
+	return maps.Clone(amounts)
+}
+
+// GetAssetWeight returns the weight derived from a validator's assets
+func (m *manager) GetAssetWeight(netID ids.ID, nodeID ids.NodeID) uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.assets.derived[validatorKey{netID: netID, nodeID: nodeID}]
+}
+
+// RegisterAssetListener registers an asset listener
+func (m *manager) RegisterAssetListener(listener AssetListener) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.assetListeners = append(m.assetListeners, listener)
+}
+
+// removeAssets drops the asset stakes of a validator that is being removed.
+// It assumes the lock is held.
+func (m *manager) removeAssets(key validatorKey) {
+	delete(m.assets.amounts, key)
+	delete(m.assets.derived, key)
+}
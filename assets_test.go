@@ -0,0 +1,123 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"math"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+type rateChangedEvent struct {
+	netID   ids.ID
+	assetID ids.ID
+	oldRate uint64
+	newRate uint64
+}
+
+type testAssetListener struct {
+	events []rateChangedEvent
+}
+
+func (l *testAssetListener) OnAssetRateChanged(netID ids.ID, assetID ids.ID, oldRate, newRate uint64) {
+	l.events = append(l.events, rateChangedEvent{netID, assetID, oldRate, newRate})
+}
+
+// TestManagerAssetStakes tests that asset stakes add converted weight
+func TestManagerAssetStakes(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	lux := ids.GenerateTestID()
+	usd := ids.GenerateTestID()
+
+	require.NoError(m.SetAssetRate(netID, lux, AssetRateDenominator))
+	require.NoError(m.SetAssetRate(netID, usd, AssetRateDenominator/2))
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+
+	require.NoError(m.SetAssetStake(netID, nodeID, lux, 30))
+	require.NoError(m.SetAssetStake(netID, nodeID, usd, 40))
+	require.Equal(uint64(50), m.GetAssetWeight(netID, nodeID))
+	require.Equal(uint64(150), m.GetLight(netID, nodeID))
+	require.Equal(uint64(100), m.GetSelfStake(netID, nodeID))
+	require.Equal(uint64(40), m.GetAssetStake(netID, nodeID, usd))
+	require.Equal(map[ids.ID]uint64{lux: 30, usd: 40}, m.GetAssetStakes(netID, nodeID))
+
+	require.NoError(m.SetAssetStake(netID, nodeID, lux, 0))
+	require.Equal(uint64(20), m.GetAssetWeight(netID, nodeID))
+	require.Equal(uint64(120), m.GetLight(netID, nodeID))
+	require.Equal(map[ids.ID]uint64{usd: 40}, m.GetAssetStakes(netID, nodeID))
+
+	// Removing the self-stake removes the asset stakes
+	require.NoError(m.RemoveWeight(netID, nodeID, 100))
+	_, ok := m.GetValidator(netID, nodeID)
+	require.False(ok)
+	require.Empty(m.GetAssetStakes(netID, nodeID))
+	require.Zero(m.GetAssetWeight(netID, nodeID))
+}
+
+// TestManagerSetAssetRate tests re-deriving weights when a rate changes
+func TestManagerSetAssetRate(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID0 := ids.GenerateTestNodeID()
+	nodeID1 := ids.GenerateTestNodeID()
+	assetID := ids.GenerateTestID()
+
+	listener := &testListener{}
+	assetListener := &testAssetListener{}
+	m.RegisterCallbackListener(listener)
+	m.RegisterAssetListener(assetListener)
+
+	require.NoError(m.AddStaker(netID, nodeID0, nil, ids.Empty, 100))
+	require.NoError(m.AddStaker(netID, nodeID1, nil, ids.Empty, 100))
+	require.NoError(m.SetAssetStake(netID, nodeID0, assetID, 10))
+	require.Equal(uint64(100), m.GetLight(netID, nodeID0))
+
+	require.NoError(m.SetAssetRate(netID, assetID, 2*AssetRateDenominator))
+	require.Equal(uint64(2*AssetRateDenominator), m.GetAssetRate(netID, assetID))
+	require.Equal(uint64(120), m.GetLight(netID, nodeID0))
+	require.Equal(uint64(100), m.GetLight(netID, nodeID1))
+	require.Equal([]rateChangedEvent{{netID, assetID, 0, 2 * AssetRateDenominator}}, assetListener.events)
+	require.Equal([]lightChangedEvent{{netID, nodeID0, 100, 120}}, listener.changed)
+
+	// Rates are per net
+	require.Zero(m.GetAssetRate(ids.GenerateTestID(), assetID))
+}
+
+// TestManagerSetAssetRateAtomic tests that a failing rate change applies
+// nothing
+func TestManagerSetAssetRateAtomic(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID0 := ids.GenerateTestNodeID()
+	nodeID1 := ids.GenerateTestNodeID()
+	assetID := ids.GenerateTestID()
+
+	require.NoError(m.SetAssetRate(netID, assetID, AssetRateDenominator))
+	require.NoError(m.AddStaker(netID, nodeID0, nil, ids.Empty, 100))
+	require.NoError(m.AddStaker(netID, nodeID1, nil, ids.Empty, math.MaxUint64-100))
+	require.NoError(m.SetAssetStake(netID, nodeID0, assetID, 10))
+	require.NoError(m.SetAssetStake(netID, nodeID1, assetID, 10))
+
+	err := m.SetAssetRate(netID, assetID, 100*AssetRateDenominator)
+	require.ErrorIs(err, ErrWeightOverflow)
+	require.Equal(uint64(AssetRateDenominator), m.GetAssetRate(netID, assetID))
+	require.Equal(uint64(110), m.GetLight(netID, nodeID0))
+	require.Equal(uint64(math.MaxUint64-90), m.GetLight(netID, nodeID1))
+
+	require.ErrorIs(m.SetAssetStake(netID, nodeID1, assetID, 1_000), ErrWeightOverflow)
+	require.Equal(uint64(10), m.GetAssetStake(netID, nodeID1, assetID))
+	require.NoError(m.SetAssetStake(netID, ids.GenerateTestNodeID(), assetID, 1))
+	m.SetStrict(true)
+	require.ErrorIs(m.SetAssetStake(netID, ids.GenerateTestNodeID(), assetID, 1), ErrValidatorNotFound)
+}
@@ -0,0 +1,263 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math"
+)
+
+var (
+	ErrNotBalanceBacked = errors.New("validator isn't balance backed")
+	ErrZeroBalance      = errors.New("balance must be non-zero")
+)
+
+// BalanceManager tracks sovereign L1 validators that pay a continuous fee out
+// of a balance. A validator whose balance is drained is deactivated: it is
+// removed from its net's validator set but its registration is kept so a
+// top-up reactivates it.
+type BalanceManager interface {
+	// RegisterL1Validator adds a validator backed by [balance]
+	RegisterL1Validator(netID ids.ID, nodeID ids.NodeID, publicKey []byte, txID ids.ID, light uint64, balance uint64) error
+	// TopUp adds [amount] to the balance of [nodeID], reactivating it if it
+	// was deactivated
+	TopUp(netID ids.ID, nodeID ids.NodeID, amount uint64) error
+	// GetBalance returns the remaining balance of [nodeID] and whether it is
+	// balance backed
+	GetBalance(netID ids.ID, nodeID ids.NodeID) (uint64, bool)
+	// IsActive returns true if [nodeID] is a balance backed validator that is
+	// currently in the validator set
+	IsActive(netID ids.ID, nodeID ids.NodeID) bool
+	// SetFeeConfig sets the rate at which balances of [netID] are drained
+	SetFeeConfig(netID ids.ID, config FeeConfig)
+	// AdvanceBalances drains the balances of [netID] for the time and heights
+	// elapsed since the previous call. The first call only records the
+	// starting point.
+	AdvanceBalances(netID ids.ID, height uint64, timestamp time.Time)
+	// RegisterBalanceListener registers a listener for balance changes
+	RegisterBalanceListener(listener BalanceListener)
+}
+
+// FeeConfig is the fee charged to every balance backed validator of a net
+type FeeConfig struct {
+	PerSecond uint64
+	PerHeight uint64
+}
+
+// BalanceListener listens to balance changes of balance backed validators
+type BalanceListener interface {
+	OnBalanceChanged(netID ids.ID, nodeID ids.NodeID, oldBalance, newBalance uint64)
+	OnValidatorDeactivated(netID ids.ID, nodeID ids.NodeID)
+	OnValidatorReactivated(netID ids.ID, nodeID ids.NodeID)
+}
+
+var _ BalanceManager = (*manager)(nil)
+
+// balanceEntry is the bookkeeping of a balance backed validator
+type balanceEntry struct {
+	balance uint64
+	// inactive holds the validator's record while it is deactivated
+	inactive *GetValidatorOutput
+}
+
+// balanceClock is the point balances of a net were last drained at
+type balanceClock struct {
+	height    uint64
+	timestamp time.Time
+}
+
+// RegisterL1Validator adds a balance backed validator
+func (m *manager) RegisterL1Validator(netID ids.ID, nodeID ids.NodeID, publicKey []byte, txID ids.ID, light uint64, balance uint64) error {
+	if balance == 0 {
+		return ErrZeroBalance
+	}
+	if err := m.AddStaker(netID, nodeID, publicKey, txID, light); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.requireThawed(netID); err != nil {
+		return err
+	}
+
+	key := validatorKey{netID: netID, nodeID: nodeID}
+	var oldBalance uint64
+	if entry, ok := m.balances[key]; ok {
+		oldBalance = entry.balance
+	}
+	m.balances[key] = &balanceEntry{balance: balance}
+	m.notifyBalanceChanged(netID, nodeID, oldBalance, balance)
+	return nil
+}
+
+// TopUp adds to a validator's balance
+func (m *manager) TopUp(netID ids.ID, nodeID ids.NodeID, amount uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.requireThawed(netID); err != nil {
+		return err
+	}
+
+	entry, ok := m.balances[validatorKey{netID: netID, nodeID: nodeID}]
+	if !ok {
+		return fmt.Errorf("%w: %s in %s", ErrNotBalanceBacked, nodeID, netID)
+	}
+	newBalance, err := math.Add64(entry.balance, amount)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+	}
+	if amount == 0 {
+		return nil
+	}
+
+	oldBalance := entry.balance
+	entry.balance = newBalance
+	m.notifyBalanceChanged(netID, nodeID, oldBalance, newBalance)
+
+	if record := entry.inactive; record != nil {
+		entry.inactive = nil
+		m.putValidator(netID, record)
+
+		for _, listener := range m.balanceListeners {
+			listener.OnValidatorReactivated(netID, nodeID)
+		}
+		m.notify(func(listener ManagerCallbackListener) {
+			listener.OnValidatorAdded(netID, nodeID, record.Light)
+		})
+	}
+	return nil
+}
+
+// GetBalance returns a validator's remaining balance
+func (m *manager) GetBalance(netID ids.ID, nodeID ids.NodeID) (uint64, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entry, ok := m.balances[validatorKey{netID: netID, nodeID: nodeID}]
+	if !ok {
+		return 0, false
+	}
+	return entry.balance, true
+}
+
+// IsActive returns true if a balance backed validator is in the validator set
+func (m *manager) IsActive(netID ids.ID, nodeID ids.NodeID) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entry, ok := m.balances[validatorKey{netID: netID, nodeID: nodeID}]
+	return ok && entry.inactive == nil
+}
+
+// SetFeeConfig sets the fee charged to balance backed validators of a net
+func (m *manager) SetFeeConfig(netID ids.ID, config FeeConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.feeConfigs[netID] = config
+}
+
+// AdvanceBalances drains balances for the elapsed time and heights
+func (m *manager) AdvanceBalances(netID ids.ID, height uint64, timestamp time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// The clock isn't advanced, so the first call after thaw charges for the
+	// frozen window
+	if m.frozen[netID] > 0 {
+		return
+	}
+
+	last, ok := m.balanceClocks[netID]
+	m.balanceClocks[netID] = balanceClock{height: height, timestamp: timestamp}
+	if !ok {
+		return
+	}
+
+	var (
+		config  = m.feeConfigs[netID]
+		seconds uint64
+		heights uint64
+	)
+	if elapsed := timestamp.Sub(last.timestamp); elapsed > 0 {
+		seconds = uint64(elapsed / time.Second)
+	}
+	if height > last.height {
+		heights = height - last.height
+	}
+	fee := saturatingAdd(saturatingMul(config.PerSecond, seconds), saturatingMul(config.PerHeight, heights))
+	if fee == 0 {
+		return
+	}
+
+	for key, entry := range m.balances {
+		if key.netID != netID || entry.inactive != nil {
+			continue
+		}
+
+		oldBalance := entry.balance
+		entry.balance -= min(fee, entry.balance)
+		m.notifyBalanceChanged(netID, key.nodeID, oldBalance, entry.balance)
+		if entry.balance == 0 {
+			m.deactivate(key, entry)
+		}
+	}
+}
+
+// deactivate moves a drained validator out of the validator set. It assumes
+// the lock is held.
+func (m *manager) deactivate(key validatorKey, entry *balanceEntry) {
+	record, ok := m.validators[key.netID][key.nodeID]
+	if !ok {
+		return
+	}
+	entry.inactive = record
+	m.deleteValidator(key.netID, key.nodeID)
+
+	for _, listener := range m.balanceListeners {
+		listener.OnValidatorDeactivated(key.netID, key.nodeID)
+	}
+	m.notify(func(listener ManagerCallbackListener) {
+		listener.OnValidatorRemoved(key.netID, key.nodeID, record.Light)
+	})
+}
+
+// notifyBalanceChanged assumes the lock is held
+func (m *manager) notifyBalanceChanged(netID ids.ID, nodeID ids.NodeID, oldBalance, newBalance uint64) {
+	for _, listener := range m.balanceListeners {
+		listener.OnBalanceChanged(netID, nodeID, oldBalance, newBalance)
+	}
+}
+
+// RegisterBalanceListener registers a balance listener
+func (m *manager) RegisterBalanceListener(listener BalanceListener) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.balanceListeners = append(m.balanceListeners, listener)
+}
+
+func saturatingMul(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	if hi != 0 {
+		return ^uint64(0)
+	}
+	return lo
+}
+
+func saturatingAdd(a, b uint64) uint64 {
+	sum, carry := bits.Add64(a, b, 0)
+	if carry != 0 {
+		return ^uint64(0)
+	}
+	return sum
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+type testBalanceListener struct {
+	balances    []uint64
+	deactivated []ids.NodeID
+	reactivated []ids.NodeID
+}
+
+func (l *testBalanceListener) OnBalanceChanged(_ ids.ID, _ ids.NodeID, _, newBalance uint64) {
+	l.balances = append(l.balances, newBalance)
+}
+
+func (l *testBalanceListener) OnValidatorDeactivated(_ ids.ID, nodeID ids.NodeID) {
+	l.deactivated = append(l.deactivated, nodeID)
+}
+
+func (l *testBalanceListener) OnValidatorReactivated(_ ids.ID, nodeID ids.NodeID) {
+	l.reactivated = append(l.reactivated, nodeID)
+}
+
+// TestManagerBalanceDrain tests deactivation on an empty balance and
+// reactivation on top-up
+func TestManagerBalanceDrain(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	start := time.Unix(1_000, 0)
+
+	balanceListener := &testBalanceListener{}
+	m.RegisterBalanceListener(balanceListener)
+	listener := &testListener{}
+	m.RegisterCallbackListener(listener)
+
+	m.SetFeeConfig(netID, FeeConfig{PerSecond: 2, PerHeight: 1})
+	require.NoError(m.RegisterL1Validator(netID, nodeID, nil, ids.Empty, 100, 50))
+	require.True(m.IsActive(netID, nodeID))
+
+	m.AdvanceBalances(netID, 10, start)
+	balance, ok := m.GetBalance(netID, nodeID)
+	require.True(ok)
+	require.Equal(uint64(50), balance)
+
+	// 10 seconds and 5 heights cost 25
+	m.AdvanceBalances(netID, 15, start.Add(10*time.Second))
+	balance, _ = m.GetBalance(netID, nodeID)
+	require.Equal(uint64(25), balance)
+	require.True(m.IsActive(netID, nodeID))
+
+	m.AdvanceBalances(netID, 30, start.Add(20*time.Second))
+	balance, ok = m.GetBalance(netID, nodeID)
+	require.True(ok)
+	require.Zero(balance)
+	require.False(m.IsActive(netID, nodeID))
+	_, ok = m.GetValidator(netID, nodeID)
+	require.False(ok)
+	require.Equal([]ids.NodeID{nodeID}, balanceListener.deactivated)
+	require.Equal([]validatorEvent{{netID, nodeID, 100}}, listener.removed)
+
+	require.NoError(m.TopUp(netID, nodeID, 40))
+	require.True(m.IsActive(netID, nodeID))
+	require.Equal(uint64(100), m.GetLight(netID, nodeID))
+	require.Equal([]ids.NodeID{nodeID}, balanceListener.reactivated)
+	require.Equal([]validatorEvent{{netID, nodeID, 100}, {netID, nodeID, 100}}, listener.added)
+	require.Equal([]uint64{50, 25, 0, 40}, balanceListener.balances)
+}
+
+// TestManagerBalanceErrors tests invalid balance operations
+func TestManagerBalanceErrors(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+
+	require.ErrorIs(m.RegisterL1Validator(netID, nodeID, nil, ids.Empty, 100, 0), ErrZeroBalance)
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+	require.ErrorIs(m.TopUp(netID, nodeID, 1), ErrNotBalanceBacked)
+	_, ok := m.GetBalance(netID, nodeID)
+	require.False(ok)
+	require.False(m.IsActive(netID, nodeID))
+
+	require.ErrorIs(m.RegisterL1Validator(netID, nodeID, nil, ids.Empty, 100, 1), ErrDuplicateValidator)
+	require.NoError(m.RemoveStaker(netID, nodeID))
+	require.NoError(m.RegisterL1Validator(netID, nodeID, nil, ids.Empty, 100, ^uint64(0)))
+	require.ErrorIs(m.TopUp(netID, nodeID, 1), ErrWeightOverflow)
+
+	// Removing the validator drops its balance
+	require.NoError(m.RemoveWeight(netID, nodeID, 100))
+	_, ok = m.GetBalance(netID, nodeID)
+	require.False(ok)
+}
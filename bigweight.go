@@ -0,0 +1,429 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"math/big"
+	"slices"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+)
+
+var (
+	ErrWrongWeightMode = errors.New("wrong weight mode")
+	ErrNetNotEmpty     = errors.New("net has validators")
+	ErrNegativeWeight  = NewCategoryError("weight is negative", ErrInvalidWeight)
+)
+
+// WeightMode selects how the weights of a net are represented
+type WeightMode uint8
+
+const (
+	// WeightModeUint64 stores weights as uint64. It is the default.
+	WeightModeUint64 WeightMode = iota
+	// WeightModeBig stores weights as arbitrary precision integers, for nets
+	// whose stake is denominated in units that overflow uint64
+	WeightModeBig
+)
+
+// String implements fmt.Stringer
+func (m WeightMode) String() string {
+	switch m {
+	case WeightModeUint64:
+		return "uint64"
+	case WeightModeBig:
+		return "big"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(m))
+	}
+}
+
+// BigWeightManager manages nets whose weights don't fit in a uint64.
+//
+// Validators of a WeightModeBig net are mutated only through the big weight
+// methods. Their uint64 Light and Weight are saturated at MaxUint64, so the
+// uint64 views of such a net are lossy and the big weight queries should be
+// used instead.
+type BigWeightManager interface {
+	// SetWeightMode sets the weight mode of [netID]. The net must not have
+	// any validators.
+	SetWeightMode(netID ids.ID, mode WeightMode) error
+	// GetWeightMode returns the weight mode of [netID]
+	GetWeightMode(netID ids.ID) WeightMode
+	// AddStakerBig adds a validator to a WeightModeBig net
+	AddStakerBig(netID ids.ID, nodeID ids.NodeID, publicKey []byte, txID ids.ID, weight *big.Int) error
+	// AddBigWeight adds weight to a validator of a WeightModeBig net
+	AddBigWeight(netID ids.ID, nodeID ids.NodeID, weight *big.Int) error
+	// RemoveBigWeight removes weight from a validator of a WeightModeBig net,
+	// removing the validator once its weight reaches zero
+	RemoveBigWeight(netID ids.ID, nodeID ids.NodeID, weight *big.Int) error
+	// GetBigWeight returns the exact weight of [nodeID] in any net
+	GetBigWeight(netID ids.ID, nodeID ids.NodeID) *big.Int
+	// TotalBigWeight returns the exact total weight of any net
+	TotalBigWeight(netID ids.ID) *big.Int
+	// GetBigCanonicalValidatorSet returns the canonical validator set of any
+	// net with exact weights
+	GetBigCanonicalValidatorSet(netID ids.ID) BigCanonicalValidatorSet
+}
+
+var _ BigWeightManager = (*manager)(nil)
+
+// BigWeightFromUint64 converts a uint64 weight to a big weight
+func BigWeightFromUint64(weight uint64) *big.Int {
+	return new(big.Int).SetUint64(weight)
+}
+
+// SaturatingUint64 converts a big weight to a uint64, saturating at
+// MaxUint64. Negative weights convert to 0.
+func SaturatingUint64(weight *big.Int) uint64 {
+	switch {
+	case weight.Sign() <= 0:
+		return 0
+	case !weight.IsUint64():
+		return ^uint64(0)
+	default:
+		return weight.Uint64()
+	}
+}
+
+// ScaleBigWeight divides [weight] by 10^[decimals], for example to express an
+// 18-decimal token amount in whole tokens. It errors if the result doesn't
+// fit in a uint64.
+func ScaleBigWeight(weight *big.Int, decimals uint) (uint64, error) {
+	if weight.Sign() < 0 {
+		return 0, ErrNegativeWeight
+	}
+	divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	scaled := new(big.Int).Quo(weight, divisor)
+	if !scaled.IsUint64() {
+		return 0, fmt.Errorf("%w: %s", ErrWeightOverflow, scaled)
+	}
+	return scaled.Uint64(), nil
+}
+
+// BigQuorumReached returns true if [signed]/[total] >= [num]/[den]. The
+// comparison is done by cross multiplication, so it can't overflow.
+func BigQuorumReached(signed, total *big.Int, num, den uint64) bool {
+	lhs := new(big.Int).Mul(signed, BigWeightFromUint64(den))
+	rhs := new(big.Int).Mul(total, BigWeightFromUint64(num))
+	return lhs.Cmp(rhs) >= 0
+}
+
+// SetWeightMode sets the weight mode of an empty net
+func (m *manager) SetWeightMode(netID ids.ID, mode WeightMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.requireThawed(netID); err != nil {
+		return err
+	}
+
+	if len(m.validators[netID]) != 0 {
+		return fmt.Errorf("%w: %s", ErrNetNotEmpty, netID)
+	}
+	if mode == WeightModeUint64 {
+		delete(m.weightModes, netID)
+	} else {
+		m.weightModes[netID] = mode
+	}
+	return nil
+}
+
+// GetWeightMode returns the weight mode of a net
+func (m *manager) GetWeightMode(netID ids.ID) WeightMode {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.weightModes[netID]
+}
+
+// requireWeightMode returns an error if [netID] isn't in [mode]. It assumes
+// the lock is held.
+func (m *manager) requireWeightMode(netID ids.ID, mode WeightMode) error {
+	if actual := m.weightModes[netID]; actual != mode {
+		return fmt.Errorf("%w: %s is in %s mode", ErrWrongWeightMode, netID, actual)
+	}
+	return nil
+}
+
+// AddStakerBig adds a validator with a big weight
+func (m *manager) AddStakerBig(netID ids.ID, nodeID ids.NodeID, publicKey []byte, txID ids.ID, weight *big.Int) error {
+	if weight.Sign() < 0 {
+		return ErrNegativeWeight
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	notify, err := m.addStakerBig(netID, nodeID, publicKey, txID, weight)
+	if err != nil {
+		return err
+	}
+	m.metrics.operation(OpAddStaker)
+	m.notify(notify)
+	return nil
+}
+
+// addStakerBig is AddStakerBig without locking or notifying. It assumes the
+// lock is held and returns the notification of the change.
+func (m *manager) addStakerBig(netID ids.ID, nodeID ids.NodeID, publicKey []byte, txID ids.ID, weight *big.Int) (func(ManagerCallbackListener), error) {
+	if err := m.requireThawed(netID); err != nil {
+		return nil, err
+	}
+	if err := m.requireWeightMode(netID, WeightModeBig); err != nil {
+		return nil, err
+	}
+	if err := m.requireAllowed(netID, nodeID); err != nil {
+		return nil, err
+	}
+	if err := m.requireCapacity(netID, nodeID); err != nil {
+		return nil, err
+	}
+
+	var (
+		key        = validatorKey{netID: netID, nodeID: nodeID}
+		metadata   *ValidatorMetadata
+		extensions map[string][]byte
+	)
+	old, exists := m.validators[netID][nodeID]
+	if exists {
+		switch m.duplicatePolicy {
+		case DuplicateError:
+			return nil, fmt.Errorf("%w: %s in %s", ErrDuplicateValidator, nodeID, netID)
+		case DuplicateMergeWeight:
+			return m.setBigWeight(netID, old, new(big.Int).Add(m.bigWeights[key], weight)), nil
+		}
+		metadata = old.Metadata
+		extensions = old.Extensions
+	}
+	light := SaturatingUint64(weight)
+	m.putValidator(netID, &GetValidatorOutput{
+		NodeID:     nodeID,
+		PublicKey:  publicKey,
+		Light:      light,
+		Weight:     light,
+		TxID:       txID,
+		Metadata:   metadata,
+		Extensions: extensions,
+	})
+	m.bigWeights[key] = new(big.Int).Set(weight)
+
+	if !exists {
+		return func(listener ManagerCallbackListener) {
+			listener.OnValidatorAdded(netID, nodeID, light)
+		}, nil
+	}
+	oldLight := old.Light
+	return func(listener ManagerCallbackListener) {
+		listener.OnValidatorRemoved(netID, nodeID, oldLight)
+		listener.OnValidatorAdded(netID, nodeID, light)
+	}, nil
+}
+
+// AddBigWeight adds a big weight to an existing validator
+func (m *manager) AddBigWeight(netID ids.ID, nodeID ids.NodeID, weight *big.Int) error {
+	if weight.Sign() < 0 {
+		return ErrNegativeWeight
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	notify, err := m.changeBigWeight(netID, nodeID, weight)
+	if err != nil {
+		return err
+	}
+	m.metrics.operation(OpAddWeight)
+	m.notify(notify)
+	return nil
+}
+
+// RemoveBigWeight removes a big weight from an existing validator
+func (m *manager) RemoveBigWeight(netID ids.ID, nodeID ids.NodeID, weight *big.Int) error {
+	if weight.Sign() < 0 {
+		return ErrNegativeWeight
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	notify, err := m.changeBigWeight(netID, nodeID, new(big.Int).Neg(weight))
+	if err != nil {
+		return err
+	}
+	m.metrics.operation(OpRemoveWeight)
+	m.notify(notify)
+	return nil
+}
+
+// changeBigWeight adds [delta] to the big weight of [nodeID], removing it
+// once its weight reaches zero. It assumes the lock is held and returns the
+// notification of the change, if any.
+func (m *manager) changeBigWeight(netID ids.ID, nodeID ids.NodeID, delta *big.Int) (func(ManagerCallbackListener), error) {
+	if err := m.requireThawed(netID); err != nil {
+		return nil, err
+	}
+	if err := m.requireWeightMode(netID, WeightModeBig); err != nil {
+		return nil, err
+	}
+	val, exists := m.validators[netID][nodeID]
+	if !exists {
+		return nil, m.requireValidator(netID, nodeID) // Validator doesn't exist, nothing to change
+	}
+
+	key := validatorKey{netID: netID, nodeID: nodeID}
+	newWeight := new(big.Int).Add(m.bigWeights[key], delta)
+	if newWeight.Sign() <= 0 {
+		oldLight := val.Light
+		m.evictValidator(netID, nodeID)
+		return func(listener ManagerCallbackListener) {
+			listener.OnValidatorRemoved(netID, nodeID, oldLight)
+		}, nil
+	}
+	return m.setBigWeight(netID, val, newWeight), nil
+}
+
+// setBigWeight sets the big weight of [val] to [weight], saturating its
+// uint64 light and weight. It assumes the lock is held and returns the
+// notification of the change.
+func (m *manager) setBigWeight(netID ids.ID, val *GetValidatorOutput, weight *big.Int) func(ManagerCallbackListener) {
+	var (
+		nodeID   = val.NodeID
+		oldLight = val.Light
+		newLight = SaturatingUint64(weight)
+	)
+	m.bigWeights[validatorKey{netID: netID, nodeID: nodeID}] = weight
+	val.Light = newLight
+	val.Weight = newLight
+	m.bumpSequence(netID, val)
+	return func(listener ManagerCallbackListener) {
+		listener.OnValidatorLightChanged(netID, nodeID, oldLight, newLight)
+	}
+}
+
+// GetBigWeight returns the exact weight of a validator
+func (m *manager) GetBigWeight(netID ids.ID, nodeID ids.NodeID) *big.Int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.bigWeight(netID, nodeID)
+}
+
+// bigWeight assumes the lock is held
+func (m *manager) bigWeight(netID ids.ID, nodeID ids.NodeID) *big.Int {
+	if weight, ok := m.bigWeights[validatorKey{netID: netID, nodeID: nodeID}]; ok {
+		return new(big.Int).Set(weight)
+	}
+	if val, ok := m.validators[netID][nodeID]; ok {
+		return BigWeightFromUint64(val.Light)
+	}
+	return new(big.Int)
+}
+
+// TotalBigWeight returns the exact total weight of a net
+func (m *manager) TotalBigWeight(netID ids.ID) *big.Int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	total := new(big.Int)
+	for nodeID := range m.validators[netID] {
+		total.Add(total, m.bigWeight(netID, nodeID))
+	}
+	return total
+}
+
+// BigCanonicalValidatorSet is a CanonicalValidatorSet with exact weights
+type BigCanonicalValidatorSet struct {
+	// Validators slice in canonical ordering of the validators that has public key
+	Validators []*BigCanonicalValidator
+	// The total weight of all the validators, including the ones that doesn't have a public key
+	TotalWeight *big.Int
+}
+
+// BigCanonicalValidator is a CanonicalValidator with an exact weight
+type BigCanonicalValidator struct {
+	PublicKey      *bls.PublicKey
+	PublicKeyBytes []byte // Uncompressed bytes for canonical ordering
+	Weight         *big.Int
+	NodeIDs        []ids.NodeID // Nodes sharing the public key, in ascending order
+}
+
+// Compare implements utils.Sortable for canonical ordering
+func (v *BigCanonicalValidator) Compare(o *BigCanonicalValidator) int {
+	return bytes.Compare(v.PublicKeyBytes, o.PublicKeyBytes)
+}
+
+var _ Sortable[*BigCanonicalValidator] = (*BigCanonicalValidator)(nil)
+
+// FlattenBigValidatorSet is FlattenValidatorSet with exact weights. The weight
+// of each validator is read from [weights], falling back to its uint64
+// Weight when it is absent. It uses the same ordering and duplicate key
+// merging as FlattenValidatorSet and can't overflow.
+func FlattenBigValidatorSet(vdrSet map[ids.NodeID]*GetValidatorOutput, weights map[ids.NodeID]*big.Int) BigCanonicalValidatorSet {
+	var (
+		pkToValidator = make(map[string]*BigCanonicalValidator)
+		totalWeight   = new(big.Int)
+	)
+	for _, nodeID := range sortNodeIDs(slices.Collect(maps.Keys(vdrSet))) {
+		vdr := vdrSet[nodeID]
+		weight, ok := weights[nodeID]
+		if !ok {
+			weight = BigWeightFromUint64(vdr.Weight)
+		}
+		totalWeight.Add(totalWeight, weight)
+
+		if len(vdr.PublicKey) == 0 {
+			continue
+		}
+		blsPK, err := bls.PublicKeyFromCompressedBytes(vdr.PublicKey)
+		if err != nil {
+			continue // Skip invalid public keys
+		}
+
+		pkBytes := bls.PublicKeyToUncompressedBytes(blsPK)
+		pkKey := string(pkBytes)
+		if existingVdr, exists := pkToValidator[pkKey]; exists {
+			existingVdr.Weight.Add(existingVdr.Weight, weight)
+			existingVdr.NodeIDs = append(existingVdr.NodeIDs, vdr.NodeID)
+		} else {
+			pkToValidator[pkKey] = &BigCanonicalValidator{
+				PublicKey:      blsPK,
+				PublicKeyBytes: pkBytes,
+				Weight:         new(big.Int).Set(weight),
+				NodeIDs:        []ids.NodeID{vdr.NodeID},
+			}
+		}
+	}
+
+	vdrList := slices.Collect(maps.Values(pkToValidator))
+	slices.SortFunc(vdrList, (*BigCanonicalValidator).Compare)
+	return BigCanonicalValidatorSet{Validators: vdrList, TotalWeight: totalWeight}
+}
+
+// GetBigCanonicalValidatorSet returns the canonical set of a net with exact
+// weights
+func (m *manager) GetBigCanonicalValidatorSet(netID ids.ID) BigCanonicalValidatorSet {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	weights := make(map[ids.NodeID]*big.Int, len(m.validators[netID]))
+	for nodeID := range m.validators[netID] {
+		weights[nodeID] = m.bigWeight(netID, nodeID)
+	}
+	return FlattenBigValidatorSet(m.validators[netID], weights)
+}
+
+// SumBigWeight returns the total weight of the provided validators
+func SumBigWeight(vdrs []*BigCanonicalValidator) *big.Int {
+	weight := new(big.Int)
+	for _, vdr := range vdrs {
+		weight.Add(weight, vdr.Weight)
+	}
+	return weight
+}
@@ -0,0 +1,170 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/validators/validatorstest/fixture"
+)
+
+// oneToken is 1 token with 18 decimals
+var oneToken = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+func tokens(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), oneToken)
+}
+
+// TestManagerBigWeights tests exact weights beyond uint64
+func TestManagerBigWeights(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID0 := ids.GenerateTestNodeID()
+	nodeID1 := ids.GenerateTestNodeID()
+
+	require.ErrorIs(m.AddStakerBig(netID, nodeID0, nil, ids.Empty, tokens(1)), ErrWrongWeightMode)
+	require.NoError(m.SetWeightMode(netID, WeightModeBig))
+	require.Equal(WeightModeBig, m.GetWeightMode(netID))
+
+	require.NoError(m.AddStakerBig(netID, nodeID0, nil, ids.Empty, tokens(100)))
+	require.NoError(m.AddStakerBig(netID, nodeID1, nil, ids.Empty, tokens(50)))
+	require.NoError(m.AddBigWeight(netID, nodeID0, tokens(10)))
+	require.NoError(m.RemoveBigWeight(netID, nodeID1, tokens(20)))
+
+	require.Equal(tokens(110), m.GetBigWeight(netID, nodeID0))
+	require.Equal(tokens(30), m.GetBigWeight(netID, nodeID1))
+	require.Equal(tokens(140), m.TotalBigWeight(netID))
+
+	// The uint64 view saturates
+	require.Equal(uint64(math.MaxUint64), m.GetLight(netID, nodeID0))
+
+	// uint64 mutations are rejected on big nets
+	require.ErrorIs(m.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 1), ErrWrongWeightMode)
+	require.ErrorIs(m.AddWeight(netID, nodeID0, 1), ErrWrongWeightMode)
+	require.ErrorIs(m.RemoveWeight(netID, nodeID0, 1), ErrWrongWeightMode)
+	require.ErrorIs(m.SetWeightMode(netID, WeightModeUint64), ErrNetNotEmpty)
+
+	require.NoError(m.RemoveBigWeight(netID, nodeID1, tokens(30)))
+	_, ok := m.GetValidator(netID, nodeID1)
+	require.False(ok)
+	require.Equal(tokens(110), m.TotalBigWeight(netID))
+	require.ErrorIs(m.AddBigWeight(netID, nodeID0, big.NewInt(-1)), ErrNegativeWeight)
+}
+
+// TestManagerBigWeightsNotify tests that big weight changes are counted,
+// dispatched and recorded like any other change, and that saturated lights
+// don't wrap the total
+func TestManagerBigWeightsNotify(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	registry := newTestRegistry()
+	require.NoError(m.RegisterMetrics(registry))
+	netID := ids.GenerateTestID()
+	nodeID0 := ids.GenerateTestNodeID()
+	nodeID1 := ids.GenerateTestNodeID()
+	require.NoError(m.SetWeightMode(netID, WeightModeBig))
+	require.NoError(m.SetHeight(1))
+
+	listener := &testListener{}
+	m.RegisterCallbackListener(listener)
+	require.NoError(m.AddStakerBig(netID, nodeID0, nil, ids.Empty, tokens(100)))
+	require.NoError(m.AddStakerBig(netID, nodeID1, nil, ids.Empty, tokens(50)))
+	require.NoError(m.AddBigWeight(netID, nodeID0, tokens(10)))
+	require.NoError(m.RemoveBigWeight(netID, nodeID1, tokens(50)))
+	require.NoError(m.SetHeight(2))
+
+	require.Equal(map[string]float64{
+		OpAddStaker:    2,
+		OpAddWeight:    1,
+		OpRemoveWeight: 1,
+	}, registry.values[MetricOperations])
+	require.Len(registry.observations[MetricDispatchDuration], 4)
+	require.Len(listener.added, 2)
+	require.Len(listener.changed, 1)
+	require.Len(listener.removed, 1)
+
+	vdrs, err := m.GetValidatorSetAt(netID, 1)
+	require.NoError(err)
+	require.Empty(vdrs)
+	vdrs, err = m.GetValidatorSetAt(netID, 2)
+	require.NoError(err)
+	require.Len(vdrs, 1)
+	require.Contains(vdrs, nodeID0)
+
+	require.NoError(m.AddStakerBig(netID, nodeID1, nil, ids.Empty, tokens(50)))
+	_, err = m.TotalLight(netID)
+	require.ErrorIs(err, ErrWeightOverflow)
+	require.Equal(uint64(math.MaxUint64), m.Snapshot(netID).Light())
+}
+
+// TestManagerBigWeightsUint64Net tests big queries on uint64 nets
+func TestManagerBigWeightsUint64Net(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, math.MaxUint64))
+	require.NoError(m.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 1))
+
+	require.Equal(BigWeightFromUint64(math.MaxUint64), m.GetBigWeight(netID, nodeID))
+	expected := new(big.Int).Add(BigWeightFromUint64(math.MaxUint64), big.NewInt(1))
+	require.Equal(expected, m.TotalBigWeight(netID))
+	require.ErrorIs(m.AddBigWeight(netID, nodeID, big.NewInt(1)), ErrWrongWeightMode)
+}
+
+// TestFlattenBigValidatorSet tests canonical ordering with exact weights
+func TestFlattenBigValidatorSet(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	require.NoError(m.SetWeightMode(netID, WeightModeBig))
+
+	generated, err := fixture.Generate(1, 1, fixture.ConstantWeights(1))
+	require.NoError(err)
+	sk := generated[0].SecretKey
+	pk := bls.PublicKeyToCompressedBytes(sk.PublicKey())
+
+	// Two nodes sharing a key are merged
+	require.NoError(m.AddStakerBig(netID, ids.GenerateTestNodeID(), pk, ids.Empty, tokens(100)))
+	require.NoError(m.AddStakerBig(netID, ids.GenerateTestNodeID(), pk, ids.Empty, tokens(200)))
+	require.NoError(m.AddStakerBig(netID, ids.GenerateTestNodeID(), nil, ids.Empty, tokens(300)))
+
+	canonical := m.GetBigCanonicalValidatorSet(netID)
+	require.Len(canonical.Validators, 1)
+	require.Equal(tokens(300), canonical.Validators[0].Weight)
+	require.Len(canonical.Validators[0].NodeIDs, 2)
+	require.Equal(tokens(600), canonical.TotalWeight)
+	require.Equal(tokens(300), SumBigWeight(canonical.Validators))
+	require.True(BigQuorumReached(SumBigWeight(canonical.Validators), canonical.TotalWeight, 1, 2))
+	require.False(BigQuorumReached(SumBigWeight(canonical.Validators), canonical.TotalWeight, 2, 3))
+}
+
+// TestBigWeightConversions tests the conversion helpers
+func TestBigWeightConversions(t *testing.T) {
+	require := require.New(t)
+
+	require.Zero(SaturatingUint64(big.NewInt(-1)))
+	require.Equal(uint64(7), SaturatingUint64(big.NewInt(7)))
+	require.Equal(uint64(math.MaxUint64), SaturatingUint64(tokens(100)))
+
+	scaled, err := ScaleBigWeight(tokens(123), 18)
+	require.NoError(err)
+	require.Equal(uint64(123), scaled)
+
+	_, err = ScaleBigWeight(tokens(100), 0)
+	require.ErrorIs(err, ErrWeightOverflow)
+	_, err = ScaleBigWeight(big.NewInt(-1), 0)
+	require.ErrorIs(err, ErrNegativeWeight)
+}
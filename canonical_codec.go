@@ -0,0 +1,266 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math"
+)
+
+// CanonicalCodecVersion is the version of the binary encoding of canonical
+// sets and validators. The encoding of a version never changes; a new format
+// gets a new version.
+const CanonicalCodecVersion uint16 = 0
+
+// HybridCodecVersion is the version of the encoding of sets and validators
+// carrying Ringtail keys, as made by FlattenHybridValidatorSet. It is only
+// used when a Ringtail key is set, so BLS only sets keep encoding as
+// CanonicalCodecVersion.
+const HybridCodecVersion uint16 = 1
+
+var (
+	ErrInvalidCanonicalEncoding = errors.New("invalid canonical encoding")
+	ErrUnknownCodecVersion      = errors.New("unknown codec version")
+)
+
+// The encoding is big endian. A set is
+//
+//	version      uint16
+//	total weight uint64
+//	count        uint32
+//	validators   count times the body of a validator
+//
+// and a validator is its version followed by its body:
+//
+//	key length   uint32
+//	key          compressed BLS public key
+//	ringtail     key length uint32 and Ringtail public key, in HybridCodecVersion only
+//	weight       uint64
+//	node count   uint32
+//	node IDs     node count times 20 bytes
+//
+// Validators are in canonical order and node IDs in the order of the set, so
+// equal sets encode to equal bytes. In a HybridCodecVersion set every
+// validator has a Ringtail key.
+
+// Marshal returns the binary encoding of the set
+func (s *CanonicalValidatorSet) Marshal() ([]byte, error) {
+	version := CanonicalCodecVersion
+	if len(s.Validators) > 0 && len(s.Validators[0].RingtailPubKey) > 0 {
+		version = HybridCodecVersion
+	}
+	b := binary.BigEndian.AppendUint16(nil, version)
+	b = binary.BigEndian.AppendUint64(b, s.TotalWeight)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(s.Validators)))
+	for i, vdr := range s.Validators {
+		var err error
+		b, err = vdr.appendBody(b, version)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't marshal validator %d: %w", i, err)
+		}
+	}
+	return b, nil
+}
+
+// Unmarshal replaces the set with the one encoded in [b]. Keys are validated,
+// and the validators must be in canonical order with weights summing to at
+// most the total weight.
+func (s *CanonicalValidatorSet) Unmarshal(b []byte) error {
+	r := codecReader{b: b}
+	version, err := r.version()
+	if err != nil {
+		return err
+	}
+	totalWeight := r.uint64()
+	count := r.uint32()
+	if r.err != nil {
+		return r.err
+	}
+
+	var (
+		vdrs   []*CanonicalValidator
+		weight uint64
+	)
+	for i := uint32(0); i < count; i++ {
+		vdr := &CanonicalValidator{}
+		if err := vdr.readBody(&r, version); err != nil {
+			return fmt.Errorf("couldn't unmarshal validator %d: %w", i, err)
+		}
+		if len(vdrs) > 0 && vdrs[len(vdrs)-1].Compare(vdr) >= 0 {
+			return fmt.Errorf("%w: validator %d isn't in canonical order", ErrInvalidCanonicalEncoding, i)
+		}
+		weight, err = math.Add64(weight, vdr.Weight)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+		}
+		vdrs = append(vdrs, vdr)
+	}
+	if err := r.done(); err != nil {
+		return err
+	}
+	if weight > totalWeight {
+		return fmt.Errorf("%w: validators weigh %d, above the total %d", ErrInvalidCanonicalEncoding, weight, totalWeight)
+	}
+
+	s.Validators = vdrs
+	s.TotalWeight = totalWeight
+	return nil
+}
+
+// Marshal returns the binary encoding of the validator
+func (v *CanonicalValidator) Marshal() ([]byte, error) {
+	version := CanonicalCodecVersion
+	if len(v.RingtailPubKey) > 0 {
+		version = HybridCodecVersion
+	}
+	return v.appendBody(binary.BigEndian.AppendUint16(nil, version), version)
+}
+
+// Unmarshal replaces the validator with the one encoded in [b]
+func (v *CanonicalValidator) Unmarshal(b []byte) error {
+	r := codecReader{b: b}
+	version, err := r.version()
+	if err != nil {
+		return err
+	}
+	if err := v.readBody(&r, version); err != nil {
+		return err
+	}
+	return r.done()
+}
+
+// appendBody appends the body of the validator in [version] to [b]
+func (v *CanonicalValidator) appendBody(b []byte, version uint16) ([]byte, error) {
+	if v.PublicKey == nil {
+		return nil, fmt.Errorf("%w: validator has no public key", ErrInvalidCanonicalEncoding)
+	}
+	if hybrid := version == HybridCodecVersion; hybrid != (len(v.RingtailPubKey) > 0) {
+		return nil, fmt.Errorf("%w: Ringtail keys must be set on all validators or none", ErrInvalidCanonicalEncoding)
+	}
+	key := bls.PublicKeyToCompressedBytes(v.PublicKey)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(key)))
+	b = append(b, key...)
+	if version == HybridCodecVersion {
+		b = binary.BigEndian.AppendUint32(b, uint32(len(v.RingtailPubKey)))
+		b = append(b, v.RingtailPubKey...)
+	}
+	b = binary.BigEndian.AppendUint64(b, v.Weight)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(v.NodeIDs)))
+	for _, nodeID := range v.NodeIDs {
+		b = append(b, nodeID[:]...)
+	}
+	return b, nil
+}
+
+// readBody replaces the validator with the body in [version] read from [r]
+func (v *CanonicalValidator) readBody(r *codecReader, version uint16) error {
+	key := r.bytes(int(r.uint32()))
+	var ringtailPubKey []byte
+	if version == HybridCodecVersion {
+		ringtailPubKey = slices.Clone(r.bytes(int(r.uint32())))
+	}
+	weight := r.uint64()
+	count := r.uint32()
+	if r.err != nil {
+		return r.err
+	}
+	if version == HybridCodecVersion && len(ringtailPubKey) == 0 {
+		return fmt.Errorf("%w: missing Ringtail key", ErrInvalidCanonicalEncoding)
+	}
+
+	publicKey, err := bls.PublicKeyFromCompressedBytes(key)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPublicKey, err)
+	}
+	// Checked before allocating, so a corrupt count can't exhaust memory
+	if uint64(count)*uint64(nodeIDLen) > uint64(r.remaining()) {
+		return fmt.Errorf("%w: %d node IDs in %d bytes", ErrInvalidCanonicalEncoding, count, r.remaining())
+	}
+	nodeIDs := make([]ids.NodeID, count)
+	for i := range nodeIDs {
+		copy(nodeIDs[i][:], r.bytes(nodeIDLen))
+	}
+	if r.err == nil && !nodeIDsAscending(nodeIDs) {
+		return fmt.Errorf("%w: node IDs aren't in ascending order", ErrInvalidCanonicalEncoding)
+	}
+
+	v.PublicKey = publicKey
+	v.PublicKeyBytes = bls.PublicKeyToUncompressedBytes(publicKey)
+	v.RingtailPubKey = ringtailPubKey
+	v.Weight = weight
+	v.NodeIDs = nodeIDs
+	return nil
+}
+
+const nodeIDLen = len(ids.NodeID{})
+
+// codecReader reads big endian values, recording the first read past the end
+// of its bytes
+type codecReader struct {
+	b   []byte
+	err error
+}
+
+func (r *codecReader) remaining() int {
+	return len(r.b)
+}
+
+func (r *codecReader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.b) {
+		r.err = fmt.Errorf("%w: read of %d bytes with %d left", ErrInvalidCanonicalEncoding, n, len(r.b))
+		return nil
+	}
+	read := r.b[:n:n]
+	r.b = r.b[n:]
+	return read
+}
+
+func (r *codecReader) uint32() uint32 {
+	if b := r.bytes(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *codecReader) uint64() uint64 {
+	if b := r.bytes(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+// version reads the version, which must be CanonicalCodecVersion or
+// HybridCodecVersion
+func (r *codecReader) version() (uint16, error) {
+	b := r.bytes(2)
+	if r.err != nil {
+		return 0, r.err
+	}
+	switch version := binary.BigEndian.Uint16(b); version {
+	case CanonicalCodecVersion, HybridCodecVersion:
+		return version, nil
+	default:
+		return 0, fmt.Errorf("%w: %d", ErrUnknownCodecVersion, version)
+	}
+}
+
+// done returns an error if anything wasn't read or couldn't be
+func (r *codecReader) done() error {
+	switch {
+	case r.err != nil:
+		return r.err
+	case len(r.b) != 0:
+		return fmt.Errorf("%w: %d trailing bytes", ErrInvalidCanonicalEncoding, len(r.b))
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"encoding/hex"
+	"slices"
+	"testing"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/validators/validatorstest/fixture"
+)
+
+// TestCanonicalCodecRoundTrip tests that flattened sets survive a round trip
+// and encode deterministically
+func TestCanonicalCodecRoundTrip(t *testing.T) {
+	require := require.New(t)
+
+	generated, err := fixture.Generate(1, 5, fixture.ConstantWeights(1))
+	require.NoError(err)
+	vdrs := make(map[ids.NodeID]*GetValidatorOutput)
+	for i, vdr := range generated {
+		vdrs[vdr.NodeID] = &GetValidatorOutput{
+			NodeID:    vdr.NodeID,
+			PublicKey: vdr.PublicKeyBytes,
+			Weight:    uint64(i + 1),
+		}
+	}
+	// Validators without keys only count towards the total weight
+	nodeID := ids.GenerateTestNodeID()
+	vdrs[nodeID] = &GetValidatorOutput{NodeID: nodeID, Weight: 10}
+
+	vdrSet, err := FlattenValidatorSet(vdrs)
+	require.NoError(err)
+	b, err := vdrSet.Marshal()
+	require.NoError(err)
+
+	var decoded CanonicalValidatorSet
+	require.NoError(decoded.Unmarshal(b))
+	require.Equal(vdrSet.TotalWeight, decoded.TotalWeight)
+	require.Len(decoded.Validators, len(vdrSet.Validators))
+	for i, vdr := range vdrSet.Validators {
+		require.Equal(vdr.PublicKeyBytes, decoded.Validators[i].PublicKeyBytes)
+		require.Equal(vdr.Weight, decoded.Validators[i].Weight)
+		require.Equal(vdr.NodeIDs, decoded.Validators[i].NodeIDs)
+	}
+
+	again, err := decoded.Marshal()
+	require.NoError(err)
+	require.Equal(b, again)
+}
+
+// TestCanonicalCodecFormat tests that the encoding stays stable
+func TestCanonicalCodecFormat(t *testing.T) {
+	require := require.New(t)
+
+	const (
+		keyHex    = "94d162adca362895eb21fc491062b4d7db6c611e2e29508437335d98855a51b1885bcbba00ff5a79b1ceef5dead1c4db"
+		nodeIDHex = "ad1c4bfbf68383618d32d0320efef3260bdafcf0"
+	)
+	keyBytes, err := hex.DecodeString(keyHex)
+	require.NoError(err)
+	publicKey, err := bls.PublicKeyFromCompressedBytes(keyBytes)
+	require.NoError(err)
+	var nodeID ids.NodeID
+	nodeIDBytes, err := hex.DecodeString(nodeIDHex)
+	require.NoError(err)
+	copy(nodeID[:], nodeIDBytes)
+
+	vdr := &CanonicalValidator{
+		PublicKey:      publicKey,
+		PublicKeyBytes: bls.PublicKeyToUncompressedBytes(publicKey),
+		Weight:         100,
+		NodeIDs:        []ids.NodeID{nodeID},
+	}
+	body := "00000030" + keyHex + "0000000000000064" + "00000001" + nodeIDHex
+
+	b, err := vdr.Marshal()
+	require.NoError(err)
+	require.Equal("0000"+body, hex.EncodeToString(b))
+
+	vdrSet := CanonicalValidatorSet{Validators: []*CanonicalValidator{vdr}, TotalWeight: 150}
+	b, err = vdrSet.Marshal()
+	require.NoError(err)
+	require.Equal("0000"+"0000000000000096"+"00000001"+body, hex.EncodeToString(b))
+
+	var decoded CanonicalValidator
+	require.NoError(decoded.Unmarshal(append([]byte{0, 0}, mustDecodeHex(t, body)...)))
+	require.Equal(vdr.NodeIDs, decoded.NodeIDs)
+	require.Equal(vdr.PublicKeyBytes, decoded.PublicKeyBytes)
+
+	// A Ringtail key switches to the hybrid version
+	vdr.RingtailPubKey = []byte{0xab, 0xcd}
+	hybridBody := "00000030" + keyHex + "00000002" + "abcd" + "0000000000000064" + "00000001" + nodeIDHex
+	b, err = vdr.Marshal()
+	require.NoError(err)
+	require.Equal("0001"+hybridBody, hex.EncodeToString(b))
+
+	require.NoError(decoded.Unmarshal(b))
+	require.Equal(vdr.RingtailPubKey, decoded.RingtailPubKey)
+
+	// Sets can't mix BLS only and hybrid validators
+	vdrSet.Validators = append(vdrSet.Validators, &CanonicalValidator{PublicKey: publicKey})
+	_, err = vdrSet.Marshal()
+	require.ErrorIs(err, ErrInvalidCanonicalEncoding)
+}
+
+// TestCanonicalCodecInvalid tests that malformed encodings are rejected
+func TestCanonicalCodecInvalid(t *testing.T) {
+	generated, err := fixture.Generate(1, 3, fixture.ConstantWeights(1))
+	require.NoError(t, err)
+	newVdr := func() *CanonicalValidator {
+		vdr := generated[0]
+		generated = generated[1:]
+		return &CanonicalValidator{
+			PublicKey:      vdr.PublicKey,
+			PublicKeyBytes: bls.PublicKeyToUncompressedBytes(vdr.PublicKey),
+			Weight:         vdr.Weight,
+			NodeIDs:        []ids.NodeID{vdr.NodeID},
+		}
+	}
+	vdrs := []*CanonicalValidator{newVdr(), newVdr()}
+	if vdrs[0].Compare(vdrs[1]) < 0 {
+		vdrs[0], vdrs[1] = vdrs[1], vdrs[0]
+	}
+	unordered, err := (&CanonicalValidatorSet{Validators: vdrs, TotalWeight: 2}).Marshal()
+	require.NoError(t, err)
+	underweight, err := (&CanonicalValidatorSet{Validators: vdrs[:1], TotalWeight: 0}).Marshal()
+	require.NoError(t, err)
+	valid, err := (&CanonicalValidatorSet{Validators: vdrs[:1], TotalWeight: 1}).Marshal()
+	require.NoError(t, err)
+	unsortedVdr := newVdr()
+	unsortedVdr.NodeIDs = sortNodeIDs([]ids.NodeID{ids.GenerateTestNodeID(), ids.GenerateTestNodeID()})
+	slices.Reverse(unsortedVdr.NodeIDs)
+	unsortedNodeIDs, err := (&CanonicalValidatorSet{Validators: []*CanonicalValidator{unsortedVdr}, TotalWeight: 1}).Marshal()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		b           []byte
+		expectedErr error
+	}{
+		{
+			name:        "empty",
+			expectedErr: ErrInvalidCanonicalEncoding,
+		},
+		{
+			name:        "unknown version",
+			b:           append([]byte{0, 2}, valid[2:]...),
+			expectedErr: ErrUnknownCodecVersion,
+		},
+		{
+			name:        "truncated",
+			b:           valid[:len(valid)-1],
+			expectedErr: ErrInvalidCanonicalEncoding,
+		},
+		{
+			name:        "trailing bytes",
+			b:           append(valid, 0),
+			expectedErr: ErrInvalidCanonicalEncoding,
+		},
+		{
+			name:        "not canonical order",
+			b:           unordered,
+			expectedErr: ErrInvalidCanonicalEncoding,
+		},
+		{
+			name:        "weight above total",
+			b:           underweight,
+			expectedErr: ErrInvalidCanonicalEncoding,
+		},
+		{
+			name:        "node IDs not ascending",
+			b:           unsortedNodeIDs,
+			expectedErr: ErrInvalidCanonicalEncoding,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var vdrSet CanonicalValidatorSet
+			require.ErrorIs(t, vdrSet.Unmarshal(test.b), test.expectedErr)
+		})
+	}
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
@@ -0,0 +1,155 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/luxfi/ids"
+)
+
+var ErrInvalidCacheSize = errors.New("cache size must be positive")
+
+// CanonicalCache memoizes the canonical ordering of the sets of a State, so
+// verifying many messages at the same height parses and sorts the keys of
+// its set once. The least recently used sets are evicted beyond its size.
+//
+// Sets at an accepted height don't change, but a State backed by a live
+// manager may serve sets that still do. Registered as a callback listener and
+// a PublicKeyListener of that manager, the cache drops the sets of a net
+// whenever the net changes or one of its validators rotates its key.
+// Returned sets are shared and must not be modified.
+type CanonicalCache struct {
+	state State
+	size  int
+
+	mu      sync.Mutex
+	entries map[canonicalKey]*list.Element
+	// order holds the cached sets, most recently used first
+	order *list.List
+	// generations counts the invalidations of each net, so a set fetched
+	// while its net changed isn't cached
+	generations map[ids.ID]uint64
+}
+
+var (
+	_ ManagerCallbackListener = (*CanonicalCache)(nil)
+	_ PublicKeyListener       = (*CanonicalCache)(nil)
+)
+
+type canonicalKey struct {
+	netID  ids.ID
+	height uint64
+}
+
+type canonicalEntry struct {
+	key    canonicalKey
+	vdrSet CanonicalValidatorSet
+}
+
+// NewCanonicalCache returns a cache of up to [size] canonical sets of [state]
+func NewCanonicalCache(state State, size int) (*CanonicalCache, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidCacheSize, size)
+	}
+	return &CanonicalCache{
+		state:       state,
+		size:        size,
+		entries:     make(map[canonicalKey]*list.Element),
+		order:       list.New(),
+		generations: make(map[ids.ID]uint64),
+	}, nil
+}
+
+// GetCanonicalValidatorSet returns FlattenValidatorSet of the set of [netID]
+// at [height], flattening it only if it isn't cached
+func (c *CanonicalCache) GetCanonicalValidatorSet(ctx context.Context, height uint64, netID ids.ID) (CanonicalValidatorSet, error) {
+	key := canonicalKey{netID: netID, height: height}
+
+	c.mu.Lock()
+	if elem, ok := c.entries[key]; ok {
+		c.order.MoveToFront(elem)
+		c.mu.Unlock()
+		return elem.Value.(*canonicalEntry).vdrSet, nil
+	}
+	generation := c.generations[netID]
+	c.mu.Unlock()
+
+	// The lock isn't held while fetching, since the State may be backed by a
+	// manager that notifies the cache with its own lock held
+	vdrs, err := c.state.GetValidatorSet(ctx, height, netID)
+	if err != nil {
+		return CanonicalValidatorSet{}, err
+	}
+	vdrSet, err := FlattenValidatorSet(vdrs)
+	if err != nil {
+		return CanonicalValidatorSet{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generations[netID] == generation {
+		c.put(key, vdrSet)
+	}
+	return vdrSet, nil
+}
+
+// Invalidate drops the cached sets of [netID]
+func (c *CanonicalCache) Invalidate(netID ids.ID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generations[netID]++
+	for key, elem := range c.entries {
+		if key.netID == netID {
+			c.order.Remove(elem)
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Len returns the number of cached sets
+func (c *CanonicalCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *CanonicalCache) OnValidatorAdded(netID ids.ID, _ ids.NodeID, _ uint64) {
+	c.Invalidate(netID)
+}
+
+func (c *CanonicalCache) OnValidatorRemoved(netID ids.ID, _ ids.NodeID, _ uint64) {
+	c.Invalidate(netID)
+}
+
+func (c *CanonicalCache) OnValidatorLightChanged(netID ids.ID, _ ids.NodeID, _, _ uint64) {
+	c.Invalidate(netID)
+}
+
+func (c *CanonicalCache) OnValidatorPublicKeyChanged(netID ids.ID, _ ids.NodeID, _, _ []byte) {
+	c.Invalidate(netID)
+}
+
+// put caches [vdrSet] as the most recently used set, evicting the least
+// recently used one if the cache is full. It assumes the lock is held.
+func (c *CanonicalCache) put(key canonicalKey, vdrSet CanonicalValidatorSet) {
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*canonicalEntry).vdrSet = vdrSet
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&canonicalEntry{key: key, vdrSet: vdrSet})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*canonicalEntry).key)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+	"github.com/luxfi/validators/validatorstest"
+)
+
+// TestCanonicalCache tests that sets are flattened once per height, evicted
+// least recently used first, and dropped when their net changes
+func TestCanonicalCache(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	netID := ids.GenerateTestID()
+	generated, err := validatorstest.GenerateValidatorSet(1, 1, validatorstest.ConstantWeights(100))
+	require.NoError(err)
+	vdr := generated.Validators[0]
+
+	source := validatorstest.NewTestState().AddValidator(netID, &validators.GetValidatorOutput{
+		NodeID:    vdr.NodeID,
+		PublicKey: vdr.PublicKeyBytes,
+		Light:     vdr.Weight,
+		Weight:    vdr.Weight,
+	})
+	var fetches int
+	backing := validatorstest.NewTestState()
+	backing.GetValidatorSetF = func(ctx context.Context, height uint64, netID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+		fetches++
+		return source.GetValidatorSet(ctx, height, netID)
+	}
+
+	_, err = validators.NewCanonicalCache(backing, 0)
+	require.ErrorIs(err, validators.ErrInvalidCacheSize)
+
+	cache, err := validators.NewCanonicalCache(backing, 2)
+	require.NoError(err)
+
+	vdrSet, err := cache.GetCanonicalValidatorSet(ctx, 1, netID)
+	require.NoError(err)
+	require.Equal(uint64(100), vdrSet.TotalWeight)
+	require.Len(vdrSet.Validators, 1)
+	_, err = cache.GetCanonicalValidatorSet(ctx, 1, netID)
+	require.NoError(err)
+	require.Equal(1, fetches)
+
+	// Height 1 was used more recently than height 2, so height 3 evicts 2
+	_, err = cache.GetCanonicalValidatorSet(ctx, 2, netID)
+	require.NoError(err)
+	_, err = cache.GetCanonicalValidatorSet(ctx, 1, netID)
+	require.NoError(err)
+	_, err = cache.GetCanonicalValidatorSet(ctx, 3, netID)
+	require.NoError(err)
+	require.Equal(3, fetches)
+	require.Equal(2, cache.Len())
+	_, err = cache.GetCanonicalValidatorSet(ctx, 1, netID)
+	require.NoError(err)
+	require.Equal(3, fetches)
+	_, err = cache.GetCanonicalValidatorSet(ctx, 2, netID)
+	require.NoError(err)
+	require.Equal(4, fetches)
+
+	// Changes to the net drop its sets
+	m := validators.NewManager()
+	m.RegisterCallbackListener(cache)
+	require.NoError(m.AddStaker(ids.GenerateTestID(), ids.GenerateTestNodeID(), nil, ids.Empty, 1))
+	require.Equal(2, cache.Len())
+	require.NoError(m.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 1))
+	require.Zero(cache.Len())
+}
+
+// TestCanonicalCacheKeyRotation tests that rotating a key drops the sets of
+// its net
+func TestCanonicalCacheKeyRotation(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	generated, err := validatorstest.GenerateValidatorSet(1, 2, validatorstest.ConstantWeights(100))
+	require.NoError(err)
+	oldKey, newKey := generated.Validators[0].PublicKeyBytes, generated.Validators[1].PublicKeyBytes
+
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, oldKey, ids.Empty, 100))
+
+	backing := validatorstest.NewTestState()
+	backing.GetValidatorSetF = func(_ context.Context, _ uint64, netID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+		return m.GetMap(netID), nil
+	}
+	cache, err := validators.NewCanonicalCache(backing, 2)
+	require.NoError(err)
+	m.RegisterCallbackListener(cache)
+	m.RegisterPublicKeyListener(cache)
+
+	vdrSet, err := cache.GetCanonicalValidatorSet(ctx, 1, netID)
+	require.NoError(err)
+	require.Equal(oldKey, bls.PublicKeyToCompressedBytes(vdrSet.Validators[0].PublicKey))
+
+	require.NoError(m.UpdatePublicKey(netID, nodeID, newKey))
+	require.Zero(cache.Len())
+	vdrSet, err = cache.GetCanonicalValidatorSet(ctx, 1, netID)
+	require.NoError(err)
+	require.Equal(newKey, bls.PublicKeyToCompressedBytes(vdrSet.Validators[0].PublicKey))
+}
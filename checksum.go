@@ -0,0 +1,88 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"slices"
+
+	"github.com/luxfi/ids"
+)
+
+// checksumVersion prefixes the checksum preimage so the encoding can change
+// without colliding with older checksums
+const checksumVersion = 1
+
+// ChecksumState is a State that can report a checksum of its validator sets,
+// so nodes can cheaply check they derived identical sets at a height
+type ChecksumState interface {
+	State
+
+	// GetValidatorSetChecksum returns the checksum of the validator set of
+	// [netID] at [height]
+	GetValidatorSetChecksum(ctx context.Context, height uint64, netID ids.ID) (ids.ID, error)
+}
+
+// GetValidatorSetChecksum returns the checksum of the validator set of
+// [netID] at [height]. States that implement ChecksumState are asked
+// directly; for all others it is computed from GetValidatorSet.
+func GetValidatorSetChecksum(ctx context.Context, state State, height uint64, netID ids.ID) (ids.ID, error) {
+	if checksumState, ok := state.(ChecksumState); ok {
+		return checksumState.GetValidatorSetChecksum(ctx, height, netID)
+	}
+	vdrSet, err := state.GetValidatorSet(ctx, height, netID)
+	if err != nil {
+		return ids.Empty, err
+	}
+	return ValidatorSetChecksum(vdrSet)
+}
+
+// ValidatorSetChecksum returns the Checksum of the canonical form of [vdrSet].
+// Validators without a valid public key only contribute to the total weight,
+// as in FlattenValidatorSet.
+func ValidatorSetChecksum(vdrSet map[ids.NodeID]*GetValidatorOutput) (ids.ID, error) {
+	canonical, err := FlattenValidatorSet(vdrSet)
+	if err != nil {
+		return ids.Empty, err
+	}
+	return canonical.Checksum(), nil
+}
+
+// Checksum returns the SHA-256 of the canonical encoding of the set: the
+// version, the total weight, and then every validator in order with its
+// public key, weight, and sorted node IDs.
+func (s *CanonicalValidatorSet) Checksum() ids.ID {
+	var (
+		hasher = sha256.New()
+		buf    [8]byte
+	)
+	writeUint64 := func(v uint64) {
+		binary.BigEndian.PutUint64(buf[:], v)
+		_, _ = hasher.Write(buf[:])
+	}
+	writeUint64(checksumVersion)
+	writeUint64(s.TotalWeight)
+	writeUint64(uint64(len(s.Validators)))
+	for _, vdr := range s.Validators {
+		writeUint64(uint64(len(vdr.PublicKeyBytes)))
+		_, _ = hasher.Write(vdr.PublicKeyBytes)
+		writeUint64(vdr.Weight)
+
+		nodeIDs := slices.Clone(vdr.NodeIDs)
+		slices.SortFunc(nodeIDs, func(a, b ids.NodeID) int {
+			return bytes.Compare(a[:], b[:])
+		})
+		writeUint64(uint64(len(nodeIDs)))
+		for _, nodeID := range nodeIDs {
+			_, _ = hasher.Write(nodeID[:])
+		}
+	}
+
+	var checksum ids.ID
+	copy(checksum[:], hasher.Sum(nil))
+	return checksum
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/validators/validatorstest/fixture"
+)
+
+type checksumState struct {
+	State
+	checksum ids.ID
+}
+
+func (s *checksumState) GetValidatorSetChecksum(context.Context, uint64, ids.ID) (ids.ID, error) {
+	return s.checksum, nil
+}
+
+type mapState struct {
+	State
+	vdrs map[ids.NodeID]*GetValidatorOutput
+}
+
+func (s *mapState) GetValidatorSet(context.Context, uint64, ids.ID) (map[ids.NodeID]*GetValidatorOutput, error) {
+	return s.vdrs, nil
+}
+
+func newChecksumTestSet(t *testing.T, n int) map[ids.NodeID]*GetValidatorOutput {
+	generated, err := fixture.Generate(1, n, fixture.ConstantWeights(1))
+	require.NoError(t, err)
+	vdrs := make(map[ids.NodeID]*GetValidatorOutput, n)
+	for i, vdr := range generated {
+		vdrs[vdr.NodeID] = &GetValidatorOutput{
+			NodeID:    vdr.NodeID,
+			PublicKey: vdr.PublicKeyBytes,
+			Light:     uint64(i + 1),
+			Weight:    uint64(i + 1),
+		}
+	}
+	return vdrs
+}
+
+// TestValidatorSetChecksum tests that the checksum tracks set contents
+func TestValidatorSetChecksum(t *testing.T) {
+	require := require.New(t)
+
+	vdrs := newChecksumTestSet(t, 5)
+	checksum, err := ValidatorSetChecksum(vdrs)
+	require.NoError(err)
+
+	// Copies hash identically regardless of map iteration order
+	for i := 0; i < 10; i++ {
+		again, err := ValidatorSetChecksum(copyValidators(vdrs))
+		require.NoError(err)
+		require.Equal(checksum, again)
+	}
+
+	for _, vdr := range vdrs {
+		vdr.Weight++
+		break
+	}
+	changed, err := ValidatorSetChecksum(vdrs)
+	require.NoError(err)
+	require.NotEqual(checksum, changed)
+
+	empty, err := ValidatorSetChecksum(nil)
+	require.NoError(err)
+	require.NotEqual(ids.Empty, empty)
+}
+
+// TestGetValidatorSetChecksum tests the State extension and its default
+func TestGetValidatorSetChecksum(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	vdrs := newChecksumTestSet(t, 3)
+	expected, err := ValidatorSetChecksum(vdrs)
+	require.NoError(err)
+
+	checksum, err := GetValidatorSetChecksum(ctx, &mapState{vdrs: vdrs}, 1, ids.GenerateTestID())
+	require.NoError(err)
+	require.Equal(expected, checksum)
+
+	reported := ids.GenerateTestID()
+	checksum, err = GetValidatorSetChecksum(ctx, &checksumState{checksum: reported}, 1, ids.GenerateTestID())
+	require.NoError(err)
+	require.Equal(reported, checksum)
+}
@@ -0,0 +1,335 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package churn limits how much of a net's weight can change per window of
+// heights, so a set that signed a warp message stays a quorum of the current
+// set for the message's validity window
+package churn
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"sync"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math"
+
+	validators "github.com/luxfi/validators"
+)
+
+var (
+	ErrInvalidConfig       = errors.New("invalid churn config")
+	ErrChurnLimitExceeded  = errors.New("change exceeds churn limit")
+	ErrChangeQueued        = errors.New("change queued for a later window")
+	ErrNonIncreasingPeriod = errors.New("height is in an earlier window")
+)
+
+// Mode selects what happens to a change that exceeds the churn limit
+type Mode uint8
+
+const (
+	// Reject fails the change with ErrChurnLimitExceeded
+	Reject Mode = iota
+	// Queue defers the change, and every later change of the net, until a
+	// window has room for it. The change returns ErrChangeQueued.
+	Queue
+)
+
+// Config is the churn budget of a Limiter
+type Config struct {
+	// Period is the number of heights, such as an epoch, a window lasts.
+	// Window w covers heights [w*Period, (w+1)*Period).
+	Period uint64
+	// MaxChurnNumerator / MaxChurnDenominator is the share of a net's light,
+	// as of the start of the window, that may be added or removed per window
+	MaxChurnNumerator   uint64
+	MaxChurnDenominator uint64
+	Mode                Mode
+}
+
+// Verify returns an error if the config can't apply any change
+func (c Config) Verify() error {
+	switch {
+	case c.Period == 0:
+		return fmt.Errorf("%w: zero period", ErrInvalidConfig)
+	case c.MaxChurnDenominator == 0:
+		return fmt.Errorf("%w: zero denominator", ErrInvalidConfig)
+	case c.MaxChurnNumerator == 0:
+		return fmt.Errorf("%w: zero churn share", ErrInvalidConfig)
+	case c.MaxChurnNumerator > c.MaxChurnDenominator:
+		return fmt.Errorf("%w: churn share %d/%d exceeds 1", ErrInvalidConfig, c.MaxChurnNumerator, c.MaxChurnDenominator)
+	case c.Mode > Queue:
+		return fmt.Errorf("%w: unknown mode %d", ErrInvalidConfig, c.Mode)
+	}
+	return nil
+}
+
+// Manager is a validator manager whose mutations a Limiter can limit
+type Manager interface {
+	validators.Manager
+	validators.StakerManager
+	validators.DiffManager
+}
+
+// Limiter is a Manager that limits the churn of each net. The churn of a
+// change is the light it adds or removes: the light of an added or removed
+// validator, or the difference a weight change makes. The first change of a
+// window is always allowed, even if its churn alone exceeds the budget, so a
+// large validator can't be blocked forever.
+//
+// Only the mutations of Manager are limited. Changes made to the wrapped
+// manager directly aren't counted.
+type Limiter struct {
+	Manager
+	config Config
+
+	mu     sync.Mutex
+	period uint64
+	nets   map[ids.ID]*netWindow
+}
+
+var _ Manager = (*Limiter)(nil)
+
+// netWindow is the spent budget and queued changes of a net
+type netWindow struct {
+	period  uint64
+	churned uint64
+	budget  uint64
+	queue   []change
+}
+
+// change is a queued mutation. Its churn is measured when it is applied,
+// since the net may have changed since it was queued.
+type change struct {
+	churn func() (uint64, error)
+	apply func() error
+}
+
+// New returns a limiter of the changes made to [manager]. Every net starts in
+// window 0.
+func New(manager Manager, config Config) (*Limiter, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	return &Limiter{
+		Manager: manager,
+		config:  config,
+		nets:    make(map[ids.ID]*netWindow),
+	}, nil
+}
+
+func (l *Limiter) AddStaker(netID ids.ID, nodeID ids.NodeID, publicKey []byte, txID ids.ID, light uint64) error {
+	return l.limit(netID, change{
+		churn: constant(light),
+		apply: func() error {
+			return l.Manager.AddStaker(netID, nodeID, publicKey, txID, light)
+		},
+	})
+}
+
+func (l *Limiter) AddWeight(netID ids.ID, nodeID ids.NodeID, light uint64) error {
+	return l.limit(netID, change{
+		churn: constant(light),
+		apply: func() error {
+			return l.Manager.AddWeight(netID, nodeID, light)
+		},
+	})
+}
+
+func (l *Limiter) RemoveWeight(netID ids.ID, nodeID ids.NodeID, light uint64) error {
+	return l.limit(netID, change{
+		churn: constant(light),
+		apply: func() error {
+			return l.Manager.RemoveWeight(netID, nodeID, light)
+		},
+	})
+}
+
+func (l *Limiter) SetWeight(netID ids.ID, nodeID ids.NodeID, light uint64) error {
+	return l.limit(netID, change{
+		churn: func() (uint64, error) {
+			current := l.Manager.GetLight(netID, nodeID)
+			return max(current, light) - min(current, light), nil
+		},
+		apply: func() error {
+			return l.Manager.SetWeight(netID, nodeID, light)
+		},
+	})
+}
+
+func (l *Limiter) RemoveStaker(netID ids.ID, nodeID ids.NodeID) error {
+	return l.limit(netID, change{
+		churn: func() (uint64, error) {
+			return l.Manager.GetLight(netID, nodeID), nil
+		},
+		apply: func() error {
+			return l.Manager.RemoveStaker(netID, nodeID)
+		},
+	})
+}
+
+// ApplyDiff applies [diff] as a single change, whose churn is the sum of the
+// churn of its parts
+func (l *Limiter) ApplyDiff(netID ids.ID, diff validators.ValidatorDiff) error {
+	return l.limit(netID, change{
+		churn: func() (uint64, error) {
+			return l.diffChurn(netID, diff)
+		},
+		apply: func() error {
+			return l.Manager.ApplyDiff(netID, diff)
+		},
+	})
+}
+
+// diffChurn returns the churn of [diff]
+func (l *Limiter) diffChurn(netID ids.ID, diff validators.ValidatorDiff) (uint64, error) {
+	var (
+		churn uint64
+		err   error
+	)
+	for _, nodeID := range diff.Removed {
+		churn, err = math.Add64(churn, l.Manager.GetLight(netID, nodeID))
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", validators.ErrWeightOverflow, err)
+		}
+	}
+	for _, added := range diff.Added {
+		churn, err = math.Add64(churn, added.Light)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", validators.ErrWeightOverflow, err)
+		}
+	}
+	for _, weightChange := range diff.WeightChanges {
+		churn, err = math.Add64(churn, weightChange.Light)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", validators.ErrWeightOverflow, err)
+		}
+	}
+	return churn, nil
+}
+
+// Process starts the window containing [height], if it is a later one, and
+// applies the queued changes its budget allows, in order. Queued changes that
+// fail are dropped and their errors returned. Heights may repeat or skip, but
+// not move to an earlier window.
+func (l *Limiter) Process(height uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	period := height / l.config.Period
+	if period < l.period {
+		return fmt.Errorf("%w: height %d is in window %d, after %d", ErrNonIncreasingPeriod, height, period, l.period)
+	}
+	l.period = period
+
+	var errs []error
+	for netID, net := range l.nets {
+		if err := l.start(netID, net); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for len(net.queue) > 0 {
+			applied, err := l.apply(net, net.queue[0])
+			if err == nil && !applied {
+				break
+			}
+			net.queue = net.queue[1:]
+			if err != nil {
+				errs = append(errs, fmt.Errorf("couldn't apply queued change to %s: %w", netID, err))
+			}
+		}
+		if len(net.queue) == 0 && net.churned == 0 {
+			delete(l.nets, netID)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Pending returns the number of changes queued for [netID]
+func (l *Limiter) Pending(netID ids.ID) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if net, ok := l.nets[netID]; ok {
+		return len(net.queue)
+	}
+	return 0
+}
+
+// limit applies [c] to [netID] if the budget of the current window allows it,
+// and otherwise rejects or queues it
+func (l *Limiter) limit(netID ids.ID, c change) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	net, ok := l.nets[netID]
+	if !ok {
+		net = &netWindow{}
+		l.nets[netID] = net
+	}
+	if err := l.start(netID, net); err != nil {
+		return err
+	}
+
+	if len(net.queue) == 0 {
+		applied, err := l.apply(net, c)
+		if err != nil || applied {
+			return err
+		}
+	}
+	if l.config.Mode == Reject {
+		return ErrChurnLimitExceeded
+	}
+	net.queue = append(net.queue, c)
+	return ErrChangeQueued
+}
+
+// start resets the budget of [net] if it is from an earlier window. It
+// assumes the lock is held.
+func (l *Limiter) start(netID ids.ID, net *netWindow) error {
+	if net.budget != 0 && net.period == l.period {
+		return nil
+	}
+	total, err := l.Manager.TotalLight(netID)
+	if err != nil {
+		return fmt.Errorf("couldn't get light of %s: %w", netID, err)
+	}
+	hi, lo := bits.Mul64(total, l.config.MaxChurnNumerator)
+	budget, _ := bits.Div64(hi, lo, l.config.MaxChurnDenominator)
+
+	net.period = l.period
+	net.churned = 0
+	// A zero budget would be indistinguishable from an unstarted window, and
+	// the first change of a window is allowed anyway
+	net.budget = max(budget, 1)
+	return nil
+}
+
+// apply applies [c] and returns true if the budget of [net] allows it. It
+// assumes the lock is held.
+func (l *Limiter) apply(net *netWindow, c change) (bool, error) {
+	churn, err := c.churn()
+	if err != nil {
+		return false, err
+	}
+	if net.churned != 0 && churn > net.budget-min(net.churned, net.budget) {
+		return false, nil
+	}
+	if err := c.apply(); err != nil {
+		return false, err
+	}
+	net.churned, err = math.Add64(net.churned, churn)
+	if err != nil {
+		net.churned = ^uint64(0)
+	}
+	return true, nil
+}
+
+// constant returns a churn function of a change whose churn doesn't depend on
+// the net
+func constant(churn uint64) func() (uint64, error) {
+	return func() (uint64, error) {
+		return churn, nil
+	}
+}
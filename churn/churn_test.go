@@ -0,0 +1,132 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package churn
+
+import (
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+// TestConfigVerify tests config validation
+func TestConfigVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      Config
+		expectedErr error
+	}{
+		{
+			name:   "valid",
+			config: Config{Period: 10, MaxChurnNumerator: 1, MaxChurnDenominator: 3, Mode: Queue},
+		},
+		{
+			name:        "zero period",
+			config:      Config{MaxChurnNumerator: 1, MaxChurnDenominator: 3},
+			expectedErr: ErrInvalidConfig,
+		},
+		{
+			name:        "zero denominator",
+			config:      Config{Period: 10, MaxChurnNumerator: 1},
+			expectedErr: ErrInvalidConfig,
+		},
+		{
+			name:        "zero churn share",
+			config:      Config{Period: 10, MaxChurnDenominator: 3},
+			expectedErr: ErrInvalidConfig,
+		},
+		{
+			name:        "churn share above 1",
+			config:      Config{Period: 10, MaxChurnNumerator: 4, MaxChurnDenominator: 3},
+			expectedErr: ErrInvalidConfig,
+		},
+		{
+			name:        "unknown mode",
+			config:      Config{Period: 10, MaxChurnNumerator: 1, MaxChurnDenominator: 3, Mode: Queue + 1},
+			expectedErr: ErrInvalidConfig,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.config.Verify(), test.expectedErr)
+		})
+	}
+}
+
+// newNet returns a manager with [count] validators of 100 light in a net
+func newNet(t *testing.T, count int) (Manager, ids.ID, []ids.NodeID) {
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	nodeIDs := make([]ids.NodeID, count)
+	for i := range nodeIDs {
+		nodeIDs[i] = ids.GenerateTestNodeID()
+		require.NoError(t, m.AddStaker(netID, nodeIDs[i], nil, ids.Empty, 100))
+	}
+	return m, netID, nodeIDs
+}
+
+// TestLimiterReject tests that changes beyond the budget are rejected until
+// the next window
+func TestLimiterReject(t *testing.T) {
+	require := require.New(t)
+
+	m, netID, nodeIDs := newNet(t, 10)
+	l, err := New(m, Config{Period: 10, MaxChurnNumerator: 1, MaxChurnDenominator: 4})
+	require.NoError(err)
+
+	// 25% of 1000 light may churn per window
+	require.NoError(l.RemoveStaker(netID, nodeIDs[0]))
+	require.NoError(l.SetWeight(netID, nodeIDs[1], 200))
+	require.ErrorIs(l.RemoveStaker(netID, nodeIDs[2]), ErrChurnLimitExceeded)
+	require.NoError(l.RemoveWeight(netID, nodeIDs[2], 50))
+	require.ErrorIs(l.AddWeight(netID, nodeIDs[2], 1), ErrChurnLimitExceeded)
+	require.Equal(uint64(50), m.GetLight(netID, nodeIDs[2]))
+
+	require.NoError(l.Process(9))
+	require.ErrorIs(l.AddWeight(netID, nodeIDs[2], 1), ErrChurnLimitExceeded)
+
+	// The first change of a window is allowed even beyond the budget
+	require.NoError(l.Process(10))
+	diff := validators.ValidatorDiff{
+		Added: []validators.ValidatorAddition{{NodeID: ids.GenerateTestNodeID(), Light: 500}},
+	}
+	require.NoError(l.ApplyDiff(netID, diff))
+	require.ErrorIs(l.AddWeight(netID, nodeIDs[2], 1), ErrChurnLimitExceeded)
+
+	// Failed changes don't spend the budget
+	require.NoError(l.Process(20))
+	require.ErrorIs(l.AddStaker(netID, nodeIDs[2], nil, ids.Empty, 1000), validators.ErrDuplicateValidator)
+	require.NoError(l.AddWeight(netID, nodeIDs[2], 1))
+
+	require.ErrorIs(l.Process(19), ErrNonIncreasingPeriod)
+}
+
+// TestLimiterQueue tests that changes beyond the budget are applied in order
+// in later windows
+func TestLimiterQueue(t *testing.T) {
+	require := require.New(t)
+
+	m, netID, nodeIDs := newNet(t, 10)
+	l, err := New(m, Config{Period: 10, MaxChurnNumerator: 1, MaxChurnDenominator: 4, Mode: Queue})
+	require.NoError(err)
+
+	require.NoError(l.RemoveStaker(netID, nodeIDs[0]))
+	require.NoError(l.RemoveStaker(netID, nodeIDs[1]))
+	require.ErrorIs(l.RemoveStaker(netID, nodeIDs[2]), ErrChangeQueued)
+	// Later changes queue behind earlier ones, even if they would fit
+	require.ErrorIs(l.RemoveWeight(netID, nodeIDs[3], 10), ErrChangeQueued)
+	require.ErrorIs(l.AddStaker(netID, nodeIDs[3], nil, ids.Empty, 10), ErrChangeQueued)
+	require.Equal(3, l.Pending(netID))
+	require.Equal(8, m.Count(netID))
+
+	// The next window applies what its budget of 200 light allows. The
+	// second addition of nodeIDs[3] fails and is dropped.
+	err = l.Process(10)
+	require.ErrorIs(err, validators.ErrDuplicateValidator)
+	require.Zero(l.Pending(netID))
+	require.Equal(7, m.Count(netID))
+	require.Equal(uint64(90), m.GetLight(netID, nodeIDs[3]))
+}
@@ -0,0 +1,174 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/luxfi/ids"
+)
+
+var (
+	ErrManagerClosed    = errors.New("manager is closed")
+	ErrInvalidQueueSize = errors.New("queue size must be positive")
+)
+
+// Closer is a component holding state that is lost unless it is closed
+// before the node shuts down
+type Closer interface {
+	// Close flushes the state of the component, waiting at most until [ctx]
+	// is done. Closing twice returns nil.
+	Close(ctx context.Context) error
+}
+
+var _ Closer = (*manager)(nil)
+
+// Close shuts the manager down, in order:
+//
+//  1. Every mutation a freeze would reject, see FreezeManager, fails with
+//     ErrManagerClosed from then on. Reads continue.
+//  2. Registered callback listeners that implement Closer, such as
+//     AsyncListener, are closed in registration order, so the events of
+//     every change made before Close are delivered before it returns.
+//
+// The manager lock isn't held while listeners drain, so their callbacks may
+// read from the manager.
+func (m *manager) Close(ctx context.Context) error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	m.mu.Unlock()
+
+	var errs []error
+	m.notify(func(listener ManagerCallbackListener) {
+		if closer, ok := listener.(Closer); ok {
+			if err := closer.Close(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// requireOpen returns an error if the manager is closed. It assumes the lock
+// is held.
+func (m *manager) requireOpen() error {
+	if m.closed {
+		return ErrManagerClosed
+	}
+	return nil
+}
+
+// AsyncListener delivers the events of a manager to a listener on its own
+// goroutine, so slow listeners don't hold up mutations. Events are delivered
+// in order. Events are queued while the manager lock is held, so once the
+// queue is full they are dropped and counted by Dropped rather than blocking
+// the mutation, which would deadlock a listener reading from the manager.
+//
+// Registered with a manager, it is drained by the manager's Close.
+type AsyncListener struct {
+	listener ManagerCallbackListener
+
+	// mu is held for reading while events are queued, so Close can't close
+	// the queue under them
+	mu     sync.RWMutex
+	closed bool
+	queue  chan func(ManagerCallbackListener)
+	done   chan struct{}
+
+	dropped atomic.Uint64
+}
+
+var (
+	_ ManagerCallbackListener = (*AsyncListener)(nil)
+	_ Closer                  = (*AsyncListener)(nil)
+)
+
+// NewAsyncListener returns an AsyncListener delivering to [listener] that
+// holds up to [queueSize] undelivered events
+func NewAsyncListener(listener ManagerCallbackListener, queueSize int) (*AsyncListener, error) {
+	if queueSize <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidQueueSize, queueSize)
+	}
+	l := &AsyncListener{
+		listener: listener,
+		queue:    make(chan func(ManagerCallbackListener), queueSize),
+		done:     make(chan struct{}),
+	}
+	go l.dispatch()
+	return l, nil
+}
+
+func (l *AsyncListener) dispatch() {
+	defer close(l.done)
+
+	for f := range l.queue {
+		f(l.listener)
+	}
+}
+
+// enqueue queues [f] for delivery without blocking. Events after Close are
+// dropped silently, events that don't fit in the queue are counted.
+func (l *AsyncListener) enqueue(f func(ManagerCallbackListener)) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.closed {
+		return
+	}
+	select {
+	case l.queue <- f:
+	default:
+		l.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of events dropped because the queue was full
+func (l *AsyncListener) Dropped() uint64 {
+	return l.dropped.Load()
+}
+
+func (l *AsyncListener) OnValidatorAdded(netID ids.ID, nodeID ids.NodeID, light uint64) {
+	l.enqueue(func(listener ManagerCallbackListener) {
+		listener.OnValidatorAdded(netID, nodeID, light)
+	})
+}
+
+func (l *AsyncListener) OnValidatorRemoved(netID ids.ID, nodeID ids.NodeID, light uint64) {
+	l.enqueue(func(listener ManagerCallbackListener) {
+		listener.OnValidatorRemoved(netID, nodeID, light)
+	})
+}
+
+func (l *AsyncListener) OnValidatorLightChanged(netID ids.ID, nodeID ids.NodeID, oldLight, newLight uint64) {
+	l.enqueue(func(listener ManagerCallbackListener) {
+		listener.OnValidatorLightChanged(netID, nodeID, oldLight, newLight)
+	})
+}
+
+// Close stops accepting events and waits until the queued ones are
+// delivered. If [ctx] is done first, delivery continues in the background
+// and the context's error is returned.
+func (l *AsyncListener) Close(ctx context.Context) error {
+	l.mu.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.queue)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("couldn't deliver %d events: %w", len(l.queue), ctx.Err())
+	}
+}
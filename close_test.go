@@ -0,0 +1,121 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// TestManagerClose tests that closing drains async listeners and rejects
+// later mutations
+func TestManagerClose(t *testing.T) {
+	require := require.New(t)
+
+	_, err := NewAsyncListener(&testListener{}, 0)
+	require.ErrorIs(err, ErrInvalidQueueSize)
+
+	var (
+		m        = NewManager()
+		netID    = ids.GenerateTestID()
+		nodeID   = ids.GenerateTestNodeID()
+		listener = &testListener{}
+	)
+	async, err := NewAsyncListener(listener, 3)
+	require.NoError(err)
+	m.RegisterCallbackListener(async)
+
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 10))
+	require.NoError(m.AddWeight(netID, nodeID, 5))
+	require.NoError(m.RemoveStaker(netID, nodeID))
+
+	require.NoError(m.Close(context.Background()))
+	require.Equal([]validatorEvent{{netID, nodeID, 10}}, listener.added)
+	require.Equal([]lightChangedEvent{{netID, nodeID, 10, 15}}, listener.changed)
+	require.Equal([]validatorEvent{{netID, nodeID, 15}}, listener.removed)
+
+	require.ErrorIs(m.AddStaker(netID, nodeID, nil, ids.Empty, 10), ErrManagerClosed)
+	require.Zero(m.GetLight(netID, nodeID))
+	require.NoError(m.Close(context.Background()))
+}
+
+// TestAsyncListenerFullQueue tests that events are dropped rather than
+// blocking mutations once the queue is full, even if the listener is waiting
+// to read from the manager
+func TestAsyncListenerFullQueue(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		m        = NewManager()
+		netID    = ids.GenerateTestID()
+		nodeID   = ids.GenerateTestNodeID()
+		listener = &readingListener{
+			m:       m,
+			started: make(chan struct{}, 3),
+			release: make(chan struct{}),
+		}
+	)
+	async, err := NewAsyncListener(listener, 1)
+	require.NoError(err)
+	m.RegisterCallbackListener(async)
+
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 10))
+	<-listener.started // The first event is out of the queue
+	require.NoError(m.AddWeight(netID, nodeID, 5))
+	require.NoError(m.AddWeight(netID, nodeID, 5))
+	require.Equal(uint64(1), async.Dropped())
+
+	close(listener.release)
+	require.NoError(m.Close(context.Background()))
+	require.Equal([]uint64{20, 20}, listener.lights)
+}
+
+// readingListener reads the light of the validator of every event from a
+// manager once [release] is closed
+type readingListener struct {
+	m       *manager
+	started chan struct{}
+	release chan struct{}
+	lights  []uint64
+}
+
+func (l *readingListener) read(netID ids.ID, nodeID ids.NodeID) {
+	l.started <- struct{}{}
+	<-l.release
+	l.lights = append(l.lights, l.m.GetLight(netID, nodeID))
+}
+
+func (l *readingListener) OnValidatorAdded(netID ids.ID, nodeID ids.NodeID, _ uint64) {
+	l.read(netID, nodeID)
+}
+
+func (l *readingListener) OnValidatorRemoved(netID ids.ID, nodeID ids.NodeID, _ uint64) {
+	l.read(netID, nodeID)
+}
+
+func (l *readingListener) OnValidatorLightChanged(netID ids.ID, nodeID ids.NodeID, _, _ uint64) {
+	l.read(netID, nodeID)
+}
+
+// TestPersistentManagerClose tests that closing flushes buffered changes
+func TestPersistentManagerClose(t *testing.T) {
+	require := require.New(t)
+
+	store := newTestStore()
+	m, err := NewPersistentManager(store, DefaultPersistenceConfig())
+	require.NoError(err)
+
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 10))
+	require.Empty(store.batches)
+
+	require.NoError(m.Close(context.Background()))
+	require.Len(store.batches, 1)
+	require.Equal(uint64(10), store.nets[netID][nodeID].Light)
+	require.ErrorIs(m.AddWeight(netID, nodeID, 1), ErrManagerClosed)
+}
@@ -0,0 +1,122 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/luxfi/ids"
+)
+
+// commitmentVersion prefixes the commitment preimage so the encoding can
+// change without colliding with older commitments
+const commitmentVersion = 1
+
+var ErrCommitmentMismatch = errors.New("warp set commitment mismatch")
+
+// ComputeCommitment returns the SHA-256 of the canonical encoding of the set:
+// the version, the height, and then every validator in node ID order with its
+// BLS key, Ringtail key and weight. The Commitment field isn't part of the
+// encoding.
+func (s *WarpSet) ComputeCommitment() ids.ID {
+	var (
+		hasher = sha256.New()
+		buf    [8]byte
+	)
+	writeUint64 := func(v uint64) {
+		binary.BigEndian.PutUint64(buf[:], v)
+		_, _ = hasher.Write(buf[:])
+	}
+	writeBytes := func(b []byte) {
+		writeUint64(uint64(len(b)))
+		_, _ = hasher.Write(b)
+	}
+
+	nodeIDs := slices.SortedFunc(maps.Keys(s.Validators), func(a, b ids.NodeID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	writeUint64(commitmentVersion)
+	writeUint64(s.Height)
+	writeUint64(uint64(len(nodeIDs)))
+	for _, nodeID := range nodeIDs {
+		vdr := s.Validators[nodeID]
+		_, _ = hasher.Write(nodeID[:])
+		writeBytes(vdr.PublicKey)
+		writeBytes(vdr.RingtailPubKey)
+		writeUint64(vdr.Weight)
+	}
+
+	var commitment ids.ID
+	copy(commitment[:], hasher.Sum(nil))
+	return commitment
+}
+
+// Commit sets the Commitment of the set to its current contents. Producers
+// call it after building the set.
+func (s *WarpSet) Commit() {
+	s.Commitment = s.ComputeCommitment()
+}
+
+// VerifyCommitment returns an error if the set has a commitment that doesn't
+// match its contents. Sets without a commitment are accepted.
+func (s *WarpSet) VerifyCommitment() error {
+	if s.Commitment == ids.Empty {
+		return nil
+	}
+	if actual := s.ComputeCommitment(); actual != s.Commitment {
+		return fmt.Errorf("%w: height %d committed to %s but hashes to %s", ErrCommitmentMismatch, s.Height, s.Commitment, actual)
+	}
+	return nil
+}
+
+// commitmentState verifies the commitments of the Warp sets returned by the
+// wrapped State
+type commitmentState struct {
+	State
+}
+
+// NewCommitmentVerifyingState returns a State that fails Warp set reads whose
+// commitment doesn't match the returned set
+func NewCommitmentVerifyingState(state State) State {
+	return &commitmentState{State: state}
+}
+
+func (s *commitmentState) GetWarpValidatorSet(ctx context.Context, height uint64, netID ids.ID) (*WarpSet, error) {
+	warpSet, err := s.State.GetWarpValidatorSet(ctx, height, netID)
+	if err != nil {
+		return nil, err
+	}
+	if warpSet == nil {
+		return nil, nil
+	}
+	if err := warpSet.VerifyCommitment(); err != nil {
+		return nil, fmt.Errorf("net %s: %w", netID, err)
+	}
+	return warpSet, nil
+}
+
+func (s *commitmentState) GetWarpValidatorSets(ctx context.Context, heights []uint64, netIDs []ids.ID) (map[ids.ID]map[uint64]*WarpSet, error) {
+	warpSets, err := s.State.GetWarpValidatorSets(ctx, heights, netIDs)
+	if err != nil {
+		return nil, err
+	}
+	for netID, byHeight := range warpSets {
+		for _, warpSet := range byHeight {
+			if warpSet == nil {
+				continue
+			}
+			if err := warpSet.VerifyCommitment(); err != nil {
+				return nil, fmt.Errorf("net %s: %w", netID, err)
+			}
+		}
+	}
+	return warpSets, nil
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+	"github.com/luxfi/validators/validatorstest"
+)
+
+// TestWarpSetCommitment tests that the commitment covers the set's contents
+func TestWarpSetCommitment(t *testing.T) {
+	require := require.New(t)
+
+	nodeID := ids.GenerateTestNodeID()
+	warpSet := &validators.WarpSet{
+		Height: 10,
+		Validators: map[ids.NodeID]*validators.WarpValidator{
+			nodeID: {NodeID: nodeID, PublicKey: []byte("bls-key"), Weight: 100},
+		},
+	}
+
+	// Uncommitted sets verify
+	require.NoError(warpSet.VerifyCommitment())
+
+	warpSet.Commit()
+	require.NotEqual(ids.Empty, warpSet.Commitment)
+	require.NoError(warpSet.VerifyCommitment())
+
+	warpSet.Validators[nodeID].Weight++
+	require.ErrorIs(warpSet.VerifyCommitment(), validators.ErrCommitmentMismatch)
+	warpSet.Validators[nodeID].Weight--
+
+	warpSet.Height++
+	require.ErrorIs(warpSet.VerifyCommitment(), validators.ErrCommitmentMismatch)
+}
+
+// TestCommitmentVerifyingState tests that corrupted Warp sets are rejected
+func TestCommitmentVerifyingState(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	backing := validatorstest.NewTestState().AddValidator(netID, &validators.GetValidatorOutput{
+		NodeID:    nodeID,
+		PublicKey: []byte("bls-key"),
+		Light:     100,
+		Weight:    100,
+	})
+	state := validators.NewCommitmentVerifyingState(backing)
+
+	warpSet, err := state.GetWarpValidatorSet(ctx, 5, netID)
+	require.NoError(err)
+	require.Equal(warpSet.ComputeCommitment(), warpSet.Commitment)
+
+	warpSets, err := state.GetWarpValidatorSets(ctx, []uint64{5, 6}, []ids.ID{netID})
+	require.NoError(err)
+	require.Len(warpSets[netID], 2)
+
+	// A set that was changed after being committed is rejected
+	original := backing.GetWarpValidatorSetF
+	backing.GetWarpValidatorSetF = func(ctx context.Context, height uint64, netID ids.ID) (*validators.WarpSet, error) {
+		warpSet := &validators.WarpSet{Height: height}
+		warpSet.Commit()
+		warpSet.Validators = map[ids.NodeID]*validators.WarpValidator{
+			nodeID: {NodeID: nodeID, Weight: 1},
+		}
+		return warpSet, nil
+	}
+	_, err = state.GetWarpValidatorSet(ctx, 5, netID)
+	require.ErrorIs(err, validators.ErrCommitmentMismatch)
+	backing.GetWarpValidatorSetF = original
+
+	backing.GetWarpValidatorSetsF = func(context.Context, []uint64, []ids.ID) (map[ids.ID]map[uint64]*validators.WarpSet, error) {
+		return map[ids.ID]map[uint64]*validators.WarpSet{
+			netID: {5: {Height: 5, Commitment: ids.GenerateTestID()}},
+		}, nil
+	}
+	_, err = state.GetWarpValidatorSets(ctx, []uint64{5}, []ids.ID{netID})
+	require.ErrorIs(err, validators.ErrCommitmentMismatch)
+}
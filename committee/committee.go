@@ -0,0 +1,216 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package committee derives deterministic, stake weighted subcommittees of a
+// net's canonical validator set
+package committee
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/luxfi/ids"
+
+	validators "github.com/luxfi/validators"
+)
+
+var (
+	ErrInvalidSize = errors.New("committee size must be positive")
+	ErrNotMember   = errors.New("not a committee member")
+	ErrSetMismatch = errors.New("validator set doesn't match proof")
+)
+
+// Committee is a subset of a canonical validator set selected for
+// (NetID, Epoch, ID)
+type Committee struct {
+	NetID ids.ID
+	Epoch uint64
+	ID    ids.ID
+	// Size is the requested size. The committee is smaller if the set has
+	// fewer validators with weight.
+	Size int
+	// SetChecksum is the checksum of the canonical set members were selected
+	// from
+	SetChecksum ids.ID
+	// Members are in selection order. Indices[i] is the index of Members[i]
+	// in the canonical set, which is its bit in a signer bitset.
+	Members []*validators.CanonicalValidator
+	Indices []int
+}
+
+// Seed returns the seed of the shuffle selecting committee [committeeID] of
+// [netID] in [epoch]
+func Seed(netID ids.ID, epoch uint64, committeeID ids.ID) ids.ID {
+	var epochBytes [8]byte
+	binary.BigEndian.PutUint64(epochBytes[:], epoch)
+
+	hasher := sha256.New()
+	_, _ = hasher.Write([]byte("lux/committee"))
+	_, _ = hasher.Write(netID[:])
+	_, _ = hasher.Write(epochBytes[:])
+	_, _ = hasher.Write(committeeID[:])
+
+	var seed ids.ID
+	copy(seed[:], hasher.Sum(nil))
+	return seed
+}
+
+// Select draws [size] validators from [set] without replacement, each draw
+// picking a remaining validator with probability proportional to its weight.
+// The draws are driven by Seed, so every node with the same canonical set
+// selects the same committee.
+func Select(set validators.CanonicalValidatorSet, netID ids.ID, epoch uint64, committeeID ids.ID, size int) (*Committee, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidSize, size)
+	}
+
+	var (
+		remaining = make([]int, 0, len(set.Validators))
+		weight    uint64
+	)
+	for i, vdr := range set.Validators {
+		if vdr.Weight == 0 {
+			continue
+		}
+		// The total weight of the set didn't overflow, so this can't either
+		weight += vdr.Weight
+		remaining = append(remaining, i)
+	}
+
+	committee := &Committee{
+		NetID:       netID,
+		Epoch:       epoch,
+		ID:          committeeID,
+		Size:        size,
+		SetChecksum: set.Checksum(),
+	}
+	rng := newStream(Seed(netID, epoch, committeeID))
+	for len(committee.Members) < size && len(remaining) > 0 {
+		target := rng.uint64n(weight)
+		for i, index := range remaining {
+			vdr := set.Validators[index]
+			if target >= vdr.Weight {
+				target -= vdr.Weight
+				continue
+			}
+
+			committee.Members = append(committee.Members, vdr)
+			committee.Indices = append(committee.Indices, index)
+			weight -= vdr.Weight
+			remaining = append(remaining[:i], remaining[i+1:]...)
+			break
+		}
+	}
+	return committee, nil
+}
+
+// FromManager selects a committee from the current validators of [netID] in
+// [m]
+func FromManager(m validators.Manager, netID ids.ID, epoch uint64, committeeID ids.ID, size int) (*Committee, error) {
+	set, err := validators.FlattenValidatorSet(m.GetMap(netID))
+	if err != nil {
+		return nil, err
+	}
+	return Select(set, netID, epoch, committeeID, size)
+}
+
+// Contains returns the position of [nodeID]'s validator in the committee
+func (c *Committee) Contains(nodeID ids.NodeID) (int, bool) {
+	for position, vdr := range c.Members {
+		for _, vdrNodeID := range vdr.NodeIDs {
+			if vdrNodeID == nodeID {
+				return position, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// Weight returns the total weight of the committee
+func (c *Committee) Weight() (uint64, error) {
+	return validators.SumWeight(c.Members)
+}
+
+// Proof shows that a node is a member of a committee. It is checked by
+// re-deriving the committee from the same canonical set.
+type Proof struct {
+	NetID       ids.ID
+	Epoch       uint64
+	CommitteeID ids.ID
+	Size        int
+	SetChecksum ids.ID
+	NodeID      ids.NodeID
+	Position    int
+}
+
+// Prove returns a proof that [nodeID] is a member of the committee
+func (c *Committee) Prove(nodeID ids.NodeID) (Proof, error) {
+	position, ok := c.Contains(nodeID)
+	if !ok {
+		return Proof{}, fmt.Errorf("%w: %s", ErrNotMember, nodeID)
+	}
+	return Proof{
+		NetID:       c.NetID,
+		Epoch:       c.Epoch,
+		CommitteeID: c.ID,
+		Size:        c.Size,
+		SetChecksum: c.SetChecksum,
+		NodeID:      nodeID,
+		Position:    position,
+	}, nil
+}
+
+// Verify returns nil if [proof] holds against [set]
+func (p Proof) Verify(set validators.CanonicalValidatorSet) error {
+	if checksum := set.Checksum(); checksum != p.SetChecksum {
+		return fmt.Errorf("%w: checksum %s != %s", ErrSetMismatch, checksum, p.SetChecksum)
+	}
+	committee, err := Select(set, p.NetID, p.Epoch, p.CommitteeID, p.Size)
+	if err != nil {
+		return err
+	}
+	if position, ok := committee.Contains(p.NodeID); !ok || position != p.Position {
+		return fmt.Errorf("%w: %s at position %d", ErrNotMember, p.NodeID, p.Position)
+	}
+	return nil
+}
+
+// stream is a deterministic source of uint64s: SHA-256 of the seed and a
+// counter
+type stream struct {
+	seed    ids.ID
+	counter uint64
+	buf     []byte
+}
+
+func newStream(seed ids.ID) *stream {
+	return &stream{seed: seed}
+}
+
+func (s *stream) uint64() uint64 {
+	if len(s.buf) < 8 {
+		var counter [8]byte
+		binary.BigEndian.PutUint64(counter[:], s.counter)
+		s.counter++
+
+		block := sha256.Sum256(append(s.seed[:], counter[:]...))
+		s.buf = block[:]
+	}
+	v := binary.BigEndian.Uint64(s.buf)
+	s.buf = s.buf[8:]
+	return v
+}
+
+// uint64n returns a uniform value in [0, n). Values from the biased tail of
+// the range are rejected.
+func (s *stream) uint64n(n uint64) uint64 {
+	limit := math.MaxUint64 - math.MaxUint64%n
+	for {
+		if v := s.uint64(); v < limit {
+			return v % n
+		}
+	}
+}
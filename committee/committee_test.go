@@ -0,0 +1,115 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package committee
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+	"github.com/luxfi/validators/validatorstest"
+)
+
+func newTestManager(t *testing.T, netID ids.ID, weights ...uint64) validators.Manager {
+	generated, err := validatorstest.GenerateValidatorSet(1, len(weights), validatorstest.ConstantWeights(1))
+	require.NoError(t, err)
+	m := validators.NewManager()
+	for i, vdr := range generated.Validators {
+		require.NoError(t, m.AddStaker(netID, vdr.NodeID, vdr.PublicKeyBytes, ids.Empty, weights[i]))
+	}
+	return m
+}
+
+// TestSelect tests that committees are deterministic and distinct
+func TestSelect(t *testing.T) {
+	require := require.New(t)
+
+	netID := ids.GenerateTestID()
+	m := newTestManager(t, netID, 10, 20, 30, 40, 50, 60, 70, 80)
+	set, err := validators.FlattenValidatorSet(m.GetMap(netID))
+	require.NoError(err)
+
+	committeeID := ids.GenerateTestID()
+	committee, err := Select(set, netID, 1, committeeID, 4)
+	require.NoError(err)
+	require.Len(committee.Members, 4)
+	require.Len(committee.Indices, 4)
+	seen := make(map[int]bool)
+	for i, index := range committee.Indices {
+		require.False(seen[index])
+		seen[index] = true
+		require.Same(set.Validators[index], committee.Members[i])
+	}
+
+	again, err := FromManager(m, netID, 1, committeeID, 4)
+	require.NoError(err)
+	require.Equal(committee.Indices, again.Indices)
+
+	// Over many epochs the committees can't all be the same
+	distinct := false
+	for epoch := uint64(2); epoch < 20 && !distinct; epoch++ {
+		other, err := Select(set, netID, epoch, committeeID, 4)
+		require.NoError(err)
+		distinct = !slices.Equal(committee.Indices, other.Indices)
+	}
+	require.True(distinct)
+
+	_, err = Select(set, netID, 1, committeeID, 0)
+	require.ErrorIs(err, ErrInvalidSize)
+}
+
+// TestSelectSmallSet tests that validators without weight are never selected
+func TestSelectSmallSet(t *testing.T) {
+	require := require.New(t)
+
+	netID := ids.GenerateTestID()
+	m := newTestManager(t, netID, 0, 5, 0)
+	committee, err := FromManager(m, netID, 0, ids.Empty, 3)
+	require.NoError(err)
+	require.Len(committee.Members, 1)
+	require.Equal(uint64(5), committee.Members[0].Weight)
+
+	weight, err := committee.Weight()
+	require.NoError(err)
+	require.Equal(uint64(5), weight)
+}
+
+// TestProof tests membership proofs
+func TestProof(t *testing.T) {
+	require := require.New(t)
+
+	netID := ids.GenerateTestID()
+	m := newTestManager(t, netID, 1, 2, 3, 4, 5)
+	set, err := validators.FlattenValidatorSet(m.GetMap(netID))
+	require.NoError(err)
+	committee, err := Select(set, netID, 7, ids.GenerateTestID(), 2)
+	require.NoError(err)
+
+	member := committee.Members[1].NodeIDs[0]
+	position, ok := committee.Contains(member)
+	require.True(ok)
+	require.Equal(1, position)
+
+	proof, err := committee.Prove(member)
+	require.NoError(err)
+	require.NoError(proof.Verify(set))
+
+	forged := proof
+	forged.Position = 0
+	require.ErrorIs(forged.Verify(set), ErrNotMember)
+
+	for _, vdr := range set.Validators {
+		if _, ok := committee.Contains(vdr.NodeIDs[0]); !ok {
+			_, err := committee.Prove(vdr.NodeIDs[0])
+			require.ErrorIs(err, ErrNotMember)
+			break
+		}
+	}
+
+	set.Validators[0].Weight++
+	require.ErrorIs(proof.Verify(set), ErrSetMismatch)
+}
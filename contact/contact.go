@@ -0,0 +1,327 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package contact schedules the signature requests of an aggregation round.
+// Validators are asked in order of their weight discounted by how often they
+// failed to respond before, and validators that time out are replaced by the
+// next best, so the round reaches its quorum with as few waits as possible.
+package contact
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/bits"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/luxfi/ids"
+
+	validators "github.com/luxfi/validators"
+)
+
+var (
+	ErrInvalidConfig   = errors.New("invalid contact config")
+	ErrUnknownRequest  = errors.New("validator wasn't requested")
+	ErrAlreadyResolved = errors.New("request already resolved")
+)
+
+// Config configures a Scheduler
+type Config struct {
+	// Parallelism is the number of requests in flight at once
+	Parallelism int
+	// Timeout is how long a request is waited on before the next best
+	// validator is asked in its place
+	Timeout time.Duration
+	// QuorumNumerator / QuorumDenominator of the total weight must sign
+	QuorumNumerator   uint64
+	QuorumDenominator uint64
+}
+
+// DefaultConfig asks 8 validators at a time for a 2/3 quorum, giving each a
+// second
+func DefaultConfig() Config {
+	return Config{
+		Parallelism:       8,
+		Timeout:           time.Second,
+		QuorumNumerator:   2,
+		QuorumDenominator: 3,
+	}
+}
+
+// Verify returns an error if the config can't collect a quorum
+func (c Config) Verify() error {
+	switch {
+	case c.Parallelism <= 0:
+		return fmt.Errorf("%w: parallelism %d must be positive", ErrInvalidConfig, c.Parallelism)
+	case c.Timeout <= 0:
+		return fmt.Errorf("%w: timeout %s must be positive", ErrInvalidConfig, c.Timeout)
+	case c.QuorumDenominator == 0:
+		return fmt.Errorf("%w: zero quorum denominator", ErrInvalidConfig)
+	case c.QuorumNumerator == 0 || c.QuorumNumerator > c.QuorumDenominator:
+		return fmt.Errorf("%w: quorum %d/%d must be in (0, 1]", ErrInvalidConfig, c.QuorumNumerator, c.QuorumDenominator)
+	}
+	return nil
+}
+
+// Reputation records how validators responded in earlier rounds
+type Reputation interface {
+	// Responses returns the number of requests [nodeID] responded to and
+	// the number it let time out
+	Responses(nodeID ids.NodeID) (responded, timedOut uint64)
+	RecordResponse(nodeID ids.NodeID)
+	RecordTimeout(nodeID ids.NodeID)
+}
+
+type responses struct {
+	responded uint64
+	timedOut  uint64
+}
+
+// Tracker is a Reputation kept in memory
+type Tracker struct {
+	mu        sync.Mutex
+	responses map[ids.NodeID]responses
+}
+
+var _ Reputation = (*Tracker)(nil)
+
+// NewTracker returns a Tracker with no history
+func NewTracker() *Tracker {
+	return &Tracker{responses: make(map[ids.NodeID]responses)}
+}
+
+func (t *Tracker) Responses(nodeID ids.NodeID) (uint64, uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r := t.responses[nodeID]
+	return r.responded, r.timedOut
+}
+
+func (t *Tracker) RecordResponse(nodeID ids.NodeID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r := t.responses[nodeID]
+	r.responded++
+	t.responses[nodeID] = r
+}
+
+func (t *Tracker) RecordTimeout(nodeID ids.NodeID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r := t.responses[nodeID]
+	r.timedOut++
+	t.responses[nodeID] = r
+}
+
+// Forget drops the history of [nodeID], such as after it left every net
+func (t *Tracker) Forget(nodeID ids.NodeID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.responses, nodeID)
+}
+
+// Priority returns the weight of a validator discounted by its reputation:
+// [weight] * (responded + 1) / (responded + timedOut + 2). Validators without
+// history get half their weight, so they are tried after reliable ones of
+// the same weight but before unreliable ones.
+func Priority(weight, responded, timedOut uint64) uint64 {
+	num := responded + 1
+	den := responded + timedOut + 2
+	hi, lo := bits.Mul64(weight, num)
+	// hi < den, since num < den
+	quo, _ := bits.Div64(hi, lo, den)
+	return quo
+}
+
+// Request is a signature request to send
+type Request struct {
+	// Index is the index of the validator in the canonical set, which is its
+	// bit in the signer bitset
+	Index  int
+	NodeID ids.NodeID
+	// Deadline is when the request times out
+	Deadline time.Time
+}
+
+type requestState uint8
+
+const (
+	queued requestState = iota
+	inFlight
+	timedOut
+	resolved
+)
+
+type candidate struct {
+	index    int
+	nodeID   ids.NodeID
+	weight   uint64
+	priority uint64
+	state    requestState
+	deadline time.Time
+}
+
+// Scheduler schedules the requests of a single round. It does no I/O: the
+// caller sends the requests returned by Next and reports how each went.
+type Scheduler struct {
+	config     Config
+	reputation Reputation
+	total      uint64
+
+	candidates []*candidate // in priority order
+	byIndex    map[int]*candidate
+	next       int // first candidate not yet requested
+	inFlight   int
+	signed     uint64
+}
+
+// New returns a Scheduler collecting the signatures of [vdrSet]. Validators
+// sharing a key are asked through whichever of their nodes has the best
+// reputation, the lowest node ID among equals. Candidates of equal priority
+// are asked by descending weight, then canonical index, so schedules are
+// reproducible.
+func New(vdrSet validators.CanonicalValidatorSet, reputation Reputation, config Config) (*Scheduler, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+
+	s := &Scheduler{
+		config:     config,
+		reputation: reputation,
+		total:      vdrSet.TotalWeight,
+		candidates: make([]*candidate, 0, len(vdrSet.Validators)),
+		byIndex:    make(map[int]*candidate, len(vdrSet.Validators)),
+	}
+	for i, vdr := range vdrSet.Validators {
+		if len(vdr.NodeIDs) == 0 {
+			continue
+		}
+		c := &candidate{index: i, weight: vdr.Weight}
+		for j, nodeID := range vdr.NodeIDs {
+			var responded, timedOut uint64
+			if reputation != nil {
+				responded, timedOut = reputation.Responses(nodeID)
+			}
+			priority := Priority(vdr.Weight, responded, timedOut)
+			if j == 0 || priority > c.priority {
+				c.nodeID = nodeID
+				c.priority = priority
+			}
+		}
+		s.candidates = append(s.candidates, c)
+		s.byIndex[i] = c
+	}
+	slices.SortFunc(s.candidates, func(a, b *candidate) int {
+		return cmp.Or(
+			cmp.Compare(b.priority, a.priority),
+			cmp.Compare(b.weight, a.weight),
+			cmp.Compare(a.index, b.index),
+		)
+	})
+	return s, nil
+}
+
+// Next times out the requests whose deadline passed at [now] and returns the
+// requests to send so that Parallelism requests are in flight, best first.
+// Nothing is returned once the quorum is reached.
+func (s *Scheduler) Next(now time.Time) []Request {
+	for _, c := range s.candidates {
+		if c.state == inFlight && !now.Before(c.deadline) {
+			c.state = timedOut
+			s.inFlight--
+			if s.reputation != nil {
+				s.reputation.RecordTimeout(c.nodeID)
+			}
+		}
+	}
+	if s.HasQuorum() {
+		return nil
+	}
+
+	var requests []Request
+	for ; s.next < len(s.candidates) && s.inFlight < s.config.Parallelism; s.next++ {
+		c := s.candidates[s.next]
+		c.state = inFlight
+		c.deadline = now.Add(s.config.Timeout)
+		s.inFlight++
+		requests = append(requests, Request{
+			Index:    c.index,
+			NodeID:   c.nodeID,
+			Deadline: c.deadline,
+		})
+	}
+	return requests
+}
+
+// Responded records that the validator at [index] returned a valid
+// signature. Responses after the deadline are still counted towards the
+// quorum, though the validator keeps its timeout.
+func (s *Scheduler) Responded(index int) error {
+	c, err := s.resolve(index)
+	if err != nil {
+		return err
+	}
+	if c.state == inFlight {
+		s.inFlight--
+		if s.reputation != nil {
+			s.reputation.RecordResponse(c.nodeID)
+		}
+	}
+	c.state = resolved
+	// Can't overflow, as the weights of a canonical set sum to at most its
+	// total
+	s.signed += c.weight
+	return nil
+}
+
+// Failed records that the request to the validator at [index] failed, such
+// as with an invalid signature, freeing its slot for the next best
+// validator
+func (s *Scheduler) Failed(index int) error {
+	c, err := s.resolve(index)
+	if err != nil {
+		return err
+	}
+	if c.state == inFlight {
+		s.inFlight--
+		if s.reputation != nil {
+			s.reputation.RecordTimeout(c.nodeID)
+		}
+	}
+	c.state = resolved
+	return nil
+}
+
+// resolve returns the requested candidate at [index]
+func (s *Scheduler) resolve(index int) (*candidate, error) {
+	c, ok := s.byIndex[index]
+	switch {
+	case !ok || c.state == queued:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownRequest, index)
+	case c.state == resolved:
+		return nil, fmt.Errorf("%w: %d", ErrAlreadyResolved, index)
+	}
+	return c, nil
+}
+
+// SignedWeight returns the weight of the validators that responded
+func (s *Scheduler) SignedWeight() uint64 {
+	return s.signed
+}
+
+// HasQuorum returns true once the validators that responded hold the quorum
+func (s *Scheduler) HasQuorum() bool {
+	return validators.VerifyWeight(s.signed, s.total, s.config.QuorumNumerator, s.config.QuorumDenominator) == nil
+}
+
+// Exhausted returns true if every validator was asked and none is still in
+// flight, so waiting longer can only help through late responses
+func (s *Scheduler) Exhausted() bool {
+	return s.next == len(s.candidates) && s.inFlight == 0
+}
@@ -0,0 +1,129 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package contact
+
+import (
+	"testing"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+// TestConfigVerify tests that configs that can't collect a quorum are
+// rejected
+func TestConfigVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      Config
+		expectedErr error
+	}{
+		{
+			name:   "default",
+			config: DefaultConfig(),
+		},
+		{
+			name:        "no parallelism",
+			config:      Config{Timeout: time.Second, QuorumNumerator: 1, QuorumDenominator: 1},
+			expectedErr: ErrInvalidConfig,
+		},
+		{
+			name:        "no timeout",
+			config:      Config{Parallelism: 1, QuorumNumerator: 1, QuorumDenominator: 1},
+			expectedErr: ErrInvalidConfig,
+		},
+		{
+			name:        "zero denominator",
+			config:      Config{Parallelism: 1, Timeout: time.Second, QuorumNumerator: 1},
+			expectedErr: ErrInvalidConfig,
+		},
+		{
+			name:        "quorum above 1",
+			config:      Config{Parallelism: 1, Timeout: time.Second, QuorumNumerator: 2, QuorumDenominator: 1},
+			expectedErr: ErrInvalidConfig,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.config.Verify(), test.expectedErr)
+		})
+	}
+}
+
+// TestPriority tests that reputation discounts weight
+func TestPriority(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal(uint64(50), Priority(100, 0, 0))
+	require.Equal(uint64(90), Priority(100, 8, 0))
+	require.Equal(uint64(10), Priority(100, 0, 8))
+	require.Equal(uint64(1<<63), Priority(1<<64-1, 0, 0)+1)
+}
+
+// TestScheduler tests that requests go to the best validators first and
+// that timed out requests are replaced by the next best
+func TestScheduler(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		nodeIDs = make([]ids.NodeID, 4)
+		vdrSet  = validators.CanonicalValidatorSet{TotalWeight: 100}
+	)
+	for i, weight := range []uint64{10, 20, 30, 40} {
+		nodeIDs[i] = ids.GenerateTestNodeID()
+		vdrSet.Validators = append(vdrSet.Validators, &validators.CanonicalValidator{
+			Weight:  weight,
+			NodeIDs: []ids.NodeID{nodeIDs[i]},
+		})
+	}
+
+	// The heaviest validator is unreliable, so it is asked last
+	tracker := NewTracker()
+	for range 10 {
+		tracker.RecordTimeout(nodeIDs[3])
+	}
+	config := Config{
+		Parallelism:       2,
+		Timeout:           time.Second,
+		QuorumNumerator:   1,
+		QuorumDenominator: 2,
+	}
+	s, err := New(vdrSet, tracker, config)
+	require.NoError(err)
+
+	now := time.Unix(0, 0)
+	requests := s.Next(now)
+	require.Equal([]Request{
+		{Index: 2, NodeID: nodeIDs[2], Deadline: now.Add(time.Second)},
+		{Index: 1, NodeID: nodeIDs[1], Deadline: now.Add(time.Second)},
+	}, requests)
+	require.Empty(s.Next(now))
+
+	require.NoError(s.Responded(2))
+	require.ErrorIs(s.Responded(2), ErrAlreadyResolved)
+	require.ErrorIs(s.Responded(0), ErrUnknownRequest)
+	require.Equal([]Request{{Index: 0, NodeID: nodeIDs[0], Deadline: now.Add(time.Second)}}, s.Next(now))
+
+	// Both outstanding requests time out and are replaced
+	now = now.Add(time.Second)
+	require.Equal([]Request{{Index: 3, NodeID: nodeIDs[3], Deadline: now.Add(time.Second)}}, s.Next(now))
+	responded, timedOut := tracker.Responses(nodeIDs[1])
+	require.Zero(responded)
+	require.Equal(uint64(1), timedOut)
+	responded, _ = tracker.Responses(nodeIDs[2])
+	require.Equal(uint64(1), responded)
+	require.False(s.HasQuorum())
+
+	// A late response still counts
+	require.NoError(s.Responded(1))
+	require.Equal(uint64(50), s.SignedWeight())
+	require.True(s.HasQuorum())
+	require.Empty(s.Next(now))
+
+	require.NoError(s.Failed(3))
+	require.NoError(s.Failed(0))
+	require.True(s.Exhausted())
+}
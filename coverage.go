@@ -0,0 +1,85 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"fmt"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math"
+)
+
+// KeyCoverageManager reports how much of a net's stake can sign Warp
+// messages. Warp quorums become unreachable once too much weight lacks keys,
+// without any error until a message fails to gather signatures.
+type KeyCoverageManager interface {
+	// KeyCoverage returns the key coverage of the current validators of
+	// [netID]. Keys are parsed on every call.
+	KeyCoverage(netID ids.ID) (KeyCoverage, error)
+}
+
+var _ KeyCoverageManager = (*manager)(nil)
+
+// KeyCoverage is the number and economic weight of a net's validators with
+// each kind of key
+type KeyCoverage struct {
+	Validators  int
+	TotalWeight uint64
+	// BLS counts validators whose BLS key parses
+	BLS KeyStats
+	// Ringtail counts validators with a Ringtail key
+	Ringtail KeyStats
+}
+
+// KeyStats is the number and economic weight of validators with a key
+type KeyStats struct {
+	Count  int
+	Weight uint64
+}
+
+// BLSFraction returns the fraction of the weight with a valid BLS key. A net
+// without weight has no coverage.
+func (c KeyCoverage) BLSFraction() float64 {
+	return c.fraction(c.BLS)
+}
+
+// RingtailFraction returns the fraction of the weight with a Ringtail key
+func (c KeyCoverage) RingtailFraction() float64 {
+	return c.fraction(c.Ringtail)
+}
+
+func (c KeyCoverage) fraction(stats KeyStats) float64 {
+	if c.TotalWeight == 0 {
+		return 0
+	}
+	return float64(stats.Weight) / float64(c.TotalWeight)
+}
+
+// KeyCoverage returns the key coverage of a net
+func (m *manager) KeyCoverage(netID ids.ID) (KeyCoverage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var (
+		vdrs     = m.validators[netID]
+		coverage = KeyCoverage{Validators: len(vdrs)}
+		err      error
+	)
+	for _, val := range vdrs {
+		weight := EconomicWeight.Of(val)
+		coverage.TotalWeight, err = math.Add64(coverage.TotalWeight, weight)
+		if err != nil {
+			return KeyCoverage{}, fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+		}
+		if _, err := val.BLSPublicKey(); err == nil {
+			coverage.BLS.Count++
+			coverage.BLS.Weight += weight
+		}
+		if len(val.RingtailPubKey) > 0 {
+			coverage.Ringtail.Count++
+			coverage.Ringtail.Weight += weight
+		}
+	}
+	return coverage, nil
+}
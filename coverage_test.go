@@ -0,0 +1,68 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"testing"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/validators/validatorstest/fixture"
+)
+
+// TestManagerKeyCoverage tests that coverage counts valid keys by weight
+func TestManagerKeyCoverage(t *testing.T) {
+	require := require.New(t)
+
+	generated, err := fixture.Generate(1, 1, fixture.ConstantWeights(1))
+	require.NoError(err)
+	sk := generated[0].SecretKey
+	pk := bls.PublicKeyToCompressedBytes(sk.PublicKey())
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	m.loadValidators(netID, []*GetValidatorOutput{
+		{
+			NodeID:         ids.GenerateTestNodeID(),
+			PublicKey:      pk,
+			RingtailPubKey: []byte("ringtail"),
+			Light:          50,
+			Weight:         50,
+		},
+		{
+			NodeID:    ids.GenerateTestNodeID(),
+			PublicKey: pk,
+			Light:     25,
+			Weight:    25,
+		},
+		{
+			NodeID:    ids.GenerateTestNodeID(),
+			PublicKey: []byte("invalid"),
+			Light:     15,
+			Weight:    15,
+		},
+		{
+			NodeID: ids.GenerateTestNodeID(),
+			Light:  10,
+			Weight: 10,
+		},
+	})
+
+	coverage, err := m.KeyCoverage(netID)
+	require.NoError(err)
+	require.Equal(KeyCoverage{
+		Validators:  4,
+		TotalWeight: 100,
+		BLS:         KeyStats{Count: 2, Weight: 75},
+		Ringtail:    KeyStats{Count: 1, Weight: 50},
+	}, coverage)
+	require.InDelta(0.75, coverage.BLSFraction(), 1e-9)
+	require.InDelta(0.5, coverage.RingtailFraction(), 1e-9)
+
+	coverage, err = m.KeyCoverage(ids.GenerateTestID())
+	require.NoError(err)
+	require.Zero(coverage.BLSFraction())
+}
@@ -0,0 +1,230 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package dampening smooths the weights a net samples and counts quorums
+// with, so a single large stake movement shifts voting power over several
+// heights instead of at once
+package dampening
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"sync"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math"
+
+	validators "github.com/luxfi/validators"
+)
+
+var (
+	ErrInvalidConfig       = errors.New("invalid dampening config")
+	ErrNonIncreasingHeight = errors.New("height is before the latest height")
+	ErrNotStarted          = errors.New("dampener hasn't advanced to a height yet")
+)
+
+// Config is the ramp effective weights follow
+type Config struct {
+	// MaxStepNumerator / MaxStepDenominator is the share of the net's total
+	// light a validator's effective weight may move towards its light per
+	// height. Every validator may move by at least 1 per height.
+	MaxStepNumerator   uint64
+	MaxStepDenominator uint64
+}
+
+// DefaultConfig moves each validator by at most 1% of the net per height
+func DefaultConfig() Config {
+	return Config{
+		MaxStepNumerator:   1,
+		MaxStepDenominator: 100,
+	}
+}
+
+// Verify returns an error if the config can't converge
+func (c Config) Verify() error {
+	switch {
+	case c.MaxStepDenominator == 0:
+		return fmt.Errorf("%w: zero denominator", ErrInvalidConfig)
+	case c.MaxStepNumerator == 0:
+		return fmt.Errorf("%w: zero step", ErrInvalidConfig)
+	case c.MaxStepNumerator > c.MaxStepDenominator:
+		return fmt.Errorf("%w: step %d/%d exceeds 1", ErrInvalidConfig, c.MaxStepNumerator, c.MaxStepDenominator)
+	}
+	return nil
+}
+
+// Manager is a validator manager a Dampener can read without copying
+type Manager interface {
+	validators.Manager
+	validators.ViewManager
+}
+
+// Dampener tracks the effective weights of a net's validators. Effective
+// weights only change when the dampener advances to a new height, moving
+// each validator's towards its light in the manager, which stays the raw
+// weight.
+//
+// Validators that join start at zero and ramp up. Validators that leave the
+// net are dropped at once, since they can no longer sign.
+type Dampener struct {
+	manager Manager
+	netID   ids.ID
+	config  Config
+
+	mu        sync.RWMutex
+	started   bool
+	height    uint64
+	effective map[ids.NodeID]uint64
+	total     uint64
+}
+
+// New returns a dampener of [netID]. Effective weights are empty until the
+// first Advance.
+func New(manager Manager, netID ids.ID, config Config) (*Dampener, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	return &Dampener{
+		manager:   manager,
+		netID:     netID,
+		config:    config,
+		effective: make(map[ids.NodeID]uint64),
+	}, nil
+}
+
+// Advance moves the effective weights towards the current lights of the net
+// by the ramp of every height since the latest one. The first call takes the
+// lights as they are. Advancing to the latest height again does nothing.
+func (d *Dampener) Advance(height uint64) error {
+	targets := make(map[ids.NodeID]uint64)
+	var (
+		totalTarget uint64
+		err         error
+	)
+	d.manager.View(d.netID).Range(func(nodeID ids.NodeID, val validators.GetValidatorOutput) bool {
+		targets[nodeID] = val.Light
+		totalTarget, err = math.Add64(totalTarget, val.Light)
+		return err == nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", validators.ErrWeightOverflow, err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch {
+	case !d.started:
+		d.started = true
+		d.height = height
+		d.effective = targets
+		d.total = totalTarget
+		return nil
+	case height < d.height:
+		return fmt.Errorf("%w: %d < %d", ErrNonIncreasingHeight, height, d.height)
+	case height == d.height:
+		return nil
+	}
+
+	maxStep := d.maxStep(totalTarget, height-d.height)
+	effective := make(map[ids.NodeID]uint64, len(targets))
+	var total uint64
+	for nodeID, target := range targets {
+		weight := step(d.effective[nodeID], target, maxStep)
+		if weight == 0 {
+			continue
+		}
+		effective[nodeID] = weight
+		// Each weight is at most its target, so the sum can't overflow
+		total += weight
+	}
+	d.height = height
+	d.effective = effective
+	d.total = total
+	return nil
+}
+
+// maxStep returns how far each effective weight may move over [heights]
+// heights of a net with [totalTarget] light. It assumes the lock is held.
+func (d *Dampener) maxStep(totalTarget uint64, heights uint64) uint64 {
+	hi, lo := bits.Mul64(totalTarget, d.config.MaxStepNumerator)
+	perHeight, _ := bits.Div64(hi, lo, d.config.MaxStepDenominator)
+	perHeight = max(perHeight, 1)
+
+	hi, lo = bits.Mul64(perHeight, heights)
+	if hi != 0 {
+		return ^uint64(0)
+	}
+	return lo
+}
+
+// step moves [current] towards [target] by at most [maxStep]
+func step(current, target, maxStep uint64) uint64 {
+	if current < target {
+		return current + min(target-current, maxStep)
+	}
+	return current - min(current-target, maxStep)
+}
+
+// Height returns the latest height the dampener advanced to, and false if it
+// hasn't advanced yet
+func (d *Dampener) Height() (uint64, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.height, d.started
+}
+
+// Weight returns the effective weight of [nodeID]
+func (d *Dampener) Weight(nodeID ids.NodeID) uint64 {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.effective[nodeID]
+}
+
+// TotalWeight returns the sum of the effective weights
+func (d *Dampener) TotalWeight() uint64 {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.total
+}
+
+// Weights returns the validators with a positive effective weight
+func (d *Dampener) Weights() map[ids.NodeID]uint64 {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	weights := make(map[ids.NodeID]uint64, len(d.effective))
+	for nodeID, weight := range d.effective {
+		weights[nodeID] = weight
+	}
+	return weights
+}
+
+// GetMap returns the records of the validators with a positive effective
+// weight, with their Light and Weight replaced by it, so the set can be
+// sampled or flattened into a canonical set for quorum checks. Validators
+// that left the net since the latest height are omitted.
+func (d *Dampener) GetMap() (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if !d.started {
+		return nil, ErrNotStarted
+	}
+
+	vdrs := d.manager.GetMap(d.netID)
+	for nodeID, val := range vdrs {
+		weight, ok := d.effective[nodeID]
+		if !ok {
+			delete(vdrs, nodeID)
+			continue
+		}
+		val.Light = weight
+		val.Weight = weight
+	}
+	return vdrs, nil
+}
@@ -0,0 +1,97 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dampening
+
+import (
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+// TestConfigVerify tests config validation
+func TestConfigVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      Config
+		expectedErr error
+	}{
+		{
+			name:   "default",
+			config: DefaultConfig(),
+		},
+		{
+			name:        "zero denominator",
+			config:      Config{MaxStepNumerator: 1},
+			expectedErr: ErrInvalidConfig,
+		},
+		{
+			name:        "zero step",
+			config:      Config{MaxStepDenominator: 100},
+			expectedErr: ErrInvalidConfig,
+		},
+		{
+			name:        "step above 1",
+			config:      Config{MaxStepNumerator: 2, MaxStepDenominator: 1},
+			expectedErr: ErrInvalidConfig,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.config.Verify(), test.expectedErr)
+		})
+	}
+}
+
+// TestDampener tests that effective weights ramp towards the raw lights
+func TestDampener(t *testing.T) {
+	require := require.New(t)
+
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	nodeID1, nodeID2, nodeID3 := ids.GenerateTestNodeID(), ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID1, nil, ids.Empty, 500))
+	require.NoError(m.AddStaker(netID, nodeID2, nil, ids.Empty, 500))
+
+	d, err := New(m, netID, Config{MaxStepNumerator: 1, MaxStepDenominator: 10})
+	require.NoError(err)
+	_, err = d.GetMap()
+	require.ErrorIs(err, ErrNotStarted)
+
+	// The first height takes the lights as they are
+	require.NoError(d.Advance(10))
+	require.Equal(uint64(500), d.Weight(nodeID1))
+	require.Equal(uint64(1000), d.TotalWeight())
+
+	// A large stake movement ramps in by 10% of the net per height
+	require.NoError(m.SetWeight(netID, nodeID1, 1500))
+	require.NoError(d.Advance(11))
+	require.Equal(uint64(700), d.Weight(nodeID1))
+	require.Equal(uint64(1200), d.TotalWeight())
+	require.Equal(uint64(1500), m.GetLight(netID, nodeID1))
+
+	// Skipped heights apply the ramp of each one, without overshooting
+	require.NoError(d.Advance(15))
+	require.Equal(uint64(1500), d.Weight(nodeID1))
+
+	// Validators that join ramp up from zero and leaving ones drop at once
+	require.NoError(m.AddStaker(netID, nodeID3, nil, ids.Empty, 1000))
+	require.NoError(m.RemoveStaker(netID, nodeID2))
+	require.NoError(d.Advance(16))
+	require.Equal(map[ids.NodeID]uint64{nodeID1: 1500, nodeID3: 250}, d.Weights())
+
+	vdrs, err := d.GetMap()
+	require.NoError(err)
+	require.Len(vdrs, 2)
+	require.Equal(uint64(250), vdrs[nodeID3].Light)
+	require.Equal(uint64(250), vdrs[nodeID3].Weight)
+
+	require.NoError(d.Advance(16))
+	require.ErrorIs(d.Advance(15), ErrNonIncreasingHeight)
+	height, ok := d.Height()
+	require.True(ok)
+	require.Equal(uint64(16), height)
+}
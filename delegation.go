@@ -0,0 +1,286 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math"
+)
+
+// DelegationManager tracks stake delegated to validators. Delegated weight is
+// tracked separately from a validator's self-stake but contributes to its
+// effective light.
+type DelegationManager interface {
+	// AddDelegator delegates [weight] from [delegatorID] to the validator
+	// [nodeID] of [netID]. Repeated delegations accumulate.
+	AddDelegator(netID ids.ID, nodeID ids.NodeID, delegatorID ids.ShortID, weight uint64) error
+	// RemoveDelegator removes up to [weight] of [delegatorID]'s delegation to
+	// [nodeID]
+	RemoveDelegator(netID ids.ID, nodeID ids.NodeID, delegatorID ids.ShortID, weight uint64) error
+	// GetDelegatedWeight returns the total weight delegated to [nodeID]
+	GetDelegatedWeight(netID ids.ID, nodeID ids.NodeID) uint64
+	// GetSelfStake returns the weight of [nodeID] that isn't delegated or
+	// derived from asset stakes
+	GetSelfStake(netID ids.ID, nodeID ids.NodeID) uint64
+	// GetDelegations returns the delegations to [nodeID] by delegator
+	GetDelegations(netID ids.ID, nodeID ids.NodeID) map[ids.ShortID]uint64
+	// GetDelegatorPositions returns every delegation made by [delegatorID]
+	GetDelegatorPositions(delegatorID ids.ShortID) []DelegatorPosition
+	// RegisterDelegationListener registers a listener for delegation changes
+	RegisterDelegationListener(listener DelegationListener)
+}
+
+// DelegatorPosition is a single delegation made by a delegator
+type DelegatorPosition struct {
+	NetID  ids.ID
+	NodeID ids.NodeID
+	Weight uint64
+}
+
+// DelegationListener listens to delegation changes. Additions are reported
+// with an [oldWeight] of 0 and removals with a [newWeight] of 0.
+type DelegationListener interface {
+	OnDelegationChanged(netID ids.ID, nodeID ids.NodeID, delegatorID ids.ShortID, oldWeight, newWeight uint64)
+}
+
+var _ DelegationManager = (*manager)(nil)
+
+// validatorKey identifies a validator of a net
+type validatorKey struct {
+	netID  ids.ID
+	nodeID ids.NodeID
+}
+
+// delegations tracks the delegations of every validator along with a reverse
+// index by delegator
+type delegations struct {
+	byValidator map[validatorKey]map[ids.ShortID]uint64
+	byDelegator map[ids.ShortID]map[validatorKey]struct{}
+	totals      map[validatorKey]uint64
+}
+
+func newDelegations() *delegations {
+	return &delegations{
+		byValidator: make(map[validatorKey]map[ids.ShortID]uint64),
+		byDelegator: make(map[ids.ShortID]map[validatorKey]struct{}),
+		totals:      make(map[validatorKey]uint64),
+	}
+}
+
+// clone returns a copy of [d] that shares none of its maps
+func (d *delegations) clone() *delegations {
+	c := &delegations{
+		byValidator: make(map[validatorKey]map[ids.ShortID]uint64, len(d.byValidator)),
+		byDelegator: make(map[ids.ShortID]map[validatorKey]struct{}, len(d.byDelegator)),
+		totals:      maps.Clone(d.totals),
+	}
+	for key, weights := range d.byValidator {
+		c.byValidator[key] = maps.Clone(weights)
+	}
+	for delegatorID, keys := range d.byDelegator {
+		c.byDelegator[delegatorID] = maps.Clone(keys)
+	}
+	return c
+}
+
+// set records [weight] as the delegation of [delegatorID] to [key]
+func (d *delegations) set(key validatorKey, delegatorID ids.ShortID, weight uint64) {
+	old := d.byValidator[key][delegatorID]
+	d.totals[key] = d.totals[key] - old + weight
+	if d.totals[key] == 0 {
+		delete(d.totals, key)
+	}
+
+	if weight == 0 {
+		delete(d.byValidator[key], delegatorID)
+		if len(d.byValidator[key]) == 0 {
+			delete(d.byValidator, key)
+		}
+		delete(d.byDelegator[delegatorID], key)
+		if len(d.byDelegator[delegatorID]) == 0 {
+			delete(d.byDelegator, delegatorID)
+		}
+		return
+	}
+
+	if d.byValidator[key] == nil {
+		d.byValidator[key] = make(map[ids.ShortID]uint64)
+	}
+	d.byValidator[key][delegatorID] = weight
+	if d.byDelegator[delegatorID] == nil {
+		d.byDelegator[delegatorID] = make(map[validatorKey]struct{})
+	}
+	d.byDelegator[delegatorID][key] = struct{}{}
+}
+
+// AddDelegator delegates weight to an existing validator
+func (m *manager) AddDelegator(netID ids.ID, nodeID ids.NodeID, delegatorID ids.ShortID, weight uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	notify, err := m.addDelegator(netID, nodeID, delegatorID, weight)
+	if err != nil {
+		return err
+	}
+	m.notify(notify)
+	return nil
+}
+
+// addDelegator is AddDelegator without locking or notifying callback
+// listeners. It returns the notification of the change, if any. It assumes
+// the lock is held.
+func (m *manager) addDelegator(netID ids.ID, nodeID ids.NodeID, delegatorID ids.ShortID, weight uint64) (func(ManagerCallbackListener), error) {
+	if err := m.requireThawed(netID); err != nil {
+		return nil, err
+	}
+	if err := m.requireWeightMode(netID, WeightModeUint64); err != nil {
+		return nil, err
+	}
+	val, exists := m.validators[netID][nodeID]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s in %s", ErrUnknownValidator, nodeID, netID)
+	}
+	if weight == 0 {
+		return nil, nil
+	}
+
+	key := validatorKey{netID: netID, nodeID: nodeID}
+	oldWeight := m.delegations.byValidator[key][delegatorID]
+	newWeight, err := math.Add64(oldWeight, weight)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+	}
+	newLight, err := math.Add64(val.Light, weight)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+	}
+	newTotal, err := math.Add64(val.Weight, weight)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+	}
+
+	oldLight := val.Light
+	val.Light = newLight
+	val.Weight = newTotal
+	m.bumpSequence(netID, val)
+	m.delegations.set(key, delegatorID, newWeight)
+
+	for _, listener := range m.delegationListeners {
+		listener.OnDelegationChanged(netID, nodeID, delegatorID, oldWeight, newWeight)
+	}
+	return func(listener ManagerCallbackListener) {
+		listener.OnValidatorLightChanged(netID, nodeID, oldLight, newLight)
+	}, nil
+}
+
+// RemoveDelegator removes delegated weight from a validator
+func (m *manager) RemoveDelegator(netID ids.ID, nodeID ids.NodeID, delegatorID ids.ShortID, weight uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.requireThawed(netID); err != nil {
+		return err
+	}
+
+	val, exists := m.validators[netID][nodeID]
+	if !exists {
+		return fmt.Errorf("%w: %s in %s", ErrUnknownValidator, nodeID, netID)
+	}
+
+	key := validatorKey{netID: netID, nodeID: nodeID}
+	oldWeight := m.delegations.byValidator[key][delegatorID]
+	removed := min(weight, oldWeight)
+	if removed == 0 {
+		return nil
+	}
+
+	newWeight := oldWeight - removed
+	oldLight := val.Light
+	val.Light -= min(removed, val.Light)
+	val.Weight -= removed
+	m.bumpSequence(netID, val)
+	m.delegations.set(key, delegatorID, newWeight)
+
+	for _, listener := range m.delegationListeners {
+		listener.OnDelegationChanged(netID, nodeID, delegatorID, oldWeight, newWeight)
+	}
+	newLight := val.Light
+	m.notify(func(listener ManagerCallbackListener) {
+		listener.OnValidatorLightChanged(netID, nodeID, oldLight, newLight)
+	})
+	return nil
+}
+
+// removeDelegations drops every delegation to a validator that is being
+// removed. It assumes the lock is held.
+func (m *manager) removeDelegations(netID ids.ID, nodeID ids.NodeID) {
+	key := validatorKey{netID: netID, nodeID: nodeID}
+	for delegatorID, weight := range m.delegations.byValidator[key] {
+		m.delegations.set(key, delegatorID, 0)
+		for _, listener := range m.delegationListeners {
+			listener.OnDelegationChanged(netID, nodeID, delegatorID, weight, 0)
+		}
+	}
+}
+
+// GetDelegatedWeight returns the total weight delegated to a validator
+func (m *manager) GetDelegatedWeight(netID ids.ID, nodeID ids.NodeID) uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.delegations.totals[validatorKey{netID: netID, nodeID: nodeID}]
+}
+
+// GetSelfStake returns the weight of a validator that isn't delegated or
+// derived from asset stakes
+func (m *manager) GetSelfStake(netID ids.ID, nodeID ids.NodeID) uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	val, exists := m.validators[netID][nodeID]
+	if !exists {
+		return 0
+	}
+	return val.Weight - m.externalWeight(validatorKey{netID: netID, nodeID: nodeID})
+}
+
+// GetDelegations returns a copy of the delegations to a validator
+func (m *manager) GetDelegations(netID ids.ID, nodeID ids.NodeID) map[ids.ShortID]uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	delegations := m.delegations.byValidator[validatorKey{netID: netID, nodeID: nodeID}]
+	if delegations == nil {
+		return make(map[ids.ShortID]uint64)
+	}
+	return maps.Clone(delegations)
+}
+
+// GetDelegatorPositions returns every delegation made by a delegator
+func (m *manager) GetDelegatorPositions(delegatorID ids.ShortID) []DelegatorPosition {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := m.delegations.byDelegator[delegatorID]
+	positions := make([]DelegatorPosition, 0, len(keys))
+	for key := range keys {
+		positions = append(positions, DelegatorPosition{
+			NetID:  key.netID,
+			NodeID: key.nodeID,
+			Weight: m.delegations.byValidator[key][delegatorID],
+		})
+	}
+	return positions
+}
+
+// RegisterDelegationListener registers a delegation listener
+func (m *manager) RegisterDelegationListener(listener DelegationListener) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.delegationListeners = append(m.delegationListeners, listener)
+}
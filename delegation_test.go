@@ -0,0 +1,154 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"math"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+type delegationEvent struct {
+	netID       ids.ID
+	nodeID      ids.NodeID
+	delegatorID ids.ShortID
+	oldWeight   uint64
+	newWeight   uint64
+}
+
+type testDelegationListener struct {
+	events []delegationEvent
+}
+
+func (l *testDelegationListener) OnDelegationChanged(netID ids.ID, nodeID ids.NodeID, delegatorID ids.ShortID, oldWeight, newWeight uint64) {
+	l.events = append(l.events, delegationEvent{netID, nodeID, delegatorID, oldWeight, newWeight})
+}
+
+// TestManagerAddDelegator tests that delegations contribute to effective light
+func TestManagerAddDelegator(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	delegatorID := ids.GenerateTestShortID()
+
+	listener := &testDelegationListener{}
+	m.RegisterDelegationListener(listener)
+
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 1000))
+	require.NoError(m.AddDelegator(netID, nodeID, delegatorID, 300))
+	require.NoError(m.AddDelegator(netID, nodeID, delegatorID, 200))
+
+	require.Equal(uint64(1500), m.GetLight(netID, nodeID))
+	require.Equal(uint64(500), m.GetDelegatedWeight(netID, nodeID))
+	require.Equal(uint64(1000), m.GetSelfStake(netID, nodeID))
+	require.Equal(map[ids.ShortID]uint64{delegatorID: 500}, m.GetDelegations(netID, nodeID))
+	require.Equal([]DelegatorPosition{{NetID: netID, NodeID: nodeID, Weight: 500}}, m.GetDelegatorPositions(delegatorID))
+	require.Equal([]delegationEvent{
+		{netID, nodeID, delegatorID, 0, 300},
+		{netID, nodeID, delegatorID, 300, 500},
+	}, listener.events)
+}
+
+// TestManagerAddDelegatorUnknownValidator tests delegating to a missing validator
+func TestManagerAddDelegatorUnknownValidator(t *testing.T) {
+	m := NewManager()
+	err := m.AddDelegator(ids.GenerateTestID(), ids.GenerateTestNodeID(), ids.GenerateTestShortID(), 100)
+	require.ErrorIs(t, err, ErrUnknownValidator)
+}
+
+// TestManagerAddDelegatorOverflow tests that delegations can't overflow light
+func TestManagerAddDelegatorOverflow(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, math.MaxUint64))
+
+	err := m.AddDelegator(netID, nodeID, ids.GenerateTestShortID(), 1)
+	require.ErrorIs(err, ErrWeightOverflow)
+	require.Zero(m.GetDelegatedWeight(netID, nodeID))
+}
+
+// TestManagerRemoveDelegator tests partial and full delegation removal
+func TestManagerRemoveDelegator(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	delegatorID := ids.GenerateTestShortID()
+
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 1000))
+	require.NoError(m.AddDelegator(netID, nodeID, delegatorID, 500))
+
+	require.NoError(m.RemoveDelegator(netID, nodeID, delegatorID, 200))
+	require.Equal(uint64(1300), m.GetLight(netID, nodeID))
+	require.Equal(uint64(300), m.GetDelegatedWeight(netID, nodeID))
+
+	// Removing more than delegated only removes the delegation
+	require.NoError(m.RemoveDelegator(netID, nodeID, delegatorID, 1000))
+	require.Equal(uint64(1000), m.GetLight(netID, nodeID))
+	require.Zero(m.GetDelegatedWeight(netID, nodeID))
+	require.Empty(m.GetDelegations(netID, nodeID))
+	require.Empty(m.GetDelegatorPositions(delegatorID))
+}
+
+// TestManagerRemoveWeightKeepsDelegations tests that RemoveWeight only removes self-stake
+func TestManagerRemoveWeightKeepsDelegations(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	delegatorID := ids.GenerateTestShortID()
+
+	listener := &testDelegationListener{}
+	m.RegisterDelegationListener(listener)
+
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 1000))
+	require.NoError(m.AddDelegator(netID, nodeID, delegatorID, 500))
+
+	require.NoError(m.RemoveWeight(netID, nodeID, 400))
+	require.Equal(uint64(1100), m.GetLight(netID, nodeID))
+	require.Equal(uint64(600), m.GetSelfStake(netID, nodeID))
+
+	// Replacing the validator replaces self-stake and keeps delegations
+	require.NoError(m.SetDuplicatePolicy(DuplicateReplace))
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 2000))
+	require.Equal(uint64(2500), m.GetLight(netID, nodeID))
+
+	// Exhausting self-stake removes the validator and its delegations
+	require.NoError(m.RemoveWeight(netID, nodeID, 2000))
+	_, ok := m.GetValidator(netID, nodeID)
+	require.False(ok)
+	require.Zero(m.GetDelegatedWeight(netID, nodeID))
+	require.Empty(m.GetDelegatorPositions(delegatorID))
+	require.Equal(delegationEvent{netID, nodeID, delegatorID, 500, 0}, listener.events[len(listener.events)-1])
+}
+
+// TestManagerDelegatorPositions tests positions across validators and nets
+func TestManagerDelegatorPositions(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID1 := ids.GenerateTestID()
+	netID2 := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	delegatorID := ids.GenerateTestShortID()
+
+	require.NoError(m.AddStaker(netID1, nodeID, nil, ids.Empty, 100))
+	require.NoError(m.AddStaker(netID2, nodeID, nil, ids.Empty, 100))
+	require.NoError(m.AddDelegator(netID1, nodeID, delegatorID, 10))
+	require.NoError(m.AddDelegator(netID2, nodeID, delegatorID, 20))
+
+	require.ElementsMatch([]DelegatorPosition{
+		{NetID: netID1, NodeID: nodeID, Weight: 10},
+		{NetID: netID2, NodeID: nodeID, Weight: 20},
+	}, m.GetDelegatorPositions(delegatorID))
+}
@@ -0,0 +1,241 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math"
+	"github.com/luxfi/math/set"
+)
+
+var ErrInvalidDiff = errors.New("invalid validator diff")
+
+// DiffManager applies a batch of changes to a net atomically
+type DiffManager interface {
+	// ApplyDiff applies every change of [diff] to [netID], or none of them
+	ApplyDiff(netID ids.ID, diff ValidatorDiff) error
+}
+
+var _ DiffManager = (*manager)(nil)
+
+// ValidatorDiff is a batch of changes to the validators of a net, such as
+// the changes made by one block. Each node may appear once in the diff.
+type ValidatorDiff struct {
+	// Removed validators are removed regardless of their weight, as by
+	// RemoveStaker
+	Removed []ids.NodeID
+	// Added validators are added as by AddStaker, including the duplicate
+	// policy for validators already in the net
+	Added []ValidatorAddition
+	// WeightChanges change the self-stake of existing validators, as by
+	// AddWeight and RemoveWeight
+	WeightChanges []WeightChange
+}
+
+// ValidatorAddition adds a validator with Light self-stake
+type ValidatorAddition struct {
+	NodeID    ids.NodeID
+	PublicKey []byte
+	TxID      ids.ID
+	Light     uint64
+}
+
+// WeightChange adds Light to the self-stake of a validator, or removes it if
+// Decrease is set
+type WeightChange struct {
+	NodeID   ids.NodeID
+	Light    uint64
+	Decrease bool
+}
+
+// ApplyDiff applies [diff] to [netID] under a single lock acquisition. The
+// whole diff is checked before anything changes, so either every change is
+// applied or none is. Removals are applied first, then additions, then
+// weight changes, and listeners are notified in that order once the diff is
+// fully applied. Each change is counted by MetricOperations as the call it
+// mirrors.
+func (m *manager) ApplyDiff(netID ids.ID, diff ValidatorDiff) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.verifyDiff(netID, diff); err != nil {
+		return err
+	}
+
+	var notify []func(ManagerCallbackListener)
+	for _, nodeID := range diff.Removed {
+		light := m.validators[netID][nodeID].Light
+		m.evictValidator(netID, nodeID)
+		m.metrics.operation(OpRemoveStaker)
+		notify = append(notify, func(listener ManagerCallbackListener) {
+			listener.OnValidatorRemoved(netID, nodeID, light)
+		})
+	}
+	for _, addition := range diff.Added {
+		m.metrics.operation(OpAddStaker)
+		if val, exists := m.validators[netID][addition.NodeID]; exists {
+			notify = append(notify, m.addDuplicate(netID, val, addition.PublicKey, addition.TxID, addition.Light))
+			continue
+		}
+		light := m.putStaker(netID, addition.NodeID, addition.PublicKey, addition.TxID, addition.Light)
+		notify = append(notify, func(listener ManagerCallbackListener) {
+			listener.OnValidatorAdded(netID, addition.NodeID, light)
+		})
+	}
+	for _, change := range diff.WeightChanges {
+		val := m.validators[netID][change.NodeID]
+		oldLight := val.Light
+		if !change.Decrease {
+			m.metrics.operation(OpAddWeight)
+			val.Light += change.Light
+			val.Weight += change.Light
+			m.bumpSequence(netID, val)
+		} else {
+			m.metrics.operation(OpRemoveWeight)
+			if m.removeSelfStake(netID, val, change.Light) {
+				notify = append(notify, func(listener ManagerCallbackListener) {
+					listener.OnValidatorRemoved(netID, change.NodeID, oldLight)
+				})
+				continue
+			}
+		}
+		newLight := val.Light
+		notify = append(notify, func(listener ManagerCallbackListener) {
+			listener.OnValidatorLightChanged(netID, change.NodeID, oldLight, newLight)
+		})
+	}
+	m.notify(notifyAll(notify))
+	return nil
+}
+
+// verifyDiff returns an error if [diff] can't be fully applied to [netID].
+// It assumes the lock is held.
+func (m *manager) verifyDiff(netID ids.ID, diff ValidatorDiff) error {
+	if err := m.requireThawed(netID); err != nil {
+		return err
+	}
+	if err := m.requireWeightMode(netID, WeightModeUint64); err != nil {
+		return err
+	}
+
+	var (
+		vdrs    = m.validators[netID]
+		nodeIDs = set.NewSet[ids.NodeID](len(diff.Removed) + len(diff.Added) + len(diff.WeightChanges))
+		visit   = func(nodeID ids.NodeID) error {
+			if nodeIDs.Contains(nodeID) {
+				return fmt.Errorf("%w: %s appears more than once", ErrInvalidDiff, nodeID)
+			}
+			nodeIDs.Add(nodeID)
+			return nil
+		}
+	)
+	for _, nodeID := range diff.Removed {
+		if err := visit(nodeID); err != nil {
+			return err
+		}
+		if _, exists := vdrs[nodeID]; !exists {
+			return fmt.Errorf("%w: can't remove %s from %s", ErrUnknownValidator, nodeID, netID)
+		}
+	}
+
+	var added int
+	for _, addition := range diff.Added {
+		if err := visit(addition.NodeID); err != nil {
+			return err
+		}
+		if err := m.requireAllowed(netID, addition.NodeID); err != nil {
+			return err
+		}
+		if val, exists := vdrs[addition.NodeID]; exists {
+			if err := m.verifyDuplicate(netID, val, addition.Light); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := m.stakerLight(netID, addition.NodeID, addition.Light); err != nil {
+			return err
+		}
+		added++
+	}
+	if added > 0 {
+		count := len(vdrs) - len(diff.Removed) + added
+		if limit := m.limits.MaxValidatorsPerNet; limit > 0 && count > limit {
+			return fmt.Errorf("%w: net %s would have %d validators", ErrLimitExceeded, netID, count)
+		}
+		if limit := m.limits.MaxNets; limit > 0 && len(vdrs) == 0 && len(m.validators) >= limit {
+			return fmt.Errorf("%w: %d nets", ErrLimitExceeded, len(m.validators))
+		}
+	}
+
+	for _, change := range diff.WeightChanges {
+		if err := visit(change.NodeID); err != nil {
+			return err
+		}
+		val, exists := vdrs[change.NodeID]
+		if !exists {
+			return fmt.Errorf("%w: can't change weight of %s in %s", ErrUnknownValidator, change.NodeID, netID)
+		}
+		if change.Decrease {
+			continue
+		}
+		if _, err := math.Add64(max(val.Light, val.Weight), change.Light); err != nil {
+			return fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+		}
+	}
+	return m.verifyDiffTotal(netID, diff)
+}
+
+// verifyDiffTotal returns an error if the total weight of [netID] would
+// overflow once [diff] is applied. Additions are counted as they're applied,
+// merged into the weight of the validator or replacing it while keeping its
+// external weight. Decreases are left out, so the check only errs on the side
+// of rejecting. It assumes the lock is held, every node appears once in
+// [diff] and each addition was verified.
+func (m *manager) verifyDiffTotal(netID ids.ID, diff ValidatorDiff) error {
+	var (
+		vdrs    = m.validators[netID]
+		removed = set.Of(diff.Removed...)
+		total   uint64
+		err     error
+	)
+	for nodeID, val := range vdrs {
+		if removed.Contains(nodeID) {
+			continue
+		}
+		total, err = math.Add64(total, max(val.Light, val.Weight))
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+		}
+	}
+	for _, addition := range diff.Added {
+		light := addition.Light
+		val, exists := vdrs[addition.NodeID]
+		if !exists || m.duplicatePolicy == DuplicateReplace {
+			if exists {
+				total -= max(val.Light, val.Weight) // Replaced, so no longer counted
+			}
+			light, err = m.stakerLight(netID, addition.NodeID, addition.Light)
+			if err != nil {
+				return err
+			}
+		}
+		total, err = math.Add64(total, light)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+		}
+	}
+	for _, change := range diff.WeightChanges {
+		if change.Decrease {
+			continue
+		}
+		total, err = math.Add64(total, change.Light)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"math"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// TestManagerApplyDiff tests that a diff is applied as a whole
+func TestManagerApplyDiff(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	var (
+		removed   = ids.GenerateTestNodeID()
+		increased = ids.GenerateTestNodeID()
+		drained   = ids.GenerateTestNodeID()
+		added     = ids.GenerateTestNodeID()
+	)
+	require.NoError(m.AddStaker(netID, removed, nil, ids.Empty, 100))
+	require.NoError(m.AddStaker(netID, increased, nil, ids.Empty, 100))
+	require.NoError(m.AddStaker(netID, drained, nil, ids.Empty, 100))
+
+	listener := &testListener{}
+	m.RegisterCallbackListener(listener)
+	listener.added = nil
+
+	require.NoError(m.ApplyDiff(netID, ValidatorDiff{
+		Removed: []ids.NodeID{removed},
+		Added:   []ValidatorAddition{{NodeID: added, Light: 50}},
+		WeightChanges: []WeightChange{
+			{NodeID: increased, Light: 20},
+			{NodeID: drained, Light: 100, Decrease: true},
+		},
+	}))
+	require.ElementsMatch([]ids.NodeID{increased, added}, m.GetValidatorIDs(netID))
+	require.Equal(uint64(120), m.GetLight(netID, increased))
+	require.Equal(uint64(50), m.GetLight(netID, added))
+
+	require.Equal([]validatorEvent{{netID, removed, 100}, {netID, drained, 100}}, listener.removed)
+	require.Equal([]validatorEvent{{netID, added, 50}}, listener.added)
+	require.Equal([]lightChangedEvent{{netID, increased, 100, 120}}, listener.changed)
+}
+
+// TestManagerApplyDiffAtomic tests that an invalid diff changes nothing
+func TestManagerApplyDiffAtomic(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+
+	listener := &testListener{}
+	m.RegisterCallbackListener(listener)
+	listener.added = nil
+
+	tests := []struct {
+		name        string
+		diff        ValidatorDiff
+		expectedErr error
+	}{
+		{
+			name: "duplicate node",
+			diff: ValidatorDiff{
+				Removed: []ids.NodeID{nodeID},
+				Added:   []ValidatorAddition{{NodeID: nodeID, Light: 1}},
+			},
+			expectedErr: ErrInvalidDiff,
+		},
+		{
+			name: "unknown removal",
+			diff: ValidatorDiff{
+				Added:   []ValidatorAddition{{NodeID: ids.GenerateTestNodeID(), Light: 1}},
+				Removed: []ids.NodeID{ids.GenerateTestNodeID()},
+			},
+			expectedErr: ErrUnknownValidator,
+		},
+		{
+			name: "unknown weight change",
+			diff: ValidatorDiff{
+				Added:         []ValidatorAddition{{NodeID: ids.GenerateTestNodeID(), Light: 1}},
+				WeightChanges: []WeightChange{{NodeID: ids.GenerateTestNodeID(), Light: 1}},
+			},
+			expectedErr: ErrUnknownValidator,
+		},
+		{
+			name: "overflow",
+			diff: ValidatorDiff{
+				Added:         []ValidatorAddition{{NodeID: ids.GenerateTestNodeID(), Light: 1}},
+				WeightChanges: []WeightChange{{NodeID: nodeID, Light: math.MaxUint64}},
+			},
+			expectedErr: ErrWeightOverflow,
+		},
+		{
+			name: "total overflow",
+			diff: ValidatorDiff{
+				Added:         []ValidatorAddition{{NodeID: ids.GenerateTestNodeID(), Light: math.MaxUint64 - 100}},
+				WeightChanges: []WeightChange{{NodeID: nodeID, Light: 1}},
+			},
+			expectedErr: ErrWeightOverflow,
+		},
+	}
+	for _, test := range tests {
+		require.ErrorIs(m.ApplyDiff(netID, test.diff), test.expectedErr, test.name)
+	}
+
+	m.SetLimits(Limits{MaxValidatorsPerNet: 1})
+	require.ErrorIs(m.ApplyDiff(netID, ValidatorDiff{
+		Added: []ValidatorAddition{{NodeID: ids.GenerateTestNodeID(), Light: 1}},
+	}), ErrLimitExceeded)
+
+	m.FreezeNet(netID)
+	require.ErrorIs(m.ApplyDiff(netID, ValidatorDiff{}), ErrNetFrozen)
+
+	require.Equal([]ids.NodeID{nodeID}, m.GetValidatorIDs(netID))
+	require.Equal(uint64(100), m.GetLight(netID, nodeID))
+	require.Empty(listener.added)
+	require.Empty(listener.removed)
+	require.Empty(listener.changed)
+
+	// Limits count the validators removed by the same diff
+	require.True(m.ThawNet(netID))
+	replacement := ids.GenerateTestNodeID()
+	require.NoError(m.ApplyDiff(netID, ValidatorDiff{
+		Removed: []ids.NodeID{nodeID},
+		Added:   []ValidatorAddition{{NodeID: replacement, Light: 1}},
+	}))
+	require.Equal([]ids.NodeID{replacement}, m.GetValidatorIDs(netID))
+}
+
+// TestManagerApplyDiffReplaceTotal tests that a replaced validator is counted
+// with the external weight it keeps
+func TestManagerApplyDiffReplaceTotal(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		m        = NewManager()
+		netID    = ids.GenerateTestID()
+		replaced = ids.GenerateTestNodeID()
+		other    = ids.GenerateTestNodeID()
+	)
+	require.NoError(m.SetDuplicatePolicy(DuplicateReplace))
+	require.NoError(m.AddStaker(netID, replaced, nil, ids.Empty, 10))
+	require.NoError(m.AddDelegator(netID, replaced, ids.GenerateTestShortID(), 10))
+	require.NoError(m.AddStaker(netID, other, nil, ids.Empty, math.MaxUint64-20))
+
+	// The new self-stake is one more than the old, on top of the kept
+	// delegation
+	require.ErrorIs(m.ApplyDiff(netID, ValidatorDiff{
+		Added: []ValidatorAddition{{NodeID: replaced, Light: 11}},
+	}), ErrWeightOverflow)
+	require.Equal(uint64(20), m.GetLight(netID, replaced))
+
+	require.NoError(m.ApplyDiff(netID, ValidatorDiff{
+		Added: []ValidatorAddition{{NodeID: replaced, Light: 5}},
+	}))
+	require.Equal(uint64(15), m.GetLight(netID, replaced))
+}
+
+// TestManagerApplyDiffMetrics tests that every change of a diff is counted
+// and the diff is dispatched once
+func TestManagerApplyDiffMetrics(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	registry := newTestRegistry()
+	require.NoError(m.RegisterMetrics(registry))
+
+	var (
+		netID     = ids.GenerateTestID()
+		removed   = ids.GenerateTestNodeID()
+		increased = ids.GenerateTestNodeID()
+		decreased = ids.GenerateTestNodeID()
+	)
+	require.NoError(m.AddStaker(netID, removed, nil, ids.Empty, 100))
+	require.NoError(m.AddStaker(netID, increased, nil, ids.Empty, 100))
+	require.NoError(m.AddStaker(netID, decreased, nil, ids.Empty, 100))
+	observations := len(registry.observations[MetricDispatchDuration])
+
+	require.NoError(m.ApplyDiff(netID, ValidatorDiff{
+		Removed: []ids.NodeID{removed},
+		Added:   []ValidatorAddition{{NodeID: ids.GenerateTestNodeID(), Light: 50}},
+		WeightChanges: []WeightChange{
+			{NodeID: increased, Light: 20},
+			{NodeID: decreased, Light: 20, Decrease: true},
+		},
+	}))
+	require.Equal(map[string]float64{
+		OpAddStaker:    4,
+		OpRemoveStaker: 1,
+		OpAddWeight:    1,
+		OpRemoveWeight: 1,
+	}, registry.values[MetricOperations])
+	require.Len(registry.observations[MetricDispatchDuration], observations+1)
+	require.Equal(map[string]float64{netID.String(): 250}, registry.values[MetricLight])
+}
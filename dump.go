@@ -0,0 +1,582 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math"
+)
+
+var (
+	ErrMalformedDump      = errors.New("malformed validator dump")
+	ErrCorruptDump        = fmt.Errorf("%w: checksum mismatch", ErrMalformedDump)
+	ErrUnsupportedRestore = errors.New("manager can't restore dump")
+)
+
+// CorruptionError is returned by RestoreValidators when a checksum of a dump
+// doesn't match its contents or is missing. It wraps ErrCorruptDump.
+type CorruptionError struct {
+	// NetID is the net whose checksum failed, or ids.Empty if it is the
+	// checksum of the whole dump
+	NetID ids.ID
+	// Expected is the checksum in the dump, or ids.Empty if it is missing
+	Expected ids.ID
+	// Actual is the checksum of the contents read
+	Actual ids.ID
+}
+
+func (e *CorruptionError) Error() string {
+	what := "dump"
+	if e.NetID != ids.Empty {
+		what = "net " + e.NetID.String()
+	}
+	if e.Expected == ids.Empty {
+		return fmt.Sprintf("%s: %s has no checksum", ErrCorruptDump, what)
+	}
+	return fmt.Sprintf("%s: %s has checksum %x, expected %x", ErrCorruptDump, what, e.Actual[:], e.Expected[:])
+}
+
+func (*CorruptionError) Unwrap() error {
+	return ErrCorruptDump
+}
+
+// DumpOrder is the order validators of a net are dumped in
+type DumpOrder uint8
+
+const (
+	// DumpByNodeID orders validators by ascending node ID
+	DumpByNodeID DumpOrder = iota
+	// DumpByLight orders validators by descending light, then node ID
+	DumpByLight
+	// DumpByWeight orders validators by descending weight, then node ID
+	DumpByWeight
+)
+
+// DumpOptions configures DumpValidators
+type DumpOptions struct {
+	// NetIDs are the nets to dump. Nets are always written in ascending ID
+	// order and nets without validators are skipped.
+	NetIDs []ids.ID
+	// Order is the order validators of each net are written in
+	Order DumpOrder
+}
+
+// DumpValidators writes the validators of [opts.NetIDs] in [m] to [w] in a
+// line based text format that RestoreValidators reads back:
+//
+//	net <netID> validators=<count> light=<total> weight=<total> [scale=<scale>] checksum=<hex>
+//		<nodeID> light=<light> weight=<weight> txID=<txID> [publicKey=<hex>] ...
+//	checksum <hex>
+//
+// Light and weight are in stored units. The scale, see WeightScaleManager, is
+// only written for nets whose scale isn't 1.
+//
+// The checksum of a net is the SHA-256 of its header, without the checksum
+// field, and its validator lines, without indentation, each followed by a
+// newline. The last line is the checksum of the whole dump: the SHA-256 of
+// the checksums of its nets in order, so dropped nets and truncation are
+// detected.
+//
+// Optional fields are external, the delegated and asset weight included in
+// light and weight when [m] implements DelegationManager, ringtailPubKey,
+// moniker, website, contact and region, which is repeated once per region
+// tag, and extension, which is repeated once per extension as "<key>=<hex>".
+// Metadata and extension values are quoted.
+//
+// The weights of WeightModeBig nets don't fit the format, so dumping one fails
+// with ErrWrongWeightMode before anything is written.
+func DumpValidators(w io.Writer, m Manager, opts DumpOptions) error {
+	netIDs := slices.Clone(opts.NetIDs)
+	slices.SortFunc(netIDs, func(a, b ids.ID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	netIDs = slices.Compact(netIDs)
+	if bigs, ok := m.(BigWeightManager); ok {
+		for _, netID := range netIDs {
+			if mode := bigs.GetWeightMode(netID); mode != WeightModeUint64 {
+				return fmt.Errorf("%w: %s is in %s mode", ErrWrongWeightMode, netID, mode)
+			}
+		}
+	}
+
+	var (
+		bw   = bufio.NewWriter(w)
+		dump = sha256.New()
+	)
+	for _, netID := range netIDs {
+		vdrs := m.GetMap(netID)
+		if len(vdrs) == 0 {
+			continue
+		}
+
+		list := make([]*GetValidatorOutput, 0, len(vdrs))
+		var totalLight, totalWeight uint64
+		for _, vdr := range vdrs {
+			var err error
+			if totalLight, err = math.Add64(totalLight, vdr.Light); err != nil {
+				return fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+			}
+			if totalWeight, err = math.Add64(totalWeight, vdr.Weight); err != nil {
+				return fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+			}
+			list = append(list, vdr)
+		}
+		slices.SortFunc(list, dumpCompare(opts.Order))
+
+		var header strings.Builder
+		fmt.Fprintf(&header, "net %s validators=%d light=%d weight=%d", netID, len(list), totalLight, totalWeight)
+		if scale := WeightScaleOf(m, netID); scale != 1 {
+			fmt.Fprintf(&header, " scale=%d", scale)
+		}
+		lines := make([]string, len(list))
+		for i, vdr := range list {
+			lines[i] = dumpValidatorLine(vdr, externalWeight(m, netID, vdr))
+		}
+
+		checksum := dumpNetChecksum(header.String(), lines)
+		dump.Write(checksum[:])
+		fmt.Fprintf(bw, "%s checksum=%x\n", header.String(), checksum[:])
+		for _, line := range lines {
+			fmt.Fprintf(bw, "\t%s\n", line)
+		}
+	}
+	fmt.Fprintf(bw, "checksum %x\n", dump.Sum(nil))
+	return bw.Flush()
+}
+
+// dumpNetChecksum returns the checksum of a net with [header] and validator
+// [lines]
+func dumpNetChecksum(header string, lines []string) ids.ID {
+	h := sha256.New()
+	io.WriteString(h, header+"\n")
+	for _, line := range lines {
+		io.WriteString(h, line+"\n")
+	}
+	return ids.ID(h.Sum(nil))
+}
+
+// externalWeight returns the delegated and asset weight of [vdr] in [m], or 0
+// if [m] doesn't implement DelegationManager
+func externalWeight(m Manager, netID ids.ID, vdr *GetValidatorOutput) uint64 {
+	delegations, ok := m.(DelegationManager)
+	if !ok {
+		return 0
+	}
+	return vdr.Weight - min(delegations.GetSelfStake(netID, vdr.NodeID), vdr.Weight)
+}
+
+// dumpValidatorLine returns the line of [vdr] with [external] delegated and
+// asset weight, without indentation or newline
+func dumpValidatorLine(vdr *GetValidatorOutput, external uint64) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s light=%d weight=%d txID=%s", vdr.NodeID, vdr.Light, vdr.Weight, vdr.TxID)
+	if external > 0 {
+		fmt.Fprintf(&b, " external=%d", external)
+	}
+	if len(vdr.PublicKey) > 0 {
+		fmt.Fprintf(&b, " publicKey=%s", hex.EncodeToString(vdr.PublicKey))
+	}
+	if len(vdr.RingtailPubKey) > 0 {
+		fmt.Fprintf(&b, " ringtailPubKey=%s", hex.EncodeToString(vdr.RingtailPubKey))
+	}
+	if md := vdr.Metadata; md != nil {
+		if md.Moniker != "" {
+			fmt.Fprintf(&b, " moniker=%s", strconv.Quote(md.Moniker))
+		}
+		if md.Website != "" {
+			fmt.Fprintf(&b, " website=%s", strconv.Quote(md.Website))
+		}
+		if md.Contact != "" {
+			fmt.Fprintf(&b, " contact=%s", strconv.Quote(md.Contact))
+		}
+		for _, tag := range md.RegionTags {
+			fmt.Fprintf(&b, " region=%s", strconv.Quote(tag))
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(vdr.Extensions)) {
+		fmt.Fprintf(&b, " extension=%s", strconv.Quote(key+"="+hex.EncodeToString(vdr.Extensions[key])))
+	}
+	return b.String()
+}
+
+func dumpCompare(order DumpOrder) func(a, b *GetValidatorOutput) int {
+	return func(a, b *GetValidatorOutput) int {
+		var c int
+		switch order {
+		case DumpByLight:
+			c = cmp.Compare(b.Light, a.Light)
+		case DumpByWeight:
+			c = cmp.Compare(b.Weight, a.Weight)
+		}
+		if c != 0 {
+			return c
+		}
+		return bytes.Compare(a.NodeID[:], b.NodeID[:])
+	}
+}
+
+// dumpedNet is a net read back from a dump
+type dumpedNet struct {
+	netID      ids.ID
+	count      int
+	scale      uint64
+	validators []*dumpedValidator
+	// checksum is the checksum in the header, if any, and hash hashes the
+	// lines read
+	checksum *ids.ID
+	hash     hash.Hash
+}
+
+// RestoreValidators reads a dump written by DumpValidators from [r] and adds
+// its validators to [m]. Blank lines and lines starting with '#' are ignored.
+//
+// The whole dump is parsed and then restored in a single transaction, so a
+// malformed or truncated dump, or a validator [m] rejects, such as one its
+// duplicate policy, access lists or limits don't allow, leaves [m] untouched.
+// [m] must implement TxManager.
+//
+// If a net doesn't match its checksum, or the dump is missing any checksum, a
+// *CorruptionError naming the net is returned.
+//
+// Dumps only carry the total of a validator's delegated and asset weight, and
+// Manager has no way to set Ringtail keys, so validators with either fail
+// with ErrUnsupportedRestore rather than being restored without them.
+func RestoreValidators(r io.Reader, m Manager) error {
+	nets, err := parseDump(r)
+	if err != nil {
+		return err
+	}
+	for _, net := range nets {
+		for _, vdr := range net.validators {
+			if vdr.external > 0 {
+				return fmt.Errorf("%w: %s in %s has %d delegated or asset weight", ErrUnsupportedRestore, vdr.NodeID, net.netID, vdr.external)
+			}
+			if len(vdr.RingtailPubKey) > 0 {
+				return fmt.Errorf("%w: %s in %s has a Ringtail key", ErrUnsupportedRestore, vdr.NodeID, net.netID)
+			}
+		}
+	}
+
+	txManager, ok := m.(TxManager)
+	if !ok {
+		return fmt.Errorf("%w: manager doesn't support transactions", ErrUnsupportedRestore)
+	}
+	err = txManager.WithTransaction(func(tx *Tx) error {
+		for _, net := range nets {
+			if err := restoreNet(tx, net); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't restore dump: %w", err)
+	}
+	return nil
+}
+
+// restoreNet stages the restoration of [net] on [tx]
+func restoreNet(tx *Tx, net *dumpedNet) error {
+	if net.scale != 1 {
+		if err := tx.SetWeightScale(net.netID, net.scale); err != nil {
+			return err
+		}
+	}
+	for _, vdr := range net.validators {
+		if err := tx.AddStaker(net.netID, vdr.NodeID, vdr.PublicKey, vdr.TxID, vdr.Light); err != nil {
+			return err
+		}
+		if vdr.Weight != vdr.Light {
+			if err := tx.SetEconomicWeight(net.netID, vdr.NodeID, vdr.Weight); err != nil {
+				return err
+			}
+		}
+		if vdr.Metadata != nil {
+			if err := tx.SetMetadata(net.netID, vdr.NodeID, vdr.Metadata); err != nil {
+				return err
+			}
+		}
+		for _, key := range slices.Sorted(maps.Keys(vdr.Extensions)) {
+			if err := tx.SetExtension(net.netID, vdr.NodeID, key, vdr.Extensions[key]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func parseDump(r io.Reader) ([]*dumpedNet, error) {
+	var (
+		nets    []*dumpedNet
+		current *dumpedNet
+		scanner = bufio.NewScanner(r)
+		lineNum int
+		trailer *ids.ID
+	)
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if trailer != nil {
+			return nil, fmt.Errorf("%w: line %d: content after dump checksum", ErrMalformedDump, lineNum)
+		}
+
+		if checksum, isTrailer := strings.CutPrefix(line, "checksum "); isTrailer {
+			if err := current.verify(); err != nil {
+				return nil, err
+			}
+			expected, err := parseDumpChecksum(checksum)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %w", ErrMalformedDump, lineNum, err)
+			}
+			trailer = &expected
+			continue
+		}
+
+		header, isNet := strings.CutPrefix(line, "net ")
+		if isNet {
+			line = header
+		}
+		head, fields, err := splitDumpLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrMalformedDump, lineNum, err)
+		}
+		if isNet {
+			if err := current.verify(); err != nil {
+				return nil, err
+			}
+			current, err = parseDumpNet(head, fields)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %w", ErrMalformedDump, lineNum, err)
+			}
+			// The checksum covers the header as it was before the checksum
+			// field was appended
+			hashed := "net " + header
+			if current.checksum != nil {
+				hashed = strings.Replace(hashed, fmt.Sprintf(" checksum=%x", current.checksum[:]), "", 1)
+			}
+			current.hash.Write([]byte(hashed + "\n"))
+			nets = append(nets, current)
+			continue
+		}
+
+		if current == nil {
+			return nil, fmt.Errorf("%w: line %d: validator before net header", ErrMalformedDump, lineNum)
+		}
+		vdr, err := parseDumpValidator(head, fields)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrMalformedDump, lineNum, err)
+		}
+		current.hash.Write([]byte(line + "\n"))
+		current.validators = append(current.validators, vdr)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := current.verify(); err != nil {
+		return nil, err
+	}
+	// Every net and the whole dump must have a checksum
+	dump := sha256.New()
+	for _, net := range nets {
+		if net.checksum == nil {
+			return nil, &CorruptionError{NetID: net.netID, Actual: net.sum()}
+		}
+		dump.Write(net.checksum[:])
+	}
+	actual := ids.ID(dump.Sum(nil))
+	if trailer == nil {
+		return nil, &CorruptionError{Actual: actual}
+	}
+	if *trailer != actual {
+		return nil, &CorruptionError{Expected: *trailer, Actual: actual}
+	}
+	return nets, nil
+}
+
+// verify returns an error if the net is missing validators, which happens
+// when a dump is truncated, or doesn't match its checksum
+func (n *dumpedNet) verify() error {
+	if n == nil {
+		return nil
+	}
+	if len(n.validators) != n.count {
+		return fmt.Errorf("%w: net %s has %d validators, header says %d", ErrMalformedDump, n.netID, len(n.validators), n.count)
+	}
+	if actual := n.sum(); n.checksum != nil && *n.checksum != actual {
+		return &CorruptionError{NetID: n.netID, Expected: *n.checksum, Actual: actual}
+	}
+	return nil
+}
+
+// sum returns the checksum of the lines of the net read so far
+func (n *dumpedNet) sum() ids.ID {
+	return ids.ID(n.hash.Sum(nil))
+}
+
+func parseDumpChecksum(value string) (ids.ID, error) {
+	b, err := hex.DecodeString(value)
+	if err != nil || len(b) != len(ids.ID{}) {
+		return ids.Empty, fmt.Errorf("bad checksum %q", value)
+	}
+	return ids.ID(b), nil
+}
+
+// dumpField is a key=value pair of a dump line
+type dumpField struct {
+	key, value string
+}
+
+// splitDumpLine splits [line] into its leading word and key=value fields.
+// Values starting with '"' are Go quoted strings.
+func splitDumpLine(line string) (string, []dumpField, error) {
+	head, rest, _ := strings.Cut(line, " ")
+	var fields []dumpField
+	for {
+		rest = strings.TrimLeft(rest, " \t")
+		if rest == "" {
+			return head, fields, nil
+		}
+		key, value, ok := strings.Cut(rest, "=")
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return "", nil, fmt.Errorf("expected key=value at %q", rest)
+		}
+		if strings.HasPrefix(value, `"`) {
+			quoted, err := strconv.QuotedPrefix(value)
+			if err != nil {
+				return "", nil, fmt.Errorf("bad quoted value of %s: %w", key, err)
+			}
+			unquoted, err := strconv.Unquote(quoted)
+			if err != nil {
+				return "", nil, fmt.Errorf("bad quoted value of %s: %w", key, err)
+			}
+			fields = append(fields, dumpField{key: key, value: unquoted})
+			rest = value[len(quoted):]
+			continue
+		}
+		value, rest, _ = strings.Cut(value, " ")
+		fields = append(fields, dumpField{key: key, value: value})
+	}
+}
+
+func parseDumpNet(head string, fields []dumpField) (*dumpedNet, error) {
+	netID, err := ids.FromString(head)
+	if err != nil {
+		return nil, fmt.Errorf("bad net ID %q: %w", head, err)
+	}
+	net := &dumpedNet{netID: netID, count: -1, scale: 1, hash: sha256.New()}
+	for _, field := range fields {
+		switch field.key {
+		case "validators":
+			count, err := strconv.Atoi(field.value)
+			if err != nil || count < 0 {
+				return nil, fmt.Errorf("bad validator count %q", field.value)
+			}
+			net.count = count
+		case "light", "weight":
+			// Totals are informational
+		case "scale":
+			scale, err := strconv.ParseUint(field.value, 10, 64)
+			if err != nil || scale == 0 {
+				return nil, fmt.Errorf("bad scale %q", field.value)
+			}
+			net.scale = scale
+		case "checksum":
+			checksum, err := parseDumpChecksum(field.value)
+			if err != nil {
+				return nil, err
+			}
+			net.checksum = &checksum
+		default:
+			return nil, fmt.Errorf("unknown net field %q", field.key)
+		}
+	}
+	if net.count < 0 {
+		return nil, errors.New("net header is missing validators")
+	}
+	return net, nil
+}
+
+// dumpedValidator is a validator read back from a dump
+type dumpedValidator struct {
+	GetValidatorOutput
+	// external is the delegated and asset weight included in Light and
+	// Weight
+	external uint64
+}
+
+func parseDumpValidator(head string, fields []dumpField) (*dumpedValidator, error) {
+	nodeID, err := ids.NodeIDFromString(head)
+	if err != nil {
+		return nil, fmt.Errorf("bad node ID %q: %w", head, err)
+	}
+	vdr := &dumpedValidator{GetValidatorOutput: GetValidatorOutput{NodeID: nodeID}}
+	metadata := &ValidatorMetadata{}
+	var hasLight, hasWeight, hasMetadata bool
+	for _, field := range fields {
+		switch field.key {
+		case "light":
+			vdr.Light, err = strconv.ParseUint(field.value, 10, 64)
+			hasLight = true
+		case "weight":
+			vdr.Weight, err = strconv.ParseUint(field.value, 10, 64)
+			hasWeight = true
+		case "external":
+			vdr.external, err = strconv.ParseUint(field.value, 10, 64)
+		case "txID":
+			vdr.TxID, err = ids.FromString(field.value)
+		case "publicKey":
+			vdr.PublicKey, err = hex.DecodeString(field.value)
+		case "ringtailPubKey":
+			vdr.RingtailPubKey, err = hex.DecodeString(field.value)
+		case "moniker":
+			metadata.Moniker, hasMetadata = field.value, true
+		case "website":
+			metadata.Website, hasMetadata = field.value, true
+		case "contact":
+			metadata.Contact, hasMetadata = field.value, true
+		case "region":
+			metadata.RegionTags, hasMetadata = append(metadata.RegionTags, field.value), true
+		case "extension":
+			// Keys may contain '=' but hex values can't
+			i := strings.LastIndexByte(field.value, '=')
+			if i < 0 {
+				err = errors.New("missing '='")
+				break
+			}
+			if vdr.Extensions == nil {
+				vdr.Extensions = make(map[string][]byte)
+			}
+			vdr.Extensions[field.value[:i]], err = hex.DecodeString(field.value[i+1:])
+		default:
+			return nil, fmt.Errorf("unknown validator field %q", field.key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("bad %s %q: %w", field.key, field.value, err)
+		}
+	}
+	if !hasLight || !hasWeight {
+		return nil, fmt.Errorf("validator %s is missing light or weight", nodeID)
+	}
+	if hasMetadata {
+		vdr.Metadata = metadata
+	}
+	return vdr, nil
+}
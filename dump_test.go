@@ -0,0 +1,299 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDumpRestoreValidators tests that a dump restores to an identical manager
+func TestDumpRestoreValidators(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netIDs := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()}
+	for i, netID := range netIDs {
+		for j := 0; j < 3; j++ {
+			nodeID := ids.GenerateTestNodeID()
+			require.NoError(m.AddStaker(netID, nodeID, []byte{byte(i), byte(j)}, ids.GenerateTestID(), uint64(100*(j+1))))
+		}
+	}
+	nodeID := m.GetValidatorIDs(netIDs[0])[0]
+	require.NoError(m.SetEconomicWeight(netIDs[0], nodeID, 1_000))
+	require.NoError(m.SetMetadata(netIDs[0], nodeID, &ValidatorMetadata{
+		Moniker:    `lux "zero"`,
+		Website:    "https://lux.network",
+		RegionTags: []string{"eu west", "bare-metal"},
+	}))
+	require.NoError(m.SetExtension(netIDs[0], nodeID, "evm=address", []byte{0xde, 0xad}))
+	require.NoError(m.SetExtension(netIDs[0], nodeID, "empty", []byte{}))
+
+	var dump bytes.Buffer
+	require.NoError(DumpValidators(&dump, m, DumpOptions{NetIDs: netIDs}))
+
+	restored := NewManager()
+	require.NoError(RestoreValidators(bytes.NewReader(dump.Bytes()), restored))
+	for _, netID := range netIDs {
+		// Sequences are local to each manager
+		expected, actual := m.GetMap(netID), restored.GetMap(netID)
+		for nodeID := range expected {
+			expected[nodeID].Sequence = 0
+		}
+		for nodeID := range actual {
+			actual[nodeID].Sequence = 0
+		}
+		require.Equal(expected, actual)
+	}
+
+	// Dumps are stable
+	var again bytes.Buffer
+	require.NoError(DumpValidators(&again, restored, DumpOptions{NetIDs: netIDs}))
+	require.Equal(dump.String(), again.String())
+}
+
+// TestDumpValidatorsOptions tests net filtering and validator ordering
+func TestDumpValidatorsOptions(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID, otherNetID := ids.GenerateTestID(), ids.GenerateTestID()
+	light := []uint64{200, 300, 100}
+	nodeIDs := make([]ids.NodeID, len(light))
+	for i := range light {
+		nodeIDs[i] = ids.GenerateTestNodeID()
+		require.NoError(m.AddStaker(netID, nodeIDs[i], nil, ids.Empty, light[i]))
+	}
+	require.NoError(m.AddStaker(otherNetID, ids.GenerateTestNodeID(), nil, ids.Empty, 1))
+
+	var dump bytes.Buffer
+	require.NoError(DumpValidators(&dump, m, DumpOptions{
+		NetIDs: []ids.ID{netID, ids.GenerateTestID()},
+		Order:  DumpByLight,
+	}))
+
+	lines := strings.Split(strings.TrimSpace(dump.String()), "\n")
+	require.Len(lines, 5)
+	require.True(strings.HasPrefix(lines[0], "net "+netID.String()+" validators=3 light=600 weight=600 checksum="))
+	for i, index := range []int{1, 0, 2} {
+		require.True(strings.HasPrefix(lines[i+1], "\t"+nodeIDs[index].String()+" "))
+	}
+	require.True(strings.HasPrefix(lines[4], "checksum "))
+	require.NotContains(dump.String(), otherNetID.String())
+}
+
+// TestRestoreValidatorsMalformed tests that bad dumps leave the manager untouched
+func TestRestoreValidatorsMalformed(t *testing.T) {
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	header := "net " + netID.String() + " validators=2 light=2 weight=2\n"
+	validator := "\t" + nodeID.String() + " light=1 weight=1 txID=" + ids.Empty.String() + "\n"
+
+	tests := []struct {
+		name string
+		dump string
+	}{
+		{
+			name: "truncated",
+			dump: header + validator,
+		},
+		{
+			name: "validator before header",
+			dump: validator + header,
+		},
+		{
+			name: "unknown field",
+			dump: "net " + netID.String() + " validators=1\n\t" + nodeID.String() + " light=1 weight=1 stake=1\n",
+		},
+		{
+			name: "missing weight",
+			dump: "net " + netID.String() + " validators=1\n\t" + nodeID.String() + " light=1\n",
+		},
+		{
+			name: "unterminated quote",
+			dump: "net " + netID.String() + " validators=1\n\t" + nodeID.String() + ` light=1 weight=1 moniker="lux` + "\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			m := NewManager()
+			err := RestoreValidators(strings.NewReader(test.dump), m)
+			require.ErrorIs(err, ErrMalformedDump)
+			require.Zero(m.Count(netID))
+		})
+	}
+}
+
+// TestRestoreValidatorsCorrupt tests that dumps not matching their checksums
+// are rejected with the net that failed
+func TestRestoreValidatorsCorrupt(t *testing.T) {
+	require := require.New(t)
+
+	source := NewManager()
+	netID, otherNetID := ids.GenerateTestID(), ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(source.AddStaker(netID, nodeID, nil, ids.Empty, 10))
+	require.NoError(source.AddStaker(otherNetID, ids.GenerateTestNodeID(), nil, ids.Empty, 20))
+
+	var buf bytes.Buffer
+	require.NoError(DumpValidators(&buf, source, DumpOptions{NetIDs: []ids.ID{netID, otherNetID}}))
+	dump := buf.String()
+	lines := strings.SplitAfter(dump, "\n")
+	// Nets are dumped in ID order
+	firstNetID := netID
+	if otherNetID.Compare(netID) < 0 {
+		firstNetID = otherNetID
+	}
+
+	tests := []struct {
+		name  string
+		dump  string
+		netID ids.ID
+	}{
+		{
+			name:  "changed light",
+			dump:  strings.Replace(dump, "light=10 ", "light=11 ", 1),
+			netID: netID,
+		},
+		{
+			name:  "missing net checksum",
+			dump:  strings.Split(lines[0], " checksum=")[0] + "\n" + strings.Join(lines[1:], ""),
+			netID: firstNetID,
+		},
+		{
+			name: "no checksums",
+			dump: strings.Split(lines[0], " checksum=")[0] + "\n" + lines[1] +
+				strings.Split(lines[2], " checksum=")[0] + "\n" + lines[3],
+			netID: firstNetID,
+		},
+		{
+			name:  "dropped trailer",
+			dump:  strings.Join(lines[:len(lines)-2], ""),
+			netID: ids.Empty,
+		},
+		{
+			name:  "dropped net",
+			dump:  strings.Join(slices.Concat(lines[:2], lines[4:]), ""),
+			netID: ids.Empty,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			m := NewManager()
+			err := RestoreValidators(strings.NewReader(test.dump), m)
+			require.ErrorIs(err, ErrCorruptDump)
+			require.ErrorIs(err, ErrMalformedDump)
+			var corruptErr *CorruptionError
+			require.ErrorAs(err, &corruptErr)
+			require.Equal(test.netID, corruptErr.NetID)
+			require.Zero(m.Count(netID))
+		})
+	}
+
+	m := NewManager()
+	require.NoError(RestoreValidators(strings.NewReader(dump), m))
+	require.Equal(uint64(10), m.GetLight(netID, nodeID))
+}
+
+// TestRestoreValidatorsAtomic tests that a validator the manager rejects in
+// the middle of a net leaves the manager untouched
+func TestRestoreValidatorsAtomic(t *testing.T) {
+	require := require.New(t)
+
+	source := NewManager()
+	netIDs := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()}
+	slices.SortFunc(netIDs, ids.ID.Compare)
+	nodeIDs := make([]ids.NodeID, 3)
+	for i := range nodeIDs {
+		nodeIDs[i] = ids.GenerateTestNodeID()
+	}
+	slices.SortFunc(nodeIDs, ids.NodeID.Compare)
+	for _, netID := range netIDs {
+		for _, nodeID := range nodeIDs {
+			require.NoError(source.AddStaker(netID, nodeID, nil, ids.Empty, 10))
+		}
+	}
+	require.NoError(source.SetMetadata(netIDs[1], nodeIDs[2], &ValidatorMetadata{Moniker: "last"}))
+
+	var dump bytes.Buffer
+	require.NoError(DumpValidators(&dump, source, DumpOptions{NetIDs: netIDs}))
+
+	// The second validator of the last net is already in the manager, which
+	// rejects duplicates
+	m := NewManager()
+	require.NoError(m.AddStaker(netIDs[1], nodeIDs[1], nil, ids.Empty, 1))
+	err := RestoreValidators(bytes.NewReader(dump.Bytes()), m)
+	require.ErrorIs(err, ErrDuplicateValidator)
+	require.Zero(m.Count(netIDs[0]))
+	require.Equal([]ids.NodeID{nodeIDs[1]}, m.GetValidatorIDs(netIDs[1]))
+	require.Equal(uint64(1), m.GetLight(netIDs[1], nodeIDs[1]))
+
+	require.ErrorIs(RestoreValidators(bytes.NewReader(dump.Bytes()), &mockManager{}), ErrUnsupportedRestore)
+}
+
+// TestRestoreValidatorsUnsupported tests that validators with state dumps
+// don't carry fail to restore instead of losing it
+func TestRestoreValidatorsUnsupported(t *testing.T) {
+	require := require.New(t)
+
+	source := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(source.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+	require.NoError(source.AddDelegator(netID, nodeID, ids.GenerateTestShortID(), 10))
+
+	var dump bytes.Buffer
+	require.NoError(DumpValidators(&dump, source, DumpOptions{NetIDs: []ids.ID{netID}}))
+	require.Contains(dump.String(), " light=110 weight=110 txID="+ids.Empty.String()+" external=10")
+
+	m := NewManager()
+	require.ErrorIs(RestoreValidators(bytes.NewReader(dump.Bytes()), m), ErrUnsupportedRestore)
+	require.Zero(m.Count(netID))
+
+	ringtail := checksumDump("net "+netID.String()+" validators=1", nodeID.String()+" light=1 weight=1 ringtailPubKey=0102")
+	require.ErrorIs(RestoreValidators(strings.NewReader(ringtail), m), ErrUnsupportedRestore)
+	require.Zero(m.Count(netID))
+}
+
+// TestDumpValidatorsBigWeight tests that nets whose weights don't fit the
+// format aren't dumped
+func TestDumpValidatorsBigWeight(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID, bigNetID := ids.GenerateTestID(), ids.GenerateTestID()
+	require.NoError(m.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 1))
+	require.NoError(m.SetWeightMode(bigNetID, WeightModeBig))
+	weight := new(big.Int).Lsh(big.NewInt(1), 70)
+	require.NoError(m.AddStakerBig(bigNetID, ids.GenerateTestNodeID(), nil, ids.Empty, weight))
+
+	var dump bytes.Buffer
+	err := DumpValidators(&dump, m, DumpOptions{NetIDs: []ids.ID{netID, bigNetID}})
+	require.ErrorIs(err, ErrWrongWeightMode)
+	require.Zero(dump.Len())
+}
+
+// checksumDump returns a dump of a single net with [header] and validator
+// [lines], with the checksums DumpValidators writes
+func checksumDump(header string, lines ...string) string {
+	checksum := dumpNetChecksum(header, lines)
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s checksum=%x\n", header, checksum[:])
+	for _, line := range lines {
+		fmt.Fprintf(&b, "\t%s\n", line)
+	}
+	trailer := sha256.Sum256(checksum[:])
+	fmt.Fprintf(&b, "checksum %x\n", trailer[:])
+	return b.String()
+}
@@ -0,0 +1,132 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math"
+)
+
+var ErrUnknownDuplicatePolicy = errors.New("unknown duplicate policy")
+
+// DuplicatePolicy selects what adding a validator that is already in the net
+// does
+type DuplicatePolicy uint8
+
+const (
+	// DuplicateError rejects the addition with ErrDuplicateValidator and
+	// leaves the validator as it was. It is the default.
+	DuplicateError DuplicatePolicy = iota
+	// DuplicateMergeWeight adds the new self-stake to the validator's,
+	// keeping its public key and TxID. Listeners are notified of the light
+	// change.
+	DuplicateMergeWeight
+	// DuplicateReplace replaces the self-stake, public key and TxID of the
+	// validator but keeps its delegations, asset stakes, metadata and
+	// extensions. Listeners are notified of the removal of the old record
+	// and the addition of the new one.
+	DuplicateReplace
+)
+
+// String implements fmt.Stringer
+func (p DuplicatePolicy) String() string {
+	switch p {
+	case DuplicateError:
+		return "error"
+	case DuplicateMergeWeight:
+		return "merge-weight"
+	case DuplicateReplace:
+		return "replace"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(p))
+	}
+}
+
+// DuplicatePolicyManager configures what AddStaker, AddStakerBig, ApplyDiff
+// and transactions do with validators that are already in the net
+type DuplicatePolicyManager interface {
+	SetDuplicatePolicy(policy DuplicatePolicy) error
+	GetDuplicatePolicy() DuplicatePolicy
+}
+
+var _ DuplicatePolicyManager = (*manager)(nil)
+
+// SetDuplicatePolicy sets the duplicate policy of the manager
+func (m *manager) SetDuplicatePolicy(policy DuplicatePolicy) error {
+	if policy > DuplicateReplace {
+		return fmt.Errorf("%w: %s", ErrUnknownDuplicatePolicy, policy)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.duplicatePolicy = policy
+	return nil
+}
+
+// GetDuplicatePolicy returns the duplicate policy of the manager
+func (m *manager) GetDuplicatePolicy() DuplicatePolicy {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.duplicatePolicy
+}
+
+// verifyDuplicate returns an error if [light] can't be added to [val], which
+// is already in [netID]. It assumes the lock is held.
+func (m *manager) verifyDuplicate(netID ids.ID, val *GetValidatorOutput, light uint64) error {
+	if err := checkDuplicate(m.duplicatePolicy, netID, val.NodeID, max(val.Light, val.Weight), light); err != nil {
+		return err
+	}
+	if m.duplicatePolicy == DuplicateReplace {
+		_, err := m.stakerLight(netID, val.NodeID, light)
+		return err
+	}
+	return nil
+}
+
+// checkDuplicate returns an error if [policy] doesn't allow adding [light]
+// to [nodeID], whose larger of light and weight is [current]
+func checkDuplicate(policy DuplicatePolicy, netID ids.ID, nodeID ids.NodeID, current uint64, light uint64) error {
+	switch policy {
+	case DuplicateMergeWeight:
+		if _, err := math.Add64(current, light); err != nil {
+			return fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+		}
+		return nil
+	case DuplicateReplace:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s in %s", ErrDuplicateValidator, nodeID, netID)
+	}
+}
+
+// addDuplicate adds [light] to [val], which is already in [netID], as the
+// duplicate policy selects and returns the notification of what changed. It
+// assumes the lock is held and the addition was verified by verifyDuplicate.
+func (m *manager) addDuplicate(netID ids.ID, val *GetValidatorOutput, publicKey []byte, txID ids.ID, light uint64) func(ManagerCallbackListener) {
+	var (
+		nodeID   = val.NodeID
+		oldLight = val.Light
+	)
+	if m.duplicatePolicy == DuplicateMergeWeight {
+		val.Light += light
+		val.Weight += light
+		m.bumpSequence(netID, val)
+
+		newLight := val.Light
+		return func(listener ManagerCallbackListener) {
+			listener.OnValidatorLightChanged(netID, nodeID, oldLight, newLight)
+		}
+	}
+
+	newLight := m.putStaker(netID, nodeID, publicKey, txID, light)
+	return func(listener ManagerCallbackListener) {
+		listener.OnValidatorRemoved(netID, nodeID, oldLight)
+		listener.OnValidatorAdded(netID, nodeID, newLight)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"math"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// TestManagerDuplicatePolicy tests what re-adding a validator does under each
+// policy and the events it notifies
+func TestManagerDuplicatePolicy(t *testing.T) {
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	txID := ids.GenerateTestID()
+
+	tests := []struct {
+		policy          DuplicatePolicy
+		expectedErr     error
+		expectedLight   uint64
+		expectedTxID    ids.ID
+		expectedAdded   []validatorEvent
+		expectedRemoved []validatorEvent
+		expectedChanged []lightChangedEvent
+	}{
+		{
+			policy:        DuplicateError,
+			expectedErr:   ErrDuplicateValidator,
+			expectedLight: 100,
+			expectedTxID:  txID,
+		},
+		{
+			policy:          DuplicateMergeWeight,
+			expectedLight:   300,
+			expectedTxID:    txID,
+			expectedChanged: []lightChangedEvent{{netID, nodeID, 100, 300}},
+		},
+		{
+			policy:          DuplicateReplace,
+			expectedLight:   200,
+			expectedTxID:    ids.Empty,
+			expectedAdded:   []validatorEvent{{netID, nodeID, 200}},
+			expectedRemoved: []validatorEvent{{netID, nodeID, 100}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.policy.String(), func(t *testing.T) {
+			require := require.New(t)
+
+			m := NewManager()
+			require.Equal(DuplicateError, m.GetDuplicatePolicy())
+			require.NoError(m.SetDuplicatePolicy(test.policy))
+			require.NoError(m.AddStaker(netID, nodeID, []byte{1}, txID, 100))
+
+			listener := &testListener{}
+			m.RegisterCallbackListener(listener)
+			listener.added = nil
+
+			err := m.AddStaker(netID, nodeID, nil, ids.Empty, 200)
+			require.ErrorIs(err, test.expectedErr)
+
+			val, ok := m.GetValidator(netID, nodeID)
+			require.True(ok)
+			require.Equal(test.expectedLight, val.Light)
+			require.Equal(test.expectedTxID, val.TxID)
+			require.Equal(test.expectedAdded, listener.added)
+			require.Equal(test.expectedRemoved, listener.removed)
+			require.Equal(test.expectedChanged, listener.changed)
+		})
+	}
+}
+
+// TestManagerDuplicatePolicyBatches tests that ApplyDiff and transactions
+// follow the duplicate policy
+func TestManagerDuplicatePolicyBatches(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+
+	diff := ValidatorDiff{
+		Added: []ValidatorAddition{{NodeID: nodeID, Light: 50}},
+	}
+	require.ErrorIs(m.ApplyDiff(netID, diff), ErrDuplicateValidator)
+	err := m.WithTransaction(func(tx *Tx) error {
+		return tx.AddStaker(netID, nodeID, nil, ids.Empty, 50)
+	})
+	require.ErrorIs(err, ErrDuplicateValidator)
+	require.Equal(uint64(100), m.GetLight(netID, nodeID))
+
+	require.NoError(m.SetDuplicatePolicy(DuplicateMergeWeight))
+	require.NoError(m.ApplyDiff(netID, diff))
+	require.Equal(uint64(150), m.GetLight(netID, nodeID))
+	err = m.WithTransaction(func(tx *Tx) error {
+		return tx.AddStaker(netID, nodeID, nil, ids.Empty, 50)
+	})
+	require.NoError(err)
+	require.Equal(uint64(200), m.GetLight(netID, nodeID))
+
+	// Merging checks for overflows before anything changes
+	diff.Added[0].Light = math.MaxUint64
+	require.ErrorIs(m.ApplyDiff(netID, diff), ErrWeightOverflow)
+	require.ErrorIs(m.AddStaker(netID, nodeID, nil, ids.Empty, math.MaxUint64), ErrWeightOverflow)
+	require.Equal(uint64(200), m.GetLight(netID, nodeID))
+
+	require.ErrorIs(m.SetDuplicatePolicy(DuplicateReplace+1), ErrUnknownDuplicatePolicy)
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/luxfi/ids"
+)
+
+var (
+	ErrInvalidEpochConfig = errors.New("invalid epoch config")
+	ErrBeforeFirstEpoch   = errors.New("timestamp is before the first epoch")
+	ErrEpochNotSealed     = errors.New("epoch boundary height isn't accepted yet")
+	ErrEpochPruned        = errors.New("epoch boundary height is below the minimum height")
+)
+
+// EpochConfig divides time into fixed length epochs. The validator set of an
+// epoch is frozen at its boundary height: the last accepted height before the
+// epoch started.
+type EpochConfig struct {
+	// Start is when epoch 0 starts
+	Start time.Time
+	// Duration is the length of every epoch
+	Duration time.Duration
+	// BoundaryHeight returns the last height accepted before [boundary]. It is
+	// typically backed by the chain's timestamp index.
+	BoundaryHeight func(ctx context.Context, boundary time.Time) (uint64, error)
+}
+
+// Verify returns an error if the config can't resolve epochs
+func (c *EpochConfig) Verify() error {
+	switch {
+	case c.Duration <= 0:
+		return fmt.Errorf("%w: duration %s must be positive", ErrInvalidEpochConfig, c.Duration)
+	case c.BoundaryHeight == nil:
+		return fmt.Errorf("%w: missing boundary height function", ErrInvalidEpochConfig)
+	}
+	return nil
+}
+
+// Epoch returns the epoch [timestamp] is in. A timestamp exactly on a
+// boundary is in the epoch that starts there.
+func (c *EpochConfig) Epoch(timestamp time.Time) (uint64, error) {
+	if c.Duration <= 0 {
+		return 0, fmt.Errorf("%w: duration %s must be positive", ErrInvalidEpochConfig, c.Duration)
+	}
+	if timestamp.Before(c.Start) {
+		return 0, fmt.Errorf("%w: %s is before %s", ErrBeforeFirstEpoch, timestamp, c.Start)
+	}
+	return uint64(timestamp.Sub(c.Start) / c.Duration), nil
+}
+
+// EpochStart returns the time [epoch] starts at
+func (c *EpochConfig) EpochStart(epoch uint64) time.Time {
+	return c.Start.Add(time.Duration(epoch) * c.Duration)
+}
+
+// boundary returns the epoch containing [timestamp] and its boundary height
+func (c *EpochConfig) boundary(ctx context.Context, timestamp time.Time) (uint64, uint64, error) {
+	epoch, err := c.Epoch(timestamp)
+	if err != nil {
+		return 0, 0, err
+	}
+	height, err := c.BoundaryHeight(ctx, c.EpochStart(epoch))
+	if err != nil {
+		return 0, 0, fmt.Errorf("couldn't get boundary height of epoch %d: %w", epoch, err)
+	}
+	return epoch, height, nil
+}
+
+// ResolveWarpSetForEpoch returns the Warp set of [netID] frozen for the epoch
+// containing [timestamp]. The set is fetched at the boundary height of that
+// epoch, never at the height [timestamp] was observed at, so every node
+// resolving a timestamp in the same epoch gets the same set.
+func ResolveWarpSetForEpoch(ctx context.Context, state State, config *EpochConfig, netID ids.ID, timestamp time.Time) (*WarpSet, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	epoch, height, err := config.boundary(ctx, timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	currentHeight, err := state.GetCurrentHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if height > currentHeight {
+		return nil, fmt.Errorf("%w: epoch %d boundary %d > current height %d", ErrEpochNotSealed, epoch, height, currentHeight)
+	}
+	minHeight, err := state.GetMinimumHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if height < minHeight {
+		return nil, fmt.Errorf("%w: epoch %d boundary %d < minimum height %d", ErrEpochPruned, epoch, height, minHeight)
+	}
+	return state.GetWarpValidatorSet(ctx, height, netID)
+}
@@ -0,0 +1,100 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+	"github.com/luxfi/validators/validatorstest"
+)
+
+// TestEpoch tests that boundary timestamps start a new epoch
+func TestEpoch(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Unix(1_000, 0)
+	config := &validators.EpochConfig{Start: start, Duration: time.Minute}
+
+	_, err := config.Epoch(start.Add(-time.Nanosecond))
+	require.ErrorIs(err, validators.ErrBeforeFirstEpoch)
+
+	tests := []struct {
+		offset time.Duration
+		epoch  uint64
+	}{
+		{offset: 0, epoch: 0},
+		{offset: time.Minute - time.Nanosecond, epoch: 0},
+		{offset: time.Minute, epoch: 1},
+		{offset: 5*time.Minute + time.Second, epoch: 5},
+	}
+	for _, test := range tests {
+		epoch, err := config.Epoch(start.Add(test.offset))
+		require.NoError(err)
+		require.Equal(test.epoch, epoch)
+		require.False(config.EpochStart(epoch).After(start.Add(test.offset)))
+	}
+
+	_, err = (&validators.EpochConfig{}).Epoch(start)
+	require.ErrorIs(err, validators.ErrInvalidEpochConfig)
+}
+
+// TestResolveWarpSetForEpoch tests that the set is fetched at the boundary
+// height of the timestamp's epoch
+func TestResolveWarpSetForEpoch(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	netID := ids.GenerateTestID()
+	start := time.Unix(1_000, 0)
+
+	// One block every 10 seconds from height 0 at [start]
+	var boundaries []time.Time
+	config := &validators.EpochConfig{
+		Start:    start,
+		Duration: time.Minute,
+		BoundaryHeight: func(_ context.Context, boundary time.Time) (uint64, error) {
+			boundaries = append(boundaries, boundary)
+			elapsed := boundary.Sub(start)
+			height := uint64(elapsed / (10 * time.Second))
+			if elapsed%(10*time.Second) == 0 && height > 0 {
+				// The block at the boundary isn't before it
+				height--
+			}
+			return height, nil
+		},
+	}
+
+	var heights []uint64
+	state := validatorstest.NewTestState().SetCurrentHeight(20)
+	state.GetWarpValidatorSetF = func(_ context.Context, height uint64, _ ids.ID) (*validators.WarpSet, error) {
+		heights = append(heights, height)
+		return &validators.WarpSet{Height: height}, nil
+	}
+
+	// Anywhere within epoch 2 resolves to the last block before it started
+	for _, offset := range []time.Duration{2 * time.Minute, 2*time.Minute + 59*time.Second} {
+		warpSet, err := validators.ResolveWarpSetForEpoch(ctx, state, config, netID, start.Add(offset))
+		require.NoError(err)
+		require.Equal(uint64(11), warpSet.Height)
+	}
+	require.Equal([]uint64{11, 11}, heights)
+	require.Equal([]time.Time{start.Add(2 * time.Minute), start.Add(2 * time.Minute)}, boundaries)
+
+	// Epoch 4 starts after the current height
+	_, err := validators.ResolveWarpSetForEpoch(ctx, state, config, netID, start.Add(4*time.Minute))
+	require.ErrorIs(err, validators.ErrEpochNotSealed)
+
+	_, err = validators.ResolveWarpSetForEpoch(ctx, state, config, netID, start.Add(-time.Second))
+	require.ErrorIs(err, validators.ErrBeforeFirstEpoch)
+
+	config.BoundaryHeight = nil
+	_, err = validators.ResolveWarpSetForEpoch(ctx, state, config, netID, start)
+	require.ErrorIs(err, validators.ErrInvalidEpochConfig)
+}
@@ -0,0 +1,137 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package epoching freezes the validator sets used for sampling and quorums
+// at epoch boundaries, while changes to the manager accumulate for the next
+// epoch
+package epoching
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/luxfi/ids"
+
+	validators "github.com/luxfi/validators"
+)
+
+var ErrInvalidConfig = errors.New("invalid epoching config")
+
+// Config configures an Epocher
+type Config struct {
+	// NetIDs are the nets whose sets are frozen
+	NetIDs []ids.ID
+	// Length is the number of heights in an epoch. Epoch e covers heights
+	// [e*Length, (e+1)*Length).
+	Length uint64
+}
+
+// Verify returns an error if the config can't freeze any set
+func (c Config) Verify() error {
+	switch {
+	case len(c.NetIDs) == 0:
+		return fmt.Errorf("%w: no nets", ErrInvalidConfig)
+	case c.Length == 0:
+		return fmt.Errorf("%w: zero length", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// Listener is notified when an epoch starts, with the sets frozen for it
+type Listener interface {
+	OnEpochAdvanced(epoch uint64, sets *validators.MultiNetSnapshot)
+}
+
+// Manager is a validator manager that can freeze several nets together
+type Manager interface {
+	validators.Manager
+	validators.SnapshotManager
+}
+
+// Epocher holds the frozen sets of the current epoch. The sets of every
+// configured net are frozen together, so they are consistent with each
+// other.
+type Epocher struct {
+	manager Manager
+	config  Config
+
+	mu        sync.RWMutex
+	epoch     uint64
+	current   *validators.MultiNetSnapshot
+	listeners []Listener
+}
+
+// New returns an epocher in epoch 0, with the current sets of [manager]
+// frozen for it
+func New(manager Manager, config Config) (*Epocher, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	config.NetIDs = slices.Clone(config.NetIDs)
+	return &Epocher{
+		manager: manager,
+		config:  config,
+		current: manager.MultiNetSnapshot(config.NetIDs),
+	}, nil
+}
+
+// RegisterListener registers [listener] to be notified of every later epoch
+func (e *Epocher) RegisterListener(listener Listener) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.listeners = append(e.listeners, listener)
+}
+
+// OnHeightAccepted advances to the epoch containing [height], freezing the
+// current sets of the manager for it, and returns true if it advanced.
+// Heights in the current or an earlier epoch don't change anything. Epochs
+// that are skipped over are never current.
+func (e *Epocher) OnHeightAccepted(height uint64) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	epoch := height / e.config.Length
+	if epoch <= e.epoch {
+		return false
+	}
+
+	e.epoch = epoch
+	e.current = e.manager.MultiNetSnapshot(e.config.NetIDs)
+	for _, listener := range e.listeners {
+		listener.OnEpochAdvanced(e.epoch, e.current)
+	}
+	return true
+}
+
+// Epoch returns the current epoch
+func (e *Epocher) Epoch() uint64 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.epoch
+}
+
+// CurrentEpochSet returns the set of [netID] frozen for the current epoch
+func (e *Epocher) CurrentEpochSet(netID ids.ID) (*validators.ValidatorSnapshot, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	snapshot, ok := e.current.Net(netID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s isn't epoched", validators.ErrNetNotFound, netID)
+	}
+	return snapshot, nil
+}
+
+// NextEpochSet returns the set of [netID] that would be frozen if the next
+// epoch started now, including every change since the current one started
+func (e *Epocher) NextEpochSet(netID ids.ID) (*validators.ValidatorSnapshot, error) {
+	if !slices.Contains(e.config.NetIDs, netID) {
+		return nil, fmt.Errorf("%w: %s isn't epoched", validators.ErrNetNotFound, netID)
+	}
+	snapshot, _ := e.manager.MultiNetSnapshot([]ids.ID{netID}).Net(netID)
+	return snapshot, nil
+}
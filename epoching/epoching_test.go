@@ -0,0 +1,103 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package epoching
+
+import (
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+type testListener struct {
+	epochs []uint64
+	lights []uint64
+	netID  ids.ID
+}
+
+func (l *testListener) OnEpochAdvanced(epoch uint64, sets *validators.MultiNetSnapshot) {
+	l.epochs = append(l.epochs, epoch)
+	snapshot, _ := sets.Net(l.netID)
+	l.lights = append(l.lights, snapshot.Light())
+}
+
+// TestConfigVerify tests config validation
+func TestConfigVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      Config
+		expectedErr error
+	}{
+		{
+			name:   "valid",
+			config: Config{NetIDs: []ids.ID{ids.GenerateTestID()}, Length: 10},
+		},
+		{
+			name:        "no nets",
+			config:      Config{Length: 10},
+			expectedErr: ErrInvalidConfig,
+		},
+		{
+			name:        "zero length",
+			config:      Config{NetIDs: []ids.ID{ids.GenerateTestID()}},
+			expectedErr: ErrInvalidConfig,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.config.Verify(), test.expectedErr)
+		})
+	}
+}
+
+// TestEpocher tests that sets stay frozen until the next epoch starts
+func TestEpocher(t *testing.T) {
+	require := require.New(t)
+
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	nodeID1, nodeID2 := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID1, nil, ids.Empty, 100))
+
+	e, err := New(m, Config{NetIDs: []ids.ID{netID}, Length: 10})
+	require.NoError(err)
+	listener := &testListener{netID: netID}
+	e.RegisterListener(listener)
+
+	// Changes accumulate for the next epoch
+	require.NoError(m.AddStaker(netID, nodeID2, nil, ids.Empty, 50))
+	current, err := e.CurrentEpochSet(netID)
+	require.NoError(err)
+	require.Equal(1, current.Len())
+	next, err := e.NextEpochSet(netID)
+	require.NoError(err)
+	require.Equal(2, next.Len())
+
+	require.False(e.OnHeightAccepted(9))
+	current, err = e.CurrentEpochSet(netID)
+	require.NoError(err)
+	require.Equal(uint64(100), current.Light())
+
+	// The next epoch freezes the accumulated changes
+	require.True(e.OnHeightAccepted(10))
+	require.Equal(uint64(1), e.Epoch())
+	require.NoError(m.RemoveStaker(netID, nodeID1))
+	current, err = e.CurrentEpochSet(netID)
+	require.NoError(err)
+	require.Equal(uint64(150), current.Light())
+
+	// Skipped epochs are never current
+	require.True(e.OnHeightAccepted(35))
+	require.False(e.OnHeightAccepted(20))
+	require.Equal(uint64(3), e.Epoch())
+	require.Equal([]uint64{1, 3}, listener.epochs)
+	require.Equal([]uint64{150, 50}, listener.lights)
+
+	_, err = e.CurrentEpochSet(ids.GenerateTestID())
+	require.ErrorIs(err, validators.ErrNetNotFound)
+	_, err = e.NextEpochSet(ids.GenerateTestID())
+	require.ErrorIs(err, validators.ErrNetNotFound)
+}
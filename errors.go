@@ -0,0 +1,48 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import "errors"
+
+// The errors below are the categories callers branch on with errors.Is.
+// Errors returned by the Manager, the State wrappers and the subpackages wrap
+// one of them, directly or through a more specific sentinel such as
+// ErrWeightOverflow, so callers never need to match on messages. Errors that
+// don't fit a category, such as ErrNetFrozen or ErrLimitExceeded, are
+// sentinels of their own.
+var (
+	// ErrValidatorNotFound is returned for changes to or lookups of a
+	// validator that isn't in the net
+	ErrValidatorNotFound = errors.New("validator not found")
+	// ErrNetNotFound is returned for lookups of a net that isn't served
+	ErrNetNotFound = errors.New("net not found")
+	// ErrDuplicateValidator is returned when a validator that is already in
+	// the net is added again
+	ErrDuplicateValidator = errors.New("duplicate validator")
+	// ErrInvalidWeight is returned for weights that are out of range
+	ErrInvalidWeight = errors.New("invalid weight")
+)
+
+// NewCategoryError returns a sentinel with message [msg] that matches
+// [category] with errors.Is. Sentinels that predate their category keep their
+// messages this way.
+func NewCategoryError(msg string, category error) error {
+	return &categoryError{
+		msg:      msg,
+		category: category,
+	}
+}
+
+type categoryError struct {
+	msg      string
+	category error
+}
+
+func (e *categoryError) Error() string {
+	return e.msg
+}
+
+func (e *categoryError) Unwrap() error {
+	return e.category
+}
@@ -0,0 +1,45 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestErrorCategories tests that specific errors wrap their category
+func TestErrorCategories(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		category error
+	}{
+		{
+			name:     "unknown validator",
+			err:      ErrUnknownValidator,
+			category: ErrValidatorNotFound,
+		},
+		{
+			name:     "weight overflow",
+			err:      ErrWeightOverflow,
+			category: ErrInvalidWeight,
+		},
+		{
+			name:     "negative weight",
+			err:      ErrNegativeWeight,
+			category: ErrInvalidWeight,
+		},
+		{
+			name:     "weight below delegated",
+			err:      ErrWeightBelowDelegated,
+			category: ErrInvalidWeight,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.err, test.category)
+		})
+	}
+}
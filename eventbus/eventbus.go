@@ -0,0 +1,193 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package eventbus publishes validator lifecycle events to a message bus.
+// Embedders bridge to their bus, such as NATS or Kafka, by implementing
+// Publisher, so this package doesn't depend on any bus client.
+package eventbus
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/luxfi/ids"
+
+	validators "github.com/luxfi/validators"
+)
+
+// Publisher sends [payload] to the subscribers of [topic]
+type Publisher interface {
+	Publish(topic string, payload []byte) error
+}
+
+// Type is the kind of lifecycle event
+type Type string
+
+const (
+	ValidatorAdded            Type = "added"
+	ValidatorRemoved          Type = "removed"
+	ValidatorLightChanged     Type = "light-changed"
+	ValidatorPublicKeyChanged Type = "public-key-changed"
+)
+
+// Event is the JSON payload of a published event. Fields that don't apply to
+// the event type are omitted.
+type Event struct {
+	Type     Type       `json:"type"`
+	NetID    ids.ID     `json:"netID"`
+	NodeID   ids.NodeID `json:"nodeID"`
+	Light    uint64     `json:"light,omitempty"`
+	OldLight uint64     `json:"oldLight,omitempty"`
+	NewLight uint64     `json:"newLight,omitempty"`
+	// OldPublicKey and NewPublicKey are hex encoded
+	OldPublicKey string `json:"oldPublicKey,omitempty"`
+	NewPublicKey string `json:"newPublicKey,omitempty"`
+}
+
+// Config configures an Adapter
+type Config struct {
+	// TopicPrefix is prepended to the event type to form the topic, so added
+	// validators are published to "<TopicPrefix>.added". An empty prefix
+	// publishes to the bare event type.
+	TopicPrefix string
+	// OnError is called with the events that couldn't be published. Listener
+	// callbacks can't fail, so errors are dropped if it is nil.
+	OnError func(event Event, err error)
+}
+
+// Topic returns the topic events of [typ] are published to
+func (c Config) Topic(typ Type) string {
+	if c.TopicPrefix == "" {
+		return string(typ)
+	}
+	return c.TopicPrefix + "." + string(typ)
+}
+
+// Adapter publishes the events it is notified of. Register it with
+// Manager.RegisterCallbackListener, and with
+// KeyRotationManager.RegisterPublicKeyListener to publish key rotations.
+type Adapter struct {
+	publisher Publisher
+	config    Config
+}
+
+var (
+	_ validators.ManagerCallbackListener = (*Adapter)(nil)
+	_ validators.PublicKeyListener       = (*Adapter)(nil)
+)
+
+// New returns an adapter publishing to [publisher]
+func New(publisher Publisher, config Config) *Adapter {
+	return &Adapter{
+		publisher: publisher,
+		config:    config,
+	}
+}
+
+func (a *Adapter) OnValidatorAdded(netID ids.ID, nodeID ids.NodeID, light uint64) {
+	a.publish(Event{
+		Type:   ValidatorAdded,
+		NetID:  netID,
+		NodeID: nodeID,
+		Light:  light,
+	})
+}
+
+func (a *Adapter) OnValidatorRemoved(netID ids.ID, nodeID ids.NodeID, light uint64) {
+	a.publish(Event{
+		Type:   ValidatorRemoved,
+		NetID:  netID,
+		NodeID: nodeID,
+		Light:  light,
+	})
+}
+
+func (a *Adapter) OnValidatorLightChanged(netID ids.ID, nodeID ids.NodeID, oldLight, newLight uint64) {
+	a.publish(Event{
+		Type:     ValidatorLightChanged,
+		NetID:    netID,
+		NodeID:   nodeID,
+		OldLight: oldLight,
+		NewLight: newLight,
+	})
+}
+
+func (a *Adapter) OnValidatorPublicKeyChanged(netID ids.ID, nodeID ids.NodeID, oldKey, newKey []byte) {
+	a.publish(Event{
+		Type:         ValidatorPublicKeyChanged,
+		NetID:        netID,
+		NodeID:       nodeID,
+		OldPublicKey: hex.EncodeToString(oldKey),
+		NewPublicKey: hex.EncodeToString(newKey),
+	})
+}
+
+func (a *Adapter) publish(event Event) {
+	payload, err := json.Marshal(event)
+	if err == nil {
+		err = a.publisher.Publish(a.config.Topic(event.Type), payload)
+	}
+	if err != nil && a.config.OnError != nil {
+		a.config.OnError(event, fmt.Errorf("couldn't publish %s of %s: %w", event.Type, event.NodeID, err))
+	}
+}
+
+// Message is a payload published to a topic
+type Message struct {
+	Topic   string
+	Payload []byte
+}
+
+// MemoryPublisher is an in-memory Publisher that records every message and
+// delivers it synchronously to the subscribers of its topic. It is meant for
+// tests and for embedders that consume events in process.
+type MemoryPublisher struct {
+	mu          sync.RWMutex
+	messages    []Message
+	subscribers map[string][]func(payload []byte)
+}
+
+var _ Publisher = (*MemoryPublisher)(nil)
+
+// NewMemoryPublisher returns an empty in-memory publisher
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{
+		subscribers: make(map[string][]func(payload []byte)),
+	}
+}
+
+// Publish records the message and calls the subscribers of [topic]
+func (p *MemoryPublisher) Publish(topic string, payload []byte) error {
+	p.mu.Lock()
+	p.messages = append(p.messages, Message{
+		Topic:   topic,
+		Payload: slices.Clone(payload),
+	})
+	subscribers := p.subscribers[topic]
+	p.mu.Unlock()
+
+	for _, subscriber := range subscribers {
+		subscriber(payload)
+	}
+	return nil
+}
+
+// Subscribe calls [f] with every payload published to [topic] from now on
+func (p *MemoryPublisher) Subscribe(topic string, f func(payload []byte)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Copy on write, since Publish calls subscribers without the lock
+	p.subscribers[topic] = append(slices.Clone(p.subscribers[topic]), f)
+}
+
+// Messages returns the published messages in order
+func (p *MemoryPublisher) Messages() []Message {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return slices.Clone(p.messages)
+}
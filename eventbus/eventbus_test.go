@@ -0,0 +1,106 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package eventbus
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+	"github.com/luxfi/validators/validatorstest"
+)
+
+var errTestPublish = errors.New("test publish error")
+
+type failingPublisher struct{}
+
+func (failingPublisher) Publish(string, []byte) error {
+	return errTestPublish
+}
+
+// TestAdapter tests that manager events are published as JSON payloads
+func TestAdapter(t *testing.T) {
+	require := require.New(t)
+
+	publisher := NewMemoryPublisher()
+	var added []Event
+	publisher.Subscribe("validators.added", func(payload []byte) {
+		var event Event
+		require.NoError(json.Unmarshal(payload, &event))
+		added = append(added, event)
+	})
+
+	adapter := New(publisher, Config{TopicPrefix: "validators"})
+	m := validators.NewManager()
+	m.RegisterCallbackListener(adapter)
+	m.RegisterPublicKeyListener(adapter)
+
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+	require.NoError(m.SetWeight(netID, nodeID, 150))
+
+	generated, err := validatorstest.GenerateValidatorSet(1, 1, validatorstest.ConstantWeights(1))
+	require.NoError(err)
+	publicKey := generated.Validators[0].PublicKeyBytes
+	require.NoError(m.UpdatePublicKey(netID, nodeID, publicKey))
+	require.NoError(m.RemoveStaker(netID, nodeID))
+
+	messages := publisher.Messages()
+	topics := make([]string, len(messages))
+	for i, message := range messages {
+		topics[i] = message.Topic
+	}
+	require.Equal([]string{
+		"validators.added",
+		"validators.light-changed",
+		"validators.public-key-changed",
+		"validators.removed",
+	}, topics)
+
+	require.Equal([]Event{{
+		Type:   ValidatorAdded,
+		NetID:  netID,
+		NodeID: nodeID,
+		Light:  100,
+	}}, added)
+
+	var changed Event
+	require.NoError(json.Unmarshal(messages[1].Payload, &changed))
+	require.Equal(uint64(100), changed.OldLight)
+	require.Equal(uint64(150), changed.NewLight)
+
+	var rotated Event
+	require.NoError(json.Unmarshal(messages[2].Payload, &rotated))
+	require.Empty(rotated.OldPublicKey)
+	require.NotEmpty(rotated.NewPublicKey)
+}
+
+// TestAdapterErrors tests that publish errors are reported
+func TestAdapterErrors(t *testing.T) {
+	require := require.New(t)
+
+	var failed []Event
+	adapter := New(failingPublisher{}, Config{
+		OnError: func(event Event, err error) {
+			require.ErrorIs(err, errTestPublish)
+			failed = append(failed, event)
+		},
+	})
+	require.Equal("added", adapter.config.Topic(ValidatorAdded))
+
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	adapter.OnValidatorRemoved(netID, nodeID, 5)
+	require.Equal([]Event{{
+		Type:   ValidatorRemoved,
+		NetID:  netID,
+		NodeID: nodeID,
+		Light:  5,
+	}}, failed)
+}
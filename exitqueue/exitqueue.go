@@ -0,0 +1,212 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package exitqueue rate limits validator exits, so a large coordinated exit
+// drains out of a net over several periods instead of collapsing its
+// security at once
+package exitqueue
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"slices"
+	"sync"
+
+	"github.com/luxfi/ids"
+
+	validators "github.com/luxfi/validators"
+)
+
+var (
+	ErrInvalidConfig       = errors.New("invalid exit queue config")
+	ErrAlreadyQueued       = errors.New("exit already queued")
+	ErrNonIncreasingPeriod = errors.New("height is in an earlier period")
+)
+
+// Config is the exit budget of a Queue
+type Config struct {
+	// Period is the number of heights, such as an epoch, the budget applies
+	// to. Period p covers heights [p*Period, (p+1)*Period).
+	Period uint64
+	// MaxExits is the number of validators that may exit per period. Zero
+	// doesn't limit the count.
+	MaxExits int
+	// MaxLightNumerator / MaxLightDenominator is the share of the net's light,
+	// as of the first Process call of the period, that may exit per period. A
+	// zero denominator doesn't limit the light.
+	MaxLightNumerator   uint64
+	MaxLightDenominator uint64
+}
+
+// Verify returns an error if the config can't drain the queue
+func (c Config) Verify() error {
+	switch {
+	case c.Period == 0:
+		return fmt.Errorf("%w: zero period", ErrInvalidConfig)
+	case c.MaxExits < 0:
+		return fmt.Errorf("%w: negative max exits %d", ErrInvalidConfig, c.MaxExits)
+	case c.MaxLightDenominator != 0 && c.MaxLightNumerator == 0:
+		return fmt.Errorf("%w: zero light share", ErrInvalidConfig)
+	case c.MaxLightNumerator > c.MaxLightDenominator:
+		return fmt.Errorf("%w: light share %d/%d exceeds 1", ErrInvalidConfig, c.MaxLightNumerator, c.MaxLightDenominator)
+	}
+	return nil
+}
+
+// Manager is a validator manager a Queue can remove validators from
+type Manager interface {
+	validators.Manager
+	validators.StakerManager
+}
+
+// Queue holds the pending exits of a net and removes them from the manager in
+// order as the budget of each period allows. The first exit of a period is
+// always processed, even if its light alone exceeds the budget, so a large
+// validator can't block the queue.
+type Queue struct {
+	manager Manager
+	netID   ids.ID
+	config  Config
+
+	mu      sync.Mutex
+	pending []ids.NodeID
+	// period is the period exits were last processed in, and exits and light
+	// what was spent of its budget
+	period   uint64
+	started  bool
+	exits    int
+	light    uint64
+	maxLight uint64
+}
+
+// New returns an empty exit queue of [netID]
+func New(manager Manager, netID ids.ID, config Config) (*Queue, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	return &Queue{
+		manager: manager,
+		netID:   netID,
+		config:  config,
+	}, nil
+}
+
+// Enqueue adds the exit of [nodeID] to the back of the queue
+func (q *Queue) Enqueue(nodeID ids.NodeID) error {
+	if _, ok := q.manager.GetValidator(q.netID, nodeID); !ok {
+		return fmt.Errorf("%w: %s in %s", validators.ErrValidatorNotFound, nodeID, q.netID)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if slices.Contains(q.pending, nodeID) {
+		return fmt.Errorf("%w: %s", ErrAlreadyQueued, nodeID)
+	}
+	q.pending = append(q.pending, nodeID)
+	return nil
+}
+
+// Cancel removes the exit of [nodeID] from the queue and returns true if it
+// was queued
+func (q *Queue) Cancel(nodeID ids.NodeID) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i := slices.Index(q.pending, nodeID)
+	if i < 0 {
+		return false
+	}
+	q.pending = slices.Delete(q.pending, i, i+1)
+	return true
+}
+
+// Position returns the number of exits ahead of [nodeID] and true, or false
+// if it isn't queued
+func (q *Queue) Position(nodeID ids.NodeID) (int, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i := slices.Index(q.pending, nodeID)
+	return i, i >= 0
+}
+
+// Len returns the number of queued exits
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.pending)
+}
+
+// Pending returns the queued exits in order
+func (q *Queue) Pending() []ids.NodeID {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return slices.Clone(q.pending)
+}
+
+// Process removes the queued validators the budget of the period containing
+// [height] still allows, in order, and returns them. Validators that already
+// left the net are dropped from the queue without spending the budget.
+// Heights may repeat or skip, but not move to an earlier period.
+func (q *Queue) Process(height uint64) ([]ids.NodeID, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	period := height / q.config.Period
+	switch {
+	case !q.started || period > q.period:
+		q.started = true
+		q.period = period
+		q.exits = 0
+		q.light = 0
+		q.maxLight = 0
+		if q.config.MaxLightDenominator != 0 {
+			total, err := q.manager.TotalLight(q.netID)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't get light of %s: %w", q.netID, err)
+			}
+			hi, lo := bits.Mul64(total, q.config.MaxLightNumerator)
+			q.maxLight, _ = bits.Div64(hi, lo, q.config.MaxLightDenominator)
+		}
+	case period < q.period:
+		return nil, fmt.Errorf("%w: height %d is in period %d, after %d", ErrNonIncreasingPeriod, height, period, q.period)
+	}
+
+	var exited []ids.NodeID
+	for len(q.pending) > 0 {
+		nodeID := q.pending[0]
+		val, ok := q.manager.GetValidator(q.netID, nodeID)
+		if !ok {
+			q.pending = q.pending[1:]
+			continue
+		}
+		light := val.Light
+		if !q.allows(light) {
+			break
+		}
+		if err := q.manager.RemoveStaker(q.netID, nodeID); err != nil {
+			return exited, fmt.Errorf("couldn't remove %s from %s: %w", nodeID, q.netID, err)
+		}
+		q.pending = q.pending[1:]
+		q.exits++
+		q.light += light
+		exited = append(exited, nodeID)
+	}
+	return exited, nil
+}
+
+// allows returns true if an exit of [light] fits in the remaining budget of
+// the period. It assumes the lock is held.
+func (q *Queue) allows(light uint64) bool {
+	if q.exits == 0 {
+		return true
+	}
+	if q.config.MaxExits > 0 && q.exits >= q.config.MaxExits {
+		return false
+	}
+	return q.config.MaxLightDenominator == 0 || light <= q.maxLight-min(q.light, q.maxLight)
+}
@@ -0,0 +1,136 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package exitqueue
+
+import (
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+// TestConfigVerify tests config validation
+func TestConfigVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      Config
+		expectedErr error
+	}{
+		{
+			name:   "valid",
+			config: Config{Period: 10, MaxExits: 1, MaxLightNumerator: 1, MaxLightDenominator: 3},
+		},
+		{
+			name:        "zero period",
+			config:      Config{MaxExits: 1},
+			expectedErr: ErrInvalidConfig,
+		},
+		{
+			name:        "negative max exits",
+			config:      Config{Period: 10, MaxExits: -1},
+			expectedErr: ErrInvalidConfig,
+		},
+		{
+			name:        "zero light share",
+			config:      Config{Period: 10, MaxLightDenominator: 3},
+			expectedErr: ErrInvalidConfig,
+		},
+		{
+			name:        "light share above 1",
+			config:      Config{Period: 10, MaxLightNumerator: 4, MaxLightDenominator: 3},
+			expectedErr: ErrInvalidConfig,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.config.Verify(), test.expectedErr)
+		})
+	}
+}
+
+// TestQueue tests that exits drain at the configured rate, in order
+func TestQueue(t *testing.T) {
+	require := require.New(t)
+
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	nodeIDs := make([]ids.NodeID, 5)
+	for i := range nodeIDs {
+		nodeIDs[i] = ids.GenerateTestNodeID()
+		require.NoError(m.AddStaker(netID, nodeIDs[i], nil, ids.Empty, 100))
+	}
+	staying := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, staying, nil, ids.Empty, 500))
+
+	q, err := New(m, netID, Config{Period: 10, MaxExits: 2, MaxLightNumerator: 1, MaxLightDenominator: 5})
+	require.NoError(err)
+	require.ErrorIs(q.Enqueue(ids.GenerateTestNodeID()), validators.ErrValidatorNotFound)
+	for _, nodeID := range nodeIDs {
+		require.NoError(q.Enqueue(nodeID))
+	}
+	require.ErrorIs(q.Enqueue(nodeIDs[0]), ErrAlreadyQueued)
+	position, ok := q.Position(nodeIDs[3])
+	require.True(ok)
+	require.Equal(3, position)
+
+	// A fifth of 1000 light is 200, so two exits fit in the first period
+	exited, err := q.Process(5)
+	require.NoError(err)
+	require.Equal(nodeIDs[:2], exited)
+
+	// The budget of the period is spent
+	exited, err = q.Process(9)
+	require.NoError(err)
+	require.Empty(exited)
+	position, ok = q.Position(nodeIDs[3])
+	require.True(ok)
+	require.Equal(1, position)
+
+	// A fifth of the remaining 800 light is 160, so only one exit fits
+	exited, err = q.Process(10)
+	require.NoError(err)
+	require.Equal(nodeIDs[2:3], exited)
+
+	_, err = q.Process(9)
+	require.ErrorIs(err, ErrNonIncreasingPeriod)
+
+	// Validators that left on their own are dropped, and cancelled exits
+	// aren't processed
+	require.NoError(m.RemoveStaker(netID, nodeIDs[3]))
+	require.True(q.Cancel(nodeIDs[4]))
+	require.False(q.Cancel(nodeIDs[4]))
+	exited, err = q.Process(20)
+	require.NoError(err)
+	require.Empty(exited)
+	require.Zero(q.Len())
+	require.Equal(uint64(100), m.GetLight(netID, nodeIDs[4]))
+}
+
+// TestQueueLargeExit tests that an exit exceeding the budget on its own
+// doesn't block the queue
+func TestQueueLargeExit(t *testing.T) {
+	require := require.New(t)
+
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	large, small := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, large, nil, ids.Empty, 900))
+	require.NoError(m.AddStaker(netID, small, nil, ids.Empty, 100))
+
+	q, err := New(m, netID, Config{Period: 1, MaxLightNumerator: 1, MaxLightDenominator: 10})
+	require.NoError(err)
+	require.NoError(q.Enqueue(large))
+	require.NoError(q.Enqueue(small))
+
+	exited, err := q.Process(0)
+	require.NoError(err)
+	require.Equal([]ids.NodeID{large}, exited)
+	require.Equal([]ids.NodeID{small}, q.Pending())
+
+	exited, err = q.Process(1)
+	require.NoError(err)
+	require.Equal([]ids.NodeID{small}, exited)
+}
@@ -0,0 +1,123 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/luxfi/ids"
+)
+
+const (
+	MaxExtensions      = 16
+	MaxExtensionKeyLen = 64
+	MaxExtensionLen    = 1024
+)
+
+var (
+	ErrExtensionTooLarge = errors.New("extension too large")
+	ErrEmptyExtensionKey = errors.New("empty extension key")
+)
+
+// ExtensionManager stores opaque chain-specific data on validator records,
+// so VMs can attach their own fields without forking GetValidatorOutput.
+// Extensions are attached to the records returned by GetValidator,
+// GetValidators, and GetMap, survive re-adding the validator, and are
+// dropped when the validator is removed.
+type ExtensionManager interface {
+	// SetExtension sets the extension [key] of [nodeID] in [netID]
+	SetExtension(netID ids.ID, nodeID ids.NodeID, key string, value []byte) error
+	// GetExtension returns the extension [key] of [nodeID] in [netID], if any
+	GetExtension(netID ids.ID, nodeID ids.NodeID, key string) ([]byte, bool)
+	// DeleteExtension removes the extension [key] of [nodeID] in [netID]
+	DeleteExtension(netID ids.ID, nodeID ids.NodeID, key string)
+}
+
+var _ ExtensionManager = (*manager)(nil)
+
+// SetExtension sets an extension of an existing validator
+func (m *manager) SetExtension(netID ids.ID, nodeID ids.NodeID, key string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.setExtension(netID, nodeID, key, value)
+}
+
+// setExtension is SetExtension without locking. It assumes the lock is held.
+func (m *manager) setExtension(netID ids.ID, nodeID ids.NodeID, key string, value []byte) error {
+	switch {
+	case key == "":
+		return ErrEmptyExtensionKey
+	case len(key) > MaxExtensionKeyLen:
+		return fmt.Errorf("%w: key is %d bytes, limit is %d", ErrExtensionTooLarge, len(key), MaxExtensionKeyLen)
+	case len(value) > MaxExtensionLen:
+		return fmt.Errorf("%w: %q is %d bytes, limit is %d", ErrExtensionTooLarge, key, len(value), MaxExtensionLen)
+	}
+
+	val, exists := m.validators[netID][nodeID]
+	if !exists {
+		return fmt.Errorf("%w: %s in %s", ErrUnknownValidator, nodeID, netID)
+	}
+	if _, ok := val.Extensions[key]; !ok && len(val.Extensions) >= MaxExtensions {
+		return fmt.Errorf("%w: %d extensions, limit is %d", ErrExtensionTooLarge, len(val.Extensions)+1, MaxExtensions)
+	}
+	// Snapshots share the map, so it's replaced rather than modified
+	extensions := make(map[string][]byte, len(val.Extensions)+1)
+	maps.Copy(extensions, val.Extensions)
+	extensions[key] = slices.Clone(value)
+	val.Extensions = extensions
+	m.bumpSequence(netID, val)
+	return nil
+}
+
+// GetExtension returns a copy of an extension of a validator
+func (m *manager) GetExtension(netID ids.ID, nodeID ids.NodeID, key string) ([]byte, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	val, exists := m.validators[netID][nodeID]
+	if !exists {
+		return nil, false
+	}
+	value, ok := val.Extensions[key]
+	return slices.Clone(value), ok
+}
+
+// DeleteExtension removes an extension of a validator
+func (m *manager) DeleteExtension(netID ids.ID, nodeID ids.NodeID, key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	val, exists := m.validators[netID][nodeID]
+	if !exists {
+		return
+	}
+	if _, ok := val.Extensions[key]; !ok {
+		return
+	}
+	// Snapshots share the map, so it's replaced rather than modified
+	if len(val.Extensions) == 1 {
+		val.Extensions = nil
+	} else {
+		val.Extensions = maps.Clone(val.Extensions)
+		delete(val.Extensions, key)
+	}
+	m.bumpSequence(netID, val)
+}
+
+// cloneExtensions returns a deep copy of [extensions]. Cloning nil returns
+// nil.
+func cloneExtensions(extensions map[string][]byte) map[string][]byte {
+	if extensions == nil {
+		return nil
+	}
+	clone := maps.Clone(extensions)
+	for key, value := range clone {
+		clone[key] = slices.Clone(value)
+	}
+	return clone
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// TestManagerExtensions tests that extensions are attached to validator reads
+func TestManagerExtensions(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+
+	value := []byte{1, 2, 3}
+	require.ErrorIs(m.SetExtension(netID, nodeID, "evm", value), ErrUnknownValidator)
+
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+	require.NoError(m.SetExtension(netID, nodeID, "evm", value))
+
+	// Mutating the caller's copy doesn't affect the stored value
+	value[0] = 9
+
+	got, ok := m.GetExtension(netID, nodeID, "evm")
+	require.True(ok)
+	require.Equal([]byte{1, 2, 3}, got)
+
+	vdr, ok := m.GetValidator(netID, nodeID)
+	require.True(ok)
+	require.Equal(map[string][]byte{"evm": {1, 2, 3}}, vdr.Extensions)
+	require.Equal(vdr.Extensions, m.GetMap(netID)[nodeID].Extensions)
+
+	// Mutating returned extensions doesn't affect the manager
+	vdr.Extensions["evm"][0] = 9
+	vdr.Extensions["other"] = nil
+	got, _ = m.GetExtension(netID, nodeID, "evm")
+	require.Equal([]byte{1, 2, 3}, got)
+	_, ok = m.GetExtension(netID, nodeID, "other")
+	require.False(ok)
+
+	// Replacing keeps extensions, removal drops them
+	require.NoError(m.SetDuplicatePolicy(DuplicateReplace))
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 200))
+	_, ok = m.GetExtension(netID, nodeID, "evm")
+	require.True(ok)
+
+	m.DeleteExtension(netID, nodeID, "evm")
+	_, ok = m.GetExtension(netID, nodeID, "evm")
+	require.False(ok)
+	vdr, _ = m.GetValidator(netID, nodeID)
+	require.Nil(vdr.Extensions)
+
+	require.NoError(m.SetExtension(netID, nodeID, "evm", value))
+	require.NoError(m.RemoveStaker(netID, nodeID))
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+	_, ok = m.GetExtension(netID, nodeID, "evm")
+	require.False(ok)
+}
+
+// TestManagerExtensionLimits tests the extension size limits
+func TestManagerExtensionLimits(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+
+	require.ErrorIs(m.SetExtension(netID, nodeID, "", nil), ErrEmptyExtensionKey)
+	require.ErrorIs(m.SetExtension(netID, nodeID, strings.Repeat("k", MaxExtensionKeyLen+1), nil), ErrExtensionTooLarge)
+	require.ErrorIs(m.SetExtension(netID, nodeID, "k", make([]byte, MaxExtensionLen+1)), ErrExtensionTooLarge)
+	require.NoError(m.SetExtension(netID, nodeID, "k", make([]byte, MaxExtensionLen)))
+
+	for i := 1; i < MaxExtensions; i++ {
+		require.NoError(m.SetExtension(netID, nodeID, fmt.Sprint(i), nil))
+	}
+	require.ErrorIs(m.SetExtension(netID, nodeID, "one too many", nil), ErrExtensionTooLarge)
+
+	// Replacing an extension doesn't count against the limit
+	require.NoError(m.SetExtension(netID, nodeID, "k", nil))
+}
@@ -0,0 +1,161 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package fairness splits a request or bandwidth budget between the peers of
+// a net in proportion to their stake, so networking layers throttle peers the
+// same way
+package fairness
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"sync"
+	"time"
+
+	"github.com/luxfi/ids"
+
+	validators "github.com/luxfi/validators"
+)
+
+// minSweep is the number of tracked peers below which idle peers aren't
+// swept
+const minSweep = 1024
+
+var ErrInvalidConfig = errors.New("invalid fairness config")
+
+// Config is the budget of a Scheduler
+type Config struct {
+	// Capacity is the number of tokens the net's validators share per
+	// Interval. A validator's quota is its share of the net's light.
+	Capacity uint64
+	// Interval is the period quotas are refilled over. A peer that has been
+	// idle for an Interval may spend its whole quota at once.
+	Interval time.Duration
+	// MinQuota is the quota of peers whose share rounds below it, including
+	// peers that aren't validators. Zero denies such peers.
+	MinQuota uint64
+}
+
+// Verify returns an error if the config can't grant tokens
+func (c Config) Verify() error {
+	switch {
+	case c.Capacity == 0:
+		return fmt.Errorf("%w: zero capacity", ErrInvalidConfig)
+	case c.Interval <= 0:
+		return fmt.Errorf("%w: interval %s must be positive", ErrInvalidConfig, c.Interval)
+	case c.MinQuota > c.Capacity:
+		return fmt.Errorf("%w: minimum quota %d exceeds capacity %d", ErrInvalidConfig, c.MinQuota, c.Capacity)
+	}
+	return nil
+}
+
+// Scheduler grants tokens to the peers of a net. Each peer's quota is
+// recomputed from the manager on every call, so stake changes take effect
+// immediately. Tokens refill continuously rather than at interval
+// boundaries, so peers can't synchronize bursts on a boundary.
+type Scheduler struct {
+	manager validators.Manager
+	netID   ids.ID
+	config  Config
+	now     func() time.Time
+
+	mu sync.Mutex
+	// tats is the time each peer's bucket is full again. Peers whose bucket
+	// is full aren't tracked.
+	tats    map[ids.NodeID]time.Time
+	sweepAt int
+}
+
+// New returns a scheduler of the tokens of [netID]
+func New(manager validators.Manager, netID ids.ID, config Config) (*Scheduler, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	return &Scheduler{
+		manager: manager,
+		netID:   netID,
+		config:  config,
+		now:     time.Now,
+		tats:    make(map[ids.NodeID]time.Time),
+		sweepAt: minSweep,
+	}, nil
+}
+
+// Quota returns the number of tokens [nodeID] is granted per interval
+func (s *Scheduler) Quota(nodeID ids.NodeID) uint64 {
+	light := s.manager.GetLight(s.netID, nodeID)
+	total, err := s.manager.TotalLight(s.netID)
+	if err != nil || total == 0 || light == 0 {
+		return s.config.MinQuota
+	}
+	// light <= total, so the quotient fits in 64 bits
+	hi, lo := bits.Mul64(s.config.Capacity, min(light, total))
+	quota, _ := bits.Div64(hi, lo, total)
+	return max(quota, s.config.MinQuota)
+}
+
+// Acquire takes a token of [nodeID]. It returns false if [nodeID] has spent
+// its quota, in which case nothing is taken.
+func (s *Scheduler) Acquire(nodeID ids.NodeID) bool {
+	return s.AcquireN(nodeID, 1)
+}
+
+// AcquireN takes [n] tokens of [nodeID] at once, such as the size of a
+// message in bytes. It returns false if [nodeID] doesn't have [n] tokens, in
+// which case nothing is taken.
+func (s *Scheduler) AcquireN(nodeID ids.NodeID, n uint64) bool {
+	if n == 0 {
+		return true
+	}
+	quota := s.Quota(nodeID)
+	if n > quota {
+		return false
+	}
+
+	// Each token refills in interval / quota. The bucket is full again at
+	// tat, and holds quota tokens, so the request fits if tat would be at
+	// most one interval away after taking the tokens.
+	var (
+		perToken = max(s.config.Interval/time.Duration(quota), 1)
+		cost     = perToken * time.Duration(n)
+	)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	tat, ok := s.tats[nodeID]
+	if !ok || tat.Before(now) {
+		tat = now
+	}
+	next := tat.Add(cost)
+	if next.Sub(now) > s.config.Interval {
+		return false
+	}
+	s.tats[nodeID] = next
+	s.sweep(now)
+	return true
+}
+
+// Reset refills the bucket of [nodeID], such as when it disconnects
+func (s *Scheduler) Reset(nodeID ids.NodeID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.tats, nodeID)
+}
+
+// sweep stops tracking peers whose bucket is full once the number of tracked
+// peers doubled since the last sweep. It assumes the lock is held.
+func (s *Scheduler) sweep(now time.Time) {
+	if len(s.tats) < s.sweepAt {
+		return
+	}
+	for nodeID, tat := range s.tats {
+		if !tat.After(now) {
+			delete(s.tats, nodeID)
+		}
+	}
+	s.sweepAt = max(2*len(s.tats), minSweep)
+}
@@ -0,0 +1,114 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package fairness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+// TestConfigVerify tests that unusable configs are rejected
+func TestConfigVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{
+			name:   "zero capacity",
+			config: Config{Interval: time.Second},
+		},
+		{
+			name:   "zero interval",
+			config: Config{Capacity: 10},
+		},
+		{
+			name:   "minimum quota exceeds capacity",
+			config: Config{Capacity: 10, Interval: time.Second, MinQuota: 11},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := New(validators.NewManager(), ids.GenerateTestID(), test.config)
+			require.ErrorIs(t, err, ErrInvalidConfig)
+		})
+	}
+}
+
+// TestSchedulerQuota tests that quotas are proportional to stake
+func TestSchedulerQuota(t *testing.T) {
+	require := require.New(t)
+
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	var (
+		large = ids.GenerateTestNodeID()
+		small = ids.GenerateTestNodeID()
+		tiny  = ids.GenerateTestNodeID()
+	)
+	require.NoError(m.AddStaker(netID, large, nil, ids.Empty, 750))
+	require.NoError(m.AddStaker(netID, small, nil, ids.Empty, 249))
+	require.NoError(m.AddStaker(netID, tiny, nil, ids.Empty, 1))
+
+	s, err := New(m, netID, Config{Capacity: 100, Interval: time.Second, MinQuota: 2})
+	require.NoError(err)
+	require.Equal(uint64(75), s.Quota(large))
+	require.Equal(uint64(24), s.Quota(small))
+	require.Equal(uint64(2), s.Quota(tiny))
+	require.Equal(uint64(2), s.Quota(ids.GenerateTestNodeID()))
+
+	// Stake changes take effect immediately
+	require.NoError(m.RemoveStaker(netID, small))
+	require.Equal(uint64(99), s.Quota(large))
+}
+
+// TestSchedulerAcquire tests that tokens are spent and refilled over the
+// interval
+func TestSchedulerAcquire(t *testing.T) {
+	require := require.New(t)
+
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	var (
+		nodeID = ids.GenerateTestNodeID()
+		other  = ids.GenerateTestNodeID()
+	)
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 40))
+	require.NoError(m.AddStaker(netID, other, nil, ids.Empty, 60))
+
+	s, err := New(m, netID, Config{Capacity: 10, Interval: time.Second})
+	require.NoError(err)
+	now := time.Unix(1_000, 0)
+	s.now = func() time.Time { return now }
+
+	// The whole quota is available at once
+	for range 4 {
+		require.True(s.Acquire(nodeID))
+	}
+	require.False(s.Acquire(nodeID))
+
+	// Other peers have their own quota
+	require.True(s.AcquireN(other, 6))
+	require.False(s.Acquire(other))
+
+	// A token refills every interval / quota
+	now = now.Add(250 * time.Millisecond)
+	require.True(s.Acquire(nodeID))
+	require.False(s.Acquire(nodeID))
+
+	// Requests larger than the quota never fit
+	now = now.Add(time.Hour)
+	require.False(s.AcquireN(nodeID, 5))
+	require.True(s.AcquireN(nodeID, 4))
+
+	s.Reset(nodeID)
+	require.True(s.AcquireN(nodeID, 4))
+
+	// Peers without stake are denied without a minimum quota
+	require.False(s.Acquire(ids.GenerateTestNodeID()))
+}
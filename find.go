@@ -0,0 +1,100 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/luxfi/ids"
+)
+
+var ErrEmptyPrefix = errors.New("empty node ID prefix")
+
+// FindManager resolves truncated node IDs, for CLIs and debug endpoints
+// where operators paste partial IDs. Prefixes are matched against the string
+// form of node IDs, with or without the "NodeID-" prefix, and are case
+// sensitive. Lookups scan every candidate and aren't meant for hot paths.
+type FindManager interface {
+	// FindValidators returns the validators of [netID] whose node ID starts
+	// with [prefix], sorted by node ID
+	FindValidators(netID ids.ID, prefix string) ([]ids.NodeID, error)
+	// FindNodeIDs returns the node IDs validating any net that start with
+	// [prefix], sorted by node ID
+	FindNodeIDs(prefix string) ([]ids.NodeID, error)
+}
+
+var _ FindManager = (*manager)(nil)
+
+// FindValidators returns the validators of a net matching a node ID prefix
+func (m *manager) FindValidators(netID ids.ID, prefix string) ([]ids.NodeID, error) {
+	prefix, err := nodeIDPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var matches []ids.NodeID
+	for nodeID := range m.validators[netID] {
+		if matchesNodeIDPrefix(nodeID, prefix) {
+			matches = append(matches, nodeID)
+		}
+	}
+	return sortNodeIDs(matches), nil
+}
+
+// FindNodeIDs returns the validators of any net matching a node ID prefix
+// using the membership index
+func (m *manager) FindNodeIDs(prefix string) ([]ids.NodeID, error) {
+	prefix, err := nodeIDPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var matches []ids.NodeID
+	for nodeID := range m.memberships {
+		if matchesNodeIDPrefix(nodeID, prefix) {
+			matches = append(matches, nodeID)
+		}
+	}
+	return sortNodeIDs(matches), nil
+}
+
+// nodeIDPrefix returns [prefix] with the "NodeID-" prefix
+func nodeIDPrefix(prefix string) (string, error) {
+	prefix = strings.TrimPrefix(strings.TrimSpace(prefix), ids.NodeIDPrefix)
+	if prefix == "" {
+		return "", ErrEmptyPrefix
+	}
+	return ids.NodeIDPrefix + prefix, nil
+}
+
+func matchesNodeIDPrefix(nodeID ids.NodeID, prefix string) bool {
+	return strings.HasPrefix(nodeID.String(), prefix)
+}
+
+func sortNodeIDs(nodeIDs []ids.NodeID) []ids.NodeID {
+	slices.SortFunc(nodeIDs, func(a, b ids.NodeID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	return nodeIDs
+}
+
+// nodeIDsAscending returns true if [nodeIDs] is in strictly ascending order,
+// which also rules out duplicates
+func nodeIDsAscending(nodeIDs []ids.NodeID) bool {
+	for i := 1; i < len(nodeIDs); i++ {
+		if bytes.Compare(nodeIDs[i-1][:], nodeIDs[i][:]) >= 0 {
+			return false
+		}
+	}
+	return true
+}
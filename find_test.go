@@ -0,0 +1,49 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFindValidators tests resolving truncated node IDs
+func TestFindValidators(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	otherNetID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	otherNodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+	require.NoError(m.AddStaker(otherNetID, otherNodeID, nil, ids.Empty, 100))
+
+	full := nodeID.String()
+	short := strings.TrimPrefix(full, ids.NodeIDPrefix)[:8]
+	for _, prefix := range []string{full, full[:len(ids.NodeIDPrefix)+8], short, " " + short + "\n"} {
+		matches, err := m.FindValidators(netID, prefix)
+		require.NoError(err)
+		require.Equal([]ids.NodeID{nodeID}, matches)
+	}
+
+	// Other nets aren't searched
+	matches, err := m.FindValidators(otherNetID, short)
+	require.NoError(err)
+	require.Empty(matches)
+
+	// A bare "NodeID-" would match every node
+	_, err = m.FindNodeIDs(ids.NodeIDPrefix)
+	require.ErrorIs(err, ErrEmptyPrefix)
+
+	matches, err = m.FindNodeIDs(short)
+	require.NoError(err)
+	require.Equal([]ids.NodeID{nodeID}, matches)
+
+	_, err = m.FindValidators(netID, " ")
+	require.ErrorIs(err, ErrEmptyPrefix)
+}
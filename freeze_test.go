@@ -0,0 +1,104 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+type testFreezeListener struct {
+	frozen []ids.ID
+	thawed []ids.ID
+}
+
+func (l *testFreezeListener) OnNetFrozen(netID ids.ID) {
+	l.frozen = append(l.frozen, netID)
+}
+
+func (l *testFreezeListener) OnNetThawed(netID ids.ID) {
+	l.thawed = append(l.thawed, netID)
+}
+
+// TestManagerFreezeNet tests that frozen nets reject mutations but not reads
+func TestManagerFreezeNet(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	freezeListener := &testFreezeListener{}
+	m.RegisterFreezeListener(freezeListener)
+
+	netID := ids.GenerateTestID()
+	otherNetID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+
+	m.FreezeNet(netID)
+	m.FreezeNet(netID)
+	require.True(m.IsFrozen(netID))
+	require.False(m.IsFrozen(otherNetID))
+	require.Equal([]ids.ID{netID}, freezeListener.frozen)
+
+	require.ErrorIs(m.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 100), ErrNetFrozen)
+	require.ErrorIs(m.AddWeight(netID, nodeID, 1), ErrNetFrozen)
+	require.ErrorIs(m.RemoveWeight(netID, nodeID, 1), ErrNetFrozen)
+	require.ErrorIs(m.RemoveStaker(netID, nodeID), ErrNetFrozen)
+	require.ErrorIs(m.AddDelegator(netID, nodeID, ids.GenerateTestShortID(), 1), ErrNetFrozen)
+	require.ErrorIs(m.AddLight(netID, nodeID, 1), ErrNetFrozen)
+	require.ErrorIs(m.SetEconomicWeight(netID, nodeID, 1), ErrNetFrozen)
+	require.ErrorIs(m.SetAssetStake(netID, nodeID, ids.GenerateTestID(), 1), ErrNetFrozen)
+	require.ErrorIs(m.SetWeightMode(netID, WeightModeBig), ErrNetFrozen)
+	require.ErrorIs(m.AddStakerBig(netID, nodeID, nil, ids.Empty, big.NewInt(1)), ErrNetFrozen)
+	require.ErrorIs(m.RegisterL1Validator(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 1, 1), ErrNetFrozen)
+
+	// Reads and other nets are unaffected
+	require.Equal(uint64(100), m.GetLight(netID, nodeID))
+	require.NoError(m.AddStaker(otherNetID, nodeID, nil, ids.Empty, 100))
+
+	// Denying is recorded but only enforced on thaw
+	m.Deny(netID, nodeID)
+	_, ok := m.GetValidator(netID, nodeID)
+	require.True(ok)
+
+	require.True(m.ThawNet(netID))
+	require.True(m.IsFrozen(netID))
+	require.Empty(freezeListener.thawed)
+
+	require.True(m.ThawNet(netID))
+	require.False(m.IsFrozen(netID))
+	require.False(m.ThawNet(netID))
+	require.Equal([]ids.ID{netID}, freezeListener.thawed)
+	_, ok = m.GetValidator(netID, nodeID)
+	require.False(ok)
+}
+
+// TestManagerFreezeNetBalances tests that fees accrued while frozen are
+// charged after thaw
+func TestManagerFreezeNetBalances(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	start := time.Unix(1_000, 0)
+
+	m.SetFeeConfig(netID, FeeConfig{PerSecond: 1})
+	require.NoError(m.RegisterL1Validator(netID, nodeID, nil, ids.Empty, 100, 50))
+	m.AdvanceBalances(netID, 1, start)
+
+	m.FreezeNet(netID)
+	m.AdvanceBalances(netID, 2, start.Add(10*time.Second))
+	balance, _ := m.GetBalance(netID, nodeID)
+	require.Equal(uint64(50), balance)
+	require.ErrorIs(m.TopUp(netID, nodeID, 10), ErrNetFrozen)
+
+	require.True(m.ThawNet(netID))
+	m.AdvanceBalances(netID, 3, start.Add(20*time.Second))
+	balance, _ = m.GetBalance(netID, nodeID)
+	require.Equal(uint64(30), balance)
+}
@@ -0,0 +1,105 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"sync"
+
+	"github.com/luxfi/ids"
+)
+
+// frozenViewBuffers pools the record buffers of released FrozenViews
+var frozenViewBuffers = sync.Pool{
+	New: func() any {
+		return new([]GetValidatorOutput)
+	},
+}
+
+// FrozenView is the validators of a net at the moment it was taken, for
+// building or verifying a single block. Unlike a ValidatorView it never
+// changes, and unlike GetMap it doesn't deep copy the records: it reads the
+// net's shared snapshot, and its ordered record list copies the records by
+// value into the buffer of a released view.
+//
+// Records are returned by value, but their slices and maps are shared between
+// readers and must not be modified. The view must not be used after Release.
+type FrozenView struct {
+	snapshot *ValidatorSnapshot
+	// records are the validators in node ID order, held in buffer until the
+	// view is released
+	records []GetValidatorOutput
+	buffer  *[]GetValidatorOutput
+}
+
+// ViewAt returns a frozen view of the validators of [netID]. Callers Release
+// it once the block is built or verified.
+func (m *manager) ViewAt(netID ids.ID) *FrozenView {
+	return newFrozenView(m.Snapshot(netID))
+}
+
+// newFrozenView returns a view of [snapshot] using a pooled buffer
+func newFrozenView(snapshot *ValidatorSnapshot) *FrozenView {
+	buffer := frozenViewBuffers.Get().(*[]GetValidatorOutput)
+	records := (*buffer)[:0]
+	for _, nodeID := range snapshot.nodeIDs {
+		records = append(records, *snapshot.validators[nodeID])
+	}
+	return &FrozenView{
+		snapshot: snapshot,
+		records:  records,
+		buffer:   buffer,
+	}
+}
+
+// NetID returns the net the view was taken of
+func (v *FrozenView) NetID() ids.ID {
+	return v.snapshot.NetID()
+}
+
+// Len returns the number of validators
+func (v *FrozenView) Len() int {
+	return len(v.records)
+}
+
+// Has returns true if [nodeID] is a validator
+func (v *FrozenView) Has(nodeID ids.NodeID) bool {
+	return v.snapshot.Has(nodeID)
+}
+
+// Get returns the record of [nodeID], if it's a validator
+func (v *FrozenView) Get(nodeID ids.NodeID) (GetValidatorOutput, bool) {
+	return v.snapshot.Get(nodeID)
+}
+
+// Light returns the total light of the validators, see
+// ValidatorSnapshot.Light
+func (v *FrozenView) Light() uint64 {
+	return v.snapshot.Light()
+}
+
+// Validators returns the validators in node ID order. The result must not be
+// modified or used after Release.
+func (v *FrozenView) Validators() []GetValidatorOutput {
+	return v.records
+}
+
+// Snapshot returns the snapshot the view reads, which remains valid after
+// Release
+func (v *FrozenView) Snapshot() *ValidatorSnapshot {
+	return v.snapshot
+}
+
+// Release returns the view's buffer to the pool. Releasing a view again is a
+// no-op.
+func (v *FrozenView) Release() {
+	if v.buffer == nil {
+		return
+	}
+	clear(v.records)
+	*v.buffer = v.records[:0]
+	frozenViewBuffers.Put(v.buffer)
+	v.buffer = nil
+	v.records = nil
+	v.snapshot = nil
+}
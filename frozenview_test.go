@@ -0,0 +1,76 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// TestManagerViewAt tests that a frozen view doesn't see later changes and
+// that released views are reused
+func TestManagerViewAt(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID1, nodeID2 := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID1, nil, ids.Empty, 100))
+	require.NoError(m.AddStaker(netID, nodeID2, nil, ids.Empty, 200))
+
+	view := m.ViewAt(netID)
+	require.Equal(netID, view.NetID())
+
+	require.NoError(m.RemoveStaker(netID, nodeID1))
+	require.NoError(m.SetWeight(netID, nodeID2, 50))
+
+	require.Equal(2, view.Len())
+	require.True(view.Has(nodeID1))
+	require.Equal(uint64(300), view.Light())
+	val, ok := view.Get(nodeID2)
+	require.True(ok)
+	require.Equal(uint64(200), val.Light)
+
+	records := view.Validators()
+	require.Len(records, 2)
+	require.Equal(-1, records[0].NodeID.Compare(records[1].NodeID))
+
+	snapshot := view.Snapshot()
+	view.Release()
+	require.Equal(2, snapshot.Len())
+
+	view = m.ViewAt(netID)
+	require.Equal(1, view.Len())
+	require.False(view.Has(nodeID1))
+	require.Equal(uint64(50), view.Light())
+	view.Release()
+}
+
+// TestFrozenViewReleaseTwice tests that releasing a view again doesn't hand
+// its buffer to another view
+func TestFrozenViewReleaseTwice(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	require.NoError(m.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 100))
+
+	view := m.ViewAt(netID)
+	view.Release()
+	other := m.ViewAt(netID)
+	view.Release()
+
+	third := m.ViewAt(netID)
+	require.NoError(m.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 100))
+	fourth := m.ViewAt(netID)
+	require.Len(other.Validators(), 1)
+	require.Len(third.Validators(), 1)
+	require.Len(fourth.Validators(), 2)
+	require.Equal(uint64(100), other.Validators()[0].Light)
+	other.Release()
+	third.Release()
+	fourth.Release()
+}
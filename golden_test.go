@@ -0,0 +1,16 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators_test
+
+import (
+	"testing"
+
+	"github.com/luxfi/validators/validatorstest"
+)
+
+// TestCanonicalGoldenV1 guards the canonical ordering, total weight, and
+// aggregate public keys against silent consensus-breaking changes
+func TestCanonicalGoldenV1(t *testing.T) {
+	validatorstest.RequireCanonicalGolden(t, "testdata/canonical_v1.json")
+}
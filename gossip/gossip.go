@@ -0,0 +1,188 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package gossip selects stake weighted gossip targets among the validators
+// of a net, spreading successive rounds over different peers
+package gossip
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math"
+	"github.com/luxfi/math/set"
+
+	validators "github.com/luxfi/validators"
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid gossip config")
+	ErrInvalidFanout = errors.New("fanout must be positive")
+)
+
+// Config configures a Selector
+type Config struct {
+	// RecentRounds is the number of past rounds of a net whose targets are
+	// only selected once no other validator is left. Zero doesn't track
+	// rounds.
+	RecentRounds int
+}
+
+// Verify returns an error if the config is invalid
+func (c Config) Verify() error {
+	if c.RecentRounds < 0 {
+		return fmt.Errorf("%w: negative recent rounds %d", ErrInvalidConfig, c.RecentRounds)
+	}
+	return nil
+}
+
+// Manager is a validator manager a Selector can read without copying
+type Manager interface {
+	validators.Manager
+	validators.ViewManager
+}
+
+// Selector picks the targets of gossip rounds
+type Selector struct {
+	manager Manager
+	config  Config
+
+	mu  sync.Mutex
+	rng *rand.Rand
+	// recent is the targets of the latest rounds of each net, oldest first
+	recent map[ids.ID][][]ids.NodeID
+}
+
+// New returns a selector drawing from [source]. Selectors with the same
+// source seed make the same selections from the same sets.
+func New(manager Manager, config Config, source rand.Source) (*Selector, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	return &Selector{
+		manager: manager,
+		config:  config,
+		rng:     rand.New(source),
+		recent:  make(map[ids.ID][][]ids.NodeID),
+	}, nil
+}
+
+// SelectGossipTargets draws up to [fanout] validators of [netID] without
+// replacement, each draw picking a remaining validator with probability
+// proportional to its light in the net's sampled unit, see
+// validators.WeightScaleManager. Validators in [exclude] and validators
+// without a whole sampled unit of light are never selected. Targets of the
+// recent rounds are only drawn once every other validator was, so
+// consecutive rounds reach different peers.
+//
+// Candidates are ordered by node ID before drawing, so the selection doesn't
+// depend on map iteration order and validators with equal light are drawn
+// in a reproducible order.
+func (s *Selector) SelectGossipTargets(netID ids.ID, fanout int, exclude set.Set[ids.NodeID]) ([]ids.NodeID, error) {
+	if fanout <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidFanout, fanout)
+	}
+
+	var (
+		scale      = validators.WeightScaleOf(s.manager, netID)
+		candidates []candidate
+	)
+	s.manager.View(netID).Range(func(nodeID ids.NodeID, val validators.GetValidatorOutput) bool {
+		candidates = append(candidates, candidate{nodeID: nodeID, light: val.Light / scale})
+		return true
+	})
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return a.nodeID.Compare(b.nodeID)
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int
+	for _, round := range s.recent[netID] {
+		n += len(round)
+	}
+	recent := set.NewSet[ids.NodeID](n)
+	for _, round := range s.recent[netID] {
+		recent.Add(round...)
+	}
+
+	var fresh, stale []candidate
+	for _, c := range candidates {
+		switch {
+		case c.light == 0 || exclude.Contains(c.nodeID):
+			// Never selected
+		case recent.Contains(c.nodeID):
+			stale = append(stale, c)
+		default:
+			fresh = append(fresh, c)
+		}
+	}
+
+	targets, err := s.draw(nil, fresh, fanout)
+	if err != nil {
+		return nil, err
+	}
+	targets, err = s.draw(targets, stale, fanout)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.config.RecentRounds > 0 {
+		rounds := append(s.recent[netID], targets)
+		if excess := len(rounds) - s.config.RecentRounds; excess > 0 {
+			rounds = rounds[excess:]
+		}
+		s.recent[netID] = rounds
+	}
+	return targets, nil
+}
+
+// Reset forgets the recent rounds of [netID]
+func (s *Selector) Reset(netID ids.ID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.recent, netID)
+}
+
+// candidate is a validator that may be drawn
+type candidate struct {
+	nodeID ids.NodeID
+	light  uint64
+}
+
+// draw appends weighted draws from [candidates] to [targets] until it has
+// [fanout] targets or no candidates are left. It assumes the lock is held.
+func (s *Selector) draw(targets []ids.NodeID, candidates []candidate, fanout int) ([]ids.NodeID, error) {
+	var (
+		weight uint64
+		err    error
+	)
+	for _, c := range candidates {
+		weight, err = math.Add64(weight, c.light)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", validators.ErrWeightOverflow, err)
+		}
+	}
+
+	for len(targets) < fanout && len(candidates) > 0 {
+		target := s.rng.Uint64N(weight)
+		for i, c := range candidates {
+			if target >= c.light {
+				target -= c.light
+				continue
+			}
+
+			targets = append(targets, c.nodeID)
+			weight -= c.light
+			candidates = append(candidates[:i], candidates[i+1:]...)
+			break
+		}
+	}
+	return targets, nil
+}
@@ -0,0 +1,95 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gossip
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math/set"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+func newTestManager(t *testing.T, netID ids.ID, lights ...uint64) (Manager, []ids.NodeID) {
+	m := validators.NewManager()
+	nodeIDs := make([]ids.NodeID, len(lights))
+	for i, light := range lights {
+		nodeIDs[i] = ids.GenerateTestNodeID()
+		require.NoError(t, m.AddStaker(netID, nodeIDs[i], nil, ids.Empty, light))
+	}
+	return m, nodeIDs
+}
+
+// TestSelectGossipTargets tests that selections respect the fanout and the
+// exclusions and are reproducible
+func TestSelectGossipTargets(t *testing.T) {
+	require := require.New(t)
+
+	netID := ids.GenerateTestID()
+	m, nodeIDs := newTestManager(t, netID, 10, 10, 10, 10, 10)
+
+	_, err := New(m, Config{RecentRounds: -1}, rand.NewPCG(0, 0))
+	require.ErrorIs(err, ErrInvalidConfig)
+
+	s, err := New(m, Config{}, rand.NewPCG(1, 2))
+	require.NoError(err)
+	_, err = s.SelectGossipTargets(netID, 0, nil)
+	require.ErrorIs(err, ErrInvalidFanout)
+
+	exclude := set.Of(nodeIDs[0], nodeIDs[1])
+	targets, err := s.SelectGossipTargets(netID, 3, exclude)
+	require.NoError(err)
+	require.ElementsMatch(nodeIDs[2:], targets)
+
+	// The same seed draws the same targets
+	s1, err := New(m, Config{}, rand.NewPCG(3, 4))
+	require.NoError(err)
+	s2, err := New(m, Config{}, rand.NewPCG(3, 4))
+	require.NoError(err)
+	for range 10 {
+		targets1, err := s1.SelectGossipTargets(netID, 2, nil)
+		require.NoError(err)
+		targets2, err := s2.SelectGossipTargets(netID, 2, nil)
+		require.NoError(err)
+		require.Equal(targets1, targets2)
+		require.Len(targets1, 2)
+	}
+
+	// Fanouts above the set size return every validator
+	targets, err = s.SelectGossipTargets(netID, 10, nil)
+	require.NoError(err)
+	require.ElementsMatch(nodeIDs, targets)
+}
+
+// TestSelectGossipTargetsRecent tests that recent targets are only drawn once
+// no other validator is left
+func TestSelectGossipTargetsRecent(t *testing.T) {
+	require := require.New(t)
+
+	netID := ids.GenerateTestID()
+	m, nodeIDs := newTestManager(t, netID, 1, 1, 1, 1_000_000)
+
+	s, err := New(m, Config{RecentRounds: 1}, rand.NewPCG(5, 6))
+	require.NoError(err)
+	first, err := s.SelectGossipTargets(netID, 2, nil)
+	require.NoError(err)
+	second, err := s.SelectGossipTargets(netID, 2, nil)
+	require.NoError(err)
+	require.Empty(set.Of(first...).Intersection(set.Of(second...)))
+	require.Contains(first, nodeIDs[3])
+
+	// Once the only fresh validators are exhausted, recent ones are drawn
+	third, err := s.SelectGossipTargets(netID, 3, nil)
+	require.NoError(err)
+	require.Len(third, 3)
+	require.ElementsMatch(first, third[:2])
+
+	s.Reset(netID)
+	targets, err := s.SelectGossipTargets(netID, 1, nil)
+	require.NoError(err)
+	require.Equal([]ids.NodeID{nodeIDs[3]}, targets)
+}
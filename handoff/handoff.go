@@ -0,0 +1,200 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package handoff produces and verifies validator set handoffs: the canonical
+// set of a net at a height, signed by a quorum of the set at the previous
+// height. A chain tracking the net's validators remotely only has to trust
+// one set to follow every later one.
+package handoff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math/set"
+
+	validators "github.com/luxfi/validators"
+)
+
+// messagePrefix separates handoff messages from every other message signed
+// by validators
+var messagePrefix = []byte("lux/validator-set-handoff")
+
+var (
+	ErrChecksumMismatch = errors.New("trusted set doesn't match handoff")
+	ErrInvalidSignature = errors.New("invalid handoff signature")
+	ErrNotSigner        = errors.New("node can't sign handoff")
+	ErrNonCanonicalSet  = errors.New("handoff set isn't canonical")
+	ErrNoQuorum         = errors.New("handoff isn't signed by a quorum")
+)
+
+// Handoff is the canonical set of NetID at Height signed by a quorum of the
+// set at Height-1, identified by FromChecksum
+type Handoff struct {
+	NetID        ids.ID
+	Height       uint64
+	FromChecksum ids.ID
+	Next         validators.CanonicalValidatorSet
+	// Signers are the indices of the signers in the previous set
+	Signers   set.Bits
+	Signature []byte
+}
+
+// Message returns the bytes signed to hand off to the set with checksum
+// [nextChecksum] of [netID] at [height]
+func Message(netID ids.ID, height uint64, fromChecksum, nextChecksum ids.ID) []byte {
+	msg := make([]byte, 0, len(messagePrefix)+len(netID)+8+len(fromChecksum)+len(nextChecksum))
+	msg = append(msg, messagePrefix...)
+	msg = append(msg, netID[:]...)
+	msg = binary.BigEndian.AppendUint64(msg, height)
+	msg = append(msg, fromChecksum[:]...)
+	return append(msg, nextChecksum[:]...)
+}
+
+// Builder collects signatures of the previous set over a handoff
+type Builder struct {
+	netID  ids.ID
+	height uint64
+	from   validators.CanonicalValidatorSet
+	next   validators.CanonicalValidatorSet
+	quorum validators.QuorumConfig
+	msg    []byte
+
+	signatures map[int]*bls.Signature
+}
+
+// NewBuilder returns a builder of the handoff from [from], the set of [netID]
+// at [height]-1, to [next], the set at [height]
+func NewBuilder(
+	netID ids.ID,
+	height uint64,
+	from validators.CanonicalValidatorSet,
+	next validators.CanonicalValidatorSet,
+	quorum validators.QuorumConfig,
+) (*Builder, error) {
+	if err := quorum.Verify(); err != nil {
+		return nil, err
+	}
+	return &Builder{
+		netID:      netID,
+		height:     height,
+		from:       from,
+		next:       next,
+		quorum:     quorum,
+		msg:        Message(netID, height, from.Checksum(), next.Checksum()),
+		signatures: make(map[int]*bls.Signature),
+	}, nil
+}
+
+// Message returns the bytes the previous set signs
+func (b *Builder) Message() []byte {
+	return b.msg
+}
+
+// AddSignature verifies and records [nodeID]'s signature of Message. Nodes
+// sharing a public key sign once.
+func (b *Builder) AddSignature(nodeID ids.NodeID, signature *bls.Signature) error {
+	index, _, ok := b.from.CanSign(nodeID)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotSigner, nodeID)
+	}
+	if !bls.Verify(b.from.Validators[index].PublicKey, signature, b.msg) {
+		return fmt.Errorf("%w: from %s", ErrInvalidSignature, nodeID)
+	}
+	b.signatures[index] = signature
+	return nil
+}
+
+// Build aggregates the recorded signatures into a handoff. It fails if the
+// signers don't meet the quorum.
+func (b *Builder) Build() (*Handoff, error) {
+	var (
+		signers    = set.NewBits()
+		signatures = make([]*bls.Signature, 0, len(b.signatures))
+		signerVdrs = make([]*validators.CanonicalValidator, 0, len(b.signatures))
+	)
+	for index, vdr := range b.from.Validators {
+		signature, ok := b.signatures[index]
+		if !ok {
+			continue
+		}
+		signers.Add(index)
+		signatures = append(signatures, signature)
+		signerVdrs = append(signerVdrs, vdr)
+	}
+	if err := b.quorum.Check(signerVdrs, b.from.TotalWeight); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNoQuorum, err)
+	}
+	aggregate, err := bls.AggregateSignatures(signatures)
+	if err != nil {
+		return nil, err
+	}
+	return &Handoff{
+		NetID:        b.netID,
+		Height:       b.height,
+		FromChecksum: b.from.Checksum(),
+		Next:         b.next,
+		Signers:      signers,
+		Signature:    bls.SignatureToBytes(aggregate),
+	}, nil
+}
+
+// Verify returns nil if [handoff] is signed by [quorum] of [trusted], the set
+// the verifier trusts at handoff.Height-1. On success the verifier can trust
+// handoff.Next at handoff.Height.
+func Verify(trusted validators.CanonicalValidatorSet, handoff *Handoff, quorum validators.QuorumConfig) error {
+	if checksum := trusted.Checksum(); checksum != handoff.FromChecksum {
+		return fmt.Errorf("%w: trusted %s, handoff from %s", ErrChecksumMismatch, checksum, handoff.FromChecksum)
+	}
+	if err := verifyCanonical(handoff.Next); err != nil {
+		return err
+	}
+
+	signers, err := validators.FilterValidators(handoff.Signers, trusted.Validators)
+	if err != nil {
+		return err
+	}
+	if err := quorum.Check(signers, trusted.TotalWeight); err != nil {
+		return fmt.Errorf("%w: %w", ErrNoQuorum, err)
+	}
+	aggregateKey, err := validators.AggregatePublicKeys(signers)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	signature, err := bls.SignatureFromBytes(handoff.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	msg := Message(handoff.NetID, handoff.Height, handoff.FromChecksum, handoff.Next.Checksum())
+	if !bls.Verify(aggregateKey, signature, msg) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// verifyCanonical returns an error if [vdrSet] couldn't have been produced by
+// validators.FlattenValidatorSet. The checksum covers the key bytes, so the
+// parsed keys must match them.
+func verifyCanonical(vdrSet validators.CanonicalValidatorSet) error {
+	var weight uint64
+	for i, vdr := range vdrSet.Validators {
+		if vdr.PublicKey == nil || !bytes.Equal(bls.PublicKeyToUncompressedBytes(vdr.PublicKey), vdr.PublicKeyBytes) {
+			return fmt.Errorf("%w: validator %d key doesn't match its bytes", ErrNonCanonicalSet, i)
+		}
+		if i > 0 && vdrSet.Validators[i-1].Compare(vdr) >= 0 {
+			return fmt.Errorf("%w: validator %d is out of order", ErrNonCanonicalSet, i)
+		}
+		if weight+vdr.Weight < weight {
+			return fmt.Errorf("%w: %w", ErrNonCanonicalSet, validators.ErrWeightOverflow)
+		}
+		weight += vdr.Weight
+	}
+	if weight > vdrSet.TotalWeight {
+		return fmt.Errorf("%w: validator weight %d exceeds total %d", ErrNonCanonicalSet, weight, vdrSet.TotalWeight)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package handoff
+
+import (
+	"testing"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+	"github.com/luxfi/validators/validatorstest"
+)
+
+type testValidator struct {
+	nodeID ids.NodeID
+	sk     *bls.SecretKey
+}
+
+func newTestSet(t *testing.T, seed uint64, weights ...uint64) ([]testValidator, validators.CanonicalValidatorSet) {
+	generated, err := validatorstest.GenerateValidatorSet(seed, len(weights), validatorstest.ConstantWeights(1))
+	require.NoError(t, err)
+	vdrs := make([]testValidator, len(weights))
+	vdrSet := make(map[ids.NodeID]*validators.GetValidatorOutput, len(weights))
+	for i, weight := range weights {
+		vdr := generated.Validators[i]
+		vdrs[i] = testValidator{nodeID: vdr.NodeID, sk: vdr.SecretKey}
+		vdrSet[vdrs[i].nodeID] = &validators.GetValidatorOutput{
+			NodeID:    vdrs[i].nodeID,
+			PublicKey: vdr.PublicKeyBytes,
+			Light:     weight,
+			Weight:    weight,
+		}
+	}
+	canonical, err := validators.FlattenValidatorSet(vdrSet)
+	require.NoError(t, err)
+	return vdrs, canonical
+}
+
+// TestHandoff tests that a quorum signed handoff verifies against the
+// previous set
+func TestHandoff(t *testing.T) {
+	require := require.New(t)
+
+	netID := ids.GenerateTestID()
+	fromVdrs, from := newTestSet(t, 1, 40, 30, 20, 10)
+	_, next := newTestSet(t, 2, 50, 50)
+	quorum := validators.DefaultQuorumConfig()
+
+	b, err := NewBuilder(netID, 11, from, next, quorum)
+	require.NoError(err)
+
+	sign := func(vdr testValidator) {
+		sig, err := vdr.sk.Sign(b.Message())
+		require.NoError(err)
+		require.NoError(b.AddSignature(vdr.nodeID, sig))
+	}
+	// 60% doesn't reach the quorum
+	sign(fromVdrs[0])
+	sign(fromVdrs[2])
+	_, err = b.Build()
+	require.ErrorIs(err, ErrNoQuorum)
+
+	sign(fromVdrs[1])
+	handoff, err := b.Build()
+	require.NoError(err)
+	require.Equal(uint64(11), handoff.Height)
+	require.NoError(Verify(from, handoff, quorum))
+
+	// The next set can't be swapped
+	_, other := newTestSet(t, 3, 100)
+	forged := *handoff
+	forged.Next = other
+	require.ErrorIs(Verify(from, &forged, quorum), ErrInvalidSignature)
+
+	// The handoff only verifies against the set that signed it
+	require.ErrorIs(Verify(next, handoff, quorum), ErrChecksumMismatch)
+
+	// A stricter verifier rejects it
+	strict := validators.QuorumConfig{Numerator: 19, Denominator: 20}
+	require.ErrorIs(Verify(from, handoff, strict), ErrNoQuorum)
+}
+
+// TestBuilderAddSignature tests that only valid signatures of the previous set
+// are recorded
+func TestBuilderAddSignature(t *testing.T) {
+	require := require.New(t)
+
+	fromVdrs, from := newTestSet(t, 1, 1, 1)
+	nextVdrs, next := newTestSet(t, 2, 1)
+	b, err := NewBuilder(ids.GenerateTestID(), 1, from, next, validators.DefaultQuorumConfig())
+	require.NoError(err)
+
+	sig, err := nextVdrs[0].sk.Sign(b.Message())
+	require.NoError(err)
+	require.ErrorIs(b.AddSignature(nextVdrs[0].nodeID, sig), ErrNotSigner)
+	require.ErrorIs(b.AddSignature(fromVdrs[0].nodeID, sig), ErrInvalidSignature)
+
+	_, err = NewBuilder(ids.GenerateTestID(), 1, from, next, validators.QuorumConfig{})
+	require.ErrorIs(err, validators.ErrInvalidQuorum)
+}
+
+// TestVerifyNonCanonical tests that malformed next sets are rejected
+func TestVerifyNonCanonical(t *testing.T) {
+	require := require.New(t)
+
+	_, next := newTestSet(t, 2, 1, 2)
+	require.NoError(verifyCanonical(next))
+
+	reordered := next
+	reordered.Validators = []*validators.CanonicalValidator{next.Validators[1], next.Validators[0]}
+	require.ErrorIs(verifyCanonical(reordered), ErrNonCanonicalSet)
+
+	underweight := next
+	underweight.TotalWeight = 1
+	require.ErrorIs(verifyCanonical(underweight), ErrNonCanonicalSet)
+}
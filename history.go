@@ -0,0 +1,285 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/luxfi/ids"
+)
+
+// DefaultHistoryRetention is the number of heights a manager keeps by default
+const DefaultHistoryRetention = 256
+
+var ErrInvalidRetention = errors.New("retention must be positive")
+
+// HistoryManager records the validator sets of a manager at accepted heights,
+// so the manager can directly back State.GetValidatorSet for recent heights.
+//
+// The first SetHeight records the sets as they are; each later one records
+// the changes made since the previous, as the diff of the new height. The key
+// time is recorded along, so the Warp sets of a height leave out the same
+// expired keys whenever they're read. Only the latest retained heights are
+// kept.
+type HistoryManager interface {
+	// SetHeight records the current sets at [height], which must be above
+	// the previously recorded height
+	SetHeight(height uint64) error
+	// OnHeightAccepted is SetHeight, for consensus acceptance callbacks
+	OnHeightAccepted(height uint64) error
+	// SetHistoryRetention sets the number of heights kept, dropping the
+	// oldest heights beyond it
+	SetHistoryRetention(retention int) error
+	// HeightBounds returns the oldest and latest recorded heights, and false
+	// if no height was recorded
+	HeightBounds() (uint64, uint64, bool)
+	// GetValidatorSetAt returns the set of [netID] as of the latest recorded
+	// height at or below [height]. Heights before the oldest recorded one
+	// return ErrUnknownHeight.
+	GetValidatorSetAt(netID ids.ID, height uint64) (map[ids.NodeID]*GetValidatorOutput, error)
+	// GetWarpSetAt returns the Warp set of [netID] as of the latest recorded
+	// height at or below [height], committed to, with its Height set to the
+	// recorded height. Keys expired as of the key time at that height are
+	// left out. Sets are built once per recorded height and shared, so they
+	// must not be modified.
+	GetWarpSetAt(netID ids.ID, height uint64) (*WarpSet, error)
+}
+
+var _ HistoryManager = (*manager)(nil)
+
+// history is the sets of a manager at its retained heights: the full sets at
+// the oldest height, and the records that changed at each later one
+type history struct {
+	retention int
+	started   bool
+	oldest    uint64
+	// oldestKeyTime is the key time at the oldest height
+	oldestKeyTime time.Time
+	base          map[ids.ID]map[ids.NodeID]*GetValidatorOutput
+	diffs         []heightDiff
+	// dirty is the records changed since the latest height
+	dirty map[validatorKey]struct{}
+
+	// warpSets caches the Warp sets built by GetWarpSetAt. It's filled by
+	// readers holding the manager's read lock, so it has its own.
+	warpLock sync.Mutex
+	warpSets map[heightNet]*WarpSet
+}
+
+// heightDiff is the records that changed at a height. A nil record was
+// removed.
+type heightDiff struct {
+	height  uint64
+	keyTime time.Time
+	records map[validatorKey]*GetValidatorOutput
+}
+
+type heightNet struct {
+	height uint64
+	netID  ids.ID
+}
+
+func newHistory() *history {
+	return &history{
+		retention: DefaultHistoryRetention,
+		dirty:     make(map[validatorKey]struct{}),
+		warpSets:  make(map[heightNet]*WarpSet),
+	}
+}
+
+// latest returns the latest recorded height
+func (h *history) latest() uint64 {
+	if len(h.diffs) == 0 {
+		return h.oldest
+	}
+	return h.diffs[len(h.diffs)-1].height
+}
+
+// at returns the latest recorded height at or below [height], the number of
+// diffs recorded up to it, and the key time at it
+func (h *history) at(height uint64) (uint64, int, time.Time, error) {
+	if !h.started || height < h.oldest {
+		return 0, 0, time.Time{}, fmt.Errorf("%w: %d", ErrUnknownHeight, height)
+	}
+	n, found := slices.BinarySearchFunc(h.diffs, height, func(diff heightDiff, height uint64) int {
+		return cmp.Compare(diff.height, height)
+	})
+	if found {
+		n++
+	}
+	if n == 0 {
+		return h.oldest, 0, h.oldestKeyTime, nil
+	}
+	diff := h.diffs[n-1]
+	return diff.height, n, diff.keyTime, nil
+}
+
+// set returns the set of [netID] with the first [n] diffs applied
+func (h *history) set(netID ids.ID, n int) map[ids.NodeID]*GetValidatorOutput {
+	vdrs := copyValidators(h.base[netID])
+	for _, diff := range h.diffs[:n] {
+		for key, record := range diff.records {
+			if key.netID != netID {
+				continue
+			}
+			if record == nil {
+				delete(vdrs, key.nodeID)
+				continue
+			}
+			vdrs[key.nodeID] = copyValidator(record)
+		}
+	}
+	return vdrs
+}
+
+// SetHeight records the sets of the manager at a height
+func (m *manager) SetHeight(height uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h := m.history
+	switch {
+	case !h.started:
+		h.started = true
+		h.oldest = height
+		h.oldestKeyTime = m.keyTime
+		h.base = make(map[ids.ID]map[ids.NodeID]*GetValidatorOutput, len(m.validators))
+		for netID, vdrs := range m.validators {
+			h.base[netID] = copyValidators(vdrs)
+		}
+		clear(h.dirty)
+		return nil
+	case height <= h.latest():
+		return fmt.Errorf("%w: %d after %d", ErrNonIncreasingHeight, height, h.latest())
+	}
+
+	diff := heightDiff{
+		height:  height,
+		keyTime: m.keyTime,
+		records: make(map[validatorKey]*GetValidatorOutput, len(h.dirty)),
+	}
+	for key := range h.dirty {
+		var record *GetValidatorOutput
+		if val, ok := m.validators[key.netID][key.nodeID]; ok {
+			record = copyValidator(val)
+		}
+		diff.records[key] = record
+	}
+	clear(h.dirty)
+	h.diffs = append(h.diffs, diff)
+	m.pruneHistory()
+	return nil
+}
+
+// OnHeightAccepted records the sets of the manager at an accepted height
+func (m *manager) OnHeightAccepted(height uint64) error {
+	return m.SetHeight(height)
+}
+
+// SetHistoryRetention sets the number of recorded heights the manager keeps
+func (m *manager) SetHistoryRetention(retention int) error {
+	if retention <= 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidRetention, retention)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.history.retention = retention
+	m.pruneHistory()
+	return nil
+}
+
+// HeightBounds returns the oldest and latest recorded heights
+func (m *manager) HeightBounds() (uint64, uint64, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	h := m.history
+	return h.oldest, h.latest(), h.started
+}
+
+// GetValidatorSetAt returns the set of a net at a recorded height
+func (m *manager) GetValidatorSetAt(netID ids.ID, height uint64) (map[ids.NodeID]*GetValidatorOutput, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, n, _, err := m.history.at(height)
+	if err != nil {
+		return nil, err
+	}
+	return m.history.set(netID, n), nil
+}
+
+// GetWarpSetAt returns the committed Warp set of a net at a recorded height
+func (m *manager) GetWarpSetAt(netID ids.ID, height uint64) (*WarpSet, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	h := m.history
+	recorded, n, keyTime, err := h.at(height)
+	if err != nil {
+		return nil, err
+	}
+
+	h.warpLock.Lock()
+	defer h.warpLock.Unlock()
+
+	key := heightNet{height: recorded, netID: netID}
+	if warpSet, ok := h.warpSets[key]; ok {
+		return warpSet, nil
+	}
+	warpSet := buildWarpSet(h.set(netID, n), keyTime)
+	warpSet.Height = recorded
+	warpSet.Commit()
+	h.warpSets[key] = warpSet
+	return warpSet, nil
+}
+
+// markDirty records that [nodeID] changed in [netID] since the latest
+// height. It assumes the lock is held.
+func (m *manager) markDirty(netID ids.ID, nodeID ids.NodeID) {
+	if m.history.started {
+		m.history.dirty[validatorKey{netID: netID, nodeID: nodeID}] = struct{}{}
+	}
+}
+
+// pruneHistory folds the heights beyond the retention into the base. It
+// assumes the lock is held.
+func (m *manager) pruneHistory() {
+	h := m.history
+	// The oldest height is kept as the base, so it counts towards retention
+	excess := len(h.diffs) + 1 - h.retention
+	if excess <= 0 {
+		return
+	}
+	for _, diff := range h.diffs[:excess] {
+		for key, record := range diff.records {
+			if record == nil {
+				delete(h.base[key.netID], key.nodeID)
+				if len(h.base[key.netID]) == 0 {
+					delete(h.base, key.netID)
+				}
+				continue
+			}
+			if h.base[key.netID] == nil {
+				h.base[key.netID] = make(map[ids.NodeID]*GetValidatorOutput)
+			}
+			h.base[key.netID][key.nodeID] = record
+		}
+		h.oldest = diff.height
+		h.oldestKeyTime = diff.keyTime
+	}
+	h.diffs = slices.Delete(h.diffs, 0, excess)
+	for key := range h.warpSets {
+		if key.height < h.oldest {
+			delete(h.warpSets, key)
+		}
+	}
+}
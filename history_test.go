@@ -0,0 +1,107 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// TestManagerHistory tests that sets are recorded at heights and read back
+func TestManagerHistory(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID, otherNetID := ids.GenerateTestID(), ids.GenerateTestID()
+	nodeID1, nodeID2 := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID1, nil, ids.Empty, 100))
+
+	_, err := m.GetValidatorSetAt(netID, 10)
+	require.ErrorIs(err, ErrUnknownHeight)
+	_, _, ok := m.HeightBounds()
+	require.False(ok)
+
+	require.NoError(m.SetHeight(10))
+
+	require.NoError(m.AddStaker(netID, nodeID2, nil, ids.Empty, 50))
+	require.NoError(m.AddWeight(netID, nodeID1, 20))
+	require.NoError(m.AddStaker(otherNetID, nodeID1, nil, ids.Empty, 5))
+	require.NoError(m.OnHeightAccepted(12))
+
+	require.NoError(m.RemoveStaker(netID, nodeID1))
+	require.NoError(m.SetHeight(13))
+
+	// Changes after the latest height aren't recorded yet
+	require.NoError(m.SetWeight(netID, nodeID2, 70))
+
+	lights := func(height uint64) map[ids.NodeID]uint64 {
+		vdrs, err := m.GetValidatorSetAt(netID, height)
+		require.NoError(err)
+		lights := make(map[ids.NodeID]uint64, len(vdrs))
+		for nodeID, vdr := range vdrs {
+			lights[nodeID] = vdr.Light
+		}
+		return lights
+	}
+	require.Equal(map[ids.NodeID]uint64{nodeID1: 100}, lights(10))
+	require.Equal(map[ids.NodeID]uint64{nodeID1: 100}, lights(11))
+	require.Equal(map[ids.NodeID]uint64{nodeID1: 120, nodeID2: 50}, lights(12))
+	require.Equal(map[ids.NodeID]uint64{nodeID2: 50}, lights(13))
+	require.Equal(map[ids.NodeID]uint64{nodeID2: 50}, lights(100))
+
+	other, err := m.GetValidatorSetAt(otherNetID, 11)
+	require.NoError(err)
+	require.Empty(other)
+
+	_, err = m.GetValidatorSetAt(netID, 9)
+	require.ErrorIs(err, ErrUnknownHeight)
+	require.ErrorIs(m.SetHeight(13), ErrNonIncreasingHeight)
+
+	// Returned sets are copies
+	vdrs, err := m.GetValidatorSetAt(netID, 13)
+	require.NoError(err)
+	vdrs[nodeID2].Light = 1
+	require.Equal(map[ids.NodeID]uint64{nodeID2: 50}, lights(13))
+
+	oldest, latest, ok := m.HeightBounds()
+	require.True(ok)
+	require.Equal(uint64(10), oldest)
+	require.Equal(uint64(13), latest)
+}
+
+// TestManagerHistoryRetention tests that heights beyond the retention are
+// dropped without changing the retained ones
+func TestManagerHistoryRetention(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.ErrorIs(m.SetHistoryRetention(0), ErrInvalidRetention)
+
+	require.NoError(m.SetHeight(1))
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+	require.NoError(m.SetHeight(2))
+	require.NoError(m.AddWeight(netID, nodeID, 10))
+	require.NoError(m.SetHeight(3))
+	require.NoError(m.RemoveStaker(netID, nodeID))
+	require.NoError(m.SetHeight(4))
+
+	require.NoError(m.SetHistoryRetention(2))
+	oldest, latest, ok := m.HeightBounds()
+	require.True(ok)
+	require.Equal(uint64(3), oldest)
+	require.Equal(uint64(4), latest)
+
+	_, err := m.GetValidatorSetAt(netID, 2)
+	require.ErrorIs(err, ErrUnknownHeight)
+	vdrs, err := m.GetValidatorSetAt(netID, 3)
+	require.NoError(err)
+	require.Equal(uint64(110), vdrs[nodeID].Light)
+	vdrs, err = m.GetValidatorSetAt(netID, 4)
+	require.NoError(err)
+	require.Empty(vdrs)
+}
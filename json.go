@@ -0,0 +1,298 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+)
+
+// The JSON encodings of validator sets hex encode keys and other bytes,
+// accepting an optional 0x prefix when decoding, and encode node IDs and
+// other IDs as their CB58 strings. Validators of maps are encoded as lists in
+// node ID order, so equal sets encode to equal bytes.
+
+var ErrInvalidJSON = errors.New("invalid validator JSON")
+
+type jsonWarpValidator struct {
+	NodeID         ids.NodeID `json:"nodeID"`
+	PublicKey      string     `json:"publicKey"`
+	RingtailPubKey string     `json:"ringtailPublicKey,omitempty"`
+	Weight         uint64     `json:"weight"`
+}
+
+// MarshalJSON implements json.Marshaler
+func (v *WarpValidator) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonWarpValidator{
+		NodeID:         v.NodeID,
+		PublicKey:      hex.EncodeToString(v.PublicKey),
+		RingtailPubKey: hex.EncodeToString(v.RingtailPubKey),
+		Weight:         v.Weight,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (v *WarpValidator) UnmarshalJSON(b []byte) error {
+	var j jsonWarpValidator
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	publicKey, err := decodeJSONHex("publicKey", j.PublicKey)
+	if err != nil {
+		return err
+	}
+	ringtailPubKey, err := decodeJSONHex("ringtailPublicKey", j.RingtailPubKey)
+	if err != nil {
+		return err
+	}
+	*v = WarpValidator{
+		NodeID:         j.NodeID,
+		PublicKey:      publicKey,
+		RingtailPubKey: ringtailPubKey,
+		Weight:         j.Weight,
+	}
+	return nil
+}
+
+type jsonWarpSet struct {
+	Height     uint64           `json:"height"`
+	Validators []*WarpValidator `json:"validators"`
+	Commitment *ids.ID          `json:"commitment,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler
+func (s *WarpSet) MarshalJSON() ([]byte, error) {
+	j := jsonWarpSet{
+		Height:     s.Height,
+		Validators: make([]*WarpValidator, 0, len(s.Validators)),
+	}
+	for _, nodeID := range sortNodeIDs(slices.Collect(maps.Keys(s.Validators))) {
+		j.Validators = append(j.Validators, s.Validators[nodeID])
+	}
+	if s.Commitment != ids.Empty {
+		j.Commitment = &s.Commitment
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Validators listed twice are
+// rejected.
+func (s *WarpSet) UnmarshalJSON(b []byte) error {
+	var j jsonWarpSet
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	vdrs := make(map[ids.NodeID]*WarpValidator, len(j.Validators))
+	for _, vdr := range j.Validators {
+		if vdr == nil {
+			return fmt.Errorf("%w: null validator", ErrInvalidJSON)
+		}
+		if _, ok := vdrs[vdr.NodeID]; ok {
+			return fmt.Errorf("%w: %s listed twice", ErrInvalidJSON, vdr.NodeID)
+		}
+		vdrs[vdr.NodeID] = vdr
+	}
+	*s = WarpSet{
+		Height:     j.Height,
+		Validators: vdrs,
+	}
+	if j.Commitment != nil {
+		s.Commitment = *j.Commitment
+	}
+	return nil
+}
+
+type jsonCanonicalValidator struct {
+	PublicKey      string       `json:"publicKey"`
+	RingtailPubKey string       `json:"ringtailPublicKey,omitempty"`
+	Weight         uint64       `json:"weight"`
+	NodeIDs        []ids.NodeID `json:"nodeIDs"`
+}
+
+// MarshalJSON implements json.Marshaler. The public key is compressed.
+func (v *CanonicalValidator) MarshalJSON() ([]byte, error) {
+	if v.PublicKey == nil {
+		return nil, fmt.Errorf("%w: validator has no public key", ErrInvalidJSON)
+	}
+	return json.Marshal(jsonCanonicalValidator{
+		PublicKey:      hex.EncodeToString(bls.PublicKeyToCompressedBytes(v.PublicKey)),
+		RingtailPubKey: hex.EncodeToString(v.RingtailPubKey),
+		Weight:         v.Weight,
+		NodeIDs:        v.NodeIDs,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The public key must be valid
+// and the node IDs in ascending order.
+func (v *CanonicalValidator) UnmarshalJSON(b []byte) error {
+	var j jsonCanonicalValidator
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	if !nodeIDsAscending(j.NodeIDs) {
+		return fmt.Errorf("%w: node IDs aren't in ascending order", ErrInvalidJSON)
+	}
+	keyBytes, err := decodeJSONHex("publicKey", j.PublicKey)
+	if err != nil {
+		return err
+	}
+	publicKey, err := bls.PublicKeyFromCompressedBytes(keyBytes)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPublicKey, err)
+	}
+	ringtailPubKey, err := decodeJSONHex("ringtailPublicKey", j.RingtailPubKey)
+	if err != nil {
+		return err
+	}
+	*v = CanonicalValidator{
+		PublicKey:      publicKey,
+		PublicKeyBytes: bls.PublicKeyToUncompressedBytes(publicKey),
+		RingtailPubKey: ringtailPubKey,
+		Weight:         j.Weight,
+		NodeIDs:        j.NodeIDs,
+	}
+	return nil
+}
+
+type jsonCanonicalValidatorSet struct {
+	Validators  []*CanonicalValidator `json:"validators"`
+	TotalWeight uint64                `json:"totalWeight"`
+}
+
+// MarshalJSON implements json.Marshaler. Validators keep their canonical
+// order.
+func (s *CanonicalValidatorSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonCanonicalValidatorSet{
+		Validators:  s.Validators,
+		TotalWeight: s.TotalWeight,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The validators must be in
+// canonical order.
+func (s *CanonicalValidatorSet) UnmarshalJSON(b []byte) error {
+	var j jsonCanonicalValidatorSet
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	for i, vdr := range j.Validators {
+		if vdr == nil {
+			return fmt.Errorf("%w: null validator", ErrInvalidJSON)
+		}
+		if i > 0 && j.Validators[i-1].Compare(vdr) >= 0 {
+			return fmt.Errorf("%w: validator %d isn't in canonical order", ErrInvalidJSON, i)
+		}
+	}
+	*s = CanonicalValidatorSet{
+		Validators:  j.Validators,
+		TotalWeight: j.TotalWeight,
+	}
+	return nil
+}
+
+type jsonValidatorOutput struct {
+	NodeID         ids.NodeID         `json:"nodeID"`
+	PublicKey      string             `json:"publicKey,omitempty"`
+	RingtailPubKey string             `json:"ringtailPublicKey,omitempty"`
+	Light          uint64             `json:"light"`
+	Weight         uint64             `json:"weight"`
+	TxID           ids.ID             `json:"txID"`
+	Metadata       *ValidatorMetadata `json:"metadata,omitempty"`
+	Extensions     map[string]string  `json:"extensions,omitempty"`
+	KeyExpiry      *time.Time         `json:"keyExpiry,omitempty"`
+	Sequence       uint64             `json:"sequence"`
+	Pending        bool               `json:"pending,omitempty"`
+	Connected      bool               `json:"connected,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. Extensions are hex encoded.
+func (v *GetValidatorOutput) MarshalJSON() ([]byte, error) {
+	j := jsonValidatorOutput{
+		NodeID:         v.NodeID,
+		PublicKey:      hex.EncodeToString(v.PublicKey),
+		RingtailPubKey: hex.EncodeToString(v.RingtailPubKey),
+		Light:          v.Light,
+		Weight:         v.Weight,
+		TxID:           v.TxID,
+		Metadata:       v.Metadata,
+		Sequence:       v.Sequence,
+		Pending:        v.Pending,
+		Connected:      v.Connected,
+	}
+	if len(v.Extensions) > 0 {
+		j.Extensions = make(map[string]string, len(v.Extensions))
+		for key, value := range v.Extensions {
+			j.Extensions[key] = hex.EncodeToString(value)
+		}
+	}
+	if !v.KeyExpiry.IsZero() {
+		j.KeyExpiry = &v.KeyExpiry
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (v *GetValidatorOutput) UnmarshalJSON(b []byte) error {
+	var j jsonValidatorOutput
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	publicKey, err := decodeJSONHex("publicKey", j.PublicKey)
+	if err != nil {
+		return err
+	}
+	ringtailPubKey, err := decodeJSONHex("ringtailPublicKey", j.RingtailPubKey)
+	if err != nil {
+		return err
+	}
+	var extensions map[string][]byte
+	if len(j.Extensions) > 0 {
+		extensions = make(map[string][]byte, len(j.Extensions))
+		for key, value := range j.Extensions {
+			extensions[key], err = decodeJSONHex("extension "+key, value)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	*v = GetValidatorOutput{
+		NodeID:         j.NodeID,
+		PublicKey:      publicKey,
+		RingtailPubKey: ringtailPubKey,
+		Light:          j.Light,
+		Weight:         j.Weight,
+		TxID:           j.TxID,
+		Metadata:       j.Metadata,
+		Extensions:     extensions,
+		Sequence:       j.Sequence,
+		Pending:        j.Pending,
+		Connected:      j.Connected,
+	}
+	if j.KeyExpiry != nil {
+		v.KeyExpiry = *j.KeyExpiry
+	}
+	return nil
+}
+
+// decodeJSONHex decodes the hex of field [name], which may be 0x prefixed.
+// An empty string decodes to nil.
+func decodeJSONHex(name, s string) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidJSON, name, err)
+	}
+	return b, nil
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/validators/validatorstest/fixture"
+)
+
+// TestWarpSetJSON tests that Warp sets round trip with hex keys and CB58 node
+// IDs
+func TestWarpSetJSON(t *testing.T) {
+	require := require.New(t)
+
+	generated, err := fixture.Generate(1, 1, fixture.ConstantWeights(1))
+	require.NoError(err)
+	sk := generated[0].SecretKey
+	nodeID1, nodeID2 := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	warpSet := &WarpSet{
+		Height: 7,
+		Validators: map[ids.NodeID]*WarpValidator{
+			nodeID1: {NodeID: nodeID1, PublicKey: bls.PublicKeyToCompressedBytes(sk.PublicKey()), Weight: 10},
+			nodeID2: {NodeID: nodeID2, PublicKey: bls.PublicKeyToCompressedBytes(sk.PublicKey()), RingtailPubKey: []byte{1, 2}, Weight: 20},
+		},
+	}
+	warpSet.Commit()
+
+	b, err := json.Marshal(warpSet)
+	require.NoError(err)
+	require.Contains(string(b), nodeID1.String())
+	require.Contains(string(b), hex.EncodeToString(warpSet.Validators[nodeID1].PublicKey))
+
+	var decoded WarpSet
+	require.NoError(json.Unmarshal(b, &decoded))
+	require.Equal(warpSet.Height, decoded.Height)
+	require.Equal(warpSet.Commitment, decoded.Commitment)
+	require.Len(decoded.Validators, 2)
+	require.Equal(warpSet.Validators[nodeID2].RingtailPubKey, decoded.Validators[nodeID2].RingtailPubKey)
+	require.NoError(decoded.VerifyCommitment())
+
+	again, err := json.Marshal(&decoded)
+	require.NoError(err)
+	require.Equal(b, again)
+
+	duplicate := []byte(`{"height":1,"validators":[{"nodeID":"` + nodeID1.String() + `","publicKey":"","weight":1},{"nodeID":"` + nodeID1.String() + `","publicKey":"","weight":1}]}`)
+	require.ErrorIs(json.Unmarshal(duplicate, &decoded), ErrInvalidJSON)
+}
+
+// TestCanonicalValidatorSetJSON tests that canonical sets round trip in
+// canonical order
+func TestCanonicalValidatorSetJSON(t *testing.T) {
+	require := require.New(t)
+
+	generated, err := fixture.Generate(1, 3, fixture.ConstantWeights(1))
+	require.NoError(err)
+	vdrs := make(map[ids.NodeID]*GetValidatorOutput)
+	for i, vdr := range generated {
+		vdrs[vdr.NodeID] = &GetValidatorOutput{
+			NodeID:    vdr.NodeID,
+			PublicKey: vdr.PublicKeyBytes,
+			Weight:    uint64(i + 1),
+		}
+	}
+	vdrSet, err := FlattenValidatorSet(vdrs)
+	require.NoError(err)
+
+	b, err := json.Marshal(&vdrSet)
+	require.NoError(err)
+	var decoded CanonicalValidatorSet
+	require.NoError(json.Unmarshal(b, &decoded))
+	require.Equal(vdrSet.TotalWeight, decoded.TotalWeight)
+	require.Len(decoded.Validators, 3)
+	for i, vdr := range vdrSet.Validators {
+		require.Equal(vdr.PublicKeyBytes, decoded.Validators[i].PublicKeyBytes)
+		require.Equal(vdr.NodeIDs, decoded.Validators[i].NodeIDs)
+	}
+
+	// Reversed validators aren't canonical
+	decoded.Validators[0], decoded.Validators[2] = decoded.Validators[2], decoded.Validators[0]
+	b, err = json.Marshal(&decoded)
+	require.NoError(err)
+	require.ErrorIs(json.Unmarshal(b, &decoded), ErrInvalidJSON)
+
+	// Neither are repeated node IDs
+	vdr := *vdrSet.Validators[0]
+	vdr.NodeIDs = []ids.NodeID{vdr.NodeIDs[0], vdr.NodeIDs[0]}
+	b, err = json.Marshal(&vdr)
+	require.NoError(err)
+	require.ErrorIs(json.Unmarshal(b, &vdr), ErrInvalidJSON)
+}
+
+// TestGetValidatorOutputJSON tests that validator records round trip
+func TestGetValidatorOutputJSON(t *testing.T) {
+	require := require.New(t)
+
+	vdr := &GetValidatorOutput{
+		NodeID:     ids.GenerateTestNodeID(),
+		PublicKey:  []byte{0xab, 0xcd},
+		Light:      5,
+		Weight:     5,
+		TxID:       ids.GenerateTestID(),
+		Metadata:   &ValidatorMetadata{Moniker: "node"},
+		Extensions: map[string][]byte{"ext": {1}},
+		KeyExpiry:  time.Unix(1_000, 0).UTC(),
+		Sequence:   3,
+	}
+	b, err := json.Marshal(vdr)
+	require.NoError(err)
+	require.Contains(string(b), `"publicKey":"abcd"`)
+
+	var decoded GetValidatorOutput
+	require.NoError(json.Unmarshal(b, &decoded))
+	require.Equal(vdr, &decoded)
+
+	require.NoError(json.Unmarshal([]byte(`{"publicKey":"0xabcd"}`), &decoded))
+	require.Equal([]byte{0xab, 0xcd}, decoded.PublicKey)
+	require.ErrorIs(json.Unmarshal([]byte(`{"publicKey":"xyz"}`), &decoded), ErrInvalidJSON)
+}
@@ -0,0 +1,145 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/luxfi/ids"
+)
+
+var ErrKeyTimeBackwards = errors.New("key time moved backwards")
+
+// KeyExpiryManager tracks when the public keys of validators expire.
+//
+// Expiry is checked against the key time of the manager rather than the wall
+// clock, so every node excludes the same keys. Callers advance the key time
+// with the timestamps of accepted blocks. Once a key expires, the validator
+// keeps its weight but is left out of the Warp sets of GetWarpSet, as if it
+// had no key, until the key is rotated with UpdatePublicKey. Rotating a key
+// clears its expiry.
+type KeyExpiryManager interface {
+	// SetKeyExpiry sets when the key of [nodeID] in [netID] expires. A zero
+	// [expiry] clears it.
+	SetKeyExpiry(netID ids.ID, nodeID ids.NodeID, expiry time.Time) error
+	// GetKeyExpiry returns when the key of [nodeID] in [netID] expires, and
+	// false if it doesn't
+	GetKeyExpiry(netID ids.ID, nodeID ids.NodeID) (time.Time, bool)
+	// SetKeyTime advances the key time to [timestamp]
+	SetKeyTime(timestamp time.Time) error
+	// KeyTime returns the key time
+	KeyTime() time.Time
+	// ExpiringKeys returns the keys of [netID] that expire within [within] of
+	// the key time, including expired keys, soonest first
+	ExpiringKeys(netID ids.ID, within time.Duration) []KeyExpiry
+}
+
+// KeyExpiry is when the key of a validator expires
+type KeyExpiry struct {
+	NodeID    ids.NodeID
+	PublicKey []byte
+	Expiry    time.Time
+	// Expired is true if the key expired as of the key time
+	Expired bool
+}
+
+var _ KeyExpiryManager = (*manager)(nil)
+
+// SetKeyExpiry sets when the key of an existing validator expires
+func (m *manager) SetKeyExpiry(netID ids.ID, nodeID ids.NodeID, expiry time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	val, exists := m.validators[netID][nodeID]
+	switch {
+	case !exists:
+		return fmt.Errorf("%w: %s in %s", ErrValidatorNotFound, nodeID, netID)
+	case len(val.PublicKey) == 0:
+		return fmt.Errorf("%w: %s in %s", ErrMissingPublicKey, nodeID, netID)
+	case val.KeyExpiry.Equal(expiry):
+		return nil
+	}
+	val.KeyExpiry = expiry
+	m.bumpSequence(netID, val)
+	return nil
+}
+
+// GetKeyExpiry returns when the key of a validator expires
+func (m *manager) GetKeyExpiry(netID ids.ID, nodeID ids.NodeID) (time.Time, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	val, exists := m.validators[netID][nodeID]
+	if !exists || val.KeyExpiry.IsZero() {
+		return time.Time{}, false
+	}
+	return val.KeyExpiry, true
+}
+
+// SetKeyTime advances the time key expiries are checked against
+func (m *manager) SetKeyTime(timestamp time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if timestamp.Before(m.keyTime) {
+		return fmt.Errorf("%w: %s is before %s", ErrKeyTimeBackwards, timestamp, m.keyTime)
+	}
+	m.keyTime = timestamp
+	return nil
+}
+
+// KeyTime returns the time key expiries are checked against
+func (m *manager) KeyTime() time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.keyTime
+}
+
+// ExpiringKeys returns the keys of a net expiring within a duration
+func (m *manager) ExpiringKeys(netID ids.ID, within time.Duration) []KeyExpiry {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	deadline := m.keyTime.Add(within)
+	var expiring []KeyExpiry
+	for nodeID, val := range m.validators[netID] {
+		if val.KeyExpiry.IsZero() || val.KeyExpiry.After(deadline) {
+			continue
+		}
+		expiring = append(expiring, KeyExpiry{
+			NodeID:    nodeID,
+			PublicKey: slices.Clone(val.PublicKey),
+			Expiry:    val.KeyExpiry,
+			Expired:   keyExpired(val, m.keyTime),
+		})
+	}
+	slices.SortFunc(expiring, func(a, b KeyExpiry) int {
+		return cmp.Or(a.Expiry.Compare(b.Expiry), a.NodeID.Compare(b.NodeID))
+	})
+	return expiring
+}
+
+// ExcludeExpiredKeys returns a copy of [vdrs] in which the validators whose
+// key expired as of [timestamp] have no keys, so canonical sets flattened from
+// it leave them out while still counting their weight
+func ExcludeExpiredKeys(vdrs map[ids.NodeID]*GetValidatorOutput, timestamp time.Time) map[ids.NodeID]*GetValidatorOutput {
+	result := copyValidators(vdrs)
+	for _, vdr := range result {
+		if keyExpired(vdr, timestamp) {
+			vdr.PublicKey = nil
+			vdr.RingtailPubKey = nil
+		}
+	}
+	return result
+}
+
+// keyExpired returns true if the key of [vdr] expired as of [timestamp]
+func keyExpired(vdr *GetValidatorOutput, timestamp time.Time) bool {
+	return !vdr.KeyExpiry.IsZero() && !timestamp.Before(vdr.KeyExpiry)
+}
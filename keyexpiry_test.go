@@ -0,0 +1,78 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/validators/validatorstest/fixture"
+)
+
+// TestManagerKeyExpiry tests that expired keys are reported and left out of
+// Warp sets until they are rotated
+func TestManagerKeyExpiry(t *testing.T) {
+	require := require.New(t)
+
+	keys, err := fixture.Generate(1, 3, fixture.ConstantWeights(100))
+	require.NoError(err)
+
+	var (
+		m      = NewManager()
+		netID  = ids.GenerateTestID()
+		nodeA  = ids.GenerateTestNodeID()
+		nodeB  = ids.GenerateTestNodeID()
+		nodeC  = ids.GenerateTestNodeID()
+		start  = time.Unix(1_000, 0)
+		expiry = start.Add(time.Hour)
+	)
+	require.NoError(m.AddStaker(netID, nodeA, keys[0].PublicKeyBytes, ids.Empty, 100))
+	require.NoError(m.AddStaker(netID, nodeB, keys[1].PublicKeyBytes, ids.Empty, 100))
+	require.NoError(m.AddStaker(netID, nodeC, nil, ids.Empty, 100))
+	require.NoError(m.SetKeyTime(start))
+
+	require.ErrorIs(m.SetKeyExpiry(netID, ids.GenerateTestNodeID(), expiry), ErrValidatorNotFound)
+	require.ErrorIs(m.SetKeyExpiry(netID, nodeC, expiry), ErrMissingPublicKey)
+	require.NoError(m.SetKeyExpiry(netID, nodeA, expiry))
+	require.NoError(m.SetKeyExpiry(netID, nodeB, expiry.Add(time.Hour)))
+
+	got, ok := m.GetKeyExpiry(netID, nodeA)
+	require.True(ok)
+	require.Equal(expiry, got)
+	_, ok = m.GetKeyExpiry(netID, nodeC)
+	require.False(ok)
+
+	// Only nodeA expires within the hour, and it hasn't expired yet
+	expiring := m.ExpiringKeys(netID, time.Hour)
+	require.Len(expiring, 1)
+	require.Equal(nodeA, expiring[0].NodeID)
+	require.False(expiring[0].Expired)
+	require.Len(m.ExpiringKeys(netID, 2*time.Hour), 2)
+	require.Len(m.GetWarpSet(netID).Validators, 2)
+
+	require.ErrorIs(m.SetKeyTime(start.Add(-time.Second)), ErrKeyTimeBackwards)
+	require.NoError(m.SetKeyTime(expiry))
+	require.True(m.ExpiringKeys(netID, 0)[0].Expired)
+
+	// nodeA keeps its weight but leaves the Warp set
+	warpSet := m.GetWarpSet(netID)
+	require.Len(warpSet.Validators, 1)
+	require.Contains(warpSet.Validators, nodeB)
+	require.Equal(uint64(100), m.GetLight(netID, nodeA))
+
+	flattened, err := FlattenValidatorSet(ExcludeExpiredKeys(m.GetMap(netID), m.KeyTime()))
+	require.NoError(err)
+	require.Len(flattened.Validators, 1)
+	require.Equal(uint64(300), flattened.TotalWeight)
+
+	// Rotating the key re-registers it without an expiry
+	require.NoError(m.UpdatePublicKey(netID, nodeA, keys[2].PublicKeyBytes))
+	_, ok = m.GetKeyExpiry(netID, nodeA)
+	require.False(ok)
+	require.Len(m.GetWarpSet(netID).Validators, 2)
+	require.Empty(m.ExpiringKeys(netID, 0))
+}
@@ -0,0 +1,161 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+)
+
+var (
+	ErrMissingPublicKey = errors.New("validator has no public key")
+	ErrInvalidPublicKey = errors.New("invalid public key")
+)
+
+// PublicKeyListener listens to validators rotating their public key
+type PublicKeyListener interface {
+	OnValidatorPublicKeyChanged(netID ids.ID, nodeID ids.NodeID, oldKey, newKey []byte)
+}
+
+// KeyRotationManager rotates the public keys of validators in place
+type KeyRotationManager interface {
+	// UpdatePublicKey sets the public key of [nodeID] in [netID]
+	UpdatePublicKey(netID ids.ID, nodeID ids.NodeID, publicKey []byte) error
+	// RegisterPublicKeyListener registers a listener for key rotations
+	RegisterPublicKeyListener(listener PublicKeyListener)
+}
+
+var _ KeyRotationManager = (*manager)(nil)
+
+// blsKeyCacheSize bounds the number of keys memoized by BLSPublicKey. It's
+// well above the size of any validator set; the cache is emptied when full.
+const blsKeyCacheSize = 1 << 14
+
+// blsKeys memoizes the parsing of compressed keys by their bytes. Records
+// don't carry their parsed key, so records holding the same fields compare
+// equal however they were built or used.
+var blsKeys = blsKeyCache{parsed: make(map[string]*parsedBLSKey)}
+
+// parsedBLSKey is the result of parsing a compressed key
+type parsedBLSKey struct {
+	key *bls.PublicKey
+	err error
+}
+
+type blsKeyCache struct {
+	mu     sync.RWMutex
+	parsed map[string]*parsedBLSKey
+}
+
+// get returns the key [compressed] parses to, parsing it on first use
+func (c *blsKeyCache) get(compressed []byte) *parsedBLSKey {
+	if len(compressed) == 0 {
+		return &parsedBLSKey{err: ErrMissingPublicKey}
+	}
+
+	c.mu.RLock()
+	parsed, ok := c.parsed[string(compressed)]
+	c.mu.RUnlock()
+	if ok {
+		return parsed
+	}
+
+	key, err := bls.PublicKeyFromCompressedBytes(compressed)
+	parsed = &parsedBLSKey{key: key, err: err}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Keep the key parsed by a concurrent call, so callers share one
+	if existing, ok := c.parsed[string(compressed)]; ok {
+		return existing
+	}
+	if len(c.parsed) >= blsKeyCacheSize {
+		clear(c.parsed)
+	}
+	c.parsed[string(compressed)] = parsed
+	return parsed
+}
+
+// BLSPublicKey returns the parsed PublicKey. Keys are parsed on first use and
+// memoized by their bytes, so every record holding a key, including copies
+// and Warp validators built from it, shares the parsed key. It never modifies
+// [v], so it is safe to call concurrently.
+func (v *GetValidatorOutput) BLSPublicKey() (*bls.PublicKey, error) {
+	parsed := blsKeys.get(v.PublicKey)
+	return parsed.key, parsed.err
+}
+
+// BLSPublicKey returns the parsed PublicKey, like
+// GetValidatorOutput.BLSPublicKey
+func (v *WarpValidator) BLSPublicKey() (*bls.PublicKey, error) {
+	parsed := blsKeys.get(v.PublicKey)
+	return parsed.key, parsed.err
+}
+
+// UpdatePublicKey rotates the public key of an existing validator to
+// [publicKey], which must be a compressed BLS key. The validator keeps its
+// TxID, weight and everything else, and only PublicKeyListeners are notified.
+// The new key doesn't expire, see KeyExpiryManager. Setting the current key
+// again is a no-op.
+//
+// Listeners are notified after the lock is released, so they may read from
+// the manager.
+func (m *manager) UpdatePublicKey(netID ids.ID, nodeID ids.NodeID, publicKey []byte) error {
+	parsed := blsKeys.get(publicKey)
+	if errors.Is(parsed.err, ErrMissingPublicKey) {
+		return ErrMissingPublicKey
+	}
+	if parsed.err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPublicKey, parsed.err)
+	}
+
+	oldKey, listeners, err := m.updatePublicKey(netID, nodeID, publicKey)
+	if err != nil {
+		return err
+	}
+	for _, listener := range listeners {
+		listener.OnValidatorPublicKeyChanged(netID, nodeID, oldKey, slices.Clone(publicKey))
+	}
+	return nil
+}
+
+// updatePublicKey sets the key of [nodeID] under the lock and returns its old
+// key and the listeners to notify, which are nil if the key didn't change
+func (m *manager) updatePublicKey(netID ids.ID, nodeID ids.NodeID, publicKey []byte) ([]byte, []PublicKeyListener, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.requireThawed(netID); err != nil {
+		return nil, nil, err
+	}
+	val, exists := m.validators[netID][nodeID]
+	if !exists {
+		return nil, nil, fmt.Errorf("%w: %s in %s", ErrUnknownValidator, nodeID, netID)
+	}
+	if bytes.Equal(val.PublicKey, publicKey) {
+		return nil, nil, nil
+	}
+
+	oldKey := val.PublicKey
+	val.PublicKey = slices.Clone(publicKey)
+	val.KeyExpiry = time.Time{}
+	m.bumpSequence(netID, val)
+	return oldKey, slices.Clone(m.keyListeners), nil
+}
+
+// RegisterPublicKeyListener registers a listener for key rotations
+func (m *manager) RegisterPublicKeyListener(listener PublicKeyListener) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.keyListeners = append(m.keyListeners, listener)
+}
@@ -0,0 +1,188 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/validators/validatorstest/fixture"
+)
+
+// TestBLSPublicKey tests that keys are parsed once, whoever built the record,
+// and that parsing never modifies the record
+func TestBLSPublicKey(t *testing.T) {
+	require := require.New(t)
+
+	generated, err := fixture.Generate(1, 2, fixture.ConstantWeights(1))
+	require.NoError(err)
+	pkBytes := generated[0].PublicKeyBytes
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, pkBytes, ids.Empty, 10))
+
+	// Copies of the record share the key parsed on first use
+	vdr, ok := m.GetValidator(netID, nodeID)
+	require.True(ok)
+	pk, err := vdr.BLSPublicKey()
+	require.NoError(err)
+	require.Equal(pkBytes, bls.PublicKeyToCompressedBytes(pk))
+	again, ok := m.GetValidator(netID, nodeID)
+	require.True(ok)
+	againPK, err := again.BLSPublicKey()
+	require.NoError(err)
+	require.Same(pk, againPK)
+
+	warpPK, err := m.GetWarpSet(netID).Validators[nodeID].BLSPublicKey()
+	require.NoError(err)
+	require.Same(pk, warpPK)
+
+	// Changing the bytes bypasses the parsed key
+	vdr.PublicKey = generated[1].PublicKeyBytes
+	other, err := vdr.BLSPublicKey()
+	require.NoError(err)
+	require.Equal(vdr.PublicKey, bls.PublicKeyToCompressedBytes(other))
+
+	vdr.PublicKey = []byte("invalid")
+	_, err = vdr.BLSPublicKey()
+	require.Error(err)
+
+	vdr.PublicKey = nil
+	_, err = vdr.BLSPublicKey()
+	require.ErrorIs(err, ErrMissingPublicKey)
+
+	// Records built elsewhere share it too
+	warpVdr := &WarpValidator{PublicKey: pkBytes}
+	warpPK, err = warpVdr.BLSPublicKey()
+	require.NoError(err)
+	require.Same(pk, warpPK)
+
+	// Parsing doesn't make equal records compare unequal
+	built := &GetValidatorOutput{
+		NodeID:    nodeID,
+		PublicKey: pkBytes,
+		Light:     10,
+		Weight:    10,
+		Sequence:  again.Sequence,
+	}
+	require.Equal(built, again)
+	_, err = built.BLSPublicKey()
+	require.NoError(err)
+	require.Equal(built, again)
+}
+
+// TestBLSPublicKeyConcurrent tests that verifiers can share a Warp set, which
+// the race detector checks
+func TestBLSPublicKeyConcurrent(t *testing.T) {
+	require := require.New(t)
+
+	generated, err := fixture.Generate(1, 1, fixture.ConstantWeights(1))
+	require.NoError(err)
+	warpSet := &WarpSet{Validators: map[ids.NodeID]*WarpValidator{
+		ids.EmptyNodeID: {PublicKey: generated[0].PublicKeyBytes},
+	}}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() {
+			_, err := warpSet.Validators[ids.EmptyNodeID].BLSPublicKey()
+			require.NoError(err)
+		})
+	}
+	wg.Wait()
+}
+
+type publicKeyEvent struct {
+	netID  ids.ID
+	nodeID ids.NodeID
+	oldKey []byte
+	newKey []byte
+}
+
+type testPublicKeyListener struct {
+	events []publicKeyEvent
+}
+
+func (l *testPublicKeyListener) OnValidatorPublicKeyChanged(netID ids.ID, nodeID ids.NodeID, oldKey, newKey []byte) {
+	l.events = append(l.events, publicKeyEvent{netID, nodeID, oldKey, newKey})
+}
+
+// TestManagerUpdatePublicKey tests that rotating a key keeps the rest of the
+// validator and only notifies key listeners
+func TestManagerUpdatePublicKey(t *testing.T) {
+	require := require.New(t)
+
+	keys, err := fixture.Generate(1, 2, fixture.ConstantWeights(1))
+	require.NoError(err)
+	oldKey, rotatedKey := keys[0].PublicKeyBytes, keys[1].PublicKeyBytes
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	txID := ids.GenerateTestID()
+	require.ErrorIs(m.UpdatePublicKey(netID, nodeID, rotatedKey), ErrUnknownValidator)
+	require.NoError(m.AddStaker(netID, nodeID, oldKey, txID, 100))
+
+	keyListener := &testPublicKeyListener{}
+	m.RegisterPublicKeyListener(keyListener)
+	listener := &testListener{}
+	m.RegisterCallbackListener(listener)
+	listener.added = nil
+
+	require.ErrorIs(m.UpdatePublicKey(netID, nodeID, nil), ErrMissingPublicKey)
+	require.ErrorIs(m.UpdatePublicKey(netID, nodeID, []byte{1, 2, 3}), ErrInvalidPublicKey)
+
+	require.NoError(m.UpdatePublicKey(netID, nodeID, rotatedKey))
+	require.NoError(m.UpdatePublicKey(netID, nodeID, rotatedKey))
+	val, ok := m.GetValidator(netID, nodeID)
+	require.True(ok)
+	require.Equal(rotatedKey, val.PublicKey)
+	require.Equal(txID, val.TxID)
+	require.Equal(uint64(100), val.Light)
+	pk, err := val.BLSPublicKey()
+	require.NoError(err)
+	require.Equal(rotatedKey, bls.PublicKeyToCompressedBytes(pk))
+
+	require.Equal([]publicKeyEvent{{netID, nodeID, oldKey, rotatedKey}}, keyListener.events)
+	require.Empty(listener.added)
+	require.Empty(listener.removed)
+	require.Empty(listener.changed)
+}
+
+// readingKeyListener reads the rotated validator back from the manager
+type readingKeyListener struct {
+	m    Manager
+	keys [][]byte
+}
+
+func (l *readingKeyListener) OnValidatorPublicKeyChanged(netID ids.ID, nodeID ids.NodeID, _, _ []byte) {
+	val, _ := l.m.GetValidator(netID, nodeID)
+	l.keys = append(l.keys, val.PublicKey)
+}
+
+// TestManagerUpdatePublicKeyUnlocked tests that key listeners are notified
+// without the lock held, so they can read the rotated key
+func TestManagerUpdatePublicKeyUnlocked(t *testing.T) {
+	require := require.New(t)
+
+	keys, err := fixture.Generate(2, 2, fixture.ConstantWeights(1))
+	require.NoError(err)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 1))
+
+	listener := &readingKeyListener{m: m}
+	m.RegisterPublicKeyListener(listener)
+	require.NoError(m.UpdatePublicKey(netID, nodeID, keys[0].PublicKeyBytes))
+	require.NoError(m.UpdatePublicKey(netID, nodeID, keys[1].PublicKeyBytes))
+	require.Equal([][]byte{keys[0].PublicKeyBytes, keys[1].PublicKeyBytes}, listener.keys)
+}
@@ -0,0 +1,140 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package latency aggregates the response latencies of validators into stake
+// weighted percentiles, which consensus engines use to adapt their timeouts
+package latency
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/luxfi/ids"
+
+	validators "github.com/luxfi/validators"
+)
+
+var (
+	ErrInvalidConfig   = errors.New("invalid latency config")
+	ErrNegativeLatency = errors.New("negative latency")
+)
+
+// Config configures a Tracker
+type Config struct {
+	// Window is the number of latest responses kept per validator. A
+	// validator's latency is the median of its window, so a few outliers
+	// don't move it.
+	Window int
+}
+
+// DefaultConfig keeps the latest 16 responses of each validator
+func DefaultConfig() Config {
+	return Config{Window: 16}
+}
+
+// Verify returns an error if the config can't hold any response
+func (c Config) Verify() error {
+	if c.Window <= 0 {
+		return fmt.Errorf("%w: window %d must be positive", ErrInvalidConfig, c.Window)
+	}
+	return nil
+}
+
+// Tracker records the response latencies callers observe from validators
+type Tracker struct {
+	manager validators.Manager
+	config  Config
+
+	mu sync.RWMutex
+	// windows are the latest responses of each validator, oldest first
+	windows map[ids.ID]map[ids.NodeID][]time.Duration
+}
+
+// New returns a tracker weighting validators by their weight in [manager]
+func New(manager validators.Manager, config Config) (*Tracker, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	return &Tracker{
+		manager: manager,
+		config:  config,
+		windows: make(map[ids.ID]map[ids.NodeID][]time.Duration),
+	}, nil
+}
+
+// Record records that [nodeID] responded after [latency] in [netID]
+func (t *Tracker) Record(netID ids.ID, nodeID ids.NodeID, latency time.Duration) error {
+	if latency < 0 {
+		return fmt.Errorf("%w: %s from %s", ErrNegativeLatency, latency, nodeID)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	net, ok := t.windows[netID]
+	if !ok {
+		net = make(map[ids.NodeID][]time.Duration)
+		t.windows[netID] = net
+	}
+	window := append(net[nodeID], latency)
+	if excess := len(window) - t.config.Window; excess > 0 {
+		window = slices.Delete(window, 0, excess)
+	}
+	net[nodeID] = window
+	return nil
+}
+
+// Latency returns the latency of [nodeID] in [netID], the median of its
+// window, and false if it has no responses
+func (t *Tracker) Latency(netID ids.ID, nodeID ids.NodeID) (time.Duration, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	window, ok := t.windows[netID][nodeID]
+	if !ok {
+		return 0, false
+	}
+	return median(window), true
+}
+
+// Percentile returns the smallest latency such that validators with at least
+// [num]/[den] of the weight of the responding validators of [netID] respond
+// within it. Validators without responses and responders that aren't
+// validators are ignored. It returns validators.ErrNoWeightedValues if no
+// validator responded.
+func (t *Tracker) Percentile(netID ids.ID, num, den uint64) (time.Duration, error) {
+	t.mu.RLock()
+	latencies := make(map[ids.NodeID]uint64, len(t.windows[netID]))
+	for nodeID, window := range t.windows[netID] {
+		latencies[nodeID] = uint64(median(window))
+	}
+	t.mu.RUnlock()
+
+	latency, err := validators.StakeWeightedQuantile(t.manager, netID, latencies, num, den)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(latency), nil
+}
+
+// Forget drops the responses of [nodeID] in [netID], such as after it left
+// the net
+func (t *Tracker) Forget(netID ids.ID, nodeID ids.NodeID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.windows[netID], nodeID)
+	if len(t.windows[netID]) == 0 {
+		delete(t.windows, netID)
+	}
+}
+
+// median returns the lower median of [window]
+func median(window []time.Duration) time.Duration {
+	sorted := slices.Clone(window)
+	slices.Sort(sorted)
+	return sorted[(len(sorted)-1)/2]
+}
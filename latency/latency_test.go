@@ -0,0 +1,75 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package latency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+// TestConfigVerify tests config validation
+func TestConfigVerify(t *testing.T) {
+	require := require.New(t)
+
+	require.NoError(DefaultConfig().Verify())
+	require.ErrorIs(Config{}.Verify(), ErrInvalidConfig)
+}
+
+// TestTracker tests that percentiles are weighted by stake
+func TestTracker(t *testing.T) {
+	require := require.New(t)
+
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	fast, slow := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, fast, nil, ids.Empty, 70))
+	require.NoError(m.AddStaker(netID, slow, nil, ids.Empty, 30))
+
+	tracker, err := New(m, Config{Window: 3})
+	require.NoError(err)
+	_, err = tracker.Percentile(netID, 1, 2)
+	require.ErrorIs(err, validators.ErrNoWeightedValues)
+
+	// The median of the window ignores a single outlier
+	for _, latency := range []time.Duration{10, 11, 500} {
+		require.NoError(tracker.Record(netID, fast, latency*time.Millisecond))
+	}
+	latency, ok := tracker.Latency(netID, fast)
+	require.True(ok)
+	require.Equal(11*time.Millisecond, latency)
+
+	// Old responses leave the window
+	require.NoError(tracker.Record(netID, fast, 20*time.Millisecond))
+	require.NoError(tracker.Record(netID, fast, 20*time.Millisecond))
+	latency, ok = tracker.Latency(netID, fast)
+	require.True(ok)
+	require.Equal(20*time.Millisecond, latency)
+
+	require.NoError(tracker.Record(netID, slow, 200*time.Millisecond))
+	require.NoError(tracker.Record(netID, ids.GenerateTestNodeID(), time.Millisecond))
+	require.ErrorIs(tracker.Record(netID, slow, -time.Millisecond), ErrNegativeLatency)
+
+	// 70% of the stake responds within 20ms, the rest within 200ms
+	latency, err = tracker.Percentile(netID, 1, 2)
+	require.NoError(err)
+	require.Equal(20*time.Millisecond, latency)
+	latency, err = tracker.Percentile(netID, 70, 100)
+	require.NoError(err)
+	require.Equal(20*time.Millisecond, latency)
+	latency, err = tracker.Percentile(netID, 99, 100)
+	require.NoError(err)
+	require.Equal(200*time.Millisecond, latency)
+
+	tracker.Forget(netID, fast)
+	_, ok = tracker.Latency(netID, fast)
+	require.False(ok)
+	latency, err = tracker.Percentile(netID, 1, 2)
+	require.NoError(err)
+	require.Equal(200*time.Millisecond, latency)
+}
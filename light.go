@@ -0,0 +1,147 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"fmt"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math"
+)
+
+// LightManager changes a validator's consensus light and economic weight
+// independently.
+//
+// AddStaker, AddWeight and RemoveWeight are stake changes and move both
+// values by the same amount. The methods below move only one of them, after
+// which TotalLight and TotalWeight of the net differ.
+type LightManager interface {
+	// AddLight adds consensus light to [nodeID] without changing its weight
+	AddLight(netID ids.ID, nodeID ids.NodeID, light uint64) error
+	// RemoveLight removes up to [light] consensus light from [nodeID] without
+	// changing its weight. The validator is kept even if its light reaches 0.
+	RemoveLight(netID ids.ID, nodeID ids.NodeID, light uint64) error
+	// SetEconomicWeight sets the economic weight of [nodeID] without changing
+	// its light. The weight can't be set below the validator's delegated and
+	// asset weight.
+	SetEconomicWeight(netID ids.ID, nodeID ids.NodeID, weight uint64) error
+}
+
+var ErrWeightBelowDelegated = NewCategoryError("weight is below delegated weight", ErrInvalidWeight)
+
+var _ LightManager = (*manager)(nil)
+
+// AddLight adds consensus light to an existing validator
+func (m *manager) AddLight(netID ids.ID, nodeID ids.NodeID, light uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	notify, err := m.addLight(netID, nodeID, light)
+	if err != nil {
+		return err
+	}
+	m.metrics.operation(OpAddLight)
+	m.notify(notify)
+	return nil
+}
+
+// addLight is AddLight without locking or notifying. It returns the
+// notification of the change, if any. It assumes the lock is held.
+func (m *manager) addLight(netID ids.ID, nodeID ids.NodeID, light uint64) (func(ManagerCallbackListener), error) {
+	if err := m.requireThawed(netID); err != nil {
+		return nil, err
+	}
+
+	val, err := m.uint64Validator(netID, nodeID)
+	if val == nil {
+		return nil, err // Validator doesn't exist, nothing to add
+	}
+	newLight, err := math.Add64(val.Light, light)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+	}
+
+	oldLight := val.Light
+	val.Light = newLight
+	m.bumpSequence(netID, val)
+	return func(listener ManagerCallbackListener) {
+		listener.OnValidatorLightChanged(netID, nodeID, oldLight, newLight)
+	}, nil
+}
+
+// RemoveLight removes consensus light from an existing validator
+func (m *manager) RemoveLight(netID ids.ID, nodeID ids.NodeID, light uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	notify, err := m.removeLight(netID, nodeID, light)
+	if err != nil {
+		return err
+	}
+	m.metrics.operation(OpRemoveLight)
+	m.notify(notify)
+	return nil
+}
+
+// removeLight is RemoveLight without locking or notifying. It returns the
+// notification of the change, if any. It assumes the lock is held.
+func (m *manager) removeLight(netID ids.ID, nodeID ids.NodeID, light uint64) (func(ManagerCallbackListener), error) {
+	if err := m.requireThawed(netID); err != nil {
+		return nil, err
+	}
+
+	val, err := m.uint64Validator(netID, nodeID)
+	if val == nil {
+		return nil, err // Validator doesn't exist, nothing to remove
+	}
+
+	oldLight := val.Light
+	newLight := oldLight - min(light, oldLight)
+	val.Light = newLight
+	m.bumpSequence(netID, val)
+	return func(listener ManagerCallbackListener) {
+		listener.OnValidatorLightChanged(netID, nodeID, oldLight, newLight)
+	}, nil
+}
+
+// SetEconomicWeight sets the economic weight of an existing validator
+func (m *manager) SetEconomicWeight(netID ids.ID, nodeID ids.NodeID, weight uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.setEconomicWeight(netID, nodeID, weight)
+}
+
+// setEconomicWeight is SetEconomicWeight without locking. It assumes the lock
+// is held.
+func (m *manager) setEconomicWeight(netID ids.ID, nodeID ids.NodeID, weight uint64) error {
+	if err := m.requireThawed(netID); err != nil {
+		return err
+	}
+
+	val, err := m.uint64Validator(netID, nodeID)
+	if val == nil {
+		return err // Validator doesn't exist, nothing to set
+	}
+	if external := m.externalWeight(validatorKey{netID: netID, nodeID: nodeID}); weight < external {
+		return fmt.Errorf("%w: %d < %d", ErrWeightBelowDelegated, weight, external)
+	}
+	val.Weight = weight
+	m.bumpSequence(netID, val)
+	return nil
+}
+
+// uint64Validator returns the record of a validator of a WeightModeUint64
+// net. If the validator doesn't exist it returns a nil record, with an error
+// only in strict mode, see StrictManager. It assumes the lock is held.
+func (m *manager) uint64Validator(netID ids.ID, nodeID ids.NodeID) (*GetValidatorOutput, error) {
+	if err := m.requireWeightMode(netID, WeightModeUint64); err != nil {
+		return nil, err
+	}
+	val, exists := m.validators[netID][nodeID]
+	if !exists {
+		return nil, m.requireValidator(netID, nodeID)
+	}
+	return val, nil
+}
@@ -0,0 +1,132 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"math"
+	"testing"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/validators/validatorstest/fixture"
+)
+
+// TestManagerDecoupledLight tests that light and weight can diverge
+func TestManagerDecoupledLight(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID0 := ids.GenerateTestNodeID()
+	nodeID1 := ids.GenerateTestNodeID()
+	listener := &testListener{}
+	m.RegisterCallbackListener(listener)
+
+	require.NoError(m.AddStaker(netID, nodeID0, nil, ids.Empty, 100))
+	require.NoError(m.AddStaker(netID, nodeID1, nil, ids.Empty, 100))
+
+	require.NoError(m.AddLight(netID, nodeID0, 50))
+	require.NoError(m.SetEconomicWeight(netID, nodeID1, 300))
+
+	vdr, ok := m.GetValidator(netID, nodeID0)
+	require.True(ok)
+	require.Equal(uint64(150), vdr.Light)
+	require.Equal(uint64(100), vdr.Weight)
+
+	vdr, ok = m.GetValidator(netID, nodeID1)
+	require.True(ok)
+	require.Equal(uint64(100), vdr.Light)
+	require.Equal(uint64(300), vdr.Weight)
+
+	totalLight, err := m.TotalLight(netID)
+	require.NoError(err)
+	require.Equal(uint64(250), totalLight)
+	totalWeight, err := m.TotalWeight(netID)
+	require.NoError(err)
+	require.Equal(uint64(400), totalWeight)
+	require.Equal([]lightChangedEvent{{netID, nodeID0, 100, 150}}, listener.changed)
+
+	// Light can be removed entirely without removing the validator
+	require.NoError(m.RemoveLight(netID, nodeID0, 1000))
+	vdr, ok = m.GetValidator(netID, nodeID0)
+	require.True(ok)
+	require.Zero(vdr.Light)
+	require.Equal(uint64(100), vdr.Weight)
+
+	// Stake changes move both values and removal follows the weight
+	require.NoError(m.AddWeight(netID, nodeID0, 10))
+	vdr, _ = m.GetValidator(netID, nodeID0)
+	require.Equal(uint64(10), vdr.Light)
+	require.Equal(uint64(110), vdr.Weight)
+
+	require.NoError(m.RemoveWeight(netID, nodeID0, 50))
+	vdr, ok = m.GetValidator(netID, nodeID0)
+	require.True(ok)
+	require.Zero(vdr.Light)
+	require.Equal(uint64(60), vdr.Weight)
+
+	require.NoError(m.RemoveWeight(netID, nodeID0, 60))
+	_, ok = m.GetValidator(netID, nodeID0)
+	require.False(ok)
+}
+
+// TestManagerDecoupledLightErrors tests invalid light and weight changes
+func TestManagerDecoupledLightErrors(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+
+	// Changes to missing validators are no-ops unless strict
+	require.NoError(m.AddLight(netID, nodeID, 1))
+	require.NoError(m.RemoveLight(netID, nodeID, 1))
+	require.NoError(m.SetEconomicWeight(netID, nodeID, 1))
+	require.Zero(m.Count(netID))
+
+	m.SetStrict(true)
+	require.ErrorIs(m.AddLight(netID, nodeID, 1), ErrNetNotFound)
+	require.ErrorIs(m.RemoveLight(netID, nodeID, 1), ErrNetNotFound)
+	require.ErrorIs(m.SetEconomicWeight(netID, nodeID, 1), ErrNetNotFound)
+	require.NoError(m.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 1))
+	require.ErrorIs(m.AddLight(netID, nodeID, 1), ErrValidatorNotFound)
+	m.SetStrict(false)
+
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, math.MaxUint64-10))
+	require.ErrorIs(m.AddLight(netID, nodeID, 11), ErrWeightOverflow)
+
+	require.NoError(m.SetEconomicWeight(netID, nodeID, 100))
+	require.NoError(m.AddDelegator(netID, nodeID, ids.GenerateTestShortID(), 5))
+	require.ErrorIs(m.SetEconomicWeight(netID, nodeID, 4), ErrWeightBelowDelegated)
+}
+
+// TestFlattenValidatorSetBy tests choosing the weight used for canonical sets
+func TestFlattenValidatorSetBy(t *testing.T) {
+	require := require.New(t)
+
+	generated, err := fixture.Generate(1, 1, fixture.ConstantWeights(1))
+	require.NoError(err)
+	sk := generated[0].SecretKey
+	nodeID := ids.GenerateTestNodeID()
+	vdrs := map[ids.NodeID]*GetValidatorOutput{
+		nodeID: {
+			NodeID:    nodeID,
+			PublicKey: bls.PublicKeyToCompressedBytes(sk.PublicKey()),
+			Light:     10,
+			Weight:    20,
+		},
+	}
+
+	byWeight, err := FlattenValidatorSet(vdrs)
+	require.NoError(err)
+	require.Equal(uint64(20), byWeight.TotalWeight)
+	require.Equal(uint64(20), byWeight.Validators[0].Weight)
+
+	byLight, err := FlattenValidatorSetBy(vdrs, ConsensusLight)
+	require.NoError(err)
+	require.Equal(uint64(10), byLight.TotalWeight)
+	require.Equal(uint64(10), byLight.Validators[0].Weight)
+}
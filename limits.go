@@ -0,0 +1,61 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/luxfi/ids"
+)
+
+var ErrLimitExceeded = errors.New("manager limit exceeded")
+
+// Limits caps the size of a manager. Zero values are unlimited.
+type Limits struct {
+	MaxNets             int
+	MaxValidatorsPerNet int
+}
+
+// LimitManager caps the number of nets and validators a manager holds.
+// Limits are checked when a validator is added, so lowering them doesn't
+// evict anyone, and re-adding an existing validator is always allowed.
+type LimitManager interface {
+	SetLimits(limits Limits)
+	GetLimits() Limits
+}
+
+var _ LimitManager = (*manager)(nil)
+
+// SetLimits sets the limits of the manager
+func (m *manager) SetLimits(limits Limits) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.limits = limits
+}
+
+// GetLimits returns the limits of the manager
+func (m *manager) GetLimits() Limits {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.limits
+}
+
+// requireCapacity returns an error if adding [nodeID] to [netID] would exceed
+// the limits. It assumes the lock is held.
+func (m *manager) requireCapacity(netID ids.ID, nodeID ids.NodeID) error {
+	vdrs, netExists := m.validators[netID]
+	if _, exists := vdrs[nodeID]; exists {
+		return nil
+	}
+	if limit := m.limits.MaxValidatorsPerNet; limit > 0 && len(vdrs) >= limit {
+		return fmt.Errorf("%w: net %s has %d validators", ErrLimitExceeded, netID, len(vdrs))
+	}
+	if limit := m.limits.MaxNets; limit > 0 && !netExists && len(m.validators) >= limit {
+		return fmt.Errorf("%w: %d nets", ErrLimitExceeded, len(m.validators))
+	}
+	return nil
+}
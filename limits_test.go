@@ -0,0 +1,41 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// TestManagerLimits tests that limits cap new validators and nets only
+func TestManagerLimits(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	m.SetLimits(Limits{MaxNets: 2, MaxValidatorsPerNet: 2})
+	require.Equal(Limits{MaxNets: 2, MaxValidatorsPerNet: 2}, m.GetLimits())
+
+	netID := ids.GenerateTestID()
+	nodeID1, nodeID2 := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID1, nil, ids.Empty, 10))
+	require.NoError(m.AddStaker(netID, nodeID2, nil, ids.Empty, 10))
+	require.ErrorIs(m.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 10), ErrLimitExceeded)
+
+	// Re-adding an existing validator is allowed
+	require.NoError(m.SetDuplicatePolicy(DuplicateReplace))
+	require.NoError(m.AddStaker(netID, nodeID1, nil, ids.Empty, 20))
+
+	bigNetID := ids.GenerateTestID()
+	require.NoError(m.SetWeightMode(bigNetID, WeightModeBig))
+	require.NoError(m.AddStakerBig(bigNetID, nodeID1, nil, ids.Empty, big.NewInt(10)))
+	require.ErrorIs(m.AddStaker(ids.GenerateTestID(), nodeID1, nil, ids.Empty, 10), ErrLimitExceeded)
+	require.Equal(2, m.NumNets())
+
+	// Freeing a net makes room for another
+	require.NoError(m.RemoveBigWeight(bigNetID, nodeID1, big.NewInt(10)))
+	require.NoError(m.AddStaker(ids.GenerateTestID(), nodeID1, nil, ids.Empty, 10))
+}
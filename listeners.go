@@ -0,0 +1,122 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/luxfi/ids"
+)
+
+// ListenerManager detaches the listeners registered with a manager.
+//
+// Listeners are matched with ==. Listeners whose values can't be compared,
+// such as slices or structs holding maps, never match, so listeners that are
+// unregistered later should be registered as pointers.
+type ListenerManager interface {
+	// UnregisterCallbackListener removes the first registration of
+	// [listener] and returns true, or returns false if it isn't registered
+	UnregisterCallbackListener(listener ManagerCallbackListener) bool
+	// UnregisterSetCallbackListener removes the first registration of
+	// [listener] for [netID] and returns true, or returns false if it isn't
+	// registered for [netID]
+	UnregisterSetCallbackListener(netID ids.ID, listener SetCallbackListener) bool
+}
+
+var _ ListenerManager = (*manager)(nil)
+
+// callbackListeners is a copy-on-write list of listeners. Notifications range
+// over a snapshot, so the list can change while callbacks run, including
+// from within a callback, without holding the manager lock.
+type callbackListeners struct {
+	mu   sync.Mutex
+	list atomic.Pointer[[]ManagerCallbackListener]
+}
+
+// load returns the current listeners. The result must not be modified.
+func (l *callbackListeners) load() []ManagerCallbackListener {
+	if list := l.list.Load(); list != nil {
+		return *list
+	}
+	return nil
+}
+
+func (l *callbackListeners) add(listener ManagerCallbackListener) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	list := append(slices.Clone(l.load()), listener)
+	l.list.Store(&list)
+}
+
+// remove removes the first registration of [listener], see sameListener
+func (l *callbackListeners) remove(listener ManagerCallbackListener) bool {
+	return l.removeFunc(func(registered ManagerCallbackListener) bool {
+		return sameListener(registered, listener)
+	})
+}
+
+// removeFunc removes the first listener [match] returns true for
+func (l *callbackListeners) removeFunc(match func(ManagerCallbackListener) bool) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	current := l.load()
+	i := slices.IndexFunc(current, match)
+	if i < 0 {
+		return false
+	}
+	list := slices.Delete(slices.Clone(current), i, i+1)
+	l.list.Store(&list)
+	return true
+}
+
+// sameListener returns true if [a] and [b] are equal with ==. Values that
+// can't be compared are never equal, where == would panic.
+func sameListener(a, b any) bool {
+	aValue, bValue := reflect.ValueOf(a), reflect.ValueOf(b)
+	return aValue.IsValid() && bValue.IsValid() &&
+		aValue.Type() == bValue.Type() &&
+		aValue.Comparable() && aValue.Equal(bValue)
+}
+
+// notifyAll returns a notification sending each of [notify] in order, or nil
+// if there is nothing to notify
+func notifyAll(notify []func(ManagerCallbackListener)) func(ManagerCallbackListener) {
+	if len(notify) == 0 {
+		return nil
+	}
+	return func(listener ManagerCallbackListener) {
+		for _, f := range notify {
+			f(listener)
+		}
+	}
+}
+
+// netListener forwards the events of a single net to a SetCallbackListener
+type netListener struct {
+	netID    ids.ID
+	listener SetCallbackListener
+}
+
+func (l *netListener) OnValidatorAdded(netID ids.ID, nodeID ids.NodeID, light uint64) {
+	if netID == l.netID {
+		l.listener.OnValidatorAdded(nodeID, light)
+	}
+}
+
+func (l *netListener) OnValidatorRemoved(netID ids.ID, nodeID ids.NodeID, light uint64) {
+	if netID == l.netID {
+		l.listener.OnValidatorRemoved(nodeID, light)
+	}
+}
+
+func (l *netListener) OnValidatorLightChanged(netID ids.ID, nodeID ids.NodeID, oldLight, newLight uint64) {
+	if netID == l.netID {
+		l.listener.OnValidatorLightChanged(nodeID, oldLight, newLight)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"fmt"
+
+	"github.com/luxfi/ids"
+)
+
+// LogLevel is the severity a validator change is logged at
+type LogLevel uint8
+
+const (
+	// LogOff doesn't log the change
+	LogOff LogLevel = iota
+	LogDebug
+	LogInfo
+	LogWarn
+	LogError
+)
+
+// String implements fmt.Stringer
+func (l LogLevel) String() string {
+	switch l {
+	case LogOff:
+		return "off"
+	case LogDebug:
+		return "debug"
+	case LogInfo:
+		return "info"
+	case LogWarn:
+		return "warn"
+	case LogError:
+		return "error"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(l))
+	}
+}
+
+// LogField is a key-value pair attached to a log message
+type LogField struct {
+	Key   string
+	Value any
+}
+
+// Logger writes structured log messages. Embedders adapt it to their logging
+// library, such as zap or slog.
+type Logger interface {
+	Log(level LogLevel, msg string, fields ...LogField)
+}
+
+// Messages the manager logs validator changes with
+const (
+	LogValidatorAdded        = "validator added"
+	LogValidatorRemoved      = "validator removed"
+	LogValidatorLightChanged = "validator light changed"
+)
+
+// LogConfig is the level each kind of change is logged at
+type LogConfig struct {
+	Added        LogLevel
+	Removed      LogLevel
+	LightChanged LogLevel
+}
+
+// DefaultLogConfig logs validators joining and leaving at info and light
+// changes at debug
+func DefaultLogConfig() LogConfig {
+	return LogConfig{
+		Added:        LogInfo,
+		Removed:      LogInfo,
+		LightChanged: LogDebug,
+	}
+}
+
+// LoggingManager logs every validator change with its netID, nodeID, light
+// and TxID. Light changes are logged with the old and new light.
+type LoggingManager interface {
+	// SetLogger replaces the logger and its config. A nil logger stops
+	// logging.
+	SetLogger(logger Logger, config LogConfig)
+}
+
+var _ LoggingManager = (*manager)(nil)
+
+// SetLogger sets the logger of the manager. Validators already in the manager
+// aren't logged, but their TxIDs are remembered for their removal.
+func (m *manager) SetLogger(logger Logger, config LogConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.logger != nil {
+		m.listeners.remove(m.logger)
+		m.logger = nil
+	}
+	if logger == nil {
+		return
+	}
+
+	m.logger = &managerLogger{
+		manager: m,
+		logger:  logger,
+		config:  config,
+		txIDs:   make(map[validatorKey]ids.ID),
+	}
+	for netID, validators := range m.validators {
+		for nodeID, val := range validators {
+			m.logger.txIDs[validatorKey{netID: netID, nodeID: nodeID}] = val.TxID
+		}
+	}
+	m.listeners.add(m.logger)
+}
+
+// managerLogger logs the changes of a manager as a ManagerCallbackListener.
+// Notifications are dispatched with the manager lock held, so it reads the
+// TxIDs of added validators from their records, and remembers them for their
+// removal.
+type managerLogger struct {
+	manager *manager
+	logger  Logger
+	config  LogConfig
+	txIDs   map[validatorKey]ids.ID
+}
+
+func (l *managerLogger) OnValidatorAdded(netID ids.ID, nodeID ids.NodeID, light uint64) {
+	key := validatorKey{netID: netID, nodeID: nodeID}
+	var txID ids.ID
+	if val, ok := l.manager.validators[netID][nodeID]; ok {
+		txID = val.TxID
+	}
+	l.txIDs[key] = txID
+
+	l.log(l.config.Added, LogValidatorAdded,
+		LogField{Key: "netID", Value: netID},
+		LogField{Key: "nodeID", Value: nodeID},
+		LogField{Key: "light", Value: light},
+		LogField{Key: "txID", Value: txID},
+	)
+}
+
+func (l *managerLogger) OnValidatorRemoved(netID ids.ID, nodeID ids.NodeID, light uint64) {
+	key := validatorKey{netID: netID, nodeID: nodeID}
+	txID := l.txIDs[key]
+	delete(l.txIDs, key)
+
+	l.log(l.config.Removed, LogValidatorRemoved,
+		LogField{Key: "netID", Value: netID},
+		LogField{Key: "nodeID", Value: nodeID},
+		LogField{Key: "light", Value: light},
+		LogField{Key: "txID", Value: txID},
+	)
+}
+
+func (l *managerLogger) OnValidatorLightChanged(netID ids.ID, nodeID ids.NodeID, oldLight, newLight uint64) {
+	l.log(l.config.LightChanged, LogValidatorLightChanged,
+		LogField{Key: "netID", Value: netID},
+		LogField{Key: "nodeID", Value: nodeID},
+		LogField{Key: "oldLight", Value: oldLight},
+		LogField{Key: "newLight", Value: newLight},
+		LogField{Key: "txID", Value: l.txIDs[validatorKey{netID: netID, nodeID: nodeID}]},
+	)
+}
+
+func (l *managerLogger) log(level LogLevel, msg string, fields ...LogField) {
+	if level != LogOff {
+		l.logger.Log(level, msg, fields...)
+	}
+}
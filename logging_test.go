@@ -0,0 +1,101 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+type logEntry struct {
+	level  LogLevel
+	msg    string
+	fields map[string]any
+}
+
+type testLogger struct {
+	entries []logEntry
+}
+
+func (l *testLogger) Log(level LogLevel, msg string, fields ...LogField) {
+	entry := logEntry{
+		level:  level,
+		msg:    msg,
+		fields: make(map[string]any, len(fields)),
+	}
+	for _, field := range fields {
+		entry.fields[field.Key] = field.Value
+	}
+	l.entries = append(l.entries, entry)
+}
+
+// TestManagerLogger tests that changes are logged with their fields at the
+// configured levels
+func TestManagerLogger(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID1, nodeID2 := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	txID1, txID2 := ids.GenerateTestID(), ids.GenerateTestID()
+	require.NoError(m.AddStaker(netID, nodeID1, nil, txID1, 100))
+
+	logger := &testLogger{}
+	m.SetLogger(logger, DefaultLogConfig())
+	require.Empty(logger.entries)
+
+	require.NoError(m.AddStaker(netID, nodeID2, nil, txID2, 50))
+	require.NoError(m.SetWeight(netID, nodeID2, 70))
+	require.NoError(m.RemoveStaker(netID, nodeID1))
+	require.Equal([]logEntry{
+		{
+			level: LogInfo,
+			msg:   LogValidatorAdded,
+			fields: map[string]any{
+				"netID":  netID,
+				"nodeID": nodeID2,
+				"light":  uint64(50),
+				"txID":   txID2,
+			},
+		},
+		{
+			level: LogDebug,
+			msg:   LogValidatorLightChanged,
+			fields: map[string]any{
+				"netID":    netID,
+				"nodeID":   nodeID2,
+				"oldLight": uint64(50),
+				"newLight": uint64(70),
+				"txID":     txID2,
+			},
+		},
+		{
+			level: LogInfo,
+			msg:   LogValidatorRemoved,
+			fields: map[string]any{
+				"netID":  netID,
+				"nodeID": nodeID1,
+				"light":  uint64(100),
+				"txID":   txID1,
+			},
+		},
+	}, logger.entries)
+
+	// Changes configured off aren't logged
+	logger.entries = nil
+	m.SetLogger(logger, LogConfig{Removed: LogWarn})
+	require.NoError(m.AddWeight(netID, nodeID2, 5))
+	require.NoError(m.RemoveStaker(netID, nodeID2))
+	require.Len(logger.entries, 1)
+	require.Equal(LogWarn, logger.entries[0].level)
+	require.Equal(txID2, logger.entries[0].fields["txID"])
+
+	// A nil logger stops logging
+	logger.entries = nil
+	m.SetLogger(nil, DefaultLogConfig())
+	require.NoError(m.AddStaker(netID, nodeID1, nil, txID1, 100))
+	require.Empty(logger.entries)
+}
@@ -0,0 +1,229 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math/set"
+	"github.com/luxfi/version"
+)
+
+var (
+	ErrUnknownHeight       = errors.New("unknown height")
+	ErrNonIncreasingHeight = errors.New("height doesn't increase")
+	ErrNoHistory           = errors.New("manager doesn't record history")
+	ErrDuplicateChainID    = errors.New("chain validated by several nets")
+)
+
+// ManagerStateConfig selects what a ManagerState serves at accepted heights
+type ManagerStateConfig struct {
+	// NetIDs are the nets whose sets are served at accepted heights. Reads of
+	// other nets at a height return ErrNetNotFound.
+	NetIDs []ids.ID
+	// ChainIDs maps the served nets to the ID of the chain each validates.
+	// A chain is validated by a single net.
+	ChainIDs map[ids.ID]ids.ID
+	// Retention is the number of accepted heights kept, which sets the
+	// history retention of the manager. Zero keeps the manager's retention.
+	Retention int
+}
+
+// ManagerState serves a Manager as a State for single-process deployments.
+//
+// The two validator reads differ as the State interface intends:
+// GetValidatorSet reads the history of the manager, see HistoryManager, so a
+// height always returns the same set, while GetCurrentValidators reads the
+// live manager, marking validators changed since the latest accepted height
+// as Pending and validators reported by the Connector methods as Connected.
+// Warp sets are built and committed to once per accepted height and shared,
+// so they must not be modified.
+type ManagerState struct {
+	manager Manager
+	history HistoryManager
+	netIDs  set.Set[ids.ID]
+	// chainIDs and chainNets map nets to their chain and back
+	chainIDs  map[ids.ID]ids.ID
+	chainNets map[ids.ID]ids.ID
+
+	mu        sync.RWMutex
+	connected set.Set[ids.NodeID]
+}
+
+var (
+	_ State     = (*ManagerState)(nil)
+	_ Connector = (*ManagerState)(nil)
+)
+
+// NewManagerState returns a State of [manager], which must implement
+// HistoryManager. Heights recorded by the manager before are served as
+// accepted.
+func NewManagerState(manager Manager, config ManagerStateConfig) (*ManagerState, error) {
+	history, ok := manager.(HistoryManager)
+	if !ok {
+		return nil, ErrNoHistory
+	}
+	if config.Retention < 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidRetention, config.Retention)
+	}
+	chainNets := make(map[ids.ID]ids.ID, len(config.ChainIDs))
+	for netID, chainID := range config.ChainIDs {
+		if otherNetID, ok := chainNets[chainID]; ok {
+			return nil, fmt.Errorf("%w: %s by %s and %s", ErrDuplicateChainID, chainID, netID, otherNetID)
+		}
+		chainNets[chainID] = netID
+	}
+	if config.Retention > 0 {
+		if err := history.SetHistoryRetention(config.Retention); err != nil {
+			return nil, err
+		}
+	}
+	return &ManagerState{
+		manager:   manager,
+		history:   history,
+		netIDs:    set.Of(config.NetIDs...),
+		chainIDs:  maps.Clone(config.ChainIDs),
+		chainNets: chainNets,
+		connected: set.NewSet[ids.NodeID](0),
+	}, nil
+}
+
+// Accept records the current sets of the manager at [height], which must be
+// above the previously accepted height. It's SetHeight of the manager.
+func (s *ManagerState) Accept(height uint64) error {
+	return s.history.SetHeight(height)
+}
+
+// GetValidatorSet returns the set of [netID] as of the latest height accepted
+// at or below [height]
+func (s *ManagerState) GetValidatorSet(_ context.Context, height uint64, netID ids.ID) (map[ids.NodeID]*GetValidatorOutput, error) {
+	if err := s.servesNet(netID); err != nil {
+		return nil, err
+	}
+	return s.history.GetValidatorSetAt(netID, height)
+}
+
+// GetCurrentValidators returns the live set of [netID]. [height] is ignored;
+// the current set isn't pinned to a height.
+func (s *ManagerState) GetCurrentValidators(_ context.Context, _ uint64, netID ids.ID) (map[ids.NodeID]*GetValidatorOutput, error) {
+	vdrs := s.manager.GetMap(netID)
+
+	var accepted map[ids.NodeID]*GetValidatorOutput
+	if _, latest, ok := s.history.HeightBounds(); ok && s.netIDs.Contains(netID) {
+		accepted, _ = s.history.GetValidatorSetAt(netID, latest)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for nodeID, vdr := range vdrs {
+		acceptedVdr, ok := accepted[nodeID]
+		vdr.Pending = !ok || acceptedVdr.Sequence != vdr.Sequence
+		vdr.Connected = s.connected.Contains(nodeID)
+	}
+	return vdrs, nil
+}
+
+// GetCurrentHeight returns the latest accepted height
+func (s *ManagerState) GetCurrentHeight(context.Context) (uint64, error) {
+	_, latest, _ := s.history.HeightBounds()
+	return latest, nil
+}
+
+// GetMinimumHeight returns the oldest retained height
+func (s *ManagerState) GetMinimumHeight(context.Context) (uint64, error) {
+	oldest, _, _ := s.history.HeightBounds()
+	return oldest, nil
+}
+
+// GetChainID returns the configured ID of the chain validated by [netID]
+func (s *ManagerState) GetChainID(netID ids.ID) (ids.ID, error) {
+	chainID, ok := s.chainIDs[netID]
+	if !ok {
+		return ids.Empty, fmt.Errorf("%w: %s has no chain", ErrNetNotFound, netID)
+	}
+	return chainID, nil
+}
+
+// GetNetworkID returns the net validating [chainID], see GetChainID
+func (s *ManagerState) GetNetworkID(chainID ids.ID) (ids.ID, error) {
+	netID, ok := s.chainNets[chainID]
+	if !ok {
+		return ids.Empty, fmt.Errorf("%w: no net validates %s", ErrNetNotFound, chainID)
+	}
+	return netID, nil
+}
+
+// GetWarpValidatorSet returns the Warp set of [netID] as of the latest height
+// accepted at or below [height]
+func (s *ManagerState) GetWarpValidatorSet(_ context.Context, height uint64, netID ids.ID) (*WarpSet, error) {
+	if err := s.servesNet(netID); err != nil {
+		return nil, err
+	}
+	return s.history.GetWarpSetAt(netID, height)
+}
+
+// GetWarpValidatorSets returns the Warp sets of [netIDs] at [heights], see
+// GetWarpValidatorSet
+func (s *ManagerState) GetWarpValidatorSets(ctx context.Context, heights []uint64, netIDs []ids.ID) (map[ids.ID]map[uint64]*WarpSet, error) {
+	result := make(map[ids.ID]map[uint64]*WarpSet, len(netIDs))
+	for _, netID := range netIDs {
+		result[netID] = make(map[uint64]*WarpSet, len(heights))
+		for _, height := range heights {
+			warpSet, err := s.GetWarpValidatorSet(ctx, height, netID)
+			if err != nil {
+				return nil, err
+			}
+			result[netID][height] = warpSet
+		}
+	}
+	return result, nil
+}
+
+// Connected marks [nodeID] as connected in GetCurrentValidators
+func (s *ManagerState) Connected(_ context.Context, nodeID ids.NodeID, _ *version.Application) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.connected.Add(nodeID)
+	return nil
+}
+
+// Disconnected marks [nodeID] as disconnected in GetCurrentValidators
+func (s *ManagerState) Disconnected(_ context.Context, nodeID ids.NodeID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.connected.Remove(nodeID)
+	return nil
+}
+
+// servesNet returns ErrNetNotFound if [netID] isn't configured
+func (s *ManagerState) servesNet(netID ids.ID) error {
+	if !s.netIDs.Contains(netID) {
+		return fmt.Errorf("%w: %s isn't served", ErrNetNotFound, netID)
+	}
+	return nil
+}
+
+// legacyCurrentState answers GetCurrentValidators with the height-pinned set
+type legacyCurrentState struct {
+	State
+}
+
+// NewLegacyCurrentState returns a State whose GetCurrentValidators returns
+// GetValidatorSet at the given height, as every State did before the two
+// were separated. It lets callers that relied on that migrate gradually.
+func NewLegacyCurrentState(state State) State {
+	return &legacyCurrentState{State: state}
+}
+
+func (s *legacyCurrentState) GetCurrentValidators(ctx context.Context, height uint64, netID ids.ID) (map[ids.NodeID]*GetValidatorOutput, error) {
+	return s.State.GetValidatorSet(ctx, height, netID)
+}
@@ -0,0 +1,182 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/validators/validatorstest/fixture"
+)
+
+// TestManagerStateHeightPinned tests that GetValidatorSet only changes on
+// Accept while GetCurrentValidators follows the manager
+func TestManagerStateHeightPinned(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+
+	state, err := NewManagerState(m, ManagerStateConfig{NetIDs: []ids.ID{netID}, Retention: 2})
+	require.NoError(err)
+	_, err = state.GetValidatorSet(ctx, 10, netID)
+	require.ErrorIs(err, ErrUnknownHeight)
+
+	require.NoError(state.Accept(10))
+	require.ErrorIs(state.Accept(10), ErrNonIncreasingHeight)
+
+	pendingNodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, pendingNodeID, nil, ids.Empty, 50))
+	require.NoError(state.Connected(ctx, nodeID, nil))
+
+	vdrs, err := state.GetValidatorSet(ctx, 10, netID)
+	require.NoError(err)
+	require.Len(vdrs, 1)
+	require.False(vdrs[nodeID].Connected)
+
+	current, err := state.GetCurrentValidators(ctx, 10, netID)
+	require.NoError(err)
+	require.Len(current, 2)
+	require.False(current[nodeID].Pending)
+	require.True(current[nodeID].Connected)
+	require.True(current[pendingNodeID].Pending)
+	require.False(current[pendingNodeID].Connected)
+
+	// Heights resolve to the latest accepted height at or below them
+	require.NoError(state.Accept(20))
+	vdrs, err = state.GetValidatorSet(ctx, 15, netID)
+	require.NoError(err)
+	require.Len(vdrs, 1)
+	vdrs, err = state.GetValidatorSet(ctx, 20, netID)
+	require.NoError(err)
+	require.Len(vdrs, 2)
+	vdrs, err = state.GetValidatorSet(ctx, 21, netID)
+	require.NoError(err)
+	require.Len(vdrs, 2)
+	_, err = state.GetValidatorSet(ctx, 20, ids.GenerateTestID())
+	require.ErrorIs(err, ErrNetNotFound)
+	_, err = state.GetWarpValidatorSet(ctx, 20, ids.GenerateTestID())
+	require.ErrorIs(err, ErrNetNotFound)
+
+	current, err = state.GetCurrentValidators(ctx, 20, netID)
+	require.NoError(err)
+	require.False(current[pendingNodeID].Pending)
+
+	// Old heights are dropped past the retention
+	require.NoError(state.Accept(30))
+	_, err = state.GetValidatorSet(ctx, 10, netID)
+	require.ErrorIs(err, ErrUnknownHeight)
+	minHeight, err := state.GetMinimumHeight(ctx)
+	require.NoError(err)
+	require.Equal(uint64(20), minHeight)
+	height, err := state.GetCurrentHeight(ctx)
+	require.NoError(err)
+	require.Equal(uint64(30), height)
+
+	require.NoError(state.Disconnected(ctx, nodeID))
+	current, err = state.GetCurrentValidators(ctx, 30, netID)
+	require.NoError(err)
+	require.False(current[nodeID].Connected)
+}
+
+// TestManagerStateHistory tests that the state serves the history of the
+// manager, with Warp sets committed to once per height and without the keys
+// expired at that height
+func TestManagerStateHistory(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	keys, err := fixture.Generate(1, 2, fixture.ConstantWeights(1))
+	require.NoError(err)
+
+	_, err = NewManagerState(&mockManager{}, ManagerStateConfig{})
+	require.ErrorIs(err, ErrNoHistory)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	chainID := ids.GenerateTestID()
+	_, err = NewManagerState(m, ManagerStateConfig{ChainIDs: map[ids.ID]ids.ID{
+		netID:                chainID,
+		ids.GenerateTestID(): chainID,
+	}})
+	require.ErrorIs(err, ErrDuplicateChainID)
+
+	expiringNodeID := ids.GenerateTestNodeID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, expiringNodeID, keys[0].PublicKeyBytes, ids.Empty, 100))
+	require.NoError(m.AddStaker(netID, nodeID, keys[1].PublicKeyBytes, ids.Empty, 100))
+	expiry := time.Unix(1000, 0)
+	require.NoError(m.SetKeyExpiry(netID, expiringNodeID, expiry))
+
+	// Heights recorded through the manager are served
+	require.NoError(m.SetHeight(10))
+	state, err := NewManagerState(m, ManagerStateConfig{
+		NetIDs:   []ids.ID{netID},
+		ChainIDs: map[ids.ID]ids.ID{netID: chainID},
+	})
+	require.NoError(err)
+	height, err := state.GetCurrentHeight(ctx)
+	require.NoError(err)
+	require.Equal(uint64(10), height)
+
+	gotChainID, err := state.GetChainID(netID)
+	require.NoError(err)
+	require.Equal(chainID, gotChainID)
+	gotNetID, err := state.GetNetworkID(chainID)
+	require.NoError(err)
+	require.Equal(netID, gotNetID)
+	_, err = state.GetChainID(ids.GenerateTestID())
+	require.ErrorIs(err, ErrNetNotFound)
+	_, err = state.GetNetworkID(netID)
+	require.ErrorIs(err, ErrNetNotFound)
+
+	require.NoError(m.SetKeyTime(expiry))
+	require.NoError(state.Accept(20))
+
+	warpSet, err := state.GetWarpValidatorSet(ctx, 15, netID)
+	require.NoError(err)
+	require.Equal(uint64(10), warpSet.Height)
+	require.Len(warpSet.Validators, 2)
+	require.NoError(warpSet.VerifyCommitment())
+	again, err := state.GetWarpValidatorSet(ctx, 10, netID)
+	require.NoError(err)
+	require.Same(warpSet, again)
+
+	warpSet, err = state.GetWarpValidatorSet(ctx, 25, netID)
+	require.NoError(err)
+	require.Equal(uint64(20), warpSet.Height)
+	require.Len(warpSet.Validators, 1)
+	require.Contains(warpSet.Validators, nodeID)
+	require.NoError(warpSet.VerifyCommitment())
+}
+
+// TestLegacyCurrentState tests that the shim serves the height-pinned set as
+// the current set
+func TestLegacyCurrentState(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	require.NoError(m.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 100))
+
+	state, err := NewManagerState(m, ManagerStateConfig{NetIDs: []ids.ID{netID}})
+	require.NoError(err)
+	require.NoError(state.Accept(1))
+	require.NoError(m.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 100))
+
+	legacy := NewLegacyCurrentState(state)
+	expected, err := state.GetValidatorSet(ctx, 1, netID)
+	require.NoError(err)
+	current, err := legacy.GetCurrentValidators(ctx, 1, netID)
+	require.NoError(err)
+	require.Equal(expected, current)
+}
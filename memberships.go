@@ -0,0 +1,47 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/luxfi/ids"
+)
+
+// MembershipManager answers node-centric queries across nets
+type MembershipManager interface {
+	// GetMemberships returns every net [nodeID] validates, sorted by net ID
+	GetMemberships(nodeID ids.NodeID) []Membership
+}
+
+// Membership is a node's position in a single net
+type Membership struct {
+	NetID  ids.ID
+	Light  uint64
+	Weight uint64
+}
+
+var _ MembershipManager = (*manager)(nil)
+
+// GetMemberships returns the nets a node validates using the membership index
+func (m *manager) GetMemberships(nodeID ids.NodeID) []Membership {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	netIDs := m.memberships[nodeID]
+	memberships := make([]Membership, 0, len(netIDs))
+	for netID := range netIDs {
+		val := m.validators[netID][nodeID]
+		memberships = append(memberships, Membership{
+			NetID:  netID,
+			Light:  val.Light,
+			Weight: val.Weight,
+		})
+	}
+	slices.SortFunc(memberships, func(a, b Membership) int {
+		return bytes.Compare(a.NetID[:], b.NetID[:])
+	})
+	return memberships
+}
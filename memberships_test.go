@@ -0,0 +1,84 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"bytes"
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// TestManagerGetMemberships tests the cross-net view of a node
+func TestManagerGetMemberships(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	nodeID := ids.GenerateTestNodeID()
+	netIDs := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()}
+	slices.SortFunc(netIDs, func(a, b ids.ID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+
+	require.Empty(m.GetMemberships(nodeID))
+	for i, netID := range netIDs {
+		require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, uint64(i+1)*100))
+	}
+	otherNodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netIDs[0], otherNodeID, nil, ids.Empty, 1))
+	require.NoError(m.AddWeight(netIDs[1], nodeID, 5))
+
+	require.Equal([]Membership{
+		{NetID: netIDs[0], Light: 100, Weight: 100},
+		{NetID: netIDs[1], Light: 205, Weight: 205},
+		{NetID: netIDs[2], Light: 300, Weight: 300},
+	}, m.GetMemberships(nodeID))
+
+	require.NoError(m.RemoveWeight(netIDs[1], nodeID, 205))
+	require.Equal([]Membership{
+		{NetID: netIDs[0], Light: 100, Weight: 100},
+		{NetID: netIDs[2], Light: 300, Weight: 300},
+	}, m.GetMemberships(nodeID))
+
+	require.NoError(m.RemoveWeight(netIDs[0], nodeID, 100))
+	require.NoError(m.RemoveWeight(netIDs[2], nodeID, 300))
+	require.Empty(m.GetMemberships(nodeID))
+	// Only the other validator is left in the index
+	require.Equal(map[ids.NodeID]map[ids.ID]struct{}{
+		otherNodeID: {netIDs[0]: {}},
+	}, m.memberships)
+}
+
+// TestManagerGetMembershipsAllPaths tests that every way of adding or removing
+// a validator maintains the index
+func TestManagerGetMembershipsAllPaths(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	nodeID := ids.GenerateTestNodeID()
+	l1NetID := ids.GenerateTestID()
+	bigNetID := ids.GenerateTestID()
+
+	m.SetFeeConfig(l1NetID, FeeConfig{PerSecond: 1})
+	require.NoError(m.RegisterL1Validator(l1NetID, nodeID, nil, ids.Empty, 10, 5))
+	require.NoError(m.SetWeightMode(bigNetID, WeightModeBig))
+	require.NoError(m.AddStakerBig(bigNetID, nodeID, nil, ids.Empty, big.NewInt(20)))
+	require.Len(m.GetMemberships(nodeID), 2)
+
+	// Draining the balance deactivates the L1 validator
+	start := time.Unix(0, 0)
+	m.AdvanceBalances(l1NetID, 0, start)
+	m.AdvanceBalances(l1NetID, 0, start.Add(5*time.Second))
+	require.Equal([]Membership{{NetID: bigNetID, Light: 20, Weight: 20}}, m.GetMemberships(nodeID))
+
+	require.NoError(m.TopUp(l1NetID, nodeID, 5))
+	require.Len(m.GetMemberships(nodeID), 2)
+
+	require.NoError(m.RemoveBigWeight(bigNetID, nodeID, big.NewInt(20)))
+	require.Equal([]Membership{{NetID: l1NetID, Light: 10, Weight: 10}}, m.GetMemberships(nodeID))
+}
@@ -0,0 +1,123 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/luxfi/ids"
+)
+
+const (
+	MaxMonikerLen   = 64
+	MaxWebsiteLen   = 256
+	MaxContactLen   = 256
+	MaxRegionTags   = 8
+	MaxRegionTagLen = 32
+)
+
+var ErrMetadataTooLarge = errors.New("metadata too large")
+
+// MetadataManager stores operator-supplied metadata about validators.
+// Metadata is attached to the validator records returned by GetValidator,
+// GetValidators, and GetMap, survives re-adding the validator, and is dropped
+// when the validator is removed.
+type MetadataManager interface {
+	// SetMetadata replaces the metadata of [nodeID] in [netID]
+	SetMetadata(netID ids.ID, nodeID ids.NodeID, metadata *ValidatorMetadata) error
+	// GetMetadata returns the metadata of [nodeID] in [netID], if any
+	GetMetadata(netID ids.ID, nodeID ids.NodeID) (*ValidatorMetadata, bool)
+	// DeleteMetadata removes the metadata of [nodeID] in [netID]
+	DeleteMetadata(netID ids.ID, nodeID ids.NodeID)
+}
+
+// ValidatorMetadata is operator-supplied information about a validator
+type ValidatorMetadata struct {
+	Moniker    string   `json:"moniker,omitempty"`
+	Website    string   `json:"website,omitempty"`
+	Contact    string   `json:"contact,omitempty"`
+	RegionTags []string `json:"regionTags,omitempty"`
+}
+
+// Verify returns an error if any field exceeds its size limit
+func (md *ValidatorMetadata) Verify() error {
+	switch {
+	case len(md.Moniker) > MaxMonikerLen:
+		return fmt.Errorf("%w: moniker is %d bytes, limit is %d", ErrMetadataTooLarge, len(md.Moniker), MaxMonikerLen)
+	case len(md.Website) > MaxWebsiteLen:
+		return fmt.Errorf("%w: website is %d bytes, limit is %d", ErrMetadataTooLarge, len(md.Website), MaxWebsiteLen)
+	case len(md.Contact) > MaxContactLen:
+		return fmt.Errorf("%w: contact is %d bytes, limit is %d", ErrMetadataTooLarge, len(md.Contact), MaxContactLen)
+	case len(md.RegionTags) > MaxRegionTags:
+		return fmt.Errorf("%w: %d region tags, limit is %d", ErrMetadataTooLarge, len(md.RegionTags), MaxRegionTags)
+	}
+	for _, tag := range md.RegionTags {
+		if len(tag) > MaxRegionTagLen {
+			return fmt.Errorf("%w: region tag is %d bytes, limit is %d", ErrMetadataTooLarge, len(tag), MaxRegionTagLen)
+		}
+	}
+	return nil
+}
+
+// Clone returns a deep copy of the metadata. Cloning nil returns nil.
+func (md *ValidatorMetadata) Clone() *ValidatorMetadata {
+	if md == nil {
+		return nil
+	}
+	mdCopy := *md
+	mdCopy.RegionTags = slices.Clone(md.RegionTags)
+	return &mdCopy
+}
+
+var _ MetadataManager = (*manager)(nil)
+
+// SetMetadata replaces the metadata of an existing validator
+func (m *manager) SetMetadata(netID ids.ID, nodeID ids.NodeID, metadata *ValidatorMetadata) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.setMetadata(netID, nodeID, metadata)
+}
+
+// setMetadata is SetMetadata without locking. It assumes the lock is held.
+func (m *manager) setMetadata(netID ids.ID, nodeID ids.NodeID, metadata *ValidatorMetadata) error {
+	if metadata != nil {
+		if err := metadata.Verify(); err != nil {
+			return err
+		}
+	}
+
+	val, exists := m.validators[netID][nodeID]
+	if !exists {
+		return fmt.Errorf("%w: %s in %s", ErrUnknownValidator, nodeID, netID)
+	}
+	val.Metadata = metadata.Clone()
+	m.bumpSequence(netID, val)
+	return nil
+}
+
+// GetMetadata returns a copy of a validator's metadata
+func (m *manager) GetMetadata(netID ids.ID, nodeID ids.NodeID) (*ValidatorMetadata, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	val, exists := m.validators[netID][nodeID]
+	if !exists || val.Metadata == nil {
+		return nil, false
+	}
+	return val.Metadata.Clone(), true
+}
+
+// DeleteMetadata removes a validator's metadata
+func (m *manager) DeleteMetadata(netID ids.ID, nodeID ids.NodeID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if val, exists := m.validators[netID][nodeID]; exists && val.Metadata != nil {
+		val.Metadata = nil
+		m.bumpSequence(netID, val)
+	}
+}
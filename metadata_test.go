@@ -0,0 +1,106 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// TestManagerMetadata tests that metadata is attached to validator reads
+func TestManagerMetadata(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+
+	metadata := &ValidatorMetadata{
+		Moniker:    "lux-0",
+		Website:    "https://lux.network",
+		Contact:    "ops@lux.network",
+		RegionTags: []string{"eu-west", "bare-metal"},
+	}
+	require.ErrorIs(m.SetMetadata(netID, nodeID, metadata), ErrUnknownValidator)
+
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+	require.NoError(m.SetMetadata(netID, nodeID, metadata))
+
+	// Mutating the caller's copy doesn't affect the stored metadata
+	metadata.RegionTags[0] = "us-east"
+
+	got, ok := m.GetMetadata(netID, nodeID)
+	require.True(ok)
+	require.Equal([]string{"eu-west", "bare-metal"}, got.RegionTags)
+
+	vdr, ok := m.GetValidator(netID, nodeID)
+	require.True(ok)
+	require.Equal(got, vdr.Metadata)
+	require.Equal(got, m.GetMap(netID)[nodeID].Metadata)
+
+	// Mutating returned metadata doesn't affect the manager
+	vdr.Metadata.Moniker = "changed"
+	got, ok = m.GetMetadata(netID, nodeID)
+	require.True(ok)
+	require.Equal("lux-0", got.Moniker)
+
+	// Replacing keeps metadata, removal drops it
+	require.NoError(m.SetDuplicatePolicy(DuplicateReplace))
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 200))
+	_, ok = m.GetMetadata(netID, nodeID)
+	require.True(ok)
+
+	m.DeleteMetadata(netID, nodeID)
+	_, ok = m.GetMetadata(netID, nodeID)
+	require.False(ok)
+
+	require.NoError(m.SetMetadata(netID, nodeID, metadata))
+	require.NoError(m.RemoveWeight(netID, nodeID, 200))
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+	_, ok = m.GetMetadata(netID, nodeID)
+	require.False(ok)
+}
+
+// TestValidatorMetadataVerify tests the metadata size limits
+func TestValidatorMetadataVerify(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata ValidatorMetadata
+	}{
+		{
+			name:     "moniker",
+			metadata: ValidatorMetadata{Moniker: strings.Repeat("a", MaxMonikerLen+1)},
+		},
+		{
+			name:     "website",
+			metadata: ValidatorMetadata{Website: strings.Repeat("a", MaxWebsiteLen+1)},
+		},
+		{
+			name:     "contact",
+			metadata: ValidatorMetadata{Contact: strings.Repeat("a", MaxContactLen+1)},
+		},
+		{
+			name:     "too many region tags",
+			metadata: ValidatorMetadata{RegionTags: make([]string, MaxRegionTags+1)},
+		},
+		{
+			name:     "region tag",
+			metadata: ValidatorMetadata{RegionTags: []string{strings.Repeat("a", MaxRegionTagLen+1)}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.metadata.Verify(), ErrMetadataTooLarge)
+		})
+	}
+
+	valid := ValidatorMetadata{
+		Moniker:    strings.Repeat("a", MaxMonikerLen),
+		RegionTags: make([]string, MaxRegionTags),
+	}
+	require.NoError(t, valid.Verify())
+}
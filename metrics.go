@@ -0,0 +1,216 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/luxfi/ids"
+)
+
+var ErrMetricsRegistered = errors.New("metrics already registered")
+
+// Gauge, Counter and Histogram are the subsets of the Prometheus metric types
+// the manager updates. prometheus.Gauge, prometheus.Counter and
+// prometheus.Histogram implement them.
+type (
+	Gauge interface {
+		Set(value float64)
+	}
+	Counter interface {
+		Inc()
+	}
+	Histogram interface {
+		Observe(value float64)
+	}
+)
+
+// GaugeVec and CounterVec are labeled families of gauges and counters, like
+// prometheus.GaugeVec and prometheus.CounterVec
+type (
+	GaugeVec interface {
+		WithLabelValues(values ...string) Gauge
+		DeleteLabelValues(values ...string) bool
+	}
+	CounterVec interface {
+		WithLabelValues(values ...string) Counter
+	}
+)
+
+// MetricsRegistry creates and registers the metrics of a manager. Embedders
+// bridge it to a prometheus.Registerer, so this package doesn't depend on the
+// Prometheus client.
+type MetricsRegistry interface {
+	NewGaugeVec(name, help string, labels ...string) (GaugeVec, error)
+	NewCounterVec(name, help string, labels ...string) (CounterVec, error)
+	NewHistogram(name, help string) (Histogram, error)
+}
+
+// Names of the metrics a manager registers
+const (
+	MetricValidators       = "validators_count"
+	MetricLight            = "validators_total_light"
+	MetricOperations       = "validators_operations_total"
+	MetricDispatchDuration = "validators_listener_dispatch_seconds"
+)
+
+// Values of the "op" label of MetricOperations
+const (
+	OpAddStaker    = "add_staker"
+	OpAddWeight    = "add_weight"
+	OpRemoveWeight = "remove_weight"
+	OpSetWeight    = "set_weight"
+	OpRemoveStaker = "remove_staker"
+	OpAddLight     = "add_light"
+	OpRemoveLight  = "remove_light"
+)
+
+// MetricsManager exports the health of the validator sets to a metrics
+// registry:
+//
+//   - MetricValidators and MetricLight are gauges of each net's validator
+//     count and total light, labeled by "net". Nets without validators are
+//     deleted.
+//   - MetricOperations counts successful AddStaker, AddWeight, RemoveWeight,
+//     SetWeight, RemoveStaker, AddLight and RemoveLight calls, and the big
+//     weight counterparts of the stake changes, including those committed in
+//     a Tx and the changes of an applied ValidatorDiff, labeled by "op".
+//   - MetricDispatchDuration observes how long the listeners of those calls
+//     take to be notified, in seconds.
+type MetricsManager interface {
+	RegisterMetrics(registry MetricsRegistry) error
+}
+
+var _ MetricsManager = (*manager)(nil)
+
+// RegisterMetrics registers the metrics of the manager with [registry]. The
+// gauges start from the validators already in the manager. Metrics can only
+// be registered once.
+func (m *manager) RegisterMetrics(registry MetricsRegistry) error {
+	metrics, err := newManagerMetrics(registry)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.metrics != nil {
+		return ErrMetricsRegistered
+	}
+	m.metrics = metrics
+
+	// The gauges follow the same notifications as any other listener
+	m.listeners.add(metrics)
+	for netID, validators := range m.validators {
+		for nodeID, val := range validators {
+			metrics.OnValidatorAdded(netID, nodeID, val.Light)
+		}
+	}
+	return nil
+}
+
+// managerMetrics is the metrics of a manager. The per-net gauges are kept up
+// to date as a ManagerCallbackListener.
+type managerMetrics struct {
+	validators GaugeVec
+	light      GaugeVec
+	operations CounterVec
+	dispatch   Histogram
+
+	mu   sync.Mutex
+	nets map[ids.ID]*netTotals
+}
+
+// netTotals is the validator count and total light of a net
+type netTotals struct {
+	validators int
+	light      uint64
+}
+
+func newManagerMetrics(registry MetricsRegistry) (*managerMetrics, error) {
+	validators, err := registry.NewGaugeVec(MetricValidators, "number of validators of the net", "net")
+	if err != nil {
+		return nil, err
+	}
+	light, err := registry.NewGaugeVec(MetricLight, "total light of the validators of the net", "net")
+	if err != nil {
+		return nil, err
+	}
+	operations, err := registry.NewCounterVec(MetricOperations, "number of successful validator operations", "op")
+	if err != nil {
+		return nil, err
+	}
+	dispatch, err := registry.NewHistogram(MetricDispatchDuration, "time taken to notify listeners of a change, in seconds")
+	if err != nil {
+		return nil, err
+	}
+	return &managerMetrics{
+		validators: validators,
+		light:      light,
+		operations: operations,
+		dispatch:   dispatch,
+		nets:       make(map[ids.ID]*netTotals),
+	}, nil
+}
+
+// operation counts a successful [op]. It is a no-op on nil metrics.
+func (m *managerMetrics) operation(op string) {
+	if m != nil {
+		m.operations.WithLabelValues(op).Inc()
+	}
+}
+
+// observeDispatch observes a dispatch that started at [start]. It is a no-op
+// on nil metrics.
+func (m *managerMetrics) observeDispatch(start time.Time) {
+	if m != nil {
+		m.dispatch.Observe(time.Since(start).Seconds())
+	}
+}
+
+func (m *managerMetrics) OnValidatorAdded(netID ids.ID, _ ids.NodeID, light uint64) {
+	m.update(netID, func(totals *netTotals) {
+		totals.validators++
+		totals.light += light
+	})
+}
+
+func (m *managerMetrics) OnValidatorRemoved(netID ids.ID, _ ids.NodeID, light uint64) {
+	m.update(netID, func(totals *netTotals) {
+		totals.validators--
+		totals.light -= light
+	})
+}
+
+func (m *managerMetrics) OnValidatorLightChanged(netID ids.ID, _ ids.NodeID, oldLight, newLight uint64) {
+	m.update(netID, func(totals *netTotals) {
+		totals.light = totals.light - oldLight + newLight
+	})
+}
+
+// update applies [f] to the totals of [netID] and exports them
+func (m *managerMetrics) update(netID ids.ID, f func(*netTotals)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	totals, ok := m.nets[netID]
+	if !ok {
+		totals = &netTotals{}
+		m.nets[netID] = totals
+	}
+	f(totals)
+
+	label := netID.String()
+	if totals.validators <= 0 {
+		delete(m.nets, netID)
+		m.validators.DeleteLabelValues(label)
+		m.light.DeleteLabelValues(label)
+		return
+	}
+	m.validators.WithLabelValues(label).Set(float64(totals.validators))
+	m.light.WithLabelValues(label).Set(float64(totals.light))
+}
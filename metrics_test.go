@@ -0,0 +1,147 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+var errTestRegistry = errors.New("test registry error")
+
+// testRegistry records metric values by name and joined label values
+type testRegistry struct {
+	values       map[string]map[string]float64
+	observations map[string][]float64
+	err          error
+}
+
+func newTestRegistry() *testRegistry {
+	return &testRegistry{
+		values:       make(map[string]map[string]float64),
+		observations: make(map[string][]float64),
+	}
+}
+
+func (r *testRegistry) NewGaugeVec(name, _ string, _ ...string) (GaugeVec, error) {
+	r.values[name] = make(map[string]float64)
+	return &testGaugeVec{values: r.values[name]}, r.err
+}
+
+func (r *testRegistry) NewCounterVec(name, _ string, _ ...string) (CounterVec, error) {
+	r.values[name] = make(map[string]float64)
+	return &testCounterVec{values: r.values[name]}, r.err
+}
+
+func (r *testRegistry) NewHistogram(name, _ string) (Histogram, error) {
+	return &testHistogram{registry: r, name: name}, r.err
+}
+
+type testGaugeVec struct {
+	values map[string]float64
+}
+
+func (v *testGaugeVec) WithLabelValues(values ...string) Gauge {
+	return &testMetric{values: v.values, label: strings.Join(values, ",")}
+}
+
+func (v *testGaugeVec) DeleteLabelValues(values ...string) bool {
+	label := strings.Join(values, ",")
+	_, ok := v.values[label]
+	delete(v.values, label)
+	return ok
+}
+
+type testCounterVec struct {
+	values map[string]float64
+}
+
+func (v *testCounterVec) WithLabelValues(values ...string) Counter {
+	return &testMetric{values: v.values, label: strings.Join(values, ",")}
+}
+
+type testMetric struct {
+	values map[string]float64
+	label  string
+}
+
+func (m *testMetric) Set(value float64) {
+	m.values[m.label] = value
+}
+
+func (m *testMetric) Inc() {
+	m.values[m.label]++
+}
+
+type testHistogram struct {
+	registry *testRegistry
+	name     string
+}
+
+func (h *testHistogram) Observe(value float64) {
+	h.registry.observations[h.name] = append(h.registry.observations[h.name], value)
+}
+
+// TestManagerMetrics tests that the gauges, counters and histogram follow the
+// manager
+func TestManagerMetrics(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID1, nodeID2 := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID1, nil, ids.Empty, 100))
+
+	registry := newTestRegistry()
+	require.NoError(m.RegisterMetrics(registry))
+	require.ErrorIs(m.RegisterMetrics(newTestRegistry()), ErrMetricsRegistered)
+
+	// Gauges start from the existing validators
+	net := netID.String()
+	require.Equal(map[string]float64{net: 1}, registry.values[MetricValidators])
+	require.Equal(map[string]float64{net: 100}, registry.values[MetricLight])
+
+	require.NoError(m.AddStaker(netID, nodeID2, nil, ids.Empty, 50))
+	require.NoError(m.RemoveWeight(netID, nodeID1, 30))
+	require.NoError(m.AddWeight(netID, nodeID1, 10))
+	require.NoError(m.SetWeight(netID, nodeID2, 70))
+
+	// Failed operations aren't counted
+	m.SetStrict(true)
+	require.ErrorIs(m.RemoveWeight(netID, ids.GenerateTestNodeID(), 1), ErrValidatorNotFound)
+	m.SetStrict(false)
+	require.Equal(map[string]float64{net: 2}, registry.values[MetricValidators])
+	require.Equal(map[string]float64{net: 150}, registry.values[MetricLight])
+	require.Equal(map[string]float64{
+		OpAddStaker:    1,
+		OpRemoveWeight: 1,
+		OpAddWeight:    1,
+		OpSetWeight:    1,
+	}, registry.values[MetricOperations])
+	require.Len(registry.observations[MetricDispatchDuration], 4)
+
+	// Nets without validators are deleted
+	require.NoError(m.RemoveStaker(netID, nodeID1))
+	require.NoError(m.RemoveStaker(netID, nodeID2))
+	require.Empty(registry.values[MetricValidators])
+	require.Empty(registry.values[MetricLight])
+	require.Equal(float64(2), registry.values[MetricOperations][OpRemoveStaker])
+}
+
+// TestManagerMetricsRegistryError tests that registry errors are returned
+func TestManagerMetricsRegistryError(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	registry := newTestRegistry()
+	registry.err = errTestRegistry
+	require.ErrorIs(m.RegisterMetrics(registry), errTestRegistry)
+
+	// A failed registration can be retried
+	require.NoError(m.RegisterMetrics(newTestRegistry()))
+}
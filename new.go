@@ -1,49 +1,193 @@
 package validators
 
 import (
+	"fmt"
+	"maps"
+	"math/big"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/luxfi/ids"
+	"github.com/luxfi/math"
 	"github.com/luxfi/math/set"
 )
 
 // NewManager creates a new validator manager
 func NewManager() *manager {
 	return &manager{
-		validators: make(map[ids.ID]map[ids.NodeID]*GetValidatorOutput),
-		mu:         &sync.RWMutex{},
-		listeners:  make([]ManagerCallbackListener, 0),
+		validators:    make(map[ids.ID]map[ids.NodeID]*GetValidatorOutput),
+		mu:            &sync.RWMutex{},
+		listeners:     &callbackListeners{},
+		delegations:   newDelegations(),
+		balances:      make(map[validatorKey]*balanceEntry),
+		feeConfigs:    make(map[ids.ID]FeeConfig),
+		balanceClocks: make(map[ids.ID]balanceClock),
+		weightModes:   make(map[ids.ID]WeightMode),
+		weightScales:  make(map[ids.ID]uint64),
+		bigWeights:    make(map[validatorKey]*big.Int),
+		assets:        newAssets(),
+		memberships:   make(map[ids.NodeID]map[ids.ID]struct{}),
+		txIDs:         make(map[ids.ID][]validatorKey),
+		allowlists:    make(map[ids.ID]map[ids.NodeID]struct{}),
+		denylists:     make(map[ids.ID]map[ids.NodeID]struct{}),
+		frozen:        make(map[ids.ID]int),
+		snapshots:     newSnapshots(),
+		history:       newHistory(),
+		self:          &selfTracker{},
 	}
 }
 
 type manager struct {
-	validators map[ids.ID]map[ids.NodeID]*GetValidatorOutput
-	mu         *sync.RWMutex
-	listeners  []ManagerCallbackListener
+	validators  map[ids.ID]map[ids.NodeID]*GetValidatorOutput
+	mu          *sync.RWMutex
+	listeners   *callbackListeners
+	delegations *delegations
+
+	// memberships indexes the nets each node validates
+	memberships map[ids.NodeID]map[ids.ID]struct{}
+	// txIDs indexes the validators each registration tx added, in the order
+	// they were added
+	txIDs map[ids.ID][]validatorKey
+
+	delegationListeners []DelegationListener
+
+	balances         map[validatorKey]*balanceEntry
+	feeConfigs       map[ids.ID]FeeConfig
+	balanceClocks    map[ids.ID]balanceClock
+	balanceListeners []BalanceListener
+
+	weightModes  map[ids.ID]WeightMode
+	bigWeights   map[validatorKey]*big.Int
+	weightScales map[ids.ID]uint64
+
+	assets         *assets
+	assetListeners []AssetListener
+
+	allowlists map[ids.ID]map[ids.NodeID]struct{}
+	denylists  map[ids.ID]map[ids.NodeID]struct{}
+
+	// sequence is the last sequence assigned to a validator record
+	sequence uint64
+
+	limits          Limits
+	duplicatePolicy DuplicatePolicy
+	strict          bool
+	sampleMode      SampleMode
+
+	// frozen counts the outstanding freezes of each frozen net
+	frozen          map[ids.ID]int
+	freezeListeners []FreezeListener
+
+	snapshots *snapshots
+	history   *history
+
+	keyListeners []PublicKeyListener
+	// keyTime is the time key expiries are checked against
+	keyTime time.Time
+
+	metrics *managerMetrics
+	logger  *managerLogger
+	self    *selfTracker
+
+	// closed is set by Close
+	closed bool
 }
 
-// AddStaker adds a validator to the set
+// AddStaker adds a validator to the set. Adding a validator that is already
+// in the net is handled by the duplicate policy, see DuplicatePolicy.
 func (m *manager) AddStaker(netID ids.ID, nodeID ids.NodeID, publicKey []byte, txID ids.ID, light uint64) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if m.validators[netID] == nil {
-		m.validators[netID] = make(map[ids.NodeID]*GetValidatorOutput)
+	notify, err := m.addStaker(netID, nodeID, publicKey, txID, light)
+	if err != nil {
+		return err
 	}
+	m.metrics.operation(OpAddStaker)
+	m.notify(notify)
+	return nil
+}
 
-	m.validators[netID][nodeID] = &GetValidatorOutput{
-		NodeID:    nodeID,
-		PublicKey: publicKey,
-		Light:     light,
-		Weight:    light,
-		TxID:      txID,
+// addStaker is AddStaker without locking or notifying. It assumes the lock is
+// held and returns the notification of the change.
+func (m *manager) addStaker(netID ids.ID, nodeID ids.NodeID, publicKey []byte, txID ids.ID, light uint64) (func(ManagerCallbackListener), error) {
+	if err := m.requireThawed(netID); err != nil {
+		return nil, err
+	}
+	if err := m.requireWeightMode(netID, WeightModeUint64); err != nil {
+		return nil, err
+	}
+	if err := m.requireAllowed(netID, nodeID); err != nil {
+		return nil, err
+	}
+	if val, exists := m.validators[netID][nodeID]; exists {
+		if err := m.verifyDuplicate(netID, val, light); err != nil {
+			return nil, err
+		}
+		return m.addDuplicate(netID, val, publicKey, txID, light), nil
+	}
+	if err := m.requireCapacity(netID, nodeID); err != nil {
+		return nil, err
+	}
+	if _, err := m.stakerLight(netID, nodeID, light); err != nil {
+		return nil, err
 	}
 
-	// Notify all listeners
-	for _, listener := range m.listeners {
+	light = m.putStaker(netID, nodeID, publicKey, txID, light)
+	return func(listener ManagerCallbackListener) {
 		listener.OnValidatorAdded(netID, nodeID, light)
+	}, nil
+}
+
+// notify sends [notify] to every listener, if there is anything to notify.
+// Changes notify with the lock held, so listeners see them in order; Close
+// notifies without it, see Close.
+func (m *manager) notify(notify func(ManagerCallbackListener)) {
+	if notify == nil {
+		return
 	}
-	return nil
+	defer m.metrics.observeDispatch(time.Now())
+	for _, listener := range m.listeners.load() {
+		notify(listener)
+	}
+}
+
+// stakerLight returns the light putStaker gives [nodeID] for a self-stake of
+// [light], or an error if it overflows. It assumes the lock is held.
+func (m *manager) stakerLight(netID ids.ID, nodeID ids.NodeID, light uint64) (uint64, error) {
+	light, err := math.Add64(light, m.externalWeight(validatorKey{netID: netID, nodeID: nodeID}))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+	}
+	return light, nil
+}
+
+// putStaker adds or replaces the self-stake of [nodeID] and returns its new
+// light. It assumes the lock is held and the addition is allowed, see
+// stakerLight.
+func (m *manager) putStaker(netID ids.ID, nodeID ids.NodeID, publicKey []byte, txID ids.ID, light uint64) uint64 {
+	// Re-adding a validator replaces its self-stake but keeps its delegations,
+	// asset stakes, metadata and extensions
+	light += m.externalWeight(validatorKey{netID: netID, nodeID: nodeID})
+	var (
+		metadata   *ValidatorMetadata
+		extensions map[string][]byte
+	)
+	if old, exists := m.validators[netID][nodeID]; exists {
+		metadata = old.Metadata
+		extensions = old.Extensions
+	}
+	m.putValidator(netID, &GetValidatorOutput{
+		NodeID:     nodeID,
+		PublicKey:  publicKey,
+		Light:      light,
+		Weight:     light,
+		TxID:       txID,
+		Metadata:   metadata,
+		Extensions: extensions,
+	})
+	return light
 }
 
 // AddWeight adds weight to an existing validator
@@ -51,18 +195,45 @@ func (m *manager) AddWeight(netID ids.ID, nodeID ids.NodeID, light uint64) error
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if m.validators[netID] == nil {
-		m.validators[netID] = make(map[ids.NodeID]*GetValidatorOutput)
+	notify, err := m.addWeight(netID, nodeID, light)
+	if err != nil {
+		return err
 	}
+	m.metrics.operation(OpAddWeight)
+	m.notify(notify)
+	return nil
+}
 
+// addWeight is AddWeight without locking or notifying. It assumes the lock is
+// held and returns the notification of the change, if any.
+func (m *manager) addWeight(netID ids.ID, nodeID ids.NodeID, light uint64) (func(ManagerCallbackListener), error) {
+	if err := m.requireThawed(netID); err != nil {
+		return nil, err
+	}
+	if err := m.requireWeightMode(netID, WeightModeUint64); err != nil {
+		return nil, err
+	}
 	val, exists := m.validators[netID][nodeID]
 	if !exists {
-		return nil // Validator doesn't exist, nothing to add
+		return nil, m.requireValidator(netID, nodeID) // Validator doesn't exist, nothing to add
 	}
 
-	val.Light += light
-	val.Weight += light
-	return nil
+	newLight, err := math.Add64(val.Light, light)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+	}
+	newWeight, err := math.Add64(val.Weight, light)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+	}
+
+	oldLight := val.Light
+	val.Light = newLight
+	val.Weight = newWeight
+	m.bumpSequence(netID, val)
+	return func(listener ManagerCallbackListener) {
+		listener.OnValidatorLightChanged(netID, nodeID, oldLight, newLight)
+	}, nil
 }
 
 // RemoveWeight removes weight from an existing validator
@@ -70,34 +241,151 @@ func (m *manager) RemoveWeight(netID ids.ID, nodeID ids.NodeID, light uint64) er
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if m.validators[netID] == nil {
-		return nil
+	notify, err := m.removeWeight(netID, nodeID, light)
+	if err != nil {
+		return err
+	}
+	m.metrics.operation(OpRemoveWeight)
+	m.notify(notify)
+	return nil
+}
+
+// removeWeight is RemoveWeight without locking or notifying. It assumes the
+// lock is held and returns the notification of the change, if any.
+func (m *manager) removeWeight(netID ids.ID, nodeID ids.NodeID, light uint64) (func(ManagerCallbackListener), error) {
+	if err := m.requireThawed(netID); err != nil {
+		return nil, err
+	}
+	if err := m.requireWeightMode(netID, WeightModeUint64); err != nil {
+		return nil, err
+	}
+	val, exists := m.validators[netID][nodeID]
+	if !exists {
+		return nil, m.requireValidator(netID, nodeID) // Validator doesn't exist, nothing to remove
+	}
+
+	oldLight := val.Light
+	if m.removeSelfStake(netID, val, light) {
+		return func(listener ManagerCallbackListener) {
+			listener.OnValidatorRemoved(netID, nodeID, oldLight)
+		}, nil
+	}
+
+	newLight := val.Light
+	return func(listener ManagerCallbackListener) {
+		listener.OnValidatorLightChanged(netID, nodeID, oldLight, newLight)
+	}, nil
+}
+
+// SetWeight sets the light and weight of an existing validator to [light],
+// which includes its delegated and asset weight, such as when an L1 weight
+// update carries the new weight rather than a change. Setting the weight to
+// 0 removes the validator.
+func (m *manager) SetWeight(netID ids.ID, nodeID ids.NodeID, light uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	notify, err := m.setWeight(netID, nodeID, light)
+	if err != nil {
+		return err
 	}
+	m.metrics.operation(OpSetWeight)
+	m.notify(notify)
+	return nil
+}
 
+// setWeight is SetWeight without locking or notifying. It assumes the lock is
+// held and returns the notification of the change, if any.
+func (m *manager) setWeight(netID ids.ID, nodeID ids.NodeID, light uint64) (func(ManagerCallbackListener), error) {
+	if err := m.requireThawed(netID); err != nil {
+		return nil, err
+	}
+	if err := m.requireWeightMode(netID, WeightModeUint64); err != nil {
+		return nil, err
+	}
 	val, exists := m.validators[netID][nodeID]
 	if !exists {
-		return nil // Validator doesn't exist, nothing to remove
+		return nil, m.requireValidator(netID, nodeID) // Validator doesn't exist, nothing to set
+	}
+
+	oldLight := val.Light
+	if light == 0 {
+		m.evictValidator(netID, nodeID)
+		return func(listener ManagerCallbackListener) {
+			listener.OnValidatorRemoved(netID, nodeID, oldLight)
+		}, nil
 	}
+	if external := m.externalWeight(validatorKey{netID: netID, nodeID: nodeID}); light < external {
+		return nil, fmt.Errorf("%w: %d < %d", ErrWeightBelowDelegated, light, external)
+	}
+
+	val.Light = light
+	val.Weight = light
+	m.bumpSequence(netID, val)
+	return func(listener ManagerCallbackListener) {
+		listener.OnValidatorLightChanged(netID, nodeID, oldLight, light)
+	}, nil
+}
 
-	if val.Light >= light {
-		val.Light -= light
+// removeSelfStake removes up to [light] of the self-stake of [val] and
+// returns true if the validator was removed. It assumes the lock is held.
+func (m *manager) removeSelfStake(netID ids.ID, val *GetValidatorOutput, light uint64) bool {
+	// Only self-stake can be removed. Once it is exhausted the validator is
+	// removed along with its delegations and asset stakes. Light is reduced
+	// by the same amount but may already be lower than the weight if it was
+	// changed separately.
+	key := validatorKey{netID: netID, nodeID: val.NodeID}
+	selfStake := val.Weight - m.externalWeight(key)
+	if selfStake > light {
+		val.Light -= min(light, val.Light)
 		val.Weight -= light
 	} else {
 		val.Light = 0
 		val.Weight = 0
 	}
+	m.bumpSequence(netID, val)
 
 	// Remove validator if weight is 0
-	if val.Light == 0 {
-		delete(m.validators[netID], nodeID)
-		if len(m.validators[netID]) == 0 {
-			delete(m.validators, netID)
-		}
+	if val.Weight == 0 {
+		m.evictValidator(netID, val.NodeID)
+		return true
 	}
+	return false
+}
 
+// RemoveStaker removes a validator regardless of its weight, along with its
+// delegations and asset stakes. Removing an unknown validator is a no-op.
+func (m *manager) RemoveStaker(netID ids.ID, nodeID ids.NodeID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	notify, err := m.removeStaker(netID, nodeID)
+	if err != nil {
+		return err
+	}
+	m.metrics.operation(OpRemoveStaker)
+	m.notify(notify)
 	return nil
 }
 
+// removeStaker is RemoveStaker without locking or notifying. It assumes the
+// lock is held and returns the notification of the change, if any.
+func (m *manager) removeStaker(netID ids.ID, nodeID ids.NodeID) (func(ManagerCallbackListener), error) {
+	if err := m.requireThawed(netID); err != nil {
+		return nil, err
+	}
+
+	val, exists := m.validators[netID][nodeID]
+	if !exists {
+		return nil, nil
+	}
+
+	m.evictValidator(netID, nodeID)
+	return func(listener ManagerCallbackListener) {
+		listener.OnValidatorRemoved(netID, nodeID, val.Light)
+	}, nil
+}
+
 // NumNets returns the number of networks with validators
 func (m *manager) NumNets() int {
 	m.mu.RLock()
@@ -106,14 +394,34 @@ func (m *manager) NumNets() int {
 	return len(m.validators)
 }
 
-func (m *manager) GetValidators(netID ids.ID) (Set, error) {
+// GetNetIDs returns the networks with validators, sorted by ID
+func (m *manager) GetNetIDs() []ids.ID {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	if validators, ok := m.validators[netID]; ok {
-		return &validatorSet{validators: validators}, nil
+	netIDs := slices.Collect(maps.Keys(m.validators))
+	slices.SortFunc(netIDs, ids.ID.Compare)
+	return netIDs
+}
+
+// TotalCount returns the number of validators across all networks. A node
+// validating several networks is counted once for each.
+func (m *manager) TotalCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var count int
+	for _, vdrs := range m.validators {
+		count += len(vdrs)
 	}
-	return &emptySet{}, nil
+	return count
+}
+
+func (m *manager) GetValidators(netID ids.ID) (Set, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.snapshot(netID).set(m.sampleMode), nil
 }
 
 func (m *manager) GetValidator(netID ids.ID, nodeID ids.NodeID) (*GetValidatorOutput, bool) {
@@ -122,7 +430,7 @@ func (m *manager) GetValidator(netID ids.ID, nodeID ids.NodeID) (*GetValidatorOu
 
 	if validators, ok := m.validators[netID]; ok {
 		if val, exists := validators[nodeID]; exists {
-			return val, true
+			return copyValidator(val), true
 		}
 	}
 	return nil, false
@@ -140,20 +448,32 @@ func (m *manager) GetWeight(netID ids.ID, nodeID ids.NodeID) uint64 {
 }
 
 func (m *manager) TotalLight(netID ids.ID) (uint64, error) {
-	set, err := m.GetValidators(netID)
-	if err != nil {
-		return 0, err
-	}
-	return set.Light(), nil
+	return m.Snapshot(netID).TotalLight()
 }
 
+// TotalWeight returns the sum of the economic weights of a net, which may
+// differ from TotalLight once light and weight have been changed separately
 func (m *manager) TotalWeight(netID ids.ID) (uint64, error) {
-	return m.TotalLight(netID)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var (
+		total uint64
+		err   error
+	)
+	for _, val := range m.validators[netID] {
+		total, err = math.Add64(total, val.Weight)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+		}
+	}
+	return total, nil
 }
 
 // validatorSet represents a validator set
 type validatorSet struct {
 	validators map[ids.NodeID]*GetValidatorOutput
+	sampleMode SampleMode
 }
 
 func (s *validatorSet) Has(nodeID ids.NodeID) bool {
@@ -176,15 +496,18 @@ func (s *validatorSet) List() []Validator {
 	return vals
 }
 
+// Light returns the total light of the set, saturated at MaxUint64
 func (s *validatorSet) Light() uint64 {
-	var total uint64
-	for _, v := range s.validators {
-		total += v.Light
-	}
+	total, _ := sumLight(s.validators)
 	return total
 }
 
+// Sample returns [size] validators, following the contract of SampleMode
 func (s *validatorSet) Sample(size int) ([]ids.NodeID, error) {
+	size, err := sampleSize(s.sampleMode, size, len(s.validators))
+	if err != nil {
+		return nil, err
+	}
 	nodeIDs := make([]ids.NodeID, 0, size)
 	for nodeID := range s.validators {
 		if len(nodeIDs) >= size {
@@ -196,13 +519,20 @@ func (s *validatorSet) Sample(size int) ([]ids.NodeID, error) {
 }
 
 // emptySet represents an empty validator set
-type emptySet struct{}
+type emptySet struct {
+	sampleMode SampleMode
+}
 
 func (s *emptySet) Has(ids.NodeID) bool { return false }
 func (s *emptySet) Len() int            { return 0 }
 func (s *emptySet) List() []Validator   { return nil }
 func (s *emptySet) Light() uint64       { return 0 }
+
+// Sample returns no validators, following the contract of SampleMode
 func (s *emptySet) Sample(size int) ([]ids.NodeID, error) {
+	if _, err := sampleSize(s.sampleMode, size, 0); err != nil {
+		return nil, err
+	}
 	return nil, nil
 }
 
@@ -222,19 +552,23 @@ func (m *manager) NumValidators(netID ids.ID) int {
 	return m.Count(netID)
 }
 
-// Sample returns a sample of validator node IDs
+// Sample returns a sample of validator node IDs, following the contract of
+// SampleMode
 func (m *manager) Sample(netID ids.ID, size int) ([]ids.NodeID, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	subnet := m.validators[netID]
+	size, err := sampleSize(m.sampleMode, size, len(subnet))
+	if err != nil {
+		return nil, err
+	}
 	nodeIDs := make([]ids.NodeID, 0, size)
-	if subnet, ok := m.validators[netID]; ok {
-		for nodeID := range subnet {
-			if len(nodeIDs) >= size {
-				break
-			}
-			nodeIDs = append(nodeIDs, nodeID)
+	for nodeID := range subnet {
+		if len(nodeIDs) >= size {
+			break
 		}
+		nodeIDs = append(nodeIDs, nodeID)
 	}
 	return nodeIDs, nil
 }
@@ -270,20 +604,95 @@ func (m *manager) SubsetWeight(netID ids.ID, nodeIDs set.Set[ids.NodeID]) (uint6
 	return totalWeight, nil
 }
 
-// GetMap returns a copy of the validator map for a network
+// GetMap returns a copy of the validator map for a network, taken from its
+// shared snapshot. The map and records belong to the caller, but the keys,
+// metadata and extensions of the records are shared and must not be
+// modified. Readers that don't modify the result should use Snapshot or
+// View, which don't copy.
 func (m *manager) GetMap(netID ids.ID) map[ids.NodeID]*GetValidatorOutput {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	if subnet, ok := m.validators[netID]; ok {
-		// Return a copy
-		result := make(map[ids.NodeID]*GetValidatorOutput, len(subnet))
-		for k, v := range subnet {
-			result[k] = v
-		}
-		return result
+	return shareValidators(m.snapshot(netID).validators)
+}
+
+// putValidator stores [val] as the record of its node in [netID], keeping the
+// membership and TxID indexes up to date. It assumes the lock is held.
+func (m *manager) putValidator(netID ids.ID, val *GetValidatorOutput) {
+	if m.validators[netID] == nil {
+		m.validators[netID] = make(map[ids.NodeID]*GetValidatorOutput)
+	}
+	key := validatorKey{netID: netID, nodeID: val.NodeID}
+	old, ok := m.validators[netID][val.NodeID]
+	if ok {
+		m.unindexTxID(old.TxID, key)
+	}
+	m.validators[netID][val.NodeID] = val
+	m.bumpSequence(netID, val)
+	m.indexTxID(val.TxID, key)
+
+	if m.memberships[val.NodeID] == nil {
+		m.memberships[val.NodeID] = make(map[ids.ID]struct{})
+	}
+	m.memberships[val.NodeID][netID] = struct{}{}
+}
+
+// bumpSequence records a change to [val] in [netID]. It assumes the lock is
+// held.
+func (m *manager) bumpSequence(netID ids.ID, val *GetValidatorOutput) {
+	m.snapshots.invalidate(netID)
+	m.markDirty(netID, val.NodeID)
+	m.sequence++
+	val.Sequence = m.sequence
+}
+
+// deleteValidator removes the record of [nodeID] in [netID], keeping the
+// membership and TxID indexes up to date. It assumes the lock is held.
+func (m *manager) deleteValidator(netID ids.ID, nodeID ids.NodeID) {
+	m.snapshots.invalidate(netID)
+	m.markDirty(netID, nodeID)
+	if val, ok := m.validators[netID][nodeID]; ok {
+		m.unindexTxID(val.TxID, validatorKey{netID: netID, nodeID: nodeID})
+	}
+	delete(m.validators[netID], nodeID)
+	if len(m.validators[netID]) == 0 {
+		delete(m.validators, netID)
+	}
+
+	delete(m.memberships[nodeID], netID)
+	if len(m.memberships[nodeID]) == 0 {
+		delete(m.memberships, nodeID)
+	}
+}
+
+// evictValidator removes [nodeID] from [netID] along with everything tracked
+// for it. It assumes the lock is held and doesn't notify listeners.
+func (m *manager) evictValidator(netID ids.ID, nodeID ids.NodeID) {
+	key := validatorKey{netID: netID, nodeID: nodeID}
+	m.deleteValidator(netID, nodeID)
+	m.removeDelegations(netID, nodeID)
+	m.removeAssets(key)
+	delete(m.balances, key)
+	delete(m.bigWeights, key)
+}
+
+// copyValidators returns a deep copy of [validators] so callers can read it
+// without holding the manager lock
+func copyValidators(validators map[ids.NodeID]*GetValidatorOutput) map[ids.NodeID]*GetValidatorOutput {
+	result := make(map[ids.NodeID]*GetValidatorOutput, len(validators))
+	for nodeID, val := range validators {
+		result[nodeID] = copyValidator(val)
 	}
-	return make(map[ids.NodeID]*GetValidatorOutput)
+	return result
+}
+
+// copyValidator returns a copy of [val] that shares none of its mutable
+// fields
+func copyValidator(val *GetValidatorOutput) *GetValidatorOutput {
+	valCopy := *val
+	valCopy.Metadata = val.Metadata.Clone()
+	valCopy.Extensions = cloneExtensions(val.Extensions)
+	return &valCopy
 }
 
 // RegisterCallbackListener registers a callback listener
@@ -291,7 +700,7 @@ func (m *manager) RegisterCallbackListener(listener ManagerCallbackListener) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.listeners = append(m.listeners, listener)
+	m.listeners.add(listener)
 
 	// Notify listener of all existing validators
 	for netID, validators := range m.validators {
@@ -301,7 +710,32 @@ func (m *manager) RegisterCallbackListener(listener ManagerCallbackListener) {
 	}
 }
 
-// RegisterSetCallbackListener registers a set callback listener (no-op for now)
+// UnregisterCallbackListener removes a callback listener. It doesn't take
+// the manager lock, so it may be called from within a callback; a
+// notification already in progress may still reach the listener.
+func (m *manager) UnregisterCallbackListener(listener ManagerCallbackListener) bool {
+	return m.listeners.remove(listener)
+}
+
+// UnregisterSetCallbackListener removes a callback listener of a single net,
+// like UnregisterCallbackListener
+func (m *manager) UnregisterSetCallbackListener(netID ids.ID, listener SetCallbackListener) bool {
+	return m.listeners.removeFunc(func(registered ManagerCallbackListener) bool {
+		l, ok := registered.(*netListener)
+		return ok && l.netID == netID && sameListener(l.listener, listener)
+	})
+}
+
+// RegisterSetCallbackListener registers a callback listener for the
+// validators of a single net
 func (m *manager) RegisterSetCallbackListener(netID ids.ID, listener SetCallbackListener) {
-	// No-op for now - can be implemented later if needed
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.listeners.add(&netListener{netID: netID, listener: listener})
+
+	// Notify listener of the net's existing validators
+	for nodeID, val := range m.validators[netID] {
+		listener.OnValidatorAdded(nodeID, val.Light)
+	}
 }
@@ -4,6 +4,7 @@
 package validators
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/luxfi/ids"
@@ -123,6 +124,39 @@ func TestManagerAddWeightNonExistent(t *testing.T) {
 	require.NoError(err)
 }
 
+// TestManagerWeightChangeListener tests that listeners are notified of weight changes
+func TestManagerWeightChangeListener(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+
+	listener := &testListener{}
+	m.RegisterCallbackListener(listener)
+
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 1000))
+	require.NoError(m.AddWeight(netID, nodeID, 500))
+	require.NoError(m.RemoveWeight(netID, nodeID, 300))
+	require.Equal([]lightChangedEvent{
+		{netID, nodeID, 1000, 1500},
+		{netID, nodeID, 1500, 1200},
+	}, listener.changed)
+	require.Empty(listener.removed)
+
+	// Removing the remaining weight removes the validator
+	require.NoError(m.RemoveWeight(netID, nodeID, 1200))
+	require.Equal([]validatorEvent{{netID, nodeID, 1200}}, listener.removed)
+	require.Len(listener.changed, 2)
+
+	// Unknown validators don't produce events
+	require.NoError(m.AddWeight(netID, nodeID, 100))
+	require.NoError(m.RemoveWeight(netID, nodeID, 100))
+	require.Len(listener.changed, 2)
+	require.Len(listener.removed, 1)
+	require.Equal(0, m.NumNets())
+}
+
 // TestManagerRemoveWeight tests removing weight from validators
 func TestManagerRemoveWeight(t *testing.T) {
 	require := require.New(t)
@@ -229,6 +263,40 @@ func TestManagerRemoveWeightKeepsOtherValidators(t *testing.T) {
 	require.Equal(1, m.NumNets())
 }
 
+// TestManagerRemoveStaker tests removing a validator with delegated weight
+func TestManagerRemoveStaker(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	listener := &testListener{}
+	m.RegisterCallbackListener(listener)
+
+	netID := ids.GenerateTestID()
+	nodeID1 := ids.GenerateTestNodeID()
+	nodeID2 := ids.GenerateTestNodeID()
+	delegatorID := ids.GenerateTestShortID()
+
+	require.NoError(m.AddStaker(netID, nodeID1, nil, ids.Empty, 100))
+	require.NoError(m.AddDelegator(netID, nodeID1, delegatorID, 50))
+	require.NoError(m.AddStaker(netID, nodeID2, nil, ids.Empty, 200))
+
+	require.NoError(m.RemoveStaker(netID, nodeID1))
+	_, ok := m.GetValidator(netID, nodeID1)
+	require.False(ok)
+	require.Empty(m.GetDelegatorPositions(delegatorID))
+	require.Equal([]validatorEvent{{netID, nodeID1, 150}}, listener.removed)
+
+	// Removing an unknown validator is a no-op
+	require.NoError(m.RemoveStaker(netID, nodeID1))
+	require.NoError(m.RemoveStaker(ids.GenerateTestID(), nodeID1))
+	require.Len(listener.removed, 1)
+
+	// Removing the last validator prunes the net
+	require.NoError(m.RemoveStaker(netID, nodeID2))
+	require.Zero(m.NumNets())
+	require.Empty(m.GetMemberships(nodeID2))
+}
+
 // TestManagerNumNets tests network counting
 func TestManagerNumNets(t *testing.T) {
 	require := require.New(t)
@@ -247,6 +315,32 @@ func TestManagerNumNets(t *testing.T) {
 	require.Equal(2, m.NumNets())
 }
 
+// TestManagerGetNetIDs tests enumerating networks and counting validators
+// across them
+func TestManagerGetNetIDs(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	require.Empty(m.GetNetIDs())
+	require.Zero(m.TotalCount())
+
+	netID1, netID2 := ids.GenerateTestID(), ids.GenerateTestID()
+	nodeID1, nodeID2 := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID1, nodeID1, nil, ids.Empty, 100))
+	require.NoError(m.AddStaker(netID1, nodeID2, nil, ids.Empty, 100))
+	require.NoError(m.AddStaker(netID2, nodeID1, nil, ids.Empty, 100))
+
+	expected := []ids.ID{netID1, netID2}
+	slices.SortFunc(expected, ids.ID.Compare)
+	require.Equal(expected, m.GetNetIDs())
+	require.Equal(3, m.TotalCount())
+
+	// Nets without validators aren't tracked
+	require.NoError(m.RemoveStaker(netID2, nodeID1))
+	require.Equal([]ids.ID{netID1}, m.GetNetIDs())
+	require.Equal(2, m.TotalCount())
+}
+
 // TestManagerGetValidators tests getting validator set
 func TestManagerGetValidators(t *testing.T) {
 	require := require.New(t)
@@ -537,6 +631,30 @@ func TestManagerGetMap(t *testing.T) {
 	require.Len(vmap2, 1)
 }
 
+// TestManagerReadsReturnCopies tests that returned records don't alias manager state
+func TestManagerReadsReturnCopies(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+
+	set, err := m.GetValidators(netID)
+	require.NoError(err)
+	vmap := m.GetMap(netID)
+	val, ok := m.GetValidator(netID, nodeID)
+	require.True(ok)
+
+	require.NoError(m.AddWeight(netID, nodeID, 50))
+	require.Equal(uint64(100), set.Light())
+	require.Equal(uint64(100), vmap[nodeID].Light)
+	require.Equal(uint64(100), val.Light)
+
+	val.Light = 1
+	require.Equal(uint64(150), m.GetLight(netID, nodeID))
+}
+
 // TestManagerRegisterCallbackListener tests callback registration
 func TestManagerRegisterCallbackListener(t *testing.T) {
 	require := require.New(t)
@@ -565,14 +683,77 @@ func TestManagerRegisterCallbackListener(t *testing.T) {
 	require.Len(listener.added, 2)
 }
 
-// TestManagerRegisterSetCallbackListener tests set callback (no-op)
+// TestManagerUnregisterCallbackListener tests detaching listeners, including
+// from within a callback
+func TestManagerUnregisterCallbackListener(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+
+	listener := &testListener{}
+	m.RegisterCallbackListener(listener)
+	once := &unregisteringListener{m: m}
+	m.RegisterCallbackListener(once)
+
+	require.NoError(m.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 100))
+	require.True(once.unregistered)
+	require.Len(listener.added, 1)
+
+	require.True(m.UnregisterCallbackListener(listener))
+	require.False(m.UnregisterCallbackListener(listener))
+	require.NoError(m.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 100))
+	require.Len(listener.added, 1)
+	require.Equal(1, once.calls)
+
+	// Listeners that can't be compared are never matched, rather than
+	// panicking
+	uncomparable := funcListener(func(ids.ID, ids.NodeID, uint64) {})
+	m.RegisterCallbackListener(uncomparable)
+	require.False(m.UnregisterCallbackListener(uncomparable))
+	require.False(m.UnregisterCallbackListener(listener))
+}
+
+// TestManagerRegisterSetCallbackListener tests that set listeners only see
+// their net
 func TestManagerRegisterSetCallbackListener(t *testing.T) {
+	require := require.New(t)
+
 	m := NewManager()
 	netID := ids.GenerateTestID()
+	otherNetID := ids.GenerateTestID()
+	nodeID1 := ids.GenerateTestNodeID()
+	nodeID2 := ids.GenerateTestNodeID()
+
+	require.NoError(m.AddStaker(netID, nodeID1, nil, ids.Empty, 100))
+	require.NoError(m.AddStaker(otherNetID, nodeID1, nil, ids.Empty, 100))
 
-	// This is a no-op but should not panic
+	// Existing validators of the net are replayed
 	listener := &testSetListener{}
 	m.RegisterSetCallbackListener(netID, listener)
+	require.Equal([]ids.NodeID{nodeID1}, listener.added)
+
+	require.NoError(m.AddStaker(netID, nodeID2, nil, ids.Empty, 100))
+	require.NoError(m.AddWeight(netID, nodeID1, 50))
+	require.NoError(m.RemoveStaker(netID, nodeID2))
+	require.Equal([]ids.NodeID{nodeID1, nodeID2}, listener.added)
+	require.Equal([]ids.NodeID{nodeID1}, listener.changed)
+	require.Equal([]ids.NodeID{nodeID2}, listener.removed)
+
+	// Other nets aren't reported
+	require.NoError(m.AddStaker(otherNetID, nodeID2, nil, ids.Empty, 100))
+	require.NoError(m.AddWeight(otherNetID, nodeID1, 50))
+	require.NoError(m.RemoveStaker(otherNetID, nodeID1))
+	require.Len(listener.added, 2)
+	require.Len(listener.changed, 1)
+	require.Len(listener.removed, 1)
+
+	// Set listeners are unregistered by their net
+	require.False(m.UnregisterSetCallbackListener(otherNetID, listener))
+	require.True(m.UnregisterSetCallbackListener(netID, listener))
+	require.False(m.UnregisterSetCallbackListener(netID, listener))
+	require.NoError(m.AddStaker(netID, nodeID2, nil, ids.Empty, 100))
+	require.Len(listener.added, 2)
 }
 
 // TestValidatorSetHas tests validatorSet.Has
@@ -707,17 +888,110 @@ func TestEmptySetSample(t *testing.T) {
 	require.Nil(sample)
 }
 
+// TestValidatorSequence tests that every change to a record increases its
+// sequence, including across removal and re-adding
+func TestValidatorSequence(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+
+	var last uint64
+	requireBumped := func() {
+		vdr, ok := m.GetValidator(netID, nodeID)
+		require.True(ok)
+		require.Greater(vdr.Sequence, last)
+		last = vdr.Sequence
+	}
+
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+	requireBumped()
+	require.NoError(m.AddWeight(netID, nodeID, 10))
+	requireBumped()
+	require.NoError(m.RemoveWeight(netID, nodeID, 10))
+	requireBumped()
+	require.NoError(m.AddLight(netID, nodeID, 5))
+	requireBumped()
+	require.NoError(m.SetEconomicWeight(netID, nodeID, 200))
+	requireBumped()
+	require.NoError(m.SetMetadata(netID, nodeID, &ValidatorMetadata{Moniker: "lux"}))
+	requireBumped()
+	m.DeleteMetadata(netID, nodeID)
+	requireBumped()
+
+	// Reads don't change the sequence
+	vdr, _ := m.GetValidator(netID, nodeID)
+	require.Equal(last, vdr.Sequence)
+
+	require.NoError(m.RemoveWeight(netID, nodeID, 200))
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+	requireBumped()
+}
+
 // Test helpers
 
+// unregisteringListener unregisters itself on its first notification
+type unregisteringListener struct {
+	m            *manager
+	calls        int
+	unregistered bool
+}
+
+func (l *unregisteringListener) OnValidatorAdded(ids.ID, ids.NodeID, uint64) {
+	l.calls++
+	l.unregistered = l.m.UnregisterCallbackListener(l)
+}
+
+func (*unregisteringListener) OnValidatorRemoved(ids.ID, ids.NodeID, uint64) {}
+
+func (*unregisteringListener) OnValidatorLightChanged(ids.ID, ids.NodeID, uint64, uint64) {}
+
 type validatorEvent struct {
 	netID  ids.ID
 	nodeID ids.NodeID
 	light  uint64
 }
 
+type lightChangedEvent struct {
+	netID    ids.ID
+	nodeID   ids.NodeID
+	oldLight uint64
+	newLight uint64
+}
+
+// TestManagerSetWeight tests absolute weight updates and their events
+func TestManagerSetWeight(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.SetWeight(netID, nodeID, 100)) // Missing validators are ignored
+
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+	require.NoError(m.AddDelegator(netID, nodeID, ids.GenerateTestShortID(), 50))
+	listener := &testListener{}
+	m.RegisterCallbackListener(listener)
+
+	require.NoError(m.SetWeight(netID, nodeID, 400))
+	require.Equal(uint64(400), m.GetLight(netID, nodeID))
+	require.Equal(uint64(350), m.GetSelfStake(netID, nodeID))
+	require.Equal([]lightChangedEvent{{netID, nodeID, 150, 400}}, listener.changed)
+
+	require.ErrorIs(m.SetWeight(netID, nodeID, 49), ErrWeightBelowDelegated)
+	require.Equal(uint64(400), m.GetLight(netID, nodeID))
+
+	require.NoError(m.SetWeight(netID, nodeID, 0))
+	_, ok := m.GetValidator(netID, nodeID)
+	require.False(ok)
+	require.Equal([]validatorEvent{{netID, nodeID, 400}}, listener.removed)
+}
+
 type testListener struct {
 	added   []validatorEvent
 	removed []validatorEvent
+	changed []lightChangedEvent
 }
 
 func (l *testListener) OnValidatorAdded(netID ids.ID, nodeID ids.NodeID, light uint64) {
@@ -729,11 +1003,34 @@ func (l *testListener) OnValidatorRemoved(netID ids.ID, nodeID ids.NodeID, light
 }
 
 func (l *testListener) OnValidatorLightChanged(netID ids.ID, nodeID ids.NodeID, oldLight, newLight uint64) {
-	// Not implemented in manager yet
+	l.changed = append(l.changed, lightChangedEvent{netID, nodeID, oldLight, newLight})
 }
 
-type testSetListener struct{}
+// funcListener is a listener that can't be compared
+type funcListener func(netID ids.ID, nodeID ids.NodeID, light uint64)
+
+func (f funcListener) OnValidatorAdded(netID ids.ID, nodeID ids.NodeID, light uint64) {
+	f(netID, nodeID, light)
+}
 
-func (l *testSetListener) OnValidatorAdded(nodeID ids.NodeID, light uint64)                     {}
-func (l *testSetListener) OnValidatorRemoved(nodeID ids.NodeID, light uint64)                   {}
-func (l *testSetListener) OnValidatorLightChanged(nodeID ids.NodeID, oldLight, newLight uint64) {}
+func (funcListener) OnValidatorRemoved(ids.ID, ids.NodeID, uint64) {}
+
+func (funcListener) OnValidatorLightChanged(ids.ID, ids.NodeID, uint64, uint64) {}
+
+type testSetListener struct {
+	added   []ids.NodeID
+	removed []ids.NodeID
+	changed []ids.NodeID
+}
+
+func (l *testSetListener) OnValidatorAdded(nodeID ids.NodeID, light uint64) {
+	l.added = append(l.added, nodeID)
+}
+
+func (l *testSetListener) OnValidatorRemoved(nodeID ids.NodeID, light uint64) {
+	l.removed = append(l.removed, nodeID)
+}
+
+func (l *testSetListener) OnValidatorLightChanged(nodeID ids.NodeID, oldLight, newLight uint64) {
+	l.changed = append(l.changed, nodeID)
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/bits"
+	"slices"
+
+	"github.com/luxfi/math"
+)
+
+var ErrNoWeight = errors.New("weights sum to zero")
+
+// NormalizeWeights scales [weights] so they sum to exactly [total], for
+// exporting a set to systems with narrower weight types, such as uint32
+// contract storage.
+//
+// Every weight is first rounded down to its exact share of [total]. The units
+// lost to rounding go one each to the weights with the largest remainders,
+// and equal remainders go to the lower index, so the result only depends on
+// the input order. A zero weight always normalizes to zero.
+func NormalizeWeights(weights []uint64, total uint64) ([]uint64, error) {
+	var (
+		sum uint64
+		err error
+	)
+	for _, weight := range weights {
+		sum, err = math.Add64(sum, weight)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+		}
+	}
+	if sum == 0 {
+		return nil, ErrNoWeight
+	}
+
+	var (
+		normalized = make([]uint64, len(weights))
+		remainders = make([]uint64, len(weights))
+		assigned   uint64
+	)
+	for i, weight := range weights {
+		// weight <= sum, so the quotient fits in 64 bits
+		hi, lo := bits.Mul64(weight, total)
+		normalized[i], remainders[i] = bits.Div64(hi, lo, sum)
+		assigned += normalized[i]
+	}
+
+	// Each weight loses less than one unit, so fewer than len(weights) units
+	// are left over
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(remainders[b], remainders[a])
+	})
+	for _, i := range order[:total-assigned] {
+		normalized[i]++
+	}
+	return normalized, nil
+}
+
+// Normalize returns the weights of the validators, in canonical order, scaled
+// to sum to exactly [total]. See NormalizeWeights.
+func (s *CanonicalValidatorSet) Normalize(total uint64) ([]uint64, error) {
+	weights := make([]uint64, len(s.Validators))
+	for i, vdr := range s.Validators {
+		weights[i] = vdr.Weight
+	}
+	return NormalizeWeights(weights, total)
+}
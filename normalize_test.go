@@ -0,0 +1,89 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestNormalizeWeights tests largest remainder rounding
+func TestNormalizeWeights(t *testing.T) {
+	tests := []struct {
+		name     string
+		weights  []uint64
+		total    uint64
+		expected []uint64
+	}{
+		{
+			name:     "exact",
+			weights:  []uint64{1, 3},
+			total:    100,
+			expected: []uint64{25, 75},
+		},
+		{
+			name:     "equal remainders go to the lower index",
+			weights:  []uint64{1, 1, 1},
+			total:    100,
+			expected: []uint64{34, 33, 33},
+		},
+		{
+			name:     "largest remainder",
+			weights:  []uint64{1, 2, 4},
+			total:    10,
+			expected: []uint64{1, 3, 6},
+		},
+		{
+			name:     "zero weight stays zero",
+			weights:  []uint64{0, 1, 1, 1},
+			total:    2,
+			expected: []uint64{0, 1, 1, 0},
+		},
+		{
+			name:     "wide weights to uint32",
+			weights:  []uint64{math.MaxUint64 / 2, math.MaxUint64 / 2},
+			total:    math.MaxUint32,
+			expected: []uint64{math.MaxUint32/2 + 1, math.MaxUint32 / 2},
+		},
+		{
+			name:     "1e18",
+			weights:  []uint64{2, 1},
+			total:    1e18,
+			expected: []uint64{666_666_666_666_666_667, 333_333_333_333_333_333},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			normalized, err := NormalizeWeights(test.weights, test.total)
+			require.NoError(err)
+			require.Equal(test.expected, normalized)
+		})
+	}
+
+	_, err := NormalizeWeights([]uint64{0}, 100)
+	require.ErrorIs(t, err, ErrNoWeight)
+	_, err = NormalizeWeights([]uint64{math.MaxUint64, 1}, 100)
+	require.ErrorIs(t, err, ErrWeightOverflow)
+}
+
+// TestCanonicalValidatorSetNormalize tests normalizing in canonical order
+func TestCanonicalValidatorSetNormalize(t *testing.T) {
+	require := require.New(t)
+
+	vdrSet := CanonicalValidatorSet{
+		Validators: []*CanonicalValidator{
+			{Weight: 70},
+			{Weight: 20},
+			{Weight: 10},
+		},
+		TotalWeight: 100,
+	}
+	normalized, err := vdrSet.Normalize(7)
+	require.NoError(err)
+	require.Equal([]uint64{5, 1, 1}, normalized)
+}
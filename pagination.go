@@ -0,0 +1,117 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/luxfi/ids"
+)
+
+var ErrInvalidPageLimit = errors.New("page limit must be positive")
+
+// PageManager lists the validators of large nets in chunks, so RPC layers can
+// stream them without allocating the whole list at once.
+//
+// Pages are ordered by node ID and the cursor is a node ID rather than an
+// offset, so a listing neither skips nor repeats validators that are present
+// throughout it, even if other validators are added or removed in between.
+// Each page reports the ETag of the set it was cut from, so explorers can
+// tell when the set changed under a listing and revalidate cached pages.
+type PageManager interface {
+	// GetValidatorIDsPage returns up to [limit] validators of [netID]
+	// starting at [cursor]. Listings start at ids.EmptyNodeID.
+	GetValidatorIDsPage(netID ids.ID, cursor ids.NodeID, limit int) (IDPage, error)
+}
+
+var _ PageManager = (*manager)(nil)
+
+// IDPage is a page of validator IDs
+type IDPage struct {
+	NodeIDs []ids.NodeID
+	// Next is the cursor of the following page. It is only set if More is.
+	Next ids.NodeID
+	More bool
+	// Total is the number of validators in the net
+	Total int
+	// CumulativeLight is the light of the validators up to the end of the
+	// page, in node ID order
+	CumulativeLight uint64
+	// ETag identifies the set the page was cut from, see
+	// ValidatorSnapshot.ETag
+	ETag string
+}
+
+// GetValidatorIDsPage returns a page of the validators of a net
+func (m *manager) GetValidatorIDsPage(netID ids.ID, cursor ids.NodeID, limit int) (IDPage, error) {
+	if limit <= 0 {
+		return IDPage{}, fmt.Errorf("%w: %d", ErrInvalidPageLimit, limit)
+	}
+
+	snapshot := m.Snapshot(netID)
+	if _, err := snapshot.TotalLight(); err != nil {
+		return IDPage{}, err
+	}
+	nodeIDs := snapshot.NodeIDs()
+	start, _ := slices.BinarySearchFunc(nodeIDs, cursor, func(a, b ids.NodeID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	end := min(start+limit, len(nodeIDs))
+
+	page := IDPage{
+		NodeIDs: slices.Clone(nodeIDs[start:end]),
+		Total:   len(nodeIDs),
+		ETag:    snapshot.ETag(),
+	}
+	if end > 0 {
+		page.CumulativeLight = snapshot.cumulativeLight()[end-1]
+	}
+	if end < len(nodeIDs) {
+		page.Next = nodeIDs[end]
+		page.More = true
+	}
+	return page, nil
+}
+
+// ETag returns a strong HTTP entity tag of the snapshot: the quoted hex of
+// the SHA-256 of every validator's node ID, light, weight and public key
+// bytes, each key prefixed by its length, in node ID order. Keys are hashed
+// as stored rather than parsed, so the tag never fails. It is computed once
+// per snapshot.
+func (s *ValidatorSnapshot) ETag() string {
+	s.etagOnce.Do(func() {
+		hasher := sha256.New()
+		for _, nodeID := range s.nodeIDs {
+			vdr := s.validators[nodeID]
+			_, _ = hasher.Write(nodeID[:])
+			_, _ = hasher.Write(binary.BigEndian.AppendUint64(nil, vdr.Light))
+			_, _ = hasher.Write(binary.BigEndian.AppendUint64(nil, vdr.Weight))
+			_, _ = hasher.Write(binary.BigEndian.AppendUint32(nil, uint32(len(vdr.PublicKey))))
+			_, _ = hasher.Write(vdr.PublicKey)
+		}
+		s.etag = `"` + hex.EncodeToString(hasher.Sum(nil)) + `"`
+	})
+	return s.etag
+}
+
+// cumulativeLight returns the light of the validators up to and including
+// each index of NodeIDs. It is computed once per snapshot.
+func (s *ValidatorSnapshot) cumulativeLight() []uint64 {
+	s.cumulativeOnce.Do(func() {
+		s.cumulative = make([]uint64, len(s.nodeIDs))
+		var light uint64
+		for i, nodeID := range s.nodeIDs {
+			// Can't overflow, since GetValidatorIDsPage checked the total
+			light += s.validators[nodeID].Light
+			s.cumulative[i] = light
+		}
+	})
+	return s.cumulative
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"math"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// TestManagerGetValidatorIDsPage tests that paging visits every validator
+// once, in node ID order
+func TestManagerGetValidatorIDsPage(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	for range 10 {
+		require.NoError(m.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 1))
+	}
+
+	_, err := m.GetValidatorIDsPage(netID, ids.EmptyNodeID, 0)
+	require.ErrorIs(err, ErrInvalidPageLimit)
+
+	var (
+		listed []ids.NodeID
+		cursor = ids.EmptyNodeID
+		pages  int
+	)
+	for {
+		page, err := m.GetValidatorIDsPage(netID, cursor, 3)
+		require.NoError(err)
+		listed = append(listed, page.NodeIDs...)
+		pages++
+		if !page.More {
+			break
+		}
+		cursor = page.Next
+	}
+	require.Equal(4, pages)
+	require.Equal(sortNodeIDs(m.GetValidatorIDs(netID)), listed)
+
+	page, err := m.GetValidatorIDsPage(ids.GenerateTestID(), ids.EmptyNodeID, 3)
+	require.NoError(err)
+	require.Empty(page.NodeIDs)
+	require.False(page.More)
+}
+
+// TestManagerGetValidatorIDsPageStable tests that changes between pages
+// don't make a listing skip or repeat validators present throughout it
+func TestManagerGetValidatorIDsPageStable(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	for range 6 {
+		require.NoError(m.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 1))
+	}
+	sorted := sortNodeIDs(m.GetValidatorIDs(netID))
+
+	first, err := m.GetValidatorIDsPage(netID, ids.EmptyNodeID, 3)
+	require.NoError(err)
+	require.Equal(sorted[:3], first.NodeIDs)
+
+	// Remove a listed validator and the first validator of the next page
+	require.NoError(m.RemoveStaker(netID, sorted[0]))
+	require.NoError(m.RemoveStaker(netID, sorted[3]))
+
+	second, err := m.GetValidatorIDsPage(netID, first.Next, 3)
+	require.NoError(err)
+	require.Equal(sorted[4:], second.NodeIDs)
+	require.False(second.More)
+}
+
+// TestManagerGetValidatorIDsPageMetadata tests that pages report the size
+// and light of the set, and an ETag that changes with it
+func TestManagerGetValidatorIDsPageMetadata(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	for i := range 5 {
+		require.NoError(m.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, uint64(i+1)))
+	}
+	sorted := sortNodeIDs(m.GetValidatorIDs(netID))
+
+	first, err := m.GetValidatorIDsPage(netID, ids.EmptyNodeID, 2)
+	require.NoError(err)
+	require.Equal(5, first.Total)
+	require.Equal(m.GetLight(netID, sorted[0])+m.GetLight(netID, sorted[1]), first.CumulativeLight)
+	require.NotEmpty(first.ETag)
+
+	second, err := m.GetValidatorIDsPage(netID, first.Next, 10)
+	require.NoError(err)
+	require.Equal(uint64(15), second.CumulativeLight)
+	require.Equal(first.ETag, second.ETag)
+
+	// Validators without keys still change the ETag
+	require.NoError(m.SetWeight(netID, sorted[0], 100))
+	changed, err := m.GetValidatorIDsPage(netID, ids.EmptyNodeID, 2)
+	require.NoError(err)
+	require.NotEqual(first.ETag, changed.ETag)
+
+	// So do economic weights, even if their total overflows
+	require.NoError(m.SetEconomicWeight(netID, sorted[1], math.MaxUint64))
+	reweighed, err := m.GetValidatorIDsPage(netID, ids.EmptyNodeID, 2)
+	require.NoError(err)
+	require.NotEqual(changed.ETag, reweighed.ETag)
+
+	empty, err := m.GetValidatorIDsPage(ids.GenerateTestID(), ids.EmptyNodeID, 2)
+	require.NoError(err)
+	require.Zero(empty.Total)
+	require.Zero(empty.CumulativeLight)
+}
@@ -0,0 +1,315 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package pchain imports validator data exported from an Avalanche P-Chain,
+// either from a platform.getCurrentValidators response or from a genesis
+// config, to ease migrating existing subnets.
+package pchain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/luxfi/crypto/address"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math"
+
+	validators "github.com/luxfi/validators"
+)
+
+var (
+	ErrMissingValidators = errors.New("no validators found")
+	ErrNoStakedFunds     = errors.New("genesis has stakers but no staked funds")
+	errInvalidUint64     = errors.New("invalid uint64")
+)
+
+// Validator is a validator in the P-Chain export formats
+type Validator struct {
+	TxID       ids.ID
+	NodeID     ids.NodeID
+	Weight     uint64
+	PublicKey  []byte // Compressed BLS public key, if the validator has one
+	Delegators []Delegator
+}
+
+// Delegator is a delegation in the P-Chain export formats
+type Delegator struct {
+	TxID ids.ID
+	// DelegatorID is the first reward owner address, or derived from TxID if
+	// the export doesn't include reward owners
+	DelegatorID ids.ShortID
+	Weight      uint64
+}
+
+// jsonUint64 is a uint64 the P-Chain APIs encode as a string
+type jsonUint64 uint64
+
+func (u *jsonUint64) UnmarshalJSON(b []byte) error {
+	str := strings.Trim(string(b), `"`)
+	if str == "" || str == "null" {
+		*u = 0
+		return nil
+	}
+	v, err := strconv.ParseUint(str, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w %q: %w", errInvalidUint64, str, err)
+	}
+	*u = jsonUint64(v)
+	return nil
+}
+
+type jsonSigner struct {
+	PublicKey string `json:"publicKey"`
+}
+
+type jsonOwner struct {
+	Addresses []string `json:"addresses"`
+}
+
+type jsonDelegator struct {
+	TxID        string     `json:"txID"`
+	NodeID      string     `json:"nodeID"`
+	Weight      jsonUint64 `json:"weight"`
+	StakeAmount jsonUint64 `json:"stakeAmount"`
+	RewardOwner *jsonOwner `json:"rewardOwner"`
+}
+
+type jsonValidator struct {
+	TxID        string          `json:"txID"`
+	NodeID      string          `json:"nodeID"`
+	Weight      jsonUint64      `json:"weight"`
+	StakeAmount jsonUint64      `json:"stakeAmount"`
+	Signer      *jsonSigner     `json:"signer"`
+	Delegators  []jsonDelegator `json:"delegators"`
+}
+
+type jsonCurrentValidators struct {
+	Validators []jsonValidator `json:"validators"`
+	// Result is set when the input is a full JSON-RPC response
+	Result *struct {
+		Validators []jsonValidator `json:"validators"`
+	} `json:"result"`
+}
+
+// ParseCurrentValidators parses a platform.getCurrentValidators reply. Both
+// the bare reply and the full JSON-RPC response are accepted, as are the
+// older stakeAmount and newer weight fields.
+func ParseCurrentValidators(r io.Reader) ([]Validator, error) {
+	var reply jsonCurrentValidators
+	if err := json.NewDecoder(r).Decode(&reply); err != nil {
+		return nil, fmt.Errorf("failed to decode current validators: %w", err)
+	}
+	jsonVdrs := reply.Validators
+	if reply.Result != nil {
+		jsonVdrs = reply.Result.Validators
+	}
+	if jsonVdrs == nil {
+		return nil, ErrMissingValidators
+	}
+
+	vdrs := make([]Validator, 0, len(jsonVdrs))
+	for _, jsonVdr := range jsonVdrs {
+		vdr, err := parseValidator(jsonVdr)
+		if err != nil {
+			return nil, err
+		}
+		vdrs = append(vdrs, vdr)
+	}
+	return vdrs, nil
+}
+
+func parseValidator(jsonVdr jsonValidator) (Validator, error) {
+	nodeID, err := ids.NodeIDFromString(jsonVdr.NodeID)
+	if err != nil {
+		return Validator{}, fmt.Errorf("invalid nodeID %q: %w", jsonVdr.NodeID, err)
+	}
+	vdr := Validator{
+		NodeID: nodeID,
+		Weight: uint64(max(jsonVdr.Weight, jsonVdr.StakeAmount)),
+	}
+	if jsonVdr.TxID != "" {
+		if vdr.TxID, err = ids.FromString(jsonVdr.TxID); err != nil {
+			return Validator{}, fmt.Errorf("invalid txID of %s: %w", nodeID, err)
+		}
+	}
+	if jsonVdr.Signer != nil {
+		if vdr.PublicKey, err = parseHex(jsonVdr.Signer.PublicKey); err != nil {
+			return Validator{}, fmt.Errorf("invalid public key of %s: %w", nodeID, err)
+		}
+	}
+
+	for _, jsonDel := range jsonVdr.Delegators {
+		del := Delegator{
+			Weight: uint64(max(jsonDel.Weight, jsonDel.StakeAmount)),
+		}
+		if del.TxID, err = ids.FromString(jsonDel.TxID); err != nil {
+			return Validator{}, fmt.Errorf("invalid delegation txID of %s: %w", nodeID, err)
+		}
+		if jsonDel.RewardOwner != nil && len(jsonDel.RewardOwner.Addresses) > 0 {
+			if del.DelegatorID, err = address.ParseToID(jsonDel.RewardOwner.Addresses[0]); err != nil {
+				return Validator{}, fmt.Errorf("invalid reward owner of delegation %s: %w", del.TxID, err)
+			}
+		} else {
+			del.DelegatorID, _ = ids.ToShortID(del.TxID[:ids.ShortIDLen])
+		}
+		vdr.Delegators = append(vdr.Delegators, del)
+	}
+	return vdr, nil
+}
+
+func parseHex(str string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(str, "0x"))
+}
+
+type jsonUnlock struct {
+	Amount jsonUint64 `json:"amount"`
+}
+
+type jsonAllocation struct {
+	AvaxAddr       string       `json:"avaxAddr"`
+	UnlockSchedule []jsonUnlock `json:"unlockSchedule"`
+}
+
+type jsonStaker struct {
+	NodeID string      `json:"nodeID"`
+	Signer *jsonSigner `json:"signer"`
+}
+
+type jsonGenesis struct {
+	Allocations        []jsonAllocation `json:"allocations"`
+	InitialStakedFunds []string         `json:"initialStakedFunds"`
+	InitialStakers     []jsonStaker     `json:"initialStakers"`
+}
+
+// ParseGenesis parses the initial stakers of a genesis config. The unlock
+// schedules of the allocations listed in initialStakedFunds are pooled and
+// split evenly between the stakers, with any remainder given to the first
+// staker. Genesis validators have no txID.
+func ParseGenesis(r io.Reader) ([]Validator, error) {
+	var genesis jsonGenesis
+	if err := json.NewDecoder(r).Decode(&genesis); err != nil {
+		return nil, fmt.Errorf("failed to decode genesis: %w", err)
+	}
+	if len(genesis.InitialStakers) == 0 {
+		return nil, ErrMissingValidators
+	}
+
+	staked := make(map[string]struct{}, len(genesis.InitialStakedFunds))
+	for _, addr := range genesis.InitialStakedFunds {
+		staked[addr] = struct{}{}
+	}
+	var (
+		totalStake uint64
+		err        error
+	)
+	for _, allocation := range genesis.Allocations {
+		if _, ok := staked[allocation.AvaxAddr]; !ok {
+			continue
+		}
+		for _, unlock := range allocation.UnlockSchedule {
+			totalStake, err = math.Add64(totalStake, uint64(unlock.Amount))
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", validators.ErrWeightOverflow, err)
+			}
+		}
+	}
+	if totalStake == 0 {
+		return nil, ErrNoStakedFunds
+	}
+
+	numStakers := uint64(len(genesis.InitialStakers))
+	vdrs := make([]Validator, 0, numStakers)
+	for i, staker := range genesis.InitialStakers {
+		nodeID, err := ids.NodeIDFromString(staker.NodeID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid nodeID %q: %w", staker.NodeID, err)
+		}
+		vdr := Validator{
+			NodeID: nodeID,
+			Weight: totalStake / numStakers,
+		}
+		if i == 0 {
+			vdr.Weight += totalStake % numStakers
+		}
+		if staker.Signer != nil {
+			if vdr.PublicKey, err = parseHex(staker.Signer.PublicKey); err != nil {
+				return nil, fmt.Errorf("invalid public key of %s: %w", nodeID, err)
+			}
+		}
+		vdrs = append(vdrs, vdr)
+	}
+	return vdrs, nil
+}
+
+// Manager is the part of a validators manager Import needs
+type Manager interface {
+	validators.Manager
+	validators.TxManager
+}
+
+// Import adds [vdrs] to [netID] in [m] in a single transaction, so either
+// every validator is imported or none is. Delegators are imported if [m]
+// implements validators.DelegationManager and are otherwise added to their
+// validator's weight.
+func Import(m Manager, netID ids.ID, vdrs []Validator) error {
+	_, hasDelegations := m.(validators.DelegationManager)
+	err := m.WithTransaction(func(tx *validators.Tx) error {
+		for _, vdr := range vdrs {
+			weight := vdr.Weight
+			if !hasDelegations {
+				for _, del := range vdr.Delegators {
+					var err error
+					weight, err = math.Add64(weight, del.Weight)
+					if err != nil {
+						return fmt.Errorf("%w: %s: %w", validators.ErrWeightOverflow, vdr.NodeID, err)
+					}
+				}
+			}
+			if err := tx.AddStaker(netID, vdr.NodeID, vdr.PublicKey, vdr.TxID, weight); err != nil {
+				return err
+			}
+			if !hasDelegations {
+				continue
+			}
+			for _, del := range vdr.Delegators {
+				if err := tx.AddDelegator(netID, vdr.NodeID, del.DelegatorID, del.Weight); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to import validators: %w", err)
+	}
+	return nil
+}
+
+// Snapshot writes [vdrs] to [w] as the validators of [netID] in the dump
+// format of validators.DumpValidators
+func Snapshot(w io.Writer, netID ids.ID, vdrs []Validator) error {
+	m := validators.NewManager()
+	if err := Import(m, netID, vdrs); err != nil {
+		return err
+	}
+	return validators.DumpValidators(w, m, validators.DumpOptions{NetIDs: []ids.ID{netID}})
+}
+
+// Parse parses either supported format, treating any input with an
+// initialStakers field as a genesis config
+func Parse(r io.Reader) ([]Validator, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Contains(data, []byte(`"initialStakers"`)) {
+		return ParseGenesis(bytes.NewReader(data))
+	}
+	return ParseCurrentValidators(bytes.NewReader(data))
+}
@@ -0,0 +1,161 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pchain
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/luxfi/crypto/address"
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+	"github.com/luxfi/validators/validatorstest"
+)
+
+// TestParseCurrentValidators tests parsing a JSON-RPC getCurrentValidators
+// response
+func TestParseCurrentValidators(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		nodeID0     = ids.GenerateTestNodeID()
+		nodeID1     = ids.GenerateTestNodeID()
+		txID0       = ids.GenerateTestID()
+		txID1       = ids.GenerateTestID()
+		delTxID     = ids.GenerateTestID()
+		delegatorID = ids.GenerateTestShortID()
+	)
+	generated, err := validatorstest.GenerateValidatorSet(1, 1, validatorstest.ConstantWeights(1))
+	require.NoError(err)
+	pk := generated.Validators[0].PublicKeyBytes
+	owner, err := address.Format("P", "avax", delegatorID[:])
+	require.NoError(err)
+
+	reply := fmt.Sprintf(`{
+	"jsonrpc": "2.0",
+	"result": {
+		"validators": [
+			{
+				"txID": %q,
+				"nodeID": %q,
+				"weight": "2000",
+				"signer": {"publicKey": "0x%x", "proofOfPossession": "0x00"},
+				"delegators": [
+					{
+						"txID": %q,
+						"nodeID": %q,
+						"stakeAmount": "500",
+						"rewardOwner": {"locktime": "0", "threshold": "1", "addresses": [%q]}
+					}
+				]
+			},
+			{
+				"txID": %q,
+				"nodeID": %q,
+				"stakeAmount": "1000"
+			}
+		]
+	},
+	"id": 1
+}`, txID0, nodeID0, pk, delTxID, nodeID0, owner, txID1, nodeID1)
+
+	vdrs, err := Parse(strings.NewReader(reply))
+	require.NoError(err)
+	require.Equal([]Validator{
+		{
+			TxID:      txID0,
+			NodeID:    nodeID0,
+			Weight:    2000,
+			PublicKey: pk,
+			Delegators: []Delegator{{
+				TxID:        delTxID,
+				DelegatorID: delegatorID,
+				Weight:      500,
+			}},
+		},
+		{
+			TxID:   txID1,
+			NodeID: nodeID1,
+			Weight: 1000,
+		},
+	}, vdrs)
+
+	netID := ids.GenerateTestID()
+	m := validators.NewManager()
+	require.NoError(Import(m, netID, vdrs))
+	require.Equal(uint64(2500), m.GetLight(netID, nodeID0))
+	require.Equal(uint64(500), m.GetDelegatedWeight(netID, nodeID0))
+	require.Equal(uint64(1000), m.GetLight(netID, nodeID1))
+
+	var snapshot bytes.Buffer
+	require.NoError(Snapshot(&snapshot, netID, vdrs))
+	require.Contains(snapshot.String(), fmt.Sprintf("net %s validators=2 light=3500 weight=3500 ", netID))
+	require.Contains(snapshot.String(), fmt.Sprintf("\t%s light=2500 weight=2500 ", nodeID0))
+}
+
+// TestImportAtomic tests that a failed import leaves the manager unchanged
+func TestImportAtomic(t *testing.T) {
+	require := require.New(t)
+
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	vdrs := []Validator{
+		{NodeID: ids.GenerateTestNodeID(), Weight: 1},
+		{NodeID: nodeID, Weight: 1},
+		{NodeID: nodeID, Weight: 1},
+	}
+
+	m := validators.NewManager()
+	require.ErrorIs(Import(m, netID, vdrs), validators.ErrDuplicateValidator)
+	require.Zero(m.Count(netID))
+}
+
+// TestParseGenesis tests splitting genesis staked funds between stakers
+func TestParseGenesis(t *testing.T) {
+	require := require.New(t)
+
+	nodeID0 := ids.GenerateTestNodeID()
+	nodeID1 := ids.GenerateTestNodeID()
+	genesis := fmt.Sprintf(`{
+	"networkID": 12345,
+	"allocations": [
+		{"avaxAddr": "X-staked", "initialAmount": 0, "unlockSchedule": [{"amount": 600}, {"amount": 401, "locktime": 1}]},
+		{"avaxAddr": "X-liquid", "initialAmount": 1000, "unlockSchedule": [{"amount": 5000}]}
+	],
+	"initialStakedFunds": ["X-staked"],
+	"initialStakers": [
+		{"nodeID": %q, "rewardAddress": "X-staked", "delegationFee": 20000},
+		{"nodeID": %q, "rewardAddress": "X-staked", "delegationFee": 20000}
+	]
+}`, nodeID0, nodeID1)
+
+	vdrs, err := Parse(strings.NewReader(genesis))
+	require.NoError(err)
+	require.Equal([]Validator{
+		{NodeID: nodeID0, Weight: 501},
+		{NodeID: nodeID1, Weight: 500},
+	}, vdrs)
+}
+
+// TestParseErrors tests malformed exports
+func TestParseErrors(t *testing.T) {
+	require := require.New(t)
+
+	_, err := ParseCurrentValidators(strings.NewReader(`{}`))
+	require.ErrorIs(err, ErrMissingValidators)
+
+	_, err = ParseCurrentValidators(strings.NewReader(`{"validators": [{"nodeID": "bad"}]}`))
+	require.ErrorContains(err, "invalid nodeID")
+
+	nodeID := ids.GenerateTestNodeID()
+	_, err = ParseCurrentValidators(strings.NewReader(fmt.Sprintf(`{"validators": [{"nodeID": %q, "weight": "-1"}]}`, nodeID)))
+	require.ErrorIs(err, errInvalidUint64)
+
+	_, err = ParseGenesis(strings.NewReader(fmt.Sprintf(`{"initialStakers": [{"nodeID": %q}]}`, nodeID)))
+	require.ErrorIs(err, ErrNoStakedFunds)
+}
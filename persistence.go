@@ -0,0 +1,451 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math/set"
+)
+
+var ErrInvalidBatchSize = errors.New("batch size must be positive")
+
+// Store persists validator records by net
+type Store interface {
+	// NetIDs returns every net with persisted validators
+	NetIDs() ([]ids.ID, error)
+	// LoadNet returns the persisted validators of [netID]
+	LoadNet(netID ids.ID) ([]*GetValidatorOutput, error)
+	// WriteBatch atomically applies [writes]
+	WriteBatch(writes []StoreWrite) error
+}
+
+// StoreWrite is a change to a persisted validator record
+type StoreWrite struct {
+	NetID  ids.ID
+	NodeID ids.NodeID
+	// Validator is the new record, or nil if the validator was removed
+	Validator *GetValidatorOutput
+}
+
+// PersistenceConfig configures a PersistentManager
+type PersistenceConfig struct {
+	// BatchSize is the number of changed records that are buffered before
+	// they are written to the store
+	BatchSize int
+}
+
+// DefaultPersistenceConfig writes changes in batches of 256 records
+func DefaultPersistenceConfig() PersistenceConfig {
+	return PersistenceConfig{BatchSize: 256}
+}
+
+// PersistentManager is a Manager backed by a Store. A net is loaded from the
+// store the first time it is accessed, and changed records are written back
+// in batches.
+//
+// A net that fails to load stays unloaded and is retried on its next access.
+// Reads that can't return an error treat it as empty.
+//
+// A mutation that succeeds in memory succeeds even if writing its batch
+// fails, since it has been applied and notified and can't be retried. The
+// batch stays buffered, is retried by later mutations, and its error is
+// returned by Flush and Close.
+//
+// Only the validator records returned by GetValidator are persisted. The
+// manager doesn't expose delegations, asset stakes, balances, access lists,
+// freezes or height history, and they aren't restored on reload.
+type PersistentManager interface {
+	Manager
+	StakerManager
+	DiffManager
+	KeyRotationManager
+	NetManager
+	ViewManager
+	WarpSetManager
+	SnapshotManager
+
+	// Load loads [netID] if it isn't loaded yet
+	Load(netID ids.ID) error
+	// Flush writes every buffered change to the store. Changes that fail to
+	// be written stay buffered.
+	Flush() error
+	// Close closes the in-memory manager, see Closer, and then flushes, so
+	// every change accepted before Close reaches the store
+	Close(ctx context.Context) error
+}
+
+type persistentManager struct {
+	inner  *manager
+	store  Store
+	config PersistenceConfig
+
+	mu       sync.RWMutex
+	unloaded set.Set[ids.ID]
+	pending  map[validatorKey]*GetValidatorOutput
+}
+
+var (
+	_ PersistentManager = (*persistentManager)(nil)
+	_ ListenerManager   = (*persistentManager)(nil)
+)
+
+// NewPersistentManager returns a manager backed by [store]. Only the IDs of
+// the persisted nets are read up front.
+func NewPersistentManager(store Store, config PersistenceConfig) (PersistentManager, error) {
+	if config.BatchSize <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidBatchSize, config.BatchSize)
+	}
+	netIDs, err := store.NetIDs()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read persisted nets: %w", err)
+	}
+	return &persistentManager{
+		inner:    NewManager(),
+		store:    store,
+		config:   config,
+		unloaded: set.Of(netIDs...),
+		pending:  make(map[validatorKey]*GetValidatorOutput),
+	}, nil
+}
+
+func (p *persistentManager) Load(netID ids.ID) error {
+	// Loaded nets are read without excluding other readers
+	p.mu.RLock()
+	loaded := !p.unloaded.Contains(netID)
+	p.mu.RUnlock()
+	if loaded {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.load(netID)
+}
+
+// load loads [netID] into the in-memory manager. It assumes the lock is held.
+func (p *persistentManager) load(netID ids.ID) error {
+	if !p.unloaded.Contains(netID) {
+		return nil
+	}
+	vdrs, err := p.store.LoadNet(netID)
+	if err != nil {
+		return fmt.Errorf("couldn't load net %s: %w", netID, err)
+	}
+	p.inner.loadValidators(netID, vdrs)
+	p.unloaded.Remove(netID)
+	return nil
+}
+
+// read loads [netID] for a read that can't fail. A net that fails to load
+// reads as empty.
+func (p *persistentManager) read(netID ids.ID) {
+	_ = p.Load(netID)
+}
+
+// mutate loads [netID], applies [f] and buffers the resulting record of
+// [nodeID]
+func (p *persistentManager) mutate(netID ids.ID, nodeID ids.NodeID, f func() error) error {
+	return p.mutateAll(netID, []ids.NodeID{nodeID}, f)
+}
+
+// mutateAll loads [netID], applies [f] and buffers the resulting records of
+// [nodeIDs]. Once [f] succeeds, failing to write a full batch is left to
+// Flush and Close to report.
+func (p *persistentManager) mutateAll(netID ids.ID, nodeIDs []ids.NodeID, f func() error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.load(netID); err != nil {
+		return err
+	}
+	if err := f(); err != nil {
+		return err
+	}
+
+	// A nil record deletes the validator from the store
+	for _, nodeID := range nodeIDs {
+		val, _ := p.inner.GetValidator(netID, nodeID)
+		p.pending[validatorKey{netID: netID, nodeID: nodeID}] = val
+	}
+	if len(p.pending) >= p.config.BatchSize {
+		_ = p.flush() // The batch stays buffered and is retried
+	}
+	return nil
+}
+
+func (p *persistentManager) Flush() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.flush()
+}
+
+// Close flushes even if listeners failed to drain, since the store must not
+// lose changes the manager accepted
+func (p *persistentManager) Close(ctx context.Context) error {
+	closeErr := p.inner.Close(ctx)
+	return errors.Join(closeErr, p.Flush())
+}
+
+// flush writes the buffered changes. It assumes the lock is held.
+func (p *persistentManager) flush() error {
+	if len(p.pending) == 0 {
+		return nil
+	}
+
+	writes := make([]StoreWrite, 0, len(p.pending))
+	for key, val := range p.pending {
+		writes = append(writes, StoreWrite{
+			NetID:     key.netID,
+			NodeID:    key.nodeID,
+			Validator: val,
+		})
+	}
+	slices.SortFunc(writes, func(a, b StoreWrite) int {
+		if c := bytes.Compare(a.NetID[:], b.NetID[:]); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.NodeID[:], b.NodeID[:])
+	})
+	if err := p.store.WriteBatch(writes); err != nil {
+		return fmt.Errorf("couldn't write %d validators: %w", len(writes), err)
+	}
+	clear(p.pending)
+	return nil
+}
+
+func (p *persistentManager) GetValidators(netID ids.ID) (Set, error) {
+	if err := p.Load(netID); err != nil {
+		return nil, err
+	}
+	return p.inner.GetValidators(netID)
+}
+
+func (p *persistentManager) GetValidator(netID ids.ID, nodeID ids.NodeID) (*GetValidatorOutput, bool) {
+	p.read(netID)
+	return p.inner.GetValidator(netID, nodeID)
+}
+
+func (p *persistentManager) GetLight(netID ids.ID, nodeID ids.NodeID) uint64 {
+	p.read(netID)
+	return p.inner.GetLight(netID, nodeID)
+}
+
+func (p *persistentManager) GetWeight(netID ids.ID, nodeID ids.NodeID) uint64 {
+	return p.GetLight(netID, nodeID)
+}
+
+func (p *persistentManager) TotalLight(netID ids.ID) (uint64, error) {
+	if err := p.Load(netID); err != nil {
+		return 0, err
+	}
+	return p.inner.TotalLight(netID)
+}
+
+func (p *persistentManager) TotalWeight(netID ids.ID) (uint64, error) {
+	if err := p.Load(netID); err != nil {
+		return 0, err
+	}
+	return p.inner.TotalWeight(netID)
+}
+
+func (p *persistentManager) AddStaker(netID ids.ID, nodeID ids.NodeID, publicKey []byte, txID ids.ID, light uint64) error {
+	return p.mutate(netID, nodeID, func() error {
+		return p.inner.AddStaker(netID, nodeID, publicKey, txID, light)
+	})
+}
+
+func (p *persistentManager) AddWeight(netID ids.ID, nodeID ids.NodeID, light uint64) error {
+	return p.mutate(netID, nodeID, func() error {
+		return p.inner.AddWeight(netID, nodeID, light)
+	})
+}
+
+func (p *persistentManager) RemoveWeight(netID ids.ID, nodeID ids.NodeID, light uint64) error {
+	return p.mutate(netID, nodeID, func() error {
+		return p.inner.RemoveWeight(netID, nodeID, light)
+	})
+}
+
+func (p *persistentManager) SetWeight(netID ids.ID, nodeID ids.NodeID, light uint64) error {
+	return p.mutate(netID, nodeID, func() error {
+		return p.inner.SetWeight(netID, nodeID, light)
+	})
+}
+
+func (p *persistentManager) UpdatePublicKey(netID ids.ID, nodeID ids.NodeID, publicKey []byte) error {
+	return p.mutate(netID, nodeID, func() error {
+		return p.inner.UpdatePublicKey(netID, nodeID, publicKey)
+	})
+}
+
+func (p *persistentManager) RemoveStaker(netID ids.ID, nodeID ids.NodeID) error {
+	return p.mutate(netID, nodeID, func() error {
+		return p.inner.RemoveStaker(netID, nodeID)
+	})
+}
+
+func (p *persistentManager) ApplyDiff(netID ids.ID, diff ValidatorDiff) error {
+	nodeIDs := slices.Clone(diff.Removed)
+	for _, addition := range diff.Added {
+		nodeIDs = append(nodeIDs, addition.NodeID)
+	}
+	for _, change := range diff.WeightChanges {
+		nodeIDs = append(nodeIDs, change.NodeID)
+	}
+	return p.mutateAll(netID, nodeIDs, func() error {
+		return p.inner.ApplyDiff(netID, diff)
+	})
+}
+
+// NumNets counts the loaded nets with validators and the nets that haven't
+// been loaded yet
+func (p *persistentManager) NumNets() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.inner.NumNets() + p.unloaded.Len()
+}
+
+// GetNetIDs returns the loaded nets with validators and the nets that
+// haven't been loaded yet
+func (p *persistentManager) GetNetIDs() []ids.ID {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	netIDs := append(p.inner.GetNetIDs(), p.unloaded.List()...)
+	slices.SortFunc(netIDs, ids.ID.Compare)
+	return netIDs
+}
+
+// TotalCount loads every net and counts their validators
+func (p *persistentManager) TotalCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, netID := range p.unloaded.List() {
+		_ = p.load(netID) // A net that fails to load counts as empty
+	}
+	return p.inner.TotalCount()
+}
+
+func (p *persistentManager) Count(netID ids.ID) int {
+	p.read(netID)
+	return p.inner.Count(netID)
+}
+
+func (p *persistentManager) NumValidators(netID ids.ID) int {
+	return p.Count(netID)
+}
+
+func (p *persistentManager) Sample(netID ids.ID, size int) ([]ids.NodeID, error) {
+	if err := p.Load(netID); err != nil {
+		return nil, err
+	}
+	return p.inner.Sample(netID, size)
+}
+
+func (p *persistentManager) GetValidatorIDs(netID ids.ID) []ids.NodeID {
+	p.read(netID)
+	return p.inner.GetValidatorIDs(netID)
+}
+
+func (p *persistentManager) SubsetWeight(netID ids.ID, nodeIDs set.Set[ids.NodeID]) (uint64, error) {
+	if err := p.Load(netID); err != nil {
+		return 0, err
+	}
+	return p.inner.SubsetWeight(netID, nodeIDs)
+}
+
+func (p *persistentManager) GetMap(netID ids.ID) map[ids.NodeID]*GetValidatorOutput {
+	p.read(netID)
+	return p.inner.GetMap(netID)
+}
+
+func (p *persistentManager) View(netID ids.ID) *ValidatorView {
+	p.read(netID)
+	return p.inner.View(netID)
+}
+
+func (p *persistentManager) ViewAt(netID ids.ID) *FrozenView {
+	p.read(netID)
+	return p.inner.ViewAt(netID)
+}
+
+func (p *persistentManager) GetWarpSet(netID ids.ID) *WarpSet {
+	p.read(netID)
+	return p.inner.GetWarpSet(netID)
+}
+
+func (p *persistentManager) Snapshot(netID ids.ID) *ValidatorSnapshot {
+	p.read(netID)
+	return p.inner.Snapshot(netID)
+}
+
+func (p *persistentManager) MultiNetSnapshot(netIDs []ids.ID) *MultiNetSnapshot {
+	for _, netID := range netIDs {
+		p.read(netID)
+	}
+	return p.inner.MultiNetSnapshot(netIDs)
+}
+
+// RegisterCallbackListener registers [listener] with the in-memory manager.
+// Validators of nets loaded later are reported as added when they load.
+func (p *persistentManager) RegisterCallbackListener(listener ManagerCallbackListener) {
+	p.inner.RegisterCallbackListener(listener)
+}
+
+func (p *persistentManager) UnregisterCallbackListener(listener ManagerCallbackListener) bool {
+	return p.inner.UnregisterCallbackListener(listener)
+}
+
+func (p *persistentManager) UnregisterSetCallbackListener(netID ids.ID, listener SetCallbackListener) bool {
+	return p.inner.UnregisterSetCallbackListener(netID, listener)
+}
+
+// RegisterSetCallbackListener loads [netID] so the listener is notified of
+// its persisted validators
+func (p *persistentManager) RegisterSetCallbackListener(netID ids.ID, listener SetCallbackListener) {
+	p.read(netID)
+	p.inner.RegisterSetCallbackListener(netID, listener)
+}
+
+func (p *persistentManager) RegisterPublicKeyListener(listener PublicKeyListener) {
+	p.inner.RegisterPublicKeyListener(listener)
+}
+
+// loadValidators adds persisted records to [netID], notifying listeners as
+// if they were added. Persisted sequence numbers are kept, and the sequence
+// of the manager moves past them so later changes order after them. Records
+// persisted without a sequence get a new one.
+func (m *manager) loadValidators(netID ids.ID, vdrs []*GetValidatorOutput) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	notify := make([]func(ManagerCallbackListener), 0, len(vdrs))
+	for _, vdr := range vdrs {
+		val := *vdr
+		val.Metadata = vdr.Metadata.Clone()
+		val.Extensions = cloneExtensions(vdr.Extensions)
+		m.putValidator(netID, &val)
+		if vdr.Sequence != 0 {
+			val.Sequence = vdr.Sequence
+			m.sequence = max(m.sequence, vdr.Sequence)
+		}
+
+		nodeID, light := val.NodeID, val.Light
+		notify = append(notify, func(listener ManagerCallbackListener) {
+			listener.OnValidatorAdded(netID, nodeID, light)
+		})
+	}
+	m.notify(notifyAll(notify))
+}
@@ -0,0 +1,244 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+var errTestStore = errors.New("test store error")
+
+type testStore struct {
+	nets     map[ids.ID]map[ids.NodeID]*GetValidatorOutput
+	loads    []ids.ID
+	batches  [][]StoreWrite
+	loadErr  error
+	writeErr error
+}
+
+func newTestStore() *testStore {
+	return &testStore{nets: make(map[ids.ID]map[ids.NodeID]*GetValidatorOutput)}
+}
+
+func (s *testStore) NetIDs() ([]ids.ID, error) {
+	netIDs := make([]ids.ID, 0, len(s.nets))
+	for netID := range s.nets {
+		netIDs = append(netIDs, netID)
+	}
+	return netIDs, nil
+}
+
+func (s *testStore) LoadNet(netID ids.ID) ([]*GetValidatorOutput, error) {
+	if s.loadErr != nil {
+		return nil, s.loadErr
+	}
+	s.loads = append(s.loads, netID)
+	vdrs := make([]*GetValidatorOutput, 0, len(s.nets[netID]))
+	for _, vdr := range s.nets[netID] {
+		vdrs = append(vdrs, vdr)
+	}
+	return vdrs, nil
+}
+
+func (s *testStore) WriteBatch(writes []StoreWrite) error {
+	if s.writeErr != nil {
+		return s.writeErr
+	}
+	s.batches = append(s.batches, writes)
+	for _, write := range writes {
+		if write.Validator == nil {
+			delete(s.nets[write.NetID], write.NodeID)
+			if len(s.nets[write.NetID]) == 0 {
+				delete(s.nets, write.NetID)
+			}
+			continue
+		}
+		if s.nets[write.NetID] == nil {
+			s.nets[write.NetID] = make(map[ids.NodeID]*GetValidatorOutput)
+		}
+		s.nets[write.NetID][write.NodeID] = write.Validator
+	}
+	return nil
+}
+
+// TestPersistentManagerLazyLoad tests that nets are loaded on first access
+func TestPersistentManagerLazyLoad(t *testing.T) {
+	require := require.New(t)
+
+	store := newTestStore()
+	netID, otherNetID := ids.GenerateTestID(), ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	store.nets[netID] = map[ids.NodeID]*GetValidatorOutput{
+		nodeID: {NodeID: nodeID, Light: 10, Weight: 20, Metadata: &ValidatorMetadata{Moniker: "lux"}},
+	}
+	store.nets[otherNetID] = map[ids.NodeID]*GetValidatorOutput{
+		nodeID: {NodeID: nodeID, Light: 5, Weight: 5},
+	}
+
+	m, err := NewPersistentManager(store, DefaultPersistenceConfig())
+	require.NoError(err)
+	require.Equal(2, m.NumNets())
+	require.Empty(store.loads)
+
+	vdr, ok := m.GetValidator(netID, nodeID)
+	require.True(ok)
+	require.Equal(uint64(10), vdr.Light)
+	require.Equal(uint64(20), vdr.Weight)
+	require.Equal("lux", vdr.Metadata.Moniker)
+	require.Equal([]ids.ID{netID}, store.loads)
+
+	// Loaded nets aren't loaded again
+	require.Equal(1, m.Count(netID))
+	require.Equal([]ids.ID{netID}, store.loads)
+	require.Equal(2, m.NumNets())
+
+	// Listeners see validators as their nets load
+	listener := &testListener{}
+	m.RegisterCallbackListener(listener)
+	require.Len(listener.added, 1)
+	require.Equal(uint64(5), m.GetLight(otherNetID, nodeID))
+	require.Len(listener.added, 2)
+}
+
+// TestPersistentManagerLoadSequence tests that loaded records keep their
+// persisted sequence numbers and that later changes order after them
+func TestPersistentManagerLoadSequence(t *testing.T) {
+	require := require.New(t)
+
+	store := newTestStore()
+	netID := ids.GenerateTestID()
+	nodeID1, nodeID2 := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	store.nets[netID] = map[ids.NodeID]*GetValidatorOutput{
+		nodeID1: {NodeID: nodeID1, Light: 10, Weight: 10, Sequence: 42},
+	}
+
+	m, err := NewPersistentManager(store, DefaultPersistenceConfig())
+	require.NoError(err)
+
+	vdr, ok := m.GetValidator(netID, nodeID1)
+	require.True(ok)
+	require.Equal(uint64(42), vdr.Sequence)
+
+	require.NoError(m.AddStaker(netID, nodeID2, nil, ids.Empty, 20))
+	vdr, ok = m.GetValidator(netID, nodeID2)
+	require.True(ok)
+	require.Greater(vdr.Sequence, uint64(42))
+}
+
+// TestPersistentManagerGetNetIDs tests that unloaded nets are enumerated
+// without loading them and counted by loading them
+func TestPersistentManagerGetNetIDs(t *testing.T) {
+	require := require.New(t)
+
+	store := newTestStore()
+	netID, otherNetID := ids.GenerateTestID(), ids.GenerateTestID()
+	nodeID1, nodeID2 := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	store.nets[netID] = map[ids.NodeID]*GetValidatorOutput{
+		nodeID1: {NodeID: nodeID1, Light: 10, Weight: 10},
+		nodeID2: {NodeID: nodeID2, Light: 10, Weight: 10},
+	}
+	store.nets[otherNetID] = map[ids.NodeID]*GetValidatorOutput{
+		nodeID1: {NodeID: nodeID1, Light: 5, Weight: 5},
+	}
+
+	m, err := NewPersistentManager(store, DefaultPersistenceConfig())
+	require.NoError(err)
+
+	expected := []ids.ID{netID, otherNetID}
+	slices.SortFunc(expected, ids.ID.Compare)
+	require.Equal(expected, m.GetNetIDs())
+	require.Empty(store.loads)
+
+	require.Equal(1, m.Count(otherNetID))
+	require.Equal(expected, m.GetNetIDs())
+
+	require.Equal(3, m.TotalCount())
+	require.Len(store.loads, 2)
+	require.Equal(expected, m.GetNetIDs())
+}
+
+// TestPersistentManagerWriteThrough tests that changes are written in batches
+func TestPersistentManagerWriteThrough(t *testing.T) {
+	require := require.New(t)
+
+	store := newTestStore()
+	m, err := NewPersistentManager(store, PersistenceConfig{BatchSize: 2})
+	require.NoError(err)
+
+	netID := ids.GenerateTestID()
+	nodeID1, nodeID2 := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID1, nil, ids.Empty, 10))
+	require.NoError(m.AddWeight(netID, nodeID1, 5))
+	require.Empty(store.batches)
+
+	// Changes to the same validator are coalesced
+	require.NoError(m.AddStaker(netID, nodeID2, nil, ids.Empty, 20))
+	require.Len(store.batches, 1)
+	require.Len(store.batches[0], 2)
+	require.Equal(uint64(15), store.nets[netID][nodeID1].Light)
+
+	require.NoError(m.RemoveWeight(netID, nodeID1, 15))
+	require.NoError(m.Flush())
+	require.NotContains(store.nets[netID], nodeID1)
+
+	// A new manager over the same store sees the persisted state
+	reopened, err := NewPersistentManager(store, DefaultPersistenceConfig())
+	require.NoError(err)
+	require.Equal([]ids.NodeID{nodeID2}, reopened.GetValidatorIDs(netID))
+
+	// Failed writes stay buffered
+	store.writeErr = errTestStore
+	require.NoError(m.AddWeight(netID, nodeID2, 1))
+	require.ErrorIs(m.Flush(), errTestStore)
+	store.writeErr = nil
+	require.NoError(m.Flush())
+	require.Equal(uint64(21), store.nets[netID][nodeID2].Light)
+
+	// Mutations applied in memory succeed even if their batch fails to be
+	// written, so they aren't retried
+	store.writeErr = errTestStore
+	require.NoError(m.AddStaker(netID, nodeID1, nil, ids.Empty, 1))
+	require.NoError(m.AddWeight(netID, nodeID2, 1))
+	require.Equal(uint64(1), m.GetLight(netID, nodeID1))
+	require.Equal(uint64(21), store.nets[netID][nodeID2].Light)
+	require.ErrorIs(m.Close(context.Background()), errTestStore)
+	store.writeErr = nil
+	require.NoError(m.Flush())
+	require.Equal(uint64(1), store.nets[netID][nodeID1].Light)
+	require.Equal(uint64(22), store.nets[netID][nodeID2].Light)
+
+	_, err = NewPersistentManager(store, PersistenceConfig{})
+	require.ErrorIs(err, ErrInvalidBatchSize)
+}
+
+// TestPersistentManagerLoadError tests that failed loads are retried
+func TestPersistentManagerLoadError(t *testing.T) {
+	require := require.New(t)
+
+	store := newTestStore()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	store.nets[netID] = map[ids.NodeID]*GetValidatorOutput{
+		nodeID: {NodeID: nodeID, Light: 10, Weight: 10},
+	}
+	m, err := NewPersistentManager(store, DefaultPersistenceConfig())
+	require.NoError(err)
+
+	store.loadErr = errTestStore
+	require.Zero(m.Count(netID))
+	_, err = m.TotalLight(netID)
+	require.ErrorIs(err, errTestStore)
+	require.ErrorIs(m.AddWeight(netID, nodeID, 1), errTestStore)
+
+	store.loadErr = nil
+	total, err := m.TotalLight(netID)
+	require.NoError(err)
+	require.Equal(uint64(10), total)
+}
@@ -0,0 +1,158 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/luxfi/ids"
+)
+
+// PrewarmConfig selects what a Prewarmer computes on every accepted height
+type PrewarmConfig struct {
+	// NetIDs are the nets whose sets are warmed
+	NetIDs []ids.ID
+	// Epoch, if set, warms the set frozen for the epoch of the accepted
+	// block instead of the set at the accepted height
+	Epoch *EpochConfig
+}
+
+// Prewarmer is a State that computes the Warp sets and their canonical
+// orderings of the configured nets as soon as a height is accepted, so the
+// first message verified at the new height doesn't pay to build them.
+//
+// Only the most recently warmed height is cached. Reads at other heights, or
+// for other nets, are passed through to the wrapped State. Returned sets are
+// shared and must not be modified.
+type Prewarmer struct {
+	State
+	config PrewarmConfig
+
+	mu        sync.RWMutex
+	height    uint64
+	warpSets  map[ids.ID]*WarpSet
+	canonical map[ids.ID]CanonicalValidatorSet
+}
+
+// NewPrewarmer returns a Prewarmer of [state]
+func NewPrewarmer(state State, config PrewarmConfig) (*Prewarmer, error) {
+	if config.Epoch != nil {
+		if err := config.Epoch.Verify(); err != nil {
+			return nil, err
+		}
+	}
+	config.NetIDs = slices.Clone(config.NetIDs)
+	return &Prewarmer{
+		State:  state,
+		config: config,
+	}, nil
+}
+
+// Accepted warms the sets for the block accepted at [height] with
+// [timestamp]. With an epoch config, nothing is fetched until the block
+// starts a new epoch. On error the previously warmed sets are kept.
+func (p *Prewarmer) Accepted(ctx context.Context, height uint64, timestamp time.Time) error {
+	if p.config.Epoch != nil {
+		var err error
+		_, height, err = p.config.Epoch.boundary(ctx, timestamp)
+		if err != nil {
+			return err
+		}
+	}
+
+	p.mu.RLock()
+	warmed := p.warpSets != nil && p.height == height
+	p.mu.RUnlock()
+	if warmed {
+		return nil
+	}
+
+	byNet, err := p.State.GetWarpValidatorSets(ctx, []uint64{height}, p.config.NetIDs)
+	if err != nil {
+		return fmt.Errorf("couldn't prewarm height %d: %w", height, err)
+	}
+	warpSets := make(map[ids.ID]*WarpSet, len(p.config.NetIDs))
+	canonical := make(map[ids.ID]CanonicalValidatorSet, len(p.config.NetIDs))
+	for _, netID := range p.config.NetIDs {
+		warpSet := byNet[netID][height]
+		if warpSet == nil {
+			continue
+		}
+		vdrSet, err := warpSet.flatten()
+		if err != nil {
+			return fmt.Errorf("couldn't prewarm net %s at height %d: %w", netID, height, err)
+		}
+		warpSets[netID] = warpSet
+		canonical[netID] = vdrSet
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.height = height
+	p.warpSets = warpSets
+	p.canonical = canonical
+	return nil
+}
+
+// WarmedHeight returns the height of the cached sets. ok is false if nothing
+// has been warmed yet.
+func (p *Prewarmer) WarmedHeight() (height uint64, ok bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.height, p.warpSets != nil
+}
+
+// GetCanonicalValidatorSet returns the canonical ordering of the Warp set of
+// [netID] at [height], if it is warmed
+func (p *Prewarmer) GetCanonicalValidatorSet(height uint64, netID ids.ID) (CanonicalValidatorSet, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.warpSets == nil || p.height != height {
+		return CanonicalValidatorSet{}, false
+	}
+	vdrSet, ok := p.canonical[netID]
+	return vdrSet, ok
+}
+
+func (p *Prewarmer) GetWarpValidatorSet(ctx context.Context, height uint64, netID ids.ID) (*WarpSet, error) {
+	if warpSet, ok := p.cached(height, netID); ok {
+		return warpSet, nil
+	}
+	return p.State.GetWarpValidatorSet(ctx, height, netID)
+}
+
+func (p *Prewarmer) GetWarpValidatorSets(ctx context.Context, heights []uint64, netIDs []ids.ID) (map[ids.ID]map[uint64]*WarpSet, error) {
+	if len(heights) != 1 {
+		return p.State.GetWarpValidatorSets(ctx, heights, netIDs)
+	}
+
+	result := make(map[ids.ID]map[uint64]*WarpSet, len(netIDs))
+	for _, netID := range netIDs {
+		warpSet, ok := p.cached(heights[0], netID)
+		if !ok {
+			return p.State.GetWarpValidatorSets(ctx, heights, netIDs)
+		}
+		result[netID] = map[uint64]*WarpSet{heights[0]: warpSet}
+	}
+	return result, nil
+}
+
+// cached returns the warmed Warp set of [netID] at [height]
+func (p *Prewarmer) cached(height uint64, netID ids.ID) (*WarpSet, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.warpSets == nil || p.height != height {
+		return nil, false
+	}
+	warpSet, ok := p.warpSets[netID]
+	return warpSet, ok
+}
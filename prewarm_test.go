@@ -0,0 +1,119 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+	"github.com/luxfi/validators/validatorstest"
+)
+
+// TestPrewarmer tests that accepted heights are served from the cache
+func TestPrewarmer(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	generated, err := validatorstest.GenerateValidatorSet(1, 1, validatorstest.ConstantWeights(100))
+	require.NoError(err)
+
+	source := validatorstest.NewTestState().AddValidator(netID, &validators.GetValidatorOutput{
+		NodeID:    nodeID,
+		PublicKey: generated.Validators[0].PublicKeyBytes,
+		Light:     100,
+		Weight:    100,
+	})
+	var fetches int
+	backing := validatorstest.NewTestState()
+	backing.GetWarpValidatorSetsF = func(ctx context.Context, heights []uint64, netIDs []ids.ID) (map[ids.ID]map[uint64]*validators.WarpSet, error) {
+		fetches++
+		return source.GetWarpValidatorSets(ctx, heights, netIDs)
+	}
+
+	p, err := validators.NewPrewarmer(backing, validators.PrewarmConfig{NetIDs: []ids.ID{netID}})
+	require.NoError(err)
+	_, ok := p.WarmedHeight()
+	require.False(ok)
+
+	require.NoError(p.Accepted(ctx, 7, time.Time{}))
+	require.Equal(1, fetches)
+	height, ok := p.WarmedHeight()
+	require.True(ok)
+	require.Equal(uint64(7), height)
+
+	vdrSet, ok := p.GetCanonicalValidatorSet(7, netID)
+	require.True(ok)
+	require.Equal(uint64(100), vdrSet.TotalWeight)
+	_, ok = p.GetCanonicalValidatorSet(6, netID)
+	require.False(ok)
+
+	// Warmed reads don't reach the wrapped state
+	backing.GetWarpValidatorSetF = func(context.Context, uint64, ids.ID) (*validators.WarpSet, error) {
+		return nil, errors.New("unexpected read")
+	}
+	warpSet, err := p.GetWarpValidatorSet(ctx, 7, netID)
+	require.NoError(err)
+	require.Contains(warpSet.Validators, nodeID)
+	require.NoError(warpSet.VerifyCommitment())
+
+	warpSets, err := p.GetWarpValidatorSets(ctx, []uint64{7}, []ids.ID{netID})
+	require.NoError(err)
+	require.Same(warpSet, warpSets[netID][7])
+	require.Equal(1, fetches)
+
+	_, err = p.GetWarpValidatorSet(ctx, 6, netID)
+	require.Error(err)
+}
+
+// TestPrewarmerEpoch tests that only a new epoch triggers a fetch
+func TestPrewarmerEpoch(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	netID := ids.GenerateTestID()
+	start := time.Unix(1_000, 0)
+
+	var heights []uint64
+	backing := validatorstest.NewTestState()
+	backing.GetWarpValidatorSetsF = func(_ context.Context, requested []uint64, _ []ids.ID) (map[ids.ID]map[uint64]*validators.WarpSet, error) {
+		heights = append(heights, requested...)
+		return map[ids.ID]map[uint64]*validators.WarpSet{
+			netID: {requested[0]: {Height: requested[0]}},
+		}, nil
+	}
+
+	_, err := validators.NewPrewarmer(backing, validators.PrewarmConfig{Epoch: &validators.EpochConfig{}})
+	require.ErrorIs(err, validators.ErrInvalidEpochConfig)
+
+	p, err := validators.NewPrewarmer(backing, validators.PrewarmConfig{
+		NetIDs: []ids.ID{netID},
+		Epoch: &validators.EpochConfig{
+			Start:    start,
+			Duration: time.Minute,
+			// One block every 10 seconds from height 1 at [start]
+			BoundaryHeight: func(_ context.Context, boundary time.Time) (uint64, error) {
+				return uint64(boundary.Sub(start) / (10 * time.Second)), nil
+			},
+		},
+	})
+	require.NoError(err)
+
+	for height := uint64(1); height <= 13; height++ {
+		timestamp := start.Add(time.Duration(height-1) * 10 * time.Second)
+		require.NoError(p.Accepted(ctx, height, timestamp))
+	}
+	require.Equal([]uint64{0, 6, 12}, heights)
+
+	_, err = p.GetWarpValidatorSet(ctx, 12, netID)
+	require.NoError(err)
+	require.ErrorIs(p.Accepted(ctx, 1, start.Add(-time.Second)), validators.ErrBeforeFirstEpoch)
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math"
+)
+
+var (
+	ErrNoWeightedValues = errors.New("no weighted values")
+	ErrInvalidQuantile  = errors.New("invalid quantile")
+)
+
+// WeightedValue is a value reported by a validator with the validator's weight
+type WeightedValue struct {
+	Value  uint64
+	Weight uint64
+}
+
+// WeightedQuantile returns the smallest value such that the values at or
+// below it carry at least [num]/[den] of the total weight. Values with zero
+// weight are ignored. The result is always one of the reported values, so a
+// minority of the weight can't move it outside the range reported by the
+// majority.
+func WeightedQuantile(values []WeightedValue, num, den uint64) (uint64, error) {
+	if den == 0 || num > den {
+		return 0, fmt.Errorf("%w: %d/%d", ErrInvalidQuantile, num, den)
+	}
+
+	sorted := make([]WeightedValue, 0, len(values))
+	var (
+		total uint64
+		err   error
+	)
+	for _, value := range values {
+		if value.Weight == 0 {
+			continue
+		}
+		total, err = math.Add64(total, value.Weight)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+		}
+		sorted = append(sorted, value)
+	}
+	if len(sorted) == 0 {
+		return 0, ErrNoWeightedValues
+	}
+	slices.SortFunc(sorted, func(a, b WeightedValue) int {
+		return cmp.Compare(a.Value, b.Value)
+	})
+
+	var cumulative uint64
+	for _, value := range sorted {
+		// Can't overflow, as the sum of all the weights didn't
+		cumulative += value.Weight
+		if meetsQuorum(cumulative, total, num, den) {
+			return value.Value, nil
+		}
+	}
+	// Unreachable, as the full weight meets every quantile
+	return sorted[len(sorted)-1].Value, nil
+}
+
+// WeightedMedian returns the weighted median of [values]. With an even split
+// the lower of the two middle values is returned.
+func WeightedMedian(values []WeightedValue) (uint64, error) {
+	return WeightedQuantile(values, 1, 2)
+}
+
+// StakeWeightedQuantile returns the WeightedQuantile of the values reported by
+// validators of [netID], each weighted by the validator's economic weight in
+// [m]. Values reported by nodes that aren't validators are ignored.
+func StakeWeightedQuantile(m Manager, netID ids.ID, values map[ids.NodeID]uint64, num, den uint64) (uint64, error) {
+	vdrs := m.GetMap(netID)
+	weighted := make([]WeightedValue, 0, len(values))
+	for nodeID, value := range values {
+		vdr, ok := vdrs[nodeID]
+		if !ok {
+			continue
+		}
+		weighted = append(weighted, WeightedValue{
+			Value:  value,
+			Weight: vdr.Weight,
+		})
+	}
+	return WeightedQuantile(weighted, num, den)
+}
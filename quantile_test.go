@@ -0,0 +1,85 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"math"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWeightedQuantile tests quantiles over weighted values
+func TestWeightedQuantile(t *testing.T) {
+	values := []WeightedValue{
+		{Value: 30, Weight: 10},
+		{Value: 10, Weight: 40},
+		{Value: 20, Weight: 10},
+		{Value: 40, Weight: 40},
+		{Value: 1_000, Weight: 0},
+	}
+	tests := []struct {
+		name     string
+		num, den uint64
+		expected uint64
+	}{
+		{name: "minimum", num: 0, den: 1, expected: 10},
+		{name: "lower quartile", num: 1, den: 4, expected: 10},
+		{name: "median", num: 1, den: 2, expected: 20},
+		{name: "upper quartile", num: 3, den: 4, expected: 40},
+		{name: "maximum", num: 1, den: 1, expected: 40},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			value, err := WeightedQuantile(values, test.num, test.den)
+			require.NoError(err)
+			require.Equal(test.expected, value)
+		})
+	}
+}
+
+// TestWeightedQuantileErrors tests invalid inputs
+func TestWeightedQuantileErrors(t *testing.T) {
+	require := require.New(t)
+
+	_, err := WeightedMedian(nil)
+	require.ErrorIs(err, ErrNoWeightedValues)
+	_, err = WeightedMedian([]WeightedValue{{Value: 1}})
+	require.ErrorIs(err, ErrNoWeightedValues)
+
+	values := []WeightedValue{{Value: 1, Weight: 1}}
+	_, err = WeightedQuantile(values, 2, 1)
+	require.ErrorIs(err, ErrInvalidQuantile)
+	_, err = WeightedQuantile(values, 0, 0)
+	require.ErrorIs(err, ErrInvalidQuantile)
+
+	_, err = WeightedMedian([]WeightedValue{{Value: 1, Weight: math.MaxUint64}, {Value: 2, Weight: 1}})
+	require.ErrorIs(err, ErrWeightOverflow)
+}
+
+// TestStakeWeightedQuantile tests that values are weighted by the manager
+func TestStakeWeightedQuantile(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	small, large := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, small, nil, ids.Empty, 10))
+	require.NoError(m.AddStaker(netID, large, nil, ids.Empty, 90))
+
+	values := map[ids.NodeID]uint64{
+		small:                    100,
+		large:                    25,
+		ids.GenerateTestNodeID(): 1,
+	}
+	median, err := StakeWeightedQuantile(m, netID, values, 1, 2)
+	require.NoError(err)
+	require.Equal(uint64(25), median)
+
+	_, err = StakeWeightedQuantile(m, ids.GenerateTestID(), values, 1, 2)
+	require.ErrorIs(err, ErrNoWeightedValues)
+}
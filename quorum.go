@@ -0,0 +1,202 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"sync"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math/set"
+)
+
+var (
+	ErrInvalidQuorum       = errors.New("invalid quorum")
+	ErrInsufficientWeight  = errors.New("insufficient signed weight")
+	ErrInsufficientSigners = errors.New("insufficient signers")
+	ErrInsufficientNodeIDs = errors.New("insufficient signing nodes")
+)
+
+// QuorumConfig is the safety parameters a net requires of a warp signature
+type QuorumConfig struct {
+	// Numerator / Denominator is the share of the total weight that must sign
+	Numerator   uint64
+	Denominator uint64
+	// MinSigners is the minimum number of distinct signing keys. Zero
+	// disables the check.
+	MinSigners int
+	// MinNodeIDs is the minimum number of distinct nodes behind the signing
+	// keys, which differs from MinSigners when nodes share a key. Zero
+	// disables the check.
+	MinNodeIDs int
+}
+
+// DefaultQuorumConfig requires 67% of the weight to sign
+func DefaultQuorumConfig() QuorumConfig {
+	return QuorumConfig{
+		Numerator:   67,
+		Denominator: 100,
+	}
+}
+
+// Verify returns an error if the config can never be, or is always, met
+func (c QuorumConfig) Verify() error {
+	switch {
+	case c.Denominator == 0:
+		return fmt.Errorf("%w: zero denominator", ErrInvalidQuorum)
+	case c.Numerator == 0:
+		return fmt.Errorf("%w: zero numerator", ErrInvalidQuorum)
+	case c.Numerator > c.Denominator:
+		return fmt.Errorf("%w: %d/%d exceeds 1", ErrInvalidQuorum, c.Numerator, c.Denominator)
+	case c.MinSigners < 0:
+		return fmt.Errorf("%w: negative minimum signers", ErrInvalidQuorum)
+	case c.MinNodeIDs < 0:
+		return fmt.Errorf("%w: negative minimum node IDs", ErrInvalidQuorum)
+	}
+	return nil
+}
+
+// Check returns an error if [signers] don't meet the quorum out of
+// [totalWeight]
+func (c QuorumConfig) Check(signers []*CanonicalValidator, totalWeight uint64) error {
+	if len(signers) < c.MinSigners {
+		return fmt.Errorf("%w: %d < %d", ErrInsufficientSigners, len(signers), c.MinSigners)
+	}
+	if c.MinNodeIDs > 0 {
+		nodeIDs := set.NewSet[ids.NodeID](c.MinNodeIDs)
+		for _, signer := range signers {
+			nodeIDs.Add(signer.NodeIDs...)
+		}
+		if nodeIDs.Len() < c.MinNodeIDs {
+			return fmt.Errorf("%w: %d < %d", ErrInsufficientNodeIDs, nodeIDs.Len(), c.MinNodeIDs)
+		}
+	}
+	signedWeight, err := SumWeight(signers)
+	if err != nil {
+		return err
+	}
+	if !meetsQuorum(signedWeight, totalWeight, c.Numerator, c.Denominator) {
+		return fmt.Errorf("%w: %d/%d is below %d/%d",
+			ErrInsufficientWeight, signedWeight, totalWeight, c.Numerator, c.Denominator,
+		)
+	}
+	return nil
+}
+
+// Reached returns true if [signedWeight] out of [totalWeight] meets the
+// weight threshold. MinSigners isn't checked.
+func (c QuorumConfig) Reached(signedWeight, totalWeight uint64) bool {
+	return meetsQuorum(signedWeight, totalWeight, c.Numerator, c.Denominator)
+}
+
+// VerifyWeight returns an error unless [signedWeight] out of [totalWeight]
+// meets the quorum [quorumNum]/[quorumDen]. The quorum must be a share in
+// (0, 1], and more than the total can't have signed. The comparison is exact
+// for every uint64 input.
+func VerifyWeight(signedWeight, totalWeight, quorumNum, quorumDen uint64) error {
+	switch {
+	case quorumDen == 0:
+		return fmt.Errorf("%w: zero denominator", ErrInvalidQuorum)
+	case quorumNum == 0:
+		return fmt.Errorf("%w: zero numerator", ErrInvalidQuorum)
+	case quorumNum > quorumDen:
+		return fmt.Errorf("%w: %d/%d exceeds 1", ErrInvalidQuorum, quorumNum, quorumDen)
+	case signedWeight > totalWeight:
+		return fmt.Errorf("%w: signed weight %d exceeds total %d", ErrInvalidWeight, signedWeight, totalWeight)
+	case !meetsQuorum(signedWeight, totalWeight, quorumNum, quorumDen):
+		return fmt.Errorf("%w: %d/%d is below %d/%d",
+			ErrInsufficientWeight, signedWeight, totalWeight, quorumNum, quorumDen,
+		)
+	}
+	return nil
+}
+
+// MeetsThreshold returns true if [signedWeight] out of the total weight of
+// the set meets the quorum [quorumNum]/[quorumDen], as by VerifyWeight
+func (s *CanonicalValidatorSet) MeetsThreshold(signedWeight, quorumNum, quorumDen uint64) bool {
+	return VerifyWeight(signedWeight, s.TotalWeight, quorumNum, quorumDen) == nil
+}
+
+// meetsQuorum returns true if signed/total >= num/den. The products are
+// computed in 128 bits so they can't overflow.
+func meetsQuorum(signed, total, num, den uint64) bool {
+	lhsHi, lhsLo := bits.Mul64(signed, den)
+	rhsHi, rhsLo := bits.Mul64(total, num)
+	if lhsHi != rhsHi {
+		return lhsHi > rhsHi
+	}
+	return lhsLo >= rhsLo
+}
+
+// QuorumRegistry holds the quorum config of every net, falling back to a
+// default for nets without one
+type QuorumRegistry struct {
+	mu            sync.RWMutex
+	defaultConfig QuorumConfig
+	configs       map[ids.ID]QuorumConfig
+}
+
+// NewQuorumRegistry returns a registry that uses [defaultConfig] for nets
+// without a config of their own
+func NewQuorumRegistry(defaultConfig QuorumConfig) (*QuorumRegistry, error) {
+	if err := defaultConfig.Verify(); err != nil {
+		return nil, err
+	}
+	return &QuorumRegistry{
+		defaultConfig: defaultConfig,
+		configs:       make(map[ids.ID]QuorumConfig),
+	}, nil
+}
+
+// Set sets the quorum config of [netID]
+func (r *QuorumRegistry) Set(netID ids.ID, config QuorumConfig) error {
+	if err := config.Verify(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.configs[netID] = config
+	return nil
+}
+
+// Reset makes [netID] use the default config again
+func (r *QuorumRegistry) Reset(netID ids.ID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.configs, netID)
+}
+
+// Get returns the quorum config of [netID]
+func (r *QuorumRegistry) Get(netID ids.ID) QuorumConfig {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if config, ok := r.configs[netID]; ok {
+		return config
+	}
+	return r.defaultConfig
+}
+
+// VerifySigners filters the signers of [vdrSet] marked in [signerIndices] and
+// checks them against the quorum config of [netID]. It returns the signers so
+// the caller can aggregate their public keys.
+func (r *QuorumRegistry) VerifySigners(
+	netID ids.ID,
+	vdrSet CanonicalValidatorSet,
+	signerIndices set.Bits,
+) ([]*CanonicalValidator, error) {
+	signers, err := FilterValidators(signerIndices, vdrSet.Validators)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.Get(netID).Check(signers, vdrSet.TotalWeight); err != nil {
+		return nil, fmt.Errorf("net %s: %w", netID, err)
+	}
+	return signers, nil
+}
@@ -0,0 +1,133 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"math"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math/set"
+	"github.com/stretchr/testify/require"
+)
+
+// TestQuorumConfigVerify tests rejected quorum configs
+func TestQuorumConfigVerify(t *testing.T) {
+	require := require.New(t)
+
+	require.NoError(DefaultQuorumConfig().Verify())
+	require.ErrorIs(QuorumConfig{Numerator: 1}.Verify(), ErrInvalidQuorum)
+	require.ErrorIs(QuorumConfig{Denominator: 1}.Verify(), ErrInvalidQuorum)
+	require.ErrorIs(QuorumConfig{Numerator: 2, Denominator: 1}.Verify(), ErrInvalidQuorum)
+	require.ErrorIs(QuorumConfig{Numerator: 1, Denominator: 1, MinSigners: -1}.Verify(), ErrInvalidQuorum)
+}
+
+// TestMeetsQuorum tests the threshold comparison at the uint64 limits
+func TestMeetsQuorum(t *testing.T) {
+	require := require.New(t)
+
+	require.True(meetsQuorum(67, 100, 67, 100))
+	require.False(meetsQuorum(66, 100, 67, 100))
+	require.True(meetsQuorum(math.MaxUint64, math.MaxUint64, 67, 100))
+	require.False(meetsQuorum(math.MaxUint64/2, math.MaxUint64, 67, 100))
+	require.True(meetsQuorum(math.MaxUint64-1, math.MaxUint64, math.MaxUint64-1, math.MaxUint64))
+	require.False(meetsQuorum(math.MaxUint64-2, math.MaxUint64, math.MaxUint64-1, math.MaxUint64))
+
+	require.True(DefaultQuorumConfig().Reached(67, 100))
+	require.False(DefaultQuorumConfig().Reached(66, 100))
+}
+
+// TestVerifyWeight tests the quorum check of warp verifiers
+func TestVerifyWeight(t *testing.T) {
+	require := require.New(t)
+
+	require.NoError(VerifyWeight(67, 100, 67, 100))
+	require.ErrorIs(VerifyWeight(66, 100, 67, 100), ErrInsufficientWeight)
+	require.NoError(VerifyWeight(math.MaxUint64-1, math.MaxUint64, 67, 100))
+	require.ErrorIs(VerifyWeight(101, 100, 67, 100), ErrInvalidWeight)
+	require.ErrorIs(VerifyWeight(1, 1, 1, 0), ErrInvalidQuorum)
+	require.ErrorIs(VerifyWeight(1, 1, 0, 1), ErrInvalidQuorum)
+	require.ErrorIs(VerifyWeight(1, 1, 2, 1), ErrInvalidQuorum)
+
+	vdrSet := CanonicalValidatorSet{TotalWeight: math.MaxUint64}
+	require.True(vdrSet.MeetsThreshold(math.MaxUint64/3*2+1, 2, 3))
+	require.False(vdrSet.MeetsThreshold(math.MaxUint64/3*2-1, 2, 3))
+	require.False(vdrSet.MeetsThreshold(math.MaxUint64, 1, 0))
+}
+
+// TestQuorumRegistry tests per-net configs and signer verification
+func TestQuorumRegistry(t *testing.T) {
+	require := require.New(t)
+
+	_, err := NewQuorumRegistry(QuorumConfig{})
+	require.ErrorIs(err, ErrInvalidQuorum)
+
+	r, err := NewQuorumRegistry(DefaultQuorumConfig())
+	require.NoError(err)
+
+	netID := ids.GenerateTestID()
+	strict := QuorumConfig{Numerator: 9, Denominator: 10, MinSigners: 2}
+	require.NoError(r.Set(netID, strict))
+	require.ErrorIs(r.Set(netID, QuorumConfig{}), ErrInvalidQuorum)
+	require.Equal(strict, r.Get(netID))
+	require.Equal(DefaultQuorumConfig(), r.Get(ids.GenerateTestID()))
+
+	vdrSet := CanonicalValidatorSet{
+		Validators: []*CanonicalValidator{
+			{Weight: 70},
+			{Weight: 20},
+			{Weight: 10},
+		},
+		TotalWeight: 100,
+	}
+
+	// A single whale meets the default quorum but not the strict one
+	signers, err := r.VerifySigners(ids.GenerateTestID(), vdrSet, set.NewBits(0))
+	require.NoError(err)
+	require.Len(signers, 1)
+
+	_, err = r.VerifySigners(netID, vdrSet, set.NewBits(0))
+	require.ErrorIs(err, ErrInsufficientSigners)
+	_, err = r.VerifySigners(netID, vdrSet, set.NewBits(0, 2))
+	require.ErrorIs(err, ErrInsufficientWeight)
+	signers, err = r.VerifySigners(netID, vdrSet, set.NewBits(0, 1))
+	require.NoError(err)
+	require.Len(signers, 2)
+
+	_, err = r.VerifySigners(netID, vdrSet, set.NewBits(3))
+	require.ErrorIs(err, ErrUnknownValidator)
+
+	r.Reset(netID)
+	require.Equal(DefaultQuorumConfig(), r.Get(netID))
+}
+
+// TestQuorumMinNodeIDs tests that a key shared by several nodes counts each
+// node once
+func TestQuorumMinNodeIDs(t *testing.T) {
+	require := require.New(t)
+
+	require.ErrorIs(QuorumConfig{Numerator: 1, Denominator: 1, MinNodeIDs: -1}.Verify(), ErrInvalidQuorum)
+
+	r, err := NewQuorumRegistry(DefaultQuorumConfig())
+	require.NoError(err)
+	netID := ids.GenerateTestID()
+	require.NoError(r.Set(netID, QuorumConfig{Numerator: 67, Denominator: 100, MinSigners: 1, MinNodeIDs: 3}))
+
+	shared, single := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	vdrSet := CanonicalValidatorSet{
+		Validators: []*CanonicalValidator{
+			{Weight: 80, NodeIDs: []ids.NodeID{shared, ids.GenerateTestNodeID()}},
+			{Weight: 20, NodeIDs: []ids.NodeID{single}},
+		},
+		TotalWeight: 100,
+	}
+
+	// One key meets the weight and signer count but is only two nodes
+	_, err = r.VerifySigners(netID, vdrSet, set.NewBits(0))
+	require.ErrorIs(err, ErrInsufficientNodeIDs)
+
+	signers, err := r.VerifySigners(netID, vdrSet, set.NewBits(0, 1))
+	require.NoError(err)
+	require.Len(signers, 2)
+}
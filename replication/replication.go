@@ -0,0 +1,319 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package replication keeps warm standby managers in step with a primary, by
+// streaming the journal of the primary's mutations to followers that apply it
+// in order and can be promoted when the primary fails
+package replication
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/luxfi/ids"
+
+	validators "github.com/luxfi/validators"
+)
+
+var (
+	ErrSequenceGap      = errors.New("journal entry out of sequence")
+	ErrUnknownOp        = errors.New("unknown journal op")
+	ErrDiverged         = errors.New("follower diverged from primary")
+	ErrPromoted         = errors.New("follower was promoted")
+	ErrFollowerDetached = errors.New("follower detached")
+)
+
+// Op is the mutation a journal entry records
+type Op uint8
+
+const (
+	OpAddStaker Op = iota
+	OpAddWeight
+	OpRemoveWeight
+	OpSetWeight
+	OpRemoveStaker
+	OpApplyDiff
+)
+
+// String implements fmt.Stringer
+func (o Op) String() string {
+	switch o {
+	case OpAddStaker:
+		return "add-staker"
+	case OpAddWeight:
+		return "add-weight"
+	case OpRemoveWeight:
+		return "remove-weight"
+	case OpSetWeight:
+		return "set-weight"
+	case OpRemoveStaker:
+		return "remove-staker"
+	case OpApplyDiff:
+		return "apply-diff"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(o))
+	}
+}
+
+// Entry is a mutation applied by a primary. Entries are numbered from 1 in
+// the order they were applied.
+type Entry struct {
+	Sequence  uint64
+	Op        Op
+	NetID     ids.ID
+	NodeID    ids.NodeID
+	PublicKey []byte
+	TxID      ids.ID
+	Light     uint64
+	// Diff is set for OpApplyDiff
+	Diff *validators.ValidatorDiff
+}
+
+// Apply applies the mutation of the entry to [manager]
+func (e *Entry) Apply(manager Manager) error {
+	switch e.Op {
+	case OpAddStaker:
+		return manager.AddStaker(e.NetID, e.NodeID, e.PublicKey, e.TxID, e.Light)
+	case OpAddWeight:
+		return manager.AddWeight(e.NetID, e.NodeID, e.Light)
+	case OpRemoveWeight:
+		return manager.RemoveWeight(e.NetID, e.NodeID, e.Light)
+	case OpSetWeight:
+		return manager.SetWeight(e.NetID, e.NodeID, e.Light)
+	case OpRemoveStaker:
+		return manager.RemoveStaker(e.NetID, e.NodeID)
+	case OpApplyDiff:
+		if e.Diff == nil {
+			return fmt.Errorf("%w: %s without a diff", ErrUnknownOp, e.Op)
+		}
+		return manager.ApplyDiff(e.NetID, *e.Diff)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownOp, e.Op)
+	}
+}
+
+// Sink receives the journal of a primary, in sequence. A Follower is a sink;
+// transports to followers in other processes implement it to forward
+// entries. Append is called with the primary's lock held, so it must not
+// block on the primary.
+type Sink interface {
+	Append(entry Entry) error
+}
+
+// Manager is a validator manager every journaled mutation can be applied to
+type Manager interface {
+	validators.Manager
+	validators.StakerManager
+	validators.DiffManager
+}
+
+// Primary is a Manager that journals its successful mutations to its
+// followers. Mutations that fail aren't journaled. Changes made to the
+// wrapped manager directly aren't replicated.
+//
+// Followers must start from the state the wrapped manager had when the first
+// entry they receive was applied, such as both starting empty.
+type Primary struct {
+	Manager
+
+	mu       sync.Mutex
+	sequence uint64
+	sinks    []Sink
+}
+
+var _ Manager = (*Primary)(nil)
+
+// NewPrimary returns a primary journaling the mutations made to [manager],
+// starting after entry [sequence]
+func NewPrimary(manager Manager, sequence uint64) *Primary {
+	return &Primary{
+		Manager:  manager,
+		sequence: sequence,
+	}
+}
+
+// AddFollower streams every later entry to [sink]
+func (p *Primary) AddFollower(sink Sink) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.sinks = append(p.sinks, sink)
+}
+
+// Sequence returns the sequence of the latest entry
+func (p *Primary) Sequence() uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.sequence
+}
+
+func (p *Primary) AddStaker(netID ids.ID, nodeID ids.NodeID, publicKey []byte, txID ids.ID, light uint64) error {
+	return p.journal(Entry{
+		Op:        OpAddStaker,
+		NetID:     netID,
+		NodeID:    nodeID,
+		PublicKey: slices.Clone(publicKey),
+		TxID:      txID,
+		Light:     light,
+	})
+}
+
+func (p *Primary) AddWeight(netID ids.ID, nodeID ids.NodeID, light uint64) error {
+	return p.journal(Entry{Op: OpAddWeight, NetID: netID, NodeID: nodeID, Light: light})
+}
+
+func (p *Primary) RemoveWeight(netID ids.ID, nodeID ids.NodeID, light uint64) error {
+	return p.journal(Entry{Op: OpRemoveWeight, NetID: netID, NodeID: nodeID, Light: light})
+}
+
+func (p *Primary) SetWeight(netID ids.ID, nodeID ids.NodeID, light uint64) error {
+	return p.journal(Entry{Op: OpSetWeight, NetID: netID, NodeID: nodeID, Light: light})
+}
+
+func (p *Primary) RemoveStaker(netID ids.ID, nodeID ids.NodeID) error {
+	return p.journal(Entry{Op: OpRemoveStaker, NetID: netID, NodeID: nodeID})
+}
+
+func (p *Primary) ApplyDiff(netID ids.ID, diff validators.ValidatorDiff) error {
+	return p.journal(Entry{Op: OpApplyDiff, NetID: netID, Diff: cloneDiff(diff)})
+}
+
+// journal applies [entry] to the wrapped manager and streams it to the
+// followers. A follower that fails to take the entry is detached, and the
+// mutation, which was still applied, returns ErrFollowerDetached.
+func (p *Primary) journal(entry Entry) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := entry.Apply(p.Manager); err != nil {
+		return err
+	}
+	p.sequence++
+	entry.Sequence = p.sequence
+
+	var errs []error
+	p.sinks = slices.DeleteFunc(p.sinks, func(sink Sink) bool {
+		if err := sink.Append(entry); err != nil {
+			errs = append(errs, fmt.Errorf("%w at entry %d: %w", ErrFollowerDetached, entry.Sequence, err))
+			return true
+		}
+		return false
+	})
+	return errors.Join(errs...)
+}
+
+// Follower is a warm standby of a primary. Entries are queued as they arrive
+// and applied to its manager by Apply, so the lag between them is visible
+// and bounded by how often the follower applies.
+type Follower struct {
+	manager Manager
+
+	mu       sync.Mutex
+	received uint64
+	applied  uint64
+	queue    []Entry
+	promoted bool
+}
+
+var _ Sink = (*Follower)(nil)
+
+// NewFollower returns a follower applying entries after [sequence] to
+// [manager]
+func NewFollower(manager Manager, sequence uint64) *Follower {
+	return &Follower{
+		manager:  manager,
+		received: sequence,
+		applied:  sequence,
+	}
+}
+
+// Append queues [entry], which must directly follow the previous entry
+func (f *Follower) Append(entry Entry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case f.promoted:
+		return ErrPromoted
+	case entry.Sequence != f.received+1:
+		return fmt.Errorf("%w: got %d, expected %d", ErrSequenceGap, entry.Sequence, f.received+1)
+	}
+	f.received = entry.Sequence
+	f.queue = append(f.queue, entry)
+	return nil
+}
+
+// Apply applies the queued entries in order and returns how many it applied.
+// An entry that fails means the follower no longer matches the primary; it
+// is left queued and ErrDiverged is returned.
+func (f *Follower) Apply() (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.apply()
+}
+
+// apply applies the queued entries. It assumes the lock is held.
+func (f *Follower) apply() (int, error) {
+	var applied int
+	for len(f.queue) > 0 {
+		entry := f.queue[0]
+		if err := entry.Apply(f.manager); err != nil {
+			return applied, fmt.Errorf("%w at entry %d (%s): %w", ErrDiverged, entry.Sequence, entry.Op, err)
+		}
+		f.queue = f.queue[1:]
+		f.applied = entry.Sequence
+		applied++
+	}
+	return applied, nil
+}
+
+// Applied returns the sequence of the latest applied entry
+func (f *Follower) Applied() uint64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.applied
+}
+
+// Lag returns the number of received entries that aren't applied yet
+func (f *Follower) Lag() uint64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.received - f.applied
+}
+
+// Promote applies every queued entry and returns a primary of the follower's
+// manager, continuing the journal where the old primary left off. The
+// follower rejects every later entry. If an entry fails, the follower isn't
+// promoted.
+func (f *Follower) Promote() (*Primary, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.promoted {
+		return nil, ErrPromoted
+	}
+	if _, err := f.apply(); err != nil {
+		return nil, err
+	}
+	f.promoted = true
+	return NewPrimary(f.manager, f.applied), nil
+}
+
+// cloneDiff returns a copy of [diff] that shares none of its slices
+func cloneDiff(diff validators.ValidatorDiff) *validators.ValidatorDiff {
+	diffCopy := validators.ValidatorDiff{
+		Removed:       slices.Clone(diff.Removed),
+		Added:         slices.Clone(diff.Added),
+		WeightChanges: slices.Clone(diff.WeightChanges),
+	}
+	for i := range diffCopy.Added {
+		diffCopy.Added[i].PublicKey = slices.Clone(diffCopy.Added[i].PublicKey)
+	}
+	return &diffCopy
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package replication
+
+import (
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+// TestReplication tests that a follower applies the journal of its primary
+// with visible lag, and can take over as primary
+func TestReplication(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		netID    = ids.GenerateTestID()
+		nodeA    = ids.GenerateTestNodeID()
+		nodeB    = ids.GenerateTestNodeID()
+		primary  = NewPrimary(validators.NewManager(), 0)
+		follower = NewFollower(validators.NewManager(), 0)
+	)
+	primary.AddFollower(follower)
+
+	require.NoError(primary.AddStaker(netID, nodeA, nil, ids.Empty, 100))
+	require.NoError(primary.ApplyDiff(netID, validators.ValidatorDiff{
+		Added: []validators.ValidatorAddition{{NodeID: nodeB, Light: 50}},
+	}))
+	require.NoError(primary.AddWeight(netID, nodeA, 10))
+	// Failed mutations aren't journaled
+	require.ErrorIs(primary.AddStaker(netID, nodeA, nil, ids.Empty, 1), validators.ErrDuplicateValidator)
+	require.Equal(uint64(3), primary.Sequence())
+
+	require.Equal(uint64(3), follower.Lag())
+	require.Zero(follower.manager.GetLight(netID, nodeA))
+
+	applied, err := follower.Apply()
+	require.NoError(err)
+	require.Equal(3, applied)
+	require.Zero(follower.Lag())
+	require.Equal(uint64(3), follower.Applied())
+	require.Equal(primary.GetMap(netID), follower.manager.GetMap(netID))
+
+	// The promoted follower continues the journal
+	require.NoError(primary.RemoveStaker(netID, nodeB))
+	require.Equal(uint64(1), follower.Lag())
+	promoted, err := follower.Promote()
+	require.NoError(err)
+	require.Equal(uint64(4), promoted.Sequence())
+	_, ok := promoted.GetValidator(netID, nodeB)
+	require.False(ok)
+
+	_, err = follower.Promote()
+	require.ErrorIs(err, ErrPromoted)
+
+	// The old primary detaches the promoted follower
+	require.ErrorIs(primary.RemoveStaker(netID, nodeA), ErrFollowerDetached)
+	require.NoError(primary.AddStaker(netID, nodeB, nil, ids.Empty, 1))
+
+	standby := NewFollower(validators.NewManager(), promoted.Sequence())
+	promoted.AddFollower(standby)
+	require.NoError(promoted.SetWeight(netID, nodeA, 5))
+	require.Equal(uint64(1), standby.Lag())
+}
+
+// TestFollowerSequenceGap tests that a follower rejects entries out of order
+func TestFollowerSequenceGap(t *testing.T) {
+	require := require.New(t)
+
+	netID := ids.GenerateTestID()
+	m := validators.NewManager()
+	m.SetStrict(true)
+	require.NoError(m.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 100))
+
+	follower := NewFollower(m, 0)
+	require.ErrorIs(follower.Append(Entry{Sequence: 2}), ErrSequenceGap)
+	require.NoError(follower.Append(Entry{
+		Sequence: 1,
+		Op:       OpRemoveWeight,
+		NetID:    netID,
+		NodeID:   ids.GenerateTestNodeID(),
+		Light:    10,
+	}))
+	require.ErrorIs(follower.Append(Entry{Sequence: 1}), ErrSequenceGap)
+
+	// Removing light from a validator the strict follower doesn't have
+	// diverges
+	_, err := follower.Apply()
+	require.ErrorIs(err, ErrDiverged)
+	require.ErrorIs(err, validators.ErrValidatorNotFound)
+	require.Equal(uint64(1), follower.Lag())
+}
@@ -0,0 +1,247 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package rewards computes how a staking reward pool is shared between
+// validators and their delegators
+package rewards
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/luxfi/ids"
+
+	validators "github.com/luxfi/validators"
+	"github.com/luxfi/validators/uptime"
+)
+
+// PercentDenominator is the denominator of commission and uptime rates, so a
+// rate of PercentDenominator is 100%
+const PercentDenominator = 1_000_000
+
+var (
+	ErrInvalidRate     = errors.New("rate exceeds PercentDenominator")
+	ErrInvalidInterval = errors.New("interval ends before it starts")
+)
+
+// Config configures a reward computation
+type Config struct {
+	// Pool is the total reward shared between all eligible stake
+	Pool uint64
+	// MinUptime is the uptime, in PercentDenominator units, a validator needs
+	// for it and its delegators to be rewarded
+	MinUptime uint64
+}
+
+// Interval is the period a validator was validating for
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration returns the length of the interval
+func (i Interval) Duration() time.Duration {
+	return i.End.Sub(i.Start)
+}
+
+// ValidatorInput describes a validator's participation in a reward period
+type ValidatorInput struct {
+	NodeID      ids.NodeID
+	Interval    Interval
+	SelfStake   uint64
+	Delegations map[ids.ShortID]uint64
+	// Uptime and Total are the time the validator was online and the time it
+	// was measured over. A Total of 0 is treated as full uptime.
+	Uptime time.Duration
+	Total  time.Duration
+	// Commission is the share, in PercentDenominator units, of its delegators'
+	// rewards that the validator keeps
+	Commission uint64
+}
+
+// ValidatorReward is the reward of a validator and its delegators
+type ValidatorReward struct {
+	NodeID ids.NodeID
+	// Eligible is false if the validator didn't meet the uptime requirement
+	Eligible bool
+	// Total is the reward earned by all stake on the validator
+	Total uint64
+	// Validator is the reward paid to the validator, including commission
+	Validator uint64
+	// Commission is the part of Validator that was taken from delegators
+	Commission uint64
+	// Delegators is sorted by delegator ID
+	Delegators []DelegatorReward
+}
+
+// DelegatorReward is the reward paid to a delegator
+type DelegatorReward struct {
+	DelegatorID ids.ShortID
+	Reward      uint64
+}
+
+// Result is the outcome of a reward computation
+type Result struct {
+	// Validators is sorted by node ID
+	Validators []ValidatorReward
+	// Distributed is the sum of all rewards paid out
+	Distributed uint64
+	// Forfeited is the part of the pool earned by ineligible validators
+	Forfeited uint64
+	// Remainder is the part of the pool lost to rounding
+	Remainder uint64
+}
+
+// Compute shares [config.Pool] between [inputs] in proportion to stake
+// multiplied by time staked. Delegators of a validator share its reward in
+// proportion to their stake, less the validator's commission. All arithmetic
+// is integer and rounds down, so the result is independent of platform and
+// input order.
+func Compute(config Config, inputs []ValidatorInput) (Result, error) {
+	if config.MinUptime > PercentDenominator {
+		return Result{}, fmt.Errorf("%w: min uptime %d", ErrInvalidRate, config.MinUptime)
+	}
+
+	sorted := slices.Clone(inputs)
+	slices.SortFunc(sorted, func(a, b ValidatorInput) int {
+		return bytes.Compare(a.NodeID[:], b.NodeID[:])
+	})
+
+	var (
+		stakes      = make([]*big.Int, len(sorted))
+		stakeTimes  = make([]*big.Int, len(sorted))
+		totalWeight = new(big.Int)
+	)
+	for i, input := range sorted {
+		if input.Commission > PercentDenominator {
+			return Result{}, fmt.Errorf("%w: commission %d of %s", ErrInvalidRate, input.Commission, input.NodeID)
+		}
+		duration := input.Interval.Duration()
+		if duration < 0 {
+			return Result{}, fmt.Errorf("%w: %s", ErrInvalidInterval, input.NodeID)
+		}
+
+		stake := new(big.Int).SetUint64(input.SelfStake)
+		for _, weight := range input.Delegations {
+			stake.Add(stake, new(big.Int).SetUint64(weight))
+		}
+		stakes[i] = stake
+		stakeTimes[i] = new(big.Int).Mul(stake, big.NewInt(int64(duration)))
+		totalWeight.Add(totalWeight, stakeTimes[i])
+	}
+
+	result := Result{
+		Validators: make([]ValidatorReward, len(sorted)),
+	}
+	pool := new(big.Int).SetUint64(config.Pool)
+	for i, input := range sorted {
+		reward := ValidatorReward{
+			NodeID:   input.NodeID,
+			Eligible: meetsUptime(input, config.MinUptime),
+		}
+		if totalWeight.Sign() > 0 {
+			reward.Total = mulDiv(pool, stakeTimes[i], totalWeight)
+		}
+		if !reward.Eligible {
+			result.Forfeited += reward.Total
+			reward.Total = 0
+			result.Validators[i] = reward
+			continue
+		}
+
+		reward.Delegators, reward.Validator, reward.Commission = split(input, reward.Total, stakes[i])
+		result.Distributed += reward.Validator
+		for _, delegator := range reward.Delegators {
+			result.Distributed += delegator.Reward
+		}
+		result.Validators[i] = reward
+	}
+	result.Remainder = config.Pool - result.Distributed - result.Forfeited
+	return result, nil
+}
+
+// split divides a validator's [total] reward between its delegators and
+// itself. Rounding dust is paid to the validator.
+func split(input ValidatorInput, total uint64, stake *big.Int) ([]DelegatorReward, uint64, uint64) {
+	delegatorIDs := make([]ids.ShortID, 0, len(input.Delegations))
+	for delegatorID, weight := range input.Delegations {
+		if weight > 0 {
+			delegatorIDs = append(delegatorIDs, delegatorID)
+		}
+	}
+	slices.SortFunc(delegatorIDs, func(a, b ids.ShortID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+
+	var (
+		delegators = make([]DelegatorReward, 0, len(delegatorIDs))
+		paid       uint64
+		commission uint64
+		totalInt   = new(big.Int).SetUint64(total)
+		rate       = new(big.Int).SetUint64(input.Commission)
+		denom      = big.NewInt(PercentDenominator)
+	)
+	for _, delegatorID := range delegatorIDs {
+		gross := mulDiv(totalInt, new(big.Int).SetUint64(input.Delegations[delegatorID]), stake)
+		fee := mulDiv(new(big.Int).SetUint64(gross), rate, denom)
+		delegators = append(delegators, DelegatorReward{
+			DelegatorID: delegatorID,
+			Reward:      gross - fee,
+		})
+		paid += gross
+		commission += fee
+	}
+	return delegators, total - paid + commission, commission
+}
+
+// mulDiv returns floor(a * b / c). The result never exceeds a as callers
+// guarantee b <= c.
+func mulDiv(a, b, c *big.Int) uint64 {
+	product := new(big.Int).Mul(a, b)
+	return product.Quo(product, c).Uint64()
+}
+
+func meetsUptime(input ValidatorInput, minUptime uint64) bool {
+	if input.Total <= 0 {
+		return true
+	}
+	uptime := new(big.Int).Mul(big.NewInt(int64(input.Uptime)), big.NewInt(PercentDenominator))
+	required := new(big.Int).Mul(big.NewInt(int64(input.Total)), new(big.Int).SetUint64(minUptime))
+	return uptime.Cmp(required) >= 0
+}
+
+// CollectInputs builds the reward inputs for every current validator of
+// [netID] in [m], reading delegations from [delegations] and uptimes from
+// [calculator]. Every validator is assumed to have validated for [interval];
+// [commission] returns each validator's commission rate.
+func CollectInputs(
+	m validators.Manager,
+	delegations validators.DelegationManager,
+	calculator uptime.Calculator,
+	netID ids.ID,
+	interval Interval,
+	commission func(ids.NodeID) uint64,
+) ([]ValidatorInput, error) {
+	vdrs := m.GetMap(netID)
+	inputs := make([]ValidatorInput, 0, len(vdrs))
+	for nodeID := range vdrs {
+		up, total, err := calculator.CalculateUptime(nodeID, netID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate uptime of %s: %w", nodeID, err)
+		}
+		inputs = append(inputs, ValidatorInput{
+			NodeID:      nodeID,
+			Interval:    interval,
+			SelfStake:   delegations.GetSelfStake(netID, nodeID),
+			Delegations: delegations.GetDelegations(netID, nodeID),
+			Uptime:      up,
+			Total:       total,
+			Commission:  commission(nodeID),
+		})
+	}
+	return inputs, nil
+}
@@ -0,0 +1,162 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rewards
+
+import (
+	"testing"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+	"github.com/luxfi/validators/uptime"
+)
+
+var testInterval = Interval{
+	Start: time.Unix(0, 0),
+	End:   time.Unix(100, 0),
+}
+
+// TestComputeProportionalShares tests that rewards follow stake and time
+func TestComputeProportionalShares(t *testing.T) {
+	require := require.New(t)
+
+	nodeID0 := ids.GenerateTestNodeID()
+	nodeID1 := ids.GenerateTestNodeID()
+	result, err := Compute(Config{Pool: 900}, []ValidatorInput{
+		{NodeID: nodeID0, Interval: testInterval, SelfStake: 100},
+		{
+			NodeID:    nodeID1,
+			Interval:  Interval{Start: testInterval.Start, End: testInterval.Start.Add(50 * time.Second)},
+			SelfStake: 400,
+		},
+	})
+	require.NoError(err)
+
+	rewards := make(map[ids.NodeID]uint64)
+	for _, vdr := range result.Validators {
+		require.True(vdr.Eligible)
+		rewards[vdr.NodeID] = vdr.Validator
+	}
+	require.Equal(map[ids.NodeID]uint64{nodeID0: 300, nodeID1: 600}, rewards)
+	require.Equal(uint64(900), result.Distributed)
+	require.Zero(result.Remainder)
+}
+
+// TestComputeCommission tests that delegators pay commission to the validator
+func TestComputeCommission(t *testing.T) {
+	require := require.New(t)
+
+	delegatorID := ids.GenerateTestShortID()
+	result, err := Compute(Config{Pool: 1000}, []ValidatorInput{{
+		NodeID:      ids.GenerateTestNodeID(),
+		Interval:    testInterval,
+		SelfStake:   500,
+		Delegations: map[ids.ShortID]uint64{delegatorID: 500},
+		Commission:  PercentDenominator / 10,
+	}})
+	require.NoError(err)
+	require.Len(result.Validators, 1)
+
+	vdr := result.Validators[0]
+	require.Equal(uint64(1000), vdr.Total)
+	require.Equal(uint64(50), vdr.Commission)
+	require.Equal(uint64(550), vdr.Validator)
+	require.Equal([]DelegatorReward{{DelegatorID: delegatorID, Reward: 450}}, vdr.Delegators)
+}
+
+// TestComputeUptimeRequirement tests that offline validators forfeit rewards
+func TestComputeUptimeRequirement(t *testing.T) {
+	require := require.New(t)
+
+	result, err := Compute(Config{Pool: 1000, MinUptime: 800_000}, []ValidatorInput{
+		{NodeID: ids.GenerateTestNodeID(), Interval: testInterval, SelfStake: 100, Uptime: 79, Total: 100},
+		{NodeID: ids.GenerateTestNodeID(), Interval: testInterval, SelfStake: 100, Uptime: 80, Total: 100},
+	})
+	require.NoError(err)
+
+	var eligible int
+	for _, vdr := range result.Validators {
+		if vdr.Eligible {
+			eligible++
+			require.Equal(uint64(500), vdr.Validator)
+		} else {
+			require.Zero(vdr.Total)
+		}
+	}
+	require.Equal(1, eligible)
+	require.Equal(uint64(500), result.Forfeited)
+	require.Equal(uint64(500), result.Distributed)
+}
+
+// TestComputeDeterministic tests that input order doesn't affect the result
+func TestComputeDeterministic(t *testing.T) {
+	require := require.New(t)
+
+	inputs := make([]ValidatorInput, 10)
+	for i := range inputs {
+		inputs[i] = ValidatorInput{
+			NodeID:    ids.GenerateTestNodeID(),
+			Interval:  testInterval,
+			SelfStake: uint64(i*7 + 3),
+			Delegations: map[ids.ShortID]uint64{
+				ids.GenerateTestShortID(): uint64(i + 1),
+				ids.GenerateTestShortID(): uint64(2*i + 1),
+			},
+			Commission: uint64(i) * 10_000,
+		}
+	}
+	config := Config{Pool: 1_000_003}
+
+	expected, err := Compute(config, inputs)
+	require.NoError(err)
+	require.Equal(config.Pool, expected.Distributed+expected.Remainder)
+
+	reversed := make([]ValidatorInput, len(inputs))
+	for i, input := range inputs {
+		reversed[len(inputs)-1-i] = input
+	}
+	actual, err := Compute(config, reversed)
+	require.NoError(err)
+	require.Equal(expected, actual)
+}
+
+// TestComputeInvalidInputs tests that invalid rates and intervals are rejected
+func TestComputeInvalidInputs(t *testing.T) {
+	_, err := Compute(Config{MinUptime: PercentDenominator + 1}, nil)
+	require.ErrorIs(t, err, ErrInvalidRate)
+
+	_, err = Compute(Config{}, []ValidatorInput{{Commission: PercentDenominator + 1}})
+	require.ErrorIs(t, err, ErrInvalidRate)
+
+	_, err = Compute(Config{}, []ValidatorInput{{
+		Interval: Interval{Start: testInterval.End, End: testInterval.Start},
+	}})
+	require.ErrorIs(t, err, ErrInvalidInterval)
+}
+
+// TestCollectInputs tests building inputs from a manager with delegations
+func TestCollectInputs(t *testing.T) {
+	require := require.New(t)
+
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	delegatorID := ids.GenerateTestShortID()
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+	require.NoError(m.AddDelegator(netID, nodeID, delegatorID, 50))
+
+	inputs, err := CollectInputs(m, m, uptime.NoOpCalculator{}, netID, testInterval, func(ids.NodeID) uint64 {
+		return 20_000
+	})
+	require.NoError(err)
+	require.Equal([]ValidatorInput{{
+		NodeID:      nodeID,
+		Interval:    testInterval,
+		SelfStake:   100,
+		Delegations: map[ids.ShortID]uint64{delegatorID: 50},
+		Commission:  20_000,
+	}}, inputs)
+}
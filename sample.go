@@ -0,0 +1,93 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNegativeSampleSize     = errors.New("negative sample size")
+	ErrInsufficientValidators = errors.New("sample size exceeds validator count")
+	ErrUnknownSampleMode      = errors.New("unknown sample mode")
+)
+
+// SampleMode selects what sampling more validators than a set has does. Every
+// Set returned by a manager, and Manager.Sample, follow the same contract:
+//
+//   - A negative size returns ErrNegativeSampleSize.
+//   - A size up to the number of validators returns that many distinct
+//     validators.
+//   - A larger size, including any positive size of an empty set, is handled
+//     by the mode.
+type SampleMode uint8
+
+const (
+	// SampleTruncate returns every validator when more are requested, so an
+	// empty set samples as empty. It is the default.
+	SampleTruncate SampleMode = iota
+	// SampleStrict returns ErrInsufficientValidators when more validators are
+	// requested than the set has
+	SampleStrict
+)
+
+// String implements fmt.Stringer
+func (s SampleMode) String() string {
+	switch s {
+	case SampleTruncate:
+		return "truncate"
+	case SampleStrict:
+		return "strict"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(s))
+	}
+}
+
+// SampleModeManager configures the sample mode of the sets a manager returns
+// and of its Sample
+type SampleModeManager interface {
+	SetSampleMode(mode SampleMode) error
+	GetSampleMode() SampleMode
+}
+
+var _ SampleModeManager = (*manager)(nil)
+
+// SetSampleMode sets the sample mode of the manager. Sets already returned
+// keep the mode they were returned with.
+func (m *manager) SetSampleMode(mode SampleMode) error {
+	if mode > SampleStrict {
+		return fmt.Errorf("%w: %s", ErrUnknownSampleMode, mode)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sampleMode = mode
+	return nil
+}
+
+// GetSampleMode returns the sample mode of the manager
+func (m *manager) GetSampleMode() SampleMode {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.sampleMode
+}
+
+// sampleSize returns the number of validators a sample of [size] out of
+// [available] validators takes under [mode], or an error if it can't be
+// taken
+func sampleSize(mode SampleMode, size int, available int) (int, error) {
+	switch {
+	case size < 0:
+		return 0, fmt.Errorf("%w: %d", ErrNegativeSampleSize, size)
+	case size <= available:
+		return size, nil
+	case mode == SampleStrict:
+		return 0, fmt.Errorf("%w: %d > %d", ErrInsufficientValidators, size, available)
+	default:
+		return available, nil
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSampleContract tests that every Set and Manager.Sample handle sizes the
+// same way under each sample mode
+func TestSampleContract(t *testing.T) {
+	tests := []struct {
+		mode        SampleMode
+		validators  int
+		size        int
+		expectedLen int
+		expectedErr error
+	}{
+		{mode: SampleTruncate, validators: 0, size: 0},
+		{mode: SampleTruncate, validators: 0, size: 3},
+		{mode: SampleTruncate, validators: 2, size: 1, expectedLen: 1},
+		{mode: SampleTruncate, validators: 2, size: 3, expectedLen: 2},
+		{mode: SampleTruncate, validators: 2, size: -1, expectedErr: ErrNegativeSampleSize},
+		{mode: SampleTruncate, validators: 0, size: -1, expectedErr: ErrNegativeSampleSize},
+		{mode: SampleStrict, validators: 0, size: 0},
+		{mode: SampleStrict, validators: 0, size: 3, expectedErr: ErrInsufficientValidators},
+		{mode: SampleStrict, validators: 2, size: 2, expectedLen: 2},
+		{mode: SampleStrict, validators: 2, size: 3, expectedErr: ErrInsufficientValidators},
+		{mode: SampleStrict, validators: 2, size: -1, expectedErr: ErrNegativeSampleSize},
+	}
+	for _, test := range tests {
+		t.Run(test.mode.String(), func(t *testing.T) {
+			require := require.New(t)
+
+			m := NewManager()
+			require.NoError(m.SetSampleMode(test.mode))
+			require.Equal(test.mode, m.GetSampleMode())
+
+			netID := ids.GenerateTestID()
+			for range test.validators {
+				require.NoError(m.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 100))
+			}
+
+			set, err := m.GetValidators(netID)
+			require.NoError(err)
+			sample, err := set.Sample(test.size)
+			require.ErrorIs(err, test.expectedErr)
+			require.Len(sample, test.expectedLen)
+
+			sample, err = m.Sample(netID, test.size)
+			require.ErrorIs(err, test.expectedErr)
+			require.Len(sample, test.expectedLen)
+		})
+	}
+}
+
+// TestSetSampleMode tests that sets keep the mode they were returned with
+func TestSetSampleMode(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	set, err := m.GetValidators(netID)
+	require.NoError(err)
+
+	require.NoError(m.SetSampleMode(SampleStrict))
+	_, err = set.Sample(1)
+	require.NoError(err)
+
+	set, err = m.GetValidators(netID)
+	require.NoError(err)
+	_, err = set.Sample(1)
+	require.ErrorIs(err, ErrInsufficientValidators)
+
+	// Snapshots always truncate
+	_, err = m.Snapshot(netID).Set().Sample(1)
+	require.NoError(err)
+
+	require.ErrorIs(m.SetSampleMode(SampleStrict+1), ErrUnknownSampleMode)
+}
@@ -0,0 +1,152 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/luxfi/ids"
+)
+
+// SelfListener listens to the local node joining and leaving validator sets
+type SelfListener interface {
+	OnSelfJoined(netID ids.ID, light uint64)
+	OnSelfLeft(netID ids.ID, light uint64)
+}
+
+// SelfManager knows which node is the local one, so embedding nodes can ask
+// whether they validate a net without tracking their own ID everywhere
+type SelfManager interface {
+	// SetSelfNodeID sets the local node. Listeners are told the nets a
+	// previous local node left and the nets the new one already validates.
+	SetSelfNodeID(nodeID ids.NodeID)
+	// SelfNodeID returns the local node, and false if it wasn't set
+	SelfNodeID() (ids.NodeID, bool)
+	// IsSelfValidator returns true if the local node validates [netID]
+	IsSelfValidator(netID ids.ID) bool
+	// SelfWeight returns the light of the local node in [netID]
+	SelfWeight(netID ids.ID) uint64
+	// RegisterSelfListener registers [listener] and tells it the nets the
+	// local node validates
+	RegisterSelfListener(listener SelfListener)
+}
+
+var _ SelfManager = (*manager)(nil)
+
+// selfTracker forwards the changes of the local node to the self listeners.
+// Notifications are dispatched with the manager lock held.
+type selfTracker struct {
+	nodeID     ids.NodeID
+	set        bool
+	registered bool
+	listeners  []SelfListener
+}
+
+// SetSelfNodeID sets the local node of the manager
+func (m *manager) SetSelfNodeID(nodeID ids.NodeID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := m.self
+	if s.set && s.nodeID == nodeID {
+		return
+	}
+	if s.set {
+		for _, netID := range m.selfNets() {
+			light := m.validators[netID][s.nodeID].Light
+			for _, listener := range s.listeners {
+				listener.OnSelfLeft(netID, light)
+			}
+		}
+	}
+	s.nodeID = nodeID
+	s.set = true
+	if !s.registered {
+		s.registered = true
+		m.listeners.add(s)
+	}
+	for _, netID := range m.selfNets() {
+		light := m.validators[netID][nodeID].Light
+		for _, listener := range s.listeners {
+			listener.OnSelfJoined(netID, light)
+		}
+	}
+}
+
+// SelfNodeID returns the local node of the manager
+func (m *manager) SelfNodeID() (ids.NodeID, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.self.nodeID, m.self.set
+}
+
+// IsSelfValidator returns true if the local node validates a net
+func (m *manager) IsSelfValidator(netID ids.ID) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.self.set {
+		return false
+	}
+	_, ok := m.validators[netID][m.self.nodeID]
+	return ok
+}
+
+// SelfWeight returns the light of the local node in a net
+func (m *manager) SelfWeight(netID ids.ID) uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.self.set {
+		return 0
+	}
+	if val, ok := m.validators[netID][m.self.nodeID]; ok {
+		return val.Light
+	}
+	return 0
+}
+
+// RegisterSelfListener registers a listener of the local node
+func (m *manager) RegisterSelfListener(listener SelfListener) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.self.listeners = append(m.self.listeners, listener)
+	if !m.self.set {
+		return
+	}
+	for _, netID := range m.selfNets() {
+		listener.OnSelfJoined(netID, m.validators[netID][m.self.nodeID].Light)
+	}
+}
+
+// selfNets returns the nets the local node validates, sorted. It assumes the
+// lock is held.
+func (m *manager) selfNets() []ids.ID {
+	netIDs := slices.Collect(maps.Keys(m.memberships[m.self.nodeID]))
+	slices.SortFunc(netIDs, ids.ID.Compare)
+	return netIDs
+}
+
+func (s *selfTracker) OnValidatorAdded(netID ids.ID, nodeID ids.NodeID, light uint64) {
+	if nodeID != s.nodeID {
+		return
+	}
+	for _, listener := range s.listeners {
+		listener.OnSelfJoined(netID, light)
+	}
+}
+
+func (s *selfTracker) OnValidatorRemoved(netID ids.ID, nodeID ids.NodeID, light uint64) {
+	if nodeID != s.nodeID {
+		return
+	}
+	for _, listener := range s.listeners {
+		listener.OnSelfLeft(netID, light)
+	}
+}
+
+func (*selfTracker) OnValidatorLightChanged(ids.ID, ids.NodeID, uint64, uint64) {}
@@ -0,0 +1,87 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+type selfEvent struct {
+	netID  ids.ID
+	light  uint64
+	joined bool
+}
+
+type testSelfListener struct {
+	events []selfEvent
+}
+
+func (l *testSelfListener) OnSelfJoined(netID ids.ID, light uint64) {
+	l.events = append(l.events, selfEvent{netID: netID, light: light, joined: true})
+}
+
+func (l *testSelfListener) OnSelfLeft(netID ids.ID, light uint64) {
+	l.events = append(l.events, selfEvent{netID: netID, light: light})
+}
+
+// TestManagerSelf tests that the local node's membership is reported as it
+// changes
+func TestManagerSelf(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		m       = NewManager()
+		netID1  = ids.GenerateTestID()
+		netID2  = ids.GenerateTestID()
+		self    = ids.GenerateTestNodeID()
+		other   = ids.GenerateTestNodeID()
+		early   = &testSelfListener{}
+		late    = &testSelfListener{}
+		_, isOK = m.SelfNodeID()
+	)
+	require.False(isOK)
+	require.False(m.IsSelfValidator(netID1))
+
+	require.NoError(m.AddStaker(netID1, self, nil, ids.Empty, 100))
+	m.RegisterSelfListener(early)
+	require.Empty(early.events)
+
+	m.SetSelfNodeID(self)
+	nodeID, ok := m.SelfNodeID()
+	require.True(ok)
+	require.Equal(self, nodeID)
+	require.True(m.IsSelfValidator(netID1))
+	require.False(m.IsSelfValidator(netID2))
+	require.Equal(uint64(100), m.SelfWeight(netID1))
+	require.Equal([]selfEvent{{netID: netID1, light: 100, joined: true}}, early.events)
+
+	m.RegisterSelfListener(late)
+	require.Equal(early.events, late.events)
+
+	// Other nodes and light changes aren't reported
+	require.NoError(m.AddStaker(netID2, other, nil, ids.Empty, 1))
+	require.NoError(m.AddWeight(netID1, self, 50))
+	require.Equal(uint64(150), m.SelfWeight(netID1))
+	require.Len(late.events, 1)
+
+	require.NoError(m.AddStaker(netID2, self, nil, ids.Empty, 10))
+	require.NoError(m.RemoveStaker(netID1, self))
+	require.Equal([]selfEvent{
+		{netID: netID1, light: 100, joined: true},
+		{netID: netID2, light: 10, joined: true},
+		{netID: netID1, light: 150},
+	}, late.events)
+	require.False(m.IsSelfValidator(netID1))
+	require.Zero(m.SelfWeight(netID1))
+
+	// Switching the local node leaves the old node's nets
+	m.SetSelfNodeID(other)
+	require.Equal([]selfEvent{
+		{netID: netID2, light: 10},
+		{netID: netID2, light: 1, joined: true},
+	}, late.events[3:])
+}
@@ -0,0 +1,152 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math"
+)
+
+var ErrUnknownReconcile = errors.New("unknown weight reconciliation")
+
+// Reconcile selects the weight of a validator present in both sets combined
+// by Intersect. It applies to Light and Weight independently.
+type Reconcile uint8
+
+const (
+	// ReconcileMin keeps the smaller weight, so the result never credits a
+	// validator with more than either set does
+	ReconcileMin Reconcile = iota
+	// ReconcileMax keeps the larger weight
+	ReconcileMax
+	// ReconcileLeft keeps the weight of the first set
+	ReconcileLeft
+	// ReconcileRight keeps the weight of the second set
+	ReconcileRight
+	// ReconcileSum adds the weights, such as when a validator stakes on both
+	// nets
+	ReconcileSum
+)
+
+// String implements fmt.Stringer
+func (r Reconcile) String() string {
+	switch r {
+	case ReconcileMin:
+		return "min"
+	case ReconcileMax:
+		return "max"
+	case ReconcileLeft:
+		return "left"
+	case ReconcileRight:
+		return "right"
+	case ReconcileSum:
+		return "sum"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(r))
+	}
+}
+
+// apply returns the reconciled weight of [a] and [b]
+func (r Reconcile) apply(a, b uint64) (uint64, error) {
+	switch r {
+	case ReconcileMin:
+		return min(a, b), nil
+	case ReconcileMax:
+		return max(a, b), nil
+	case ReconcileLeft:
+		return a, nil
+	case ReconcileRight:
+		return b, nil
+	case ReconcileSum:
+		sum, err := math.Add64(a, b)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+		}
+		return sum, nil
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrUnknownReconcile, r)
+	}
+}
+
+// Intersect returns the validators present in both [a] and [b], such as the
+// validators shared by two nets. Records are copied from [a], with Light and
+// Weight reconciled by [reconcile]. The inputs aren't modified.
+func Intersect(a, b map[ids.NodeID]*GetValidatorOutput, reconcile Reconcile) (map[ids.NodeID]*GetValidatorOutput, error) {
+	if _, err := reconcile.apply(0, 0); err != nil {
+		return nil, err
+	}
+
+	result := make(map[ids.NodeID]*GetValidatorOutput)
+	for nodeID, left := range a {
+		right, ok := b[nodeID]
+		if !ok {
+			continue
+		}
+		light, err := reconcile.apply(left.Light, right.Light)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't reconcile light of %s: %w", nodeID, err)
+		}
+		weight, err := reconcile.apply(left.Weight, right.Weight)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't reconcile weight of %s: %w", nodeID, err)
+		}
+
+		val := copyValidator(left)
+		val.Light = light
+		val.Weight = weight
+		result[nodeID] = val
+	}
+	return result, nil
+}
+
+// Difference returns copies of the validators of [a] that aren't in [b],
+// such as the validators a local set has that a remote set is missing. The
+// inputs aren't modified.
+func Difference(a, b map[ids.NodeID]*GetValidatorOutput) map[ids.NodeID]*GetValidatorOutput {
+	result := make(map[ids.NodeID]*GetValidatorOutput)
+	for nodeID, val := range a {
+		if _, ok := b[nodeID]; !ok {
+			result[nodeID] = copyValidator(val)
+		}
+	}
+	return result
+}
+
+// WeightMismatch is a validator whose weight differs between two sets
+type WeightMismatch struct {
+	NodeID      ids.NodeID
+	LeftLight   uint64
+	RightLight  uint64
+	LeftWeight  uint64
+	RightWeight uint64
+}
+
+// WeightMismatches returns the validators present in both [a] and [b] whose
+// Light or Weight differ, sorted by node ID, for comparing a local set
+// against a remote one
+func WeightMismatches(a, b map[ids.NodeID]*GetValidatorOutput) []WeightMismatch {
+	var nodeIDs []ids.NodeID
+	for nodeID, left := range a {
+		right, ok := b[nodeID]
+		if ok && (left.Light != right.Light || left.Weight != right.Weight) {
+			nodeIDs = append(nodeIDs, nodeID)
+		}
+	}
+
+	mismatches := make([]WeightMismatch, len(nodeIDs))
+	for i, nodeID := range sortNodeIDs(nodeIDs) {
+		left, right := a[nodeID], b[nodeID]
+		mismatches[i] = WeightMismatch{
+			NodeID:      nodeID,
+			LeftLight:   left.Light,
+			RightLight:  right.Light,
+			LeftWeight:  left.Weight,
+			RightWeight: right.Weight,
+		}
+	}
+	return mismatches
+}
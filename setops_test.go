@@ -0,0 +1,130 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"math"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+func testRecord(nodeID ids.NodeID, light, weight uint64) *GetValidatorOutput {
+	return &GetValidatorOutput{NodeID: nodeID, Light: light, Weight: weight}
+}
+
+// TestIntersect tests that shared validators are reconciled by each policy
+func TestIntersect(t *testing.T) {
+	var (
+		shared    = ids.GenerateTestNodeID()
+		leftOnly  = ids.GenerateTestNodeID()
+		rightOnly = ids.GenerateTestNodeID()
+		left      = map[ids.NodeID]*GetValidatorOutput{
+			shared:   testRecord(shared, 10, 100),
+			leftOnly: testRecord(leftOnly, 1, 1),
+		}
+		right = map[ids.NodeID]*GetValidatorOutput{
+			shared:    testRecord(shared, 30, 50),
+			rightOnly: testRecord(rightOnly, 1, 1),
+		}
+	)
+
+	tests := []struct {
+		reconcile      Reconcile
+		expectedLight  uint64
+		expectedWeight uint64
+	}{
+		{ReconcileMin, 10, 50},
+		{ReconcileMax, 30, 100},
+		{ReconcileLeft, 10, 100},
+		{ReconcileRight, 30, 50},
+		{ReconcileSum, 40, 150},
+	}
+	for _, test := range tests {
+		t.Run(test.reconcile.String(), func(t *testing.T) {
+			require := require.New(t)
+
+			result, err := Intersect(left, right, test.reconcile)
+			require.NoError(err)
+			require.Len(result, 1)
+			require.Equal(testRecord(shared, test.expectedLight, test.expectedWeight), result[shared])
+
+			// The inputs are unchanged
+			require.Equal(uint64(10), left[shared].Light)
+			require.Equal(uint64(30), right[shared].Light)
+		})
+	}
+}
+
+// TestIntersectErrors tests that unknown policies and overflows are rejected
+func TestIntersectErrors(t *testing.T) {
+	require := require.New(t)
+
+	nodeID := ids.GenerateTestNodeID()
+	set := map[ids.NodeID]*GetValidatorOutput{
+		nodeID: testRecord(nodeID, math.MaxUint64, 1),
+	}
+
+	_, err := Intersect(set, set, Reconcile(math.MaxUint8))
+	require.ErrorIs(err, ErrUnknownReconcile)
+
+	_, err = Intersect(set, set, ReconcileSum)
+	require.ErrorIs(err, ErrWeightOverflow)
+}
+
+// TestDifference tests that only validators missing from the second set are
+// kept
+func TestDifference(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		shared   = ids.GenerateTestNodeID()
+		leftOnly = ids.GenerateTestNodeID()
+		left     = map[ids.NodeID]*GetValidatorOutput{
+			shared:   testRecord(shared, 10, 10),
+			leftOnly: testRecord(leftOnly, 1, 1),
+		}
+		right = map[ids.NodeID]*GetValidatorOutput{
+			shared: testRecord(shared, 30, 30),
+		}
+	)
+
+	result := Difference(left, right)
+	require.Equal(map[ids.NodeID]*GetValidatorOutput{
+		leftOnly: testRecord(leftOnly, 1, 1),
+	}, result)
+	require.NotSame(left[leftOnly], result[leftOnly])
+	require.Empty(Difference(right, left))
+}
+
+// TestWeightMismatches tests that shared validators with differing weights
+// are reported
+func TestWeightMismatches(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		same     = ids.GenerateTestNodeID()
+		changed  = ids.GenerateTestNodeID()
+		leftOnly = ids.GenerateTestNodeID()
+		left     = map[ids.NodeID]*GetValidatorOutput{
+			same:     testRecord(same, 10, 10),
+			changed:  testRecord(changed, 10, 20),
+			leftOnly: testRecord(leftOnly, 1, 1),
+		}
+		right = map[ids.NodeID]*GetValidatorOutput{
+			same:    testRecord(same, 10, 10),
+			changed: testRecord(changed, 10, 25),
+		}
+	)
+
+	require.Equal([]WeightMismatch{{
+		NodeID:      changed,
+		LeftLight:   10,
+		RightLight:  10,
+		LeftWeight:  20,
+		RightWeight: 25,
+	}}, WeightMismatches(left, right))
+	require.Empty(WeightMismatches(left, left))
+}
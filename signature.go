@@ -0,0 +1,200 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/math/set"
+)
+
+var (
+	ErrNoSigners             = errors.New("no signers")
+	ErrInvalidAggregateKey   = errors.New("couldn't aggregate signer keys")
+	ErrMissingSignature      = errors.New("missing aggregate signature")
+	ErrSignatureVerification = errors.New("aggregate signature doesn't verify")
+	ErrMissingRingtailKey    = errors.New("validator has no Ringtail key")
+)
+
+// VerifySignature verifies that the validators of [vdrSet] marked in
+// [signerBits] signed [msg] with [aggregateSig], and that they hold at least
+// [quorumNum]/[quorumDen] of the total weight of the set. Each failure has
+// its own error:
+//
+//   - ErrValidatorNotFound if a bit marks a validator the set doesn't have
+//   - ErrNoSigners if no bit is set
+//   - ErrWeightOverflow if the signers' weight overflows
+//   - ErrInvalidQuorum, ErrInvalidWeight or ErrInsufficientWeight from
+//     VerifyWeight
+//   - ErrInvalidAggregateKey if the signers' keys don't aggregate
+//   - ErrMissingSignature if [aggregateSig] is nil
+//   - ErrSignatureVerification if the signature doesn't verify
+//
+// The threshold is checked before the keys are aggregated, so signatures
+// without a quorum are rejected without any pairing.
+func VerifySignature(
+	vdrSet CanonicalValidatorSet,
+	signerBits set.Bits,
+	aggregateSig *bls.Signature,
+	msg []byte,
+	quorumNum uint64,
+	quorumDen uint64,
+) error {
+	signers, err := quorumSigners(vdrSet, signerBits, quorumNum, quorumDen)
+	if err != nil {
+		return err
+	}
+	return verifyBLS(signers, aggregateSig, msg)
+}
+
+// quorumSigners returns the validators of [vdrSet] marked in [signerBits] if
+// they hold at least [quorumNum]/[quorumDen] of its weight
+func quorumSigners(vdrSet CanonicalValidatorSet, signerBits set.Bits, quorumNum, quorumDen uint64) ([]*CanonicalValidator, error) {
+	signers, err := FilterValidators(signerBits, vdrSet.Validators)
+	if err != nil {
+		return nil, err
+	}
+	if len(signers) == 0 {
+		return nil, ErrNoSigners
+	}
+	signedWeight, err := SumWeight(signers)
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifyWeight(signedWeight, vdrSet.TotalWeight, quorumNum, quorumDen); err != nil {
+		return nil, err
+	}
+	return signers, nil
+}
+
+// verifyBLS verifies that [signers] signed [msg] with [aggregateSig]
+func verifyBLS(signers []*CanonicalValidator, aggregateSig *bls.Signature, msg []byte) error {
+	aggregateKey, err := AggregatePublicKeys(signers)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAggregateKey, err)
+	}
+	if aggregateSig == nil {
+		return ErrMissingSignature
+	}
+	if !bls.Verify(aggregateKey, aggregateSig, msg) {
+		return ErrSignatureVerification
+	}
+	return nil
+}
+
+// RingtailScheme aggregates and verifies Ringtail keys and signatures. This
+// package treats Ringtail keys as opaque bytes, so the scheme is supplied by
+// the caller.
+type RingtailScheme interface {
+	// AggregatePublicKeys returns the aggregate of [keys]
+	AggregatePublicKeys(keys [][]byte) ([]byte, error)
+	// Verify returns true if [signature] is a signature of [msg] by
+	// [aggregateKey]
+	Verify(aggregateKey, signature, msg []byte) bool
+}
+
+// AggregateRingtailKeys returns the aggregate Ringtail key of [vdrs], which
+// must all carry one, such as the validators of a set flattened by
+// FlattenHybridValidatorSet
+func AggregateRingtailKeys(scheme RingtailScheme, vdrs []*CanonicalValidator) ([]byte, error) {
+	keys := make([][]byte, len(vdrs))
+	for i, vdr := range vdrs {
+		if len(vdr.RingtailPubKey) == 0 {
+			return nil, fmt.Errorf("%w: %v", ErrMissingRingtailKey, vdr.NodeIDs)
+		}
+		keys[i] = vdr.RingtailPubKey
+	}
+	aggregateKey, err := scheme.AggregatePublicKeys(keys)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAggregateKey, err)
+	}
+	return aggregateKey, nil
+}
+
+// SignatureScheme is a scheme of a hybrid signature
+type SignatureScheme uint8
+
+const (
+	SchemeBLS SignatureScheme = iota + 1
+	SchemeRingtail
+)
+
+// String implements fmt.Stringer
+func (s SignatureScheme) String() string {
+	switch s {
+	case SchemeBLS:
+		return "bls"
+	case SchemeRingtail:
+		return "ringtail"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(s))
+	}
+}
+
+// HybridSignature is a message signed by the same signers with both BLS and
+// Ringtail
+type HybridSignature struct {
+	BLS      *bls.Signature
+	Ringtail []byte
+}
+
+// SchemeError is returned by VerifyHybridSignature when one of the schemes
+// fails. It wraps the error of the scheme, such as ErrSignatureVerification.
+type SchemeError struct {
+	Scheme SignatureScheme
+	Err    error
+}
+
+func (e *SchemeError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Scheme, e.Err)
+}
+
+func (e *SchemeError) Unwrap() error {
+	return e.Err
+}
+
+// VerifyHybridSignature is VerifySignature for hybrid signatures over a set
+// flattened by FlattenHybridValidatorSet: the signers marked in [signerBits]
+// must hold the quorum, and both the BLS and the Ringtail aggregate must
+// verify. Failures of either scheme are returned as a *SchemeError naming
+// it; BLS is checked first. Failures before either scheme is checked, such
+// as a missing quorum, are returned as they are by VerifySignature.
+func VerifyHybridSignature(
+	scheme RingtailScheme,
+	vdrSet CanonicalValidatorSet,
+	signerBits set.Bits,
+	sig HybridSignature,
+	msg []byte,
+	quorumNum uint64,
+	quorumDen uint64,
+) error {
+	signers, err := quorumSigners(vdrSet, signerBits, quorumNum, quorumDen)
+	if err != nil {
+		return err
+	}
+	if err := verifyBLS(signers, sig.BLS, msg); err != nil {
+		return &SchemeError{Scheme: SchemeBLS, Err: err}
+	}
+	if err := verifyRingtail(scheme, signers, sig.Ringtail, msg); err != nil {
+		return &SchemeError{Scheme: SchemeRingtail, Err: err}
+	}
+	return nil
+}
+
+// verifyRingtail verifies that [signers] signed [msg] with [signature]
+func verifyRingtail(scheme RingtailScheme, signers []*CanonicalValidator, signature, msg []byte) error {
+	aggregateKey, err := AggregateRingtailKeys(scheme, signers)
+	if err != nil {
+		return err
+	}
+	if len(signature) == 0 {
+		return ErrMissingSignature
+	}
+	if !scheme.Verify(aggregateKey, signature, msg) {
+		return ErrSignatureVerification
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math/set"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/validators/validatorstest/fixture"
+)
+
+// TestVerifySignature tests each failure mode of signature verification
+func TestVerifySignature(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		sks  = make(map[string]*bls.SecretKey)
+		vdrs = make(map[ids.NodeID]*GetValidatorOutput)
+	)
+	generated, err := fixture.Generate(1, 3, fixture.ConstantWeights(100))
+	require.NoError(err)
+	for _, vdr := range generated {
+		sks[string(bls.PublicKeyToUncompressedBytes(vdr.PublicKey))] = vdr.SecretKey
+		vdrs[vdr.NodeID] = &GetValidatorOutput{
+			NodeID:    vdr.NodeID,
+			PublicKey: vdr.PublicKeyBytes,
+			Light:     vdr.Weight,
+			Weight:    vdr.Weight,
+		}
+	}
+	vdrSet, err := FlattenValidatorSet(vdrs)
+	require.NoError(err)
+
+	msg := []byte("warp message")
+	sign := func(msg []byte, indices ...int) *bls.Signature {
+		sigs := make([]*bls.Signature, len(indices))
+		for i, index := range indices {
+			sig, err := sks[string(vdrSet.Validators[index].PublicKeyBytes)].Sign(msg)
+			require.NoError(err)
+			sigs[i] = sig
+		}
+		sig, err := bls.AggregateSignatures(sigs)
+		require.NoError(err)
+		return sig
+	}
+
+	tests := []struct {
+		name        string
+		signers     set.Bits
+		sig         *bls.Signature
+		quorumNum   uint64
+		quorumDen   uint64
+		expectedErr error
+	}{
+		{
+			name:      "valid",
+			signers:   set.NewBits(0, 1),
+			sig:       sign(msg, 0, 1),
+			quorumNum: 2,
+			quorumDen: 3,
+		},
+		{
+			name:        "unknown signer",
+			signers:     set.NewBits(3),
+			sig:         sign(msg, 0),
+			quorumNum:   2,
+			quorumDen:   3,
+			expectedErr: ErrValidatorNotFound,
+		},
+		{
+			name:        "no signers",
+			signers:     set.NewBits(),
+			sig:         sign(msg, 0),
+			quorumNum:   2,
+			quorumDen:   3,
+			expectedErr: ErrNoSigners,
+		},
+		{
+			name:        "invalid quorum",
+			signers:     set.NewBits(0, 1),
+			sig:         sign(msg, 0, 1),
+			quorumNum:   2,
+			expectedErr: ErrInvalidQuorum,
+		},
+		{
+			name:        "insufficient weight",
+			signers:     set.NewBits(0),
+			sig:         sign(msg, 0),
+			quorumNum:   2,
+			quorumDen:   3,
+			expectedErr: ErrInsufficientWeight,
+		},
+		{
+			name:        "missing signature",
+			signers:     set.NewBits(0, 1),
+			quorumNum:   2,
+			quorumDen:   3,
+			expectedErr: ErrMissingSignature,
+		},
+		{
+			name:        "wrong signers",
+			signers:     set.NewBits(0, 1),
+			sig:         sign(msg, 0, 2),
+			quorumNum:   2,
+			quorumDen:   3,
+			expectedErr: ErrSignatureVerification,
+		},
+		{
+			name:        "wrong message",
+			signers:     set.NewBits(0, 1),
+			sig:         sign([]byte("other message"), 0, 1),
+			quorumNum:   2,
+			quorumDen:   3,
+			expectedErr: ErrSignatureVerification,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := VerifySignature(vdrSet, test.signers, test.sig, msg, test.quorumNum, test.quorumDen)
+			require.ErrorIs(err, test.expectedErr)
+		})
+	}
+}
+
+// xorScheme is a RingtailScheme whose aggregate key is the XOR of the keys
+// and whose signatures are hashes of the key and message
+type xorScheme struct{}
+
+func (xorScheme) AggregatePublicKeys(keys [][]byte) ([]byte, error) {
+	aggregate := make([]byte, sha256.Size)
+	for _, key := range keys {
+		if len(key) != sha256.Size {
+			return nil, errors.New("invalid key length")
+		}
+		for i := range aggregate {
+			aggregate[i] ^= key[i]
+		}
+	}
+	return aggregate, nil
+}
+
+func (xorScheme) Verify(aggregateKey, signature, msg []byte) bool {
+	digest := sha256.Sum256(append(slices.Clone(aggregateKey), msg...))
+	return bytes.Equal(digest[:], signature)
+}
+
+// TestVerifyHybridSignature tests that hybrid verification reports the
+// scheme that failed
+func TestVerifyHybridSignature(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		sks    = make(map[string]*bls.SecretKey)
+		vdrs   = make(map[ids.NodeID]*GetValidatorOutput)
+		scheme = xorScheme{}
+	)
+	generated, err := fixture.Generate(1, 3, fixture.ConstantWeights(100), fixture.WithRingtailKeys())
+	require.NoError(err)
+	for _, vdr := range generated {
+		sks[string(bls.PublicKeyToUncompressedBytes(vdr.PublicKey))] = vdr.SecretKey
+		vdrs[vdr.NodeID] = &GetValidatorOutput{
+			NodeID:         vdr.NodeID,
+			PublicKey:      vdr.PublicKeyBytes,
+			RingtailPubKey: vdr.RingtailPubKey,
+			Weight:         vdr.Weight,
+		}
+	}
+	vdrSet, err := FlattenHybridValidatorSet(vdrs, EconomicWeight)
+	require.NoError(err)
+
+	msg := []byte("warp message")
+	sign := func(msg []byte, indices ...int) HybridSignature {
+		var (
+			blsSigs = make([]*bls.Signature, len(indices))
+			signers = make([]*CanonicalValidator, len(indices))
+		)
+		for i, index := range indices {
+			vdr := vdrSet.Validators[index]
+			sig, err := sks[string(vdr.PublicKeyBytes)].Sign(msg)
+			require.NoError(err)
+			blsSigs[i] = sig
+			signers[i] = vdr
+		}
+		blsSig, err := bls.AggregateSignatures(blsSigs)
+		require.NoError(err)
+		ringtailKey, err := AggregateRingtailKeys(scheme, signers)
+		require.NoError(err)
+		digest := sha256.Sum256(append(ringtailKey, msg...))
+		return HybridSignature{BLS: blsSig, Ringtail: digest[:]}
+	}
+
+	signers := set.NewBits(0, 1)
+	require.NoError(VerifyHybridSignature(scheme, vdrSet, signers, sign(msg, 0, 1), msg, 2, 3))
+
+	// A quorum failure isn't attributed to a scheme
+	err = VerifyHybridSignature(scheme, vdrSet, set.NewBits(0), sign(msg, 0), msg, 2, 3)
+	require.ErrorIs(err, ErrInsufficientWeight)
+	var schemeErr *SchemeError
+	require.NotErrorAs(err, &schemeErr)
+
+	badBLS := sign(msg, 0, 1)
+	badBLS.BLS = sign([]byte("other message"), 0, 1).BLS
+	err = VerifyHybridSignature(scheme, vdrSet, signers, badBLS, msg, 2, 3)
+	require.ErrorIs(err, ErrSignatureVerification)
+	require.ErrorAs(err, &schemeErr)
+	require.Equal(SchemeBLS, schemeErr.Scheme)
+
+	badRingtail := sign(msg, 0, 1)
+	badRingtail.Ringtail = sign(msg, 0, 2).Ringtail
+	err = VerifyHybridSignature(scheme, vdrSet, signers, badRingtail, msg, 2, 3)
+	require.ErrorIs(err, ErrSignatureVerification)
+	require.ErrorAs(err, &schemeErr)
+	require.Equal(SchemeRingtail, schemeErr.Scheme)
+
+	missingRingtail := sign(msg, 0, 1)
+	missingRingtail.Ringtail = nil
+	err = VerifyHybridSignature(scheme, vdrSet, signers, missingRingtail, msg, 2, 3)
+	require.ErrorIs(err, ErrMissingSignature)
+
+	// BLS only sets have no Ringtail keys to aggregate
+	blsSet, err := FlattenValidatorSet(vdrs)
+	require.NoError(err)
+	_, err = AggregateRingtailKeys(scheme, blsSet.Validators)
+	require.ErrorIs(err, ErrMissingRingtailKey)
+}
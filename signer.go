@@ -0,0 +1,51 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"github.com/luxfi/ids"
+)
+
+// CanSign returns the index of [nodeID]'s validator in the canonical ordering,
+// which is its bit in a signer bitset, and the weight it signs with. The
+// weight includes every node sharing the validator's public key. ok is false
+// if [nodeID] isn't a validator with a valid public key.
+func (s *CanonicalValidatorSet) CanSign(nodeID ids.NodeID) (index int, weight uint64, ok bool) {
+	for i, vdr := range s.Validators {
+		for _, vdrNodeID := range vdr.NodeIDs {
+			if vdrNodeID == nodeID {
+				return i, vdr.Weight, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// CanSign is CanonicalValidatorSet.CanSign for the canonical ordering of the
+// Warp set. Nodes that aren't in the set are rejected without flattening it.
+func (s *WarpSet) CanSign(nodeID ids.NodeID) (index int, weight uint64, ok bool) {
+	if vdr, exists := s.Validators[nodeID]; !exists || len(vdr.PublicKey) == 0 {
+		return 0, 0, false
+	}
+
+	canonical, err := s.flatten()
+	if err != nil {
+		return 0, 0, false
+	}
+	return canonical.CanSign(nodeID)
+}
+
+// flatten returns the canonical ordering of the Warp set
+func (s *WarpSet) flatten() (CanonicalValidatorSet, error) {
+	vdrSet := make(map[ids.NodeID]*GetValidatorOutput, len(s.Validators))
+	for nodeID, vdr := range s.Validators {
+		vdrSet[nodeID] = &GetValidatorOutput{
+			NodeID:    nodeID,
+			PublicKey: vdr.PublicKey,
+			Light:     vdr.Weight,
+			Weight:    vdr.Weight,
+		}
+	}
+	return FlattenValidatorSet(vdrSet)
+}
@@ -0,0 +1,71 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/validators/validatorstest/fixture"
+)
+
+// TestCanSign tests signer eligibility against canonical and Warp sets
+func TestCanSign(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		vdrSet   = make(map[ids.NodeID]*GetValidatorOutput)
+		warpSet  = &WarpSet{Height: 1, Validators: make(map[ids.NodeID]*WarpValidator)}
+		nodeIDs  = make([]ids.NodeID, 4)
+		sharedPK []byte
+	)
+	generated, err := fixture.Generate(1, len(nodeIDs), fixture.ConstantWeights(1))
+	require.NoError(err)
+	for i := range nodeIDs {
+		nodeIDs[i] = generated[i].NodeID
+		pk := generated[i].PublicKeyBytes
+		switch i {
+		case 1:
+			sharedPK = pk
+		case 2:
+			// Shares its key with node 1
+			pk = sharedPK
+		case 3:
+			// No key
+			pk = nil
+		}
+		weight := uint64(100 * (i + 1))
+		vdrSet[nodeIDs[i]] = &GetValidatorOutput{NodeID: nodeIDs[i], PublicKey: pk, Light: weight, Weight: weight}
+		warpSet.Validators[nodeIDs[i]] = &WarpValidator{NodeID: nodeIDs[i], PublicKey: pk, Weight: weight}
+	}
+
+	canonical, err := FlattenValidatorSet(vdrSet)
+	require.NoError(err)
+	require.Len(canonical.Validators, 2)
+
+	for _, nodeID := range nodeIDs[:3] {
+		index, weight, ok := canonical.CanSign(nodeID)
+		require.True(ok)
+		require.Contains(canonical.Validators[index].NodeIDs, nodeID)
+		require.Equal(canonical.Validators[index].Weight, weight)
+
+		warpIndex, warpWeight, ok := warpSet.CanSign(nodeID)
+		require.True(ok)
+		require.Equal(index, warpIndex)
+		require.Equal(weight, warpWeight)
+	}
+
+	// Nodes sharing a key sign with their combined weight
+	_, weight, _ := canonical.CanSign(nodeIDs[1])
+	require.Equal(uint64(500), weight)
+
+	for _, nodeID := range []ids.NodeID{nodeIDs[3], ids.GenerateTestNodeID()} {
+		_, _, ok := canonical.CanSign(nodeID)
+		require.False(ok)
+		_, _, ok = warpSet.CanSign(nodeID)
+		require.False(ok)
+	}
+}
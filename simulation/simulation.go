@@ -0,0 +1,382 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package simulation evolves a synthetic validator set over time, driving a
+// Manager through joins, exits, weight drift and churn bursts for soak tests
+// and capacity planning
+package simulation
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/luxfi/ids"
+
+	validators "github.com/luxfi/validators"
+)
+
+var ErrInvalidConfig = errors.New("invalid simulation config")
+
+// Distribution draws values of a simulated quantity
+type Distribution interface {
+	Sample(r *rand.Rand) uint64
+}
+
+// Constant always draws Value
+type Constant struct {
+	Value uint64
+}
+
+// Sample implements Distribution
+func (c Constant) Sample(*rand.Rand) uint64 {
+	return c.Value
+}
+
+// Uniform draws from [Min, Max]
+type Uniform struct {
+	Min uint64
+	Max uint64
+}
+
+// Sample implements Distribution
+func (u Uniform) Sample(r *rand.Rand) uint64 {
+	if u.Max <= u.Min {
+		return u.Min
+	}
+	span := u.Max - u.Min
+	if span == math.MaxUint64 {
+		return r.Uint64()
+	}
+	return u.Min + r.Uint64N(span+1)
+}
+
+// Poisson draws the number of events of a Poisson process with Mean events,
+// such as the validators joining in a step
+type Poisson struct {
+	Mean float64
+}
+
+// Sample implements Distribution
+func (p Poisson) Sample(r *rand.Rand) uint64 {
+	if p.Mean <= 0 {
+		return 0
+	}
+	// Sum exponential inter-arrival times, which stays exact for large means
+	// unlike Knuth's product of uniforms
+	var (
+		n       uint64
+		elapsed = r.ExpFloat64()
+	)
+	for elapsed < p.Mean {
+		n++
+		elapsed += r.ExpFloat64()
+	}
+	return n
+}
+
+// Pareto draws from a Pareto distribution with scale Min and shape Alpha,
+// which models the heavy tail of stake sizes. Draws are capped at Max if it
+// is set.
+type Pareto struct {
+	Min   uint64
+	Alpha float64
+	Max   uint64
+}
+
+// Sample implements Distribution
+func (p Pareto) Sample(r *rand.Rand) uint64 {
+	if p.Alpha <= 0 {
+		return p.Min
+	}
+	// 1 - Float64() is in (0, 1], so the power is finite
+	value := float64(p.Min) / math.Pow(1-r.Float64(), 1/p.Alpha)
+	if p.Max > 0 && value >= float64(p.Max) {
+		return p.Max
+	}
+	if value >= math.MaxUint64 {
+		return math.MaxUint64
+	}
+	return uint64(value)
+}
+
+// Config configures a Simulation
+type Config struct {
+	// Seed makes the simulation reproducible
+	Seed  uint64
+	NetID ids.ID
+	// InitialValidators is the number of validators added by New
+	InitialValidators int
+	// MinValidators and MaxValidators bound the size of the set. Exits and
+	// joins that would cross them are skipped. A zero MaxValidators doesn't
+	// bound the size.
+	MinValidators int
+	MaxValidators int
+	// Stake is the light of joining validators. Draws below 1 join with 1.
+	Stake Distribution
+	// Joins and Exits are the number of validators that join and exit each
+	// step. Nil distributions don't join or exit anyone.
+	Joins Distribution
+	Exits Distribution
+	// Drift is the standard deviation of the relative change of each
+	// validator's light per step, such as 0.01 for 1%. Zero disables drift.
+	Drift float64
+	// BurstRate is the probability that a step has a churn burst, in which
+	// BurstFraction of the validators exit and as many new ones join
+	BurstRate     float64
+	BurstFraction float64
+}
+
+// Verify returns an error if the config can't be simulated
+func (c Config) Verify() error {
+	switch {
+	case c.Stake == nil:
+		return fmt.Errorf("%w: missing stake distribution", ErrInvalidConfig)
+	case c.MinValidators < 0:
+		return fmt.Errorf("%w: negative min validators %d", ErrInvalidConfig, c.MinValidators)
+	case c.MaxValidators < 0:
+		return fmt.Errorf("%w: negative max validators %d", ErrInvalidConfig, c.MaxValidators)
+	case c.MaxValidators > 0 && c.MaxValidators < c.MinValidators:
+		return fmt.Errorf("%w: max validators %d < min validators %d", ErrInvalidConfig, c.MaxValidators, c.MinValidators)
+	case c.InitialValidators < c.MinValidators || (c.MaxValidators > 0 && c.InitialValidators > c.MaxValidators):
+		return fmt.Errorf("%w: %d initial validators are out of bounds", ErrInvalidConfig, c.InitialValidators)
+	case c.Drift < 0:
+		return fmt.Errorf("%w: negative drift %f", ErrInvalidConfig, c.Drift)
+	case c.BurstRate < 0 || c.BurstRate > 1:
+		return fmt.Errorf("%w: burst rate %f isn't a probability", ErrInvalidConfig, c.BurstRate)
+	case c.BurstFraction < 0 || c.BurstFraction > 1:
+		return fmt.Errorf("%w: burst fraction %f isn't a fraction", ErrInvalidConfig, c.BurstFraction)
+	}
+	return nil
+}
+
+// Stats summarizes what changed in one or more steps
+type Stats struct {
+	Steps   int
+	Joined  int
+	Exited  int
+	Drifted int
+	Bursts  int
+	// Validators and Light are the size of the set after the last step
+	Validators int
+	Light      uint64
+}
+
+// add accumulates [other] into [s], keeping the size of [other]
+func (s *Stats) add(other Stats) {
+	s.Steps += other.Steps
+	s.Joined += other.Joined
+	s.Exited += other.Exited
+	s.Drifted += other.Drifted
+	s.Bursts += other.Bursts
+	s.Validators = other.Validators
+	s.Light = other.Light
+}
+
+// Manager is a validator manager a Simulation can remove and reweigh
+// validators in
+type Manager interface {
+	validators.Manager
+	validators.StakerManager
+}
+
+// Simulation evolves the validators of a net in a Manager. Only validators
+// the simulation added are changed, so it can share a net with others.
+type Simulation struct {
+	manager Manager
+	config  Config
+	rng     *rand.Rand
+	// members are the validators the simulation added, in node ID order so
+	// draws don't depend on map iteration order
+	members []ids.NodeID
+}
+
+// New adds the initial validators to [manager] and returns the simulation
+func New(manager Manager, config Config) (*Simulation, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	s := &Simulation{
+		manager: manager,
+		config:  config,
+		rng:     rand.New(rand.NewPCG(config.Seed, config.Seed)),
+	}
+	for range config.InitialValidators {
+		if err := s.join(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Members returns the validators the simulation added that are still in the
+// net, in node ID order
+func (s *Simulation) Members() []ids.NodeID {
+	return slices.Clone(s.members)
+}
+
+// Step advances the simulation by one step: validators exit, the light of
+// the rest drifts, new validators join, and a churn burst may follow
+func (s *Simulation) Step() (Stats, error) {
+	s.prune()
+
+	var stats Stats
+	for range s.draw(s.config.Exits) {
+		exited, err := s.exit()
+		if err != nil {
+			return stats, err
+		}
+		if !exited {
+			break
+		}
+		stats.Exited++
+	}
+
+	if s.config.Drift > 0 {
+		for _, nodeID := range s.members {
+			drifted, err := s.drift(nodeID)
+			if err != nil {
+				return stats, err
+			}
+			if drifted {
+				stats.Drifted++
+			}
+		}
+	}
+
+	for range s.draw(s.config.Joins) {
+		if !s.canJoin() {
+			break
+		}
+		if err := s.join(); err != nil {
+			return stats, err
+		}
+		stats.Joined++
+	}
+
+	if s.config.BurstRate > 0 && s.rng.Float64() < s.config.BurstRate {
+		stats.Bursts++
+		size := int(float64(len(s.members)) * s.config.BurstFraction)
+		for range size {
+			exited, err := s.exit()
+			if err != nil {
+				return stats, err
+			}
+			if !exited {
+				break
+			}
+			stats.Exited++
+			if err := s.join(); err != nil {
+				return stats, err
+			}
+			stats.Joined++
+		}
+	}
+
+	stats.Steps = 1
+	stats.Validators = len(s.members)
+	for _, nodeID := range s.members {
+		stats.Light += s.manager.GetLight(s.config.NetID, nodeID)
+	}
+	return stats, nil
+}
+
+// Run advances the simulation by [steps] steps, calling [observe], if set,
+// after each one
+func (s *Simulation) Run(steps int, observe func(Stats) error) (Stats, error) {
+	var total Stats
+	for range steps {
+		stats, err := s.Step()
+		total.add(stats)
+		if err != nil {
+			return total, err
+		}
+		if observe == nil {
+			continue
+		}
+		if err := observe(stats); err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// prune forgets members that were removed by someone else
+func (s *Simulation) prune() {
+	s.members = slices.DeleteFunc(s.members, func(nodeID ids.NodeID) bool {
+		_, ok := s.manager.GetValidator(s.config.NetID, nodeID)
+		return !ok
+	})
+}
+
+// draw returns a count drawn from [d], or 0 if it is nil
+func (s *Simulation) draw(d Distribution) uint64 {
+	if d == nil {
+		return 0
+	}
+	return d.Sample(s.rng)
+}
+
+func (s *Simulation) canJoin() bool {
+	return s.config.MaxValidators == 0 || len(s.members) < s.config.MaxValidators
+}
+
+// join adds a validator with a random node ID and drawn stake
+func (s *Simulation) join() error {
+	var nodeID ids.NodeID
+	for {
+		for i := 0; i < len(nodeID); i += 8 {
+			var buf [8]byte
+			binary.BigEndian.PutUint64(buf[:], s.rng.Uint64())
+			copy(nodeID[i:], buf[:])
+		}
+		if _, ok := s.manager.GetValidator(s.config.NetID, nodeID); !ok {
+			break
+		}
+	}
+
+	light := max(s.config.Stake.Sample(s.rng), 1)
+	if err := s.manager.AddStaker(s.config.NetID, nodeID, nil, ids.Empty, light); err != nil {
+		return fmt.Errorf("couldn't add %s: %w", nodeID, err)
+	}
+	i, _ := slices.BinarySearchFunc(s.members, nodeID, ids.NodeID.Compare)
+	s.members = slices.Insert(s.members, i, nodeID)
+	return nil
+}
+
+// exit removes a random member and returns true, or returns false if that
+// would take the set below MinValidators
+func (s *Simulation) exit() (bool, error) {
+	if len(s.members) == 0 || len(s.members) <= s.config.MinValidators {
+		return false, nil
+	}
+	i := s.rng.IntN(len(s.members))
+	nodeID := s.members[i]
+	if err := s.manager.RemoveStaker(s.config.NetID, nodeID); err != nil {
+		return false, fmt.Errorf("couldn't remove %s: %w", nodeID, err)
+	}
+	s.members = slices.Delete(s.members, i, i+1)
+	return true, nil
+}
+
+// drift changes the light of [nodeID] by a normally distributed share of it
+// and returns true if it changed
+func (s *Simulation) drift(nodeID ids.NodeID) (bool, error) {
+	light := s.manager.GetLight(s.config.NetID, nodeID)
+	factor := 1 + s.rng.NormFloat64()*s.config.Drift
+	drifted := float64(light) * max(factor, 0)
+	newLight := uint64(math.MaxUint64)
+	if drifted < math.MaxUint64 {
+		newLight = max(uint64(drifted), 1)
+	}
+	if newLight == light {
+		return false, nil
+	}
+	if err := s.manager.SetWeight(s.config.NetID, nodeID, newLight); err != nil {
+		return false, fmt.Errorf("couldn't set light of %s: %w", nodeID, err)
+	}
+	return true, nil
+}
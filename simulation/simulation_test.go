@@ -0,0 +1,88 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulation
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+// TestDistributions tests that draws stay within their bounds
+func TestDistributions(t *testing.T) {
+	require := require.New(t)
+
+	r := rand.New(rand.NewPCG(1, 1))
+	require.Equal(uint64(7), Constant{Value: 7}.Sample(r))
+	for range 1000 {
+		v := Uniform{Min: 10, Max: 20}.Sample(r)
+		require.GreaterOrEqual(v, uint64(10))
+		require.LessOrEqual(v, uint64(20))
+
+		v = Pareto{Min: 100, Alpha: 1.5, Max: 10_000}.Sample(r)
+		require.GreaterOrEqual(v, uint64(100))
+		require.LessOrEqual(v, uint64(10_000))
+	}
+	require.Zero(Poisson{}.Sample(r))
+
+	var sum uint64
+	for range 1000 {
+		sum += Poisson{Mean: 4}.Sample(r)
+	}
+	require.InDelta(4, float64(sum)/1000, 0.5)
+}
+
+// TestSimulation tests that a simulation drives the manager within its bounds
+// and is reproducible
+func TestSimulation(t *testing.T) {
+	require := require.New(t)
+
+	config := Config{
+		Seed:              1,
+		NetID:             ids.GenerateTestID(),
+		InitialValidators: 20,
+		MinValidators:     10,
+		MaxValidators:     40,
+		Stake:             Pareto{Min: 1_000, Alpha: 1.2, Max: 1_000_000},
+		Joins:             Poisson{Mean: 2},
+		Exits:             Poisson{Mean: 2},
+		Drift:             0.01,
+		BurstRate:         0.1,
+		BurstFraction:     0.25,
+	}
+	_, err := New(validators.NewManager(), Config{})
+	require.ErrorIs(err, ErrInvalidConfig)
+
+	run := func() (validators.Manager, *Simulation, Stats) {
+		m := validators.NewManager()
+		s, err := New(m, config)
+		require.NoError(err)
+		stats, err := s.Run(100, func(stats Stats) error {
+			require.GreaterOrEqual(stats.Validators, config.MinValidators)
+			require.LessOrEqual(stats.Validators, config.MaxValidators)
+			require.Equal(stats.Validators, m.Count(config.NetID))
+			return nil
+		})
+		require.NoError(err)
+		return m, s, stats
+	}
+
+	m1, s1, stats1 := run()
+	_, s2, stats2 := run()
+	require.Equal(stats1, stats2)
+	require.Equal(s1.Members(), s2.Members())
+	require.Equal(100, stats1.Steps)
+	require.Positive(stats1.Joined)
+	require.Positive(stats1.Exited)
+	require.Positive(stats1.Drifted)
+	require.Positive(stats1.Bursts)
+
+	light, err := m1.TotalLight(config.NetID)
+	require.NoError(err)
+	require.Equal(stats1.Light, light)
+}
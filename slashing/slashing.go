@@ -0,0 +1,185 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package slashing applies penalties to validators that misbehave
+package slashing
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/luxfi/ids"
+
+	validators "github.com/luxfi/validators"
+)
+
+// PercentDenominator is the denominator of penalty rates, so a rate of
+// PercentDenominator slashes the whole stake
+const PercentDenominator = 1_000_000
+
+var (
+	ErrDuplicateEvidence = errors.New("duplicate evidence")
+	ErrNoPenalty         = errors.New("no penalty scheduled")
+	ErrInvalidRate       = errors.New("rate exceeds PercentDenominator")
+)
+
+// Kind is the kind of misbehavior evidence proves
+type Kind uint8
+
+const (
+	DoubleSign Kind = iota + 1
+	Downtime
+)
+
+// String implements fmt.Stringer
+func (k Kind) String() string {
+	switch k {
+	case DoubleSign:
+		return "double-sign"
+	case Downtime:
+		return "downtime"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(k))
+	}
+}
+
+// Evidence proves that a validator misbehaved
+type Evidence struct {
+	// ID uniquely identifies the evidence so it can't be applied twice
+	ID     ids.ID
+	Kind   Kind
+	NetID  ids.ID
+	NodeID ids.NodeID
+	Height uint64
+}
+
+// Schedule maps each kind of evidence to the share of stake, in
+// PercentDenominator units, that is slashed for it
+type Schedule map[Kind]uint64
+
+// DefaultSchedule slashes 5% of stake for double signing and 1% for downtime
+func DefaultSchedule() Schedule {
+	return Schedule{
+		DoubleSign: 50_000,
+		Downtime:   10_000,
+	}
+}
+
+// Slash is a record of an applied penalty
+type Slash struct {
+	Evidence Evidence
+	// Penalty is the light removed from the validator
+	Penalty  uint64
+	OldLight uint64
+	NewLight uint64
+}
+
+// Listener is notified of every applied slash
+type Listener interface {
+	OnSlash(slash Slash)
+}
+
+// Slasher registers evidence and slashes validators in a Manager
+type Slasher struct {
+	manager  validators.Manager
+	schedule Schedule
+
+	mu        sync.RWMutex
+	applied   map[ids.ID]struct{}
+	slashes   []Slash
+	listeners []Listener
+}
+
+// NewSlasher returns a Slasher that penalizes validators of [manager]
+// according to [schedule]
+func NewSlasher(manager validators.Manager, schedule Schedule) (*Slasher, error) {
+	for kind, rate := range schedule {
+		if rate > PercentDenominator {
+			return nil, fmt.Errorf("%w: %s rate %d", ErrInvalidRate, kind, rate)
+		}
+	}
+	return &Slasher{
+		manager:  manager,
+		schedule: schedule,
+		applied:  make(map[ids.ID]struct{}),
+	}, nil
+}
+
+// Report registers [evidence] and slashes the validator it implicates. The
+// penalty is taken from the validator's self-stake when the manager tracks
+// delegations, since delegated stake isn't held by the validator. A penalty
+// that exhausts the self-stake removes the validator.
+func (s *Slasher) Report(evidence Evidence) (Slash, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.applied[evidence.ID]; ok {
+		return Slash{}, fmt.Errorf("%w: %s", ErrDuplicateEvidence, evidence.ID)
+	}
+	rate, ok := s.schedule[evidence.Kind]
+	if !ok {
+		return Slash{}, fmt.Errorf("%w: %s", ErrNoPenalty, evidence.Kind)
+	}
+	if _, ok := s.manager.GetValidator(evidence.NetID, evidence.NodeID); !ok {
+		return Slash{}, fmt.Errorf("%w: %s in %s", validators.ErrUnknownValidator, evidence.NodeID, evidence.NetID)
+	}
+
+	oldLight := s.manager.GetLight(evidence.NetID, evidence.NodeID)
+	stake := oldLight
+	if delegations, ok := s.manager.(validators.DelegationManager); ok {
+		stake = delegations.GetSelfStake(evidence.NetID, evidence.NodeID)
+	}
+	penalty := new(big.Int).Mul(new(big.Int).SetUint64(stake), new(big.Int).SetUint64(rate))
+	penalty.Quo(penalty, big.NewInt(PercentDenominator))
+
+	slash := Slash{
+		Evidence: evidence,
+		Penalty:  penalty.Uint64(),
+		OldLight: oldLight,
+	}
+	if slash.Penalty > 0 {
+		if err := s.manager.RemoveWeight(evidence.NetID, evidence.NodeID, slash.Penalty); err != nil {
+			return Slash{}, fmt.Errorf("failed to slash %s: %w", evidence.NodeID, err)
+		}
+	}
+	slash.NewLight = s.manager.GetLight(evidence.NetID, evidence.NodeID)
+
+	s.applied[evidence.ID] = struct{}{}
+	s.slashes = append(s.slashes, slash)
+	for _, listener := range s.listeners {
+		listener.OnSlash(slash)
+	}
+	return slash, nil
+}
+
+// Slashes returns every applied slash in the order it was applied
+func (s *Slasher) Slashes() []Slash {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]Slash(nil), s.slashes...)
+}
+
+// SlashesOf returns the slashes applied to [nodeID] of [netID]
+func (s *Slasher) SlashesOf(netID ids.ID, nodeID ids.NodeID) []Slash {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var slashes []Slash
+	for _, slash := range s.slashes {
+		if slash.Evidence.NetID == netID && slash.Evidence.NodeID == nodeID {
+			slashes = append(slashes, slash)
+		}
+	}
+	return slashes
+}
+
+// RegisterListener registers a listener that is notified of future slashes
+func (s *Slasher) RegisterListener(listener Listener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.listeners = append(s.listeners, listener)
+}
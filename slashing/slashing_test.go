@@ -0,0 +1,125 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package slashing
+
+import (
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+type testListener struct {
+	slashes []Slash
+}
+
+func (l *testListener) OnSlash(slash Slash) {
+	l.slashes = append(l.slashes, slash)
+}
+
+// TestSlasherReport tests that evidence reduces the validator's light
+func TestSlasherReport(t *testing.T) {
+	require := require.New(t)
+
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 1000))
+
+	s, err := NewSlasher(m, DefaultSchedule())
+	require.NoError(err)
+	listener := &testListener{}
+	s.RegisterListener(listener)
+
+	evidence := Evidence{
+		ID:     ids.GenerateTestID(),
+		Kind:   DoubleSign,
+		NetID:  netID,
+		NodeID: nodeID,
+		Height: 10,
+	}
+	slash, err := s.Report(evidence)
+	require.NoError(err)
+	expected := Slash{
+		Evidence: evidence,
+		Penalty:  50,
+		OldLight: 1000,
+		NewLight: 950,
+	}
+	require.Equal(expected, slash)
+	require.Equal(uint64(950), m.GetLight(netID, nodeID))
+	require.Equal([]Slash{expected}, listener.slashes)
+	require.Equal([]Slash{expected}, s.Slashes())
+	require.Equal([]Slash{expected}, s.SlashesOf(netID, nodeID))
+	require.Empty(s.SlashesOf(netID, ids.GenerateTestNodeID()))
+
+	_, err = s.Report(evidence)
+	require.ErrorIs(err, ErrDuplicateEvidence)
+	require.Equal(uint64(950), m.GetLight(netID, nodeID))
+}
+
+// TestSlasherSelfStakeOnly tests that delegated stake isn't slashed
+func TestSlasherSelfStakeOnly(t *testing.T) {
+	require := require.New(t)
+
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 1000))
+	require.NoError(m.AddDelegator(netID, nodeID, ids.GenerateTestShortID(), 1000))
+
+	s, err := NewSlasher(m, Schedule{Downtime: PercentDenominator / 2})
+	require.NoError(err)
+
+	slash, err := s.Report(Evidence{ID: ids.GenerateTestID(), Kind: Downtime, NetID: netID, NodeID: nodeID})
+	require.NoError(err)
+	require.Equal(uint64(500), slash.Penalty)
+	require.Equal(uint64(1500), m.GetLight(netID, nodeID))
+	require.Equal(uint64(1000), m.GetDelegatedWeight(netID, nodeID))
+}
+
+// TestSlasherFullPenaltyRemovesValidator tests a penalty of the whole stake
+func TestSlasherFullPenaltyRemovesValidator(t *testing.T) {
+	require := require.New(t)
+
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 1000))
+
+	s, err := NewSlasher(m, Schedule{DoubleSign: PercentDenominator})
+	require.NoError(err)
+
+	slash, err := s.Report(Evidence{ID: ids.GenerateTestID(), Kind: DoubleSign, NetID: netID, NodeID: nodeID})
+	require.NoError(err)
+	require.Equal(uint64(1000), slash.Penalty)
+	require.Zero(slash.NewLight)
+	_, ok := m.GetValidator(netID, nodeID)
+	require.False(ok)
+}
+
+// TestSlasherErrors tests rejected evidence and schedules
+func TestSlasherErrors(t *testing.T) {
+	require := require.New(t)
+
+	_, err := NewSlasher(validators.NewManager(), Schedule{DoubleSign: PercentDenominator + 1})
+	require.ErrorIs(err, ErrInvalidRate)
+
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 1000))
+
+	s, err := NewSlasher(m, Schedule{DoubleSign: 1})
+	require.NoError(err)
+
+	_, err = s.Report(Evidence{ID: ids.GenerateTestID(), Kind: Downtime, NetID: netID, NodeID: nodeID})
+	require.ErrorIs(err, ErrNoPenalty)
+
+	_, err = s.Report(Evidence{ID: ids.GenerateTestID(), Kind: DoubleSign, NetID: netID, NodeID: ids.GenerateTestNodeID()})
+	require.ErrorIs(err, validators.ErrUnknownValidator)
+	require.Empty(s.Slashes())
+}
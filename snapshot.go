@@ -0,0 +1,260 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math"
+)
+
+// SnapshotManager serves immutable views of validator sets. A net's snapshot
+// is taken once after each change to the net, by the first reader that needs
+// it, and then shared by every reader until the next change, so hot paths
+// read without copying or holding the manager lock. Taking a snapshot copies
+// the records but not their keys, metadata or extensions, which the manager
+// replaces rather than modifies.
+type SnapshotManager interface {
+	// Snapshot returns the current validators of [netID]. The snapshot never
+	// changes, even if the net does.
+	Snapshot(netID ids.ID) *ValidatorSnapshot
+	// MultiNetSnapshot returns the snapshots of [netIDs] at the same instant
+	MultiNetSnapshot(netIDs []ids.ID) *MultiNetSnapshot
+}
+
+var _ SnapshotManager = (*manager)(nil)
+
+// ValidatorSnapshot is an immutable view of a net's validators. Records are
+// returned by value, but their slices and maps are shared between readers and
+// must not be modified.
+type ValidatorSnapshot struct {
+	netID      ids.ID
+	validators map[ids.NodeID]*GetValidatorOutput
+	nodeIDs    []ids.NodeID
+	light      uint64
+	lightErr   error
+
+	// Computed on first use, see ETag and cumulativeLight
+	etagOnce       sync.Once
+	etag           string
+	cumulativeOnce sync.Once
+	cumulative     []uint64
+}
+
+// NetID returns the net the snapshot was taken of
+func (s *ValidatorSnapshot) NetID() ids.ID {
+	return s.netID
+}
+
+// Len returns the number of validators
+func (s *ValidatorSnapshot) Len() int {
+	return len(s.validators)
+}
+
+// Has returns true if [nodeID] is a validator
+func (s *ValidatorSnapshot) Has(nodeID ids.NodeID) bool {
+	_, ok := s.validators[nodeID]
+	return ok
+}
+
+// Get returns the record of [nodeID], if it's a validator
+func (s *ValidatorSnapshot) Get(nodeID ids.NodeID) (GetValidatorOutput, bool) {
+	val, ok := s.validators[nodeID]
+	if !ok {
+		return GetValidatorOutput{}, false
+	}
+	return *val, true
+}
+
+// Light returns the total light of the validators, saturated at MaxUint64 if
+// it overflows, see TotalLight
+func (s *ValidatorSnapshot) Light() uint64 {
+	return s.light
+}
+
+// TotalLight returns the total light of the validators, or ErrWeightOverflow
+// if it overflows
+func (s *ValidatorSnapshot) TotalLight() (uint64, error) {
+	return s.light, s.lightErr
+}
+
+// NodeIDs returns the validators sorted by node ID. The result must not be
+// modified.
+func (s *ValidatorSnapshot) NodeIDs() []ids.NodeID {
+	return s.nodeIDs
+}
+
+// All iterates over the validators in node ID order
+func (s *ValidatorSnapshot) All() iter.Seq2[ids.NodeID, GetValidatorOutput] {
+	return func(yield func(ids.NodeID, GetValidatorOutput) bool) {
+		for _, nodeID := range s.nodeIDs {
+			if !yield(nodeID, *s.validators[nodeID]) {
+				return
+			}
+		}
+	}
+}
+
+// Set returns the snapshot as a Set that samples with SampleTruncate
+func (s *ValidatorSnapshot) Set() Set {
+	return s.set(SampleTruncate)
+}
+
+// set returns the snapshot as a Set that samples with [mode]
+func (s *ValidatorSnapshot) set(mode SampleMode) Set {
+	if len(s.validators) == 0 {
+		return &emptySet{sampleMode: mode}
+	}
+	return &validatorSet{
+		validators: s.validators,
+		sampleMode: mode,
+	}
+}
+
+// Snapshot returns the cached snapshot of a net, taking it if the net changed
+// since the last one
+func (m *manager) Snapshot(netID ids.ID) *ValidatorSnapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.snapshot(netID)
+}
+
+// snapshot returns the cached snapshot of [netID]. Concurrent readers may
+// both take a snapshot after a change; they're identical, so either may be
+// cached. Nets without validators aren't cached. It assumes the lock is held,
+// for reading or writing.
+func (m *manager) snapshot(netID ids.ID) *ValidatorSnapshot {
+	if snapshot, ok := m.snapshots.get(netID); ok {
+		return snapshot
+	}
+
+	vdrs := m.validators[netID]
+	snapshot := newValidatorSnapshot(netID, shareValidators(vdrs))
+	if len(vdrs) > 0 {
+		m.snapshots.put(netID, snapshot)
+	}
+	return snapshot
+}
+
+// newValidatorSnapshot returns a snapshot of [vdrs], which it takes ownership
+// of
+func newValidatorSnapshot(netID ids.ID, vdrs map[ids.NodeID]*GetValidatorOutput) *ValidatorSnapshot {
+	light, err := sumLight(vdrs)
+	if err != nil {
+		err = fmt.Errorf("%w: total light of %s: %w", ErrWeightOverflow, netID, err)
+	}
+	return &ValidatorSnapshot{
+		netID:      netID,
+		validators: vdrs,
+		nodeIDs:    sortNodeIDs(slices.Collect(maps.Keys(vdrs))),
+		light:      light,
+		lightErr:   err,
+	}
+}
+
+// sumLight returns the total light of [vdrs], saturated at MaxUint64 and with
+// an error if it overflows
+func sumLight(vdrs map[ids.NodeID]*GetValidatorOutput) (uint64, error) {
+	var total uint64
+	for _, val := range vdrs {
+		sum, err := math.Add64(total, val.Light)
+		if err != nil {
+			return ^uint64(0), err
+		}
+		total = sum
+	}
+	return total, nil
+}
+
+// shareValidators returns a copy of [validators] whose records share their
+// keys, metadata and extensions with the originals
+func shareValidators(validators map[ids.NodeID]*GetValidatorOutput) map[ids.NodeID]*GetValidatorOutput {
+	result := make(map[ids.NodeID]*GetValidatorOutput, len(validators))
+	for nodeID, val := range validators {
+		valCopy := *val
+		result[nodeID] = &valCopy
+	}
+	return result
+}
+
+// MultiNetSnapshot is the validators of several nets at the same instant: no
+// change to any of them happened between the snapshots of two nets
+type MultiNetSnapshot struct {
+	netIDs []ids.ID
+	nets   map[ids.ID]*ValidatorSnapshot
+}
+
+// NetIDs returns the nets in the order they were requested, without
+// duplicates. The result must not be modified.
+func (s *MultiNetSnapshot) NetIDs() []ids.ID {
+	return s.netIDs
+}
+
+// Net returns the snapshot of [netID], if it was requested
+func (s *MultiNetSnapshot) Net(netID ids.ID) (*ValidatorSnapshot, bool) {
+	snapshot, ok := s.nets[netID]
+	return snapshot, ok
+}
+
+// MultiNetSnapshot returns the snapshots of [netIDs] taken under a single
+// acquisition of the lock
+func (m *manager) MultiNetSnapshot(netIDs []ids.ID) *MultiNetSnapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	s := &MultiNetSnapshot{
+		netIDs: make([]ids.ID, 0, len(netIDs)),
+		nets:   make(map[ids.ID]*ValidatorSnapshot, len(netIDs)),
+	}
+	for _, netID := range netIDs {
+		if _, ok := s.nets[netID]; ok {
+			continue
+		}
+		s.netIDs = append(s.netIDs, netID)
+		s.nets[netID] = m.snapshot(netID)
+	}
+	return s
+}
+
+// snapshots caches the latest snapshot of each net. Snapshots are invalidated
+// with the manager's write lock held and taken with at least its read lock
+// held, so a snapshot is never cached after a change it doesn't include.
+type snapshots struct {
+	mu   sync.Mutex
+	nets map[ids.ID]*ValidatorSnapshot
+}
+
+func newSnapshots() *snapshots {
+	return &snapshots{
+		nets: make(map[ids.ID]*ValidatorSnapshot),
+	}
+}
+
+func (s *snapshots) get(netID ids.ID) (*ValidatorSnapshot, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot, ok := s.nets[netID]
+	return snapshot, ok
+}
+
+func (s *snapshots) put(netID ids.ID, snapshot *ValidatorSnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nets[netID] = snapshot
+}
+
+func (s *snapshots) invalidate(netID ids.ID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.nets, netID)
+}
@@ -0,0 +1,168 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"math"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// TestManagerSnapshot tests that snapshots are shared until the net changes
+// and never change themselves
+func TestManagerSnapshot(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	otherNetID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+
+	snapshot := m.Snapshot(netID)
+	require.Same(snapshot, m.Snapshot(netID))
+	require.Equal(netID, snapshot.NetID())
+	require.Equal(1, snapshot.Len())
+	require.True(snapshot.Has(nodeID))
+	require.Equal(uint64(100), snapshot.Light())
+	require.Equal([]ids.NodeID{nodeID}, snapshot.NodeIDs())
+
+	// Changes to other nets keep the snapshot
+	require.NoError(m.AddStaker(otherNetID, nodeID, nil, ids.Empty, 100))
+	require.Same(snapshot, m.Snapshot(netID))
+
+	// Changes to the net replace it, leaving the old one intact
+	require.NoError(m.AddWeight(netID, nodeID, 50))
+	updated := m.Snapshot(netID)
+	require.NotSame(snapshot, updated)
+	require.Equal(uint64(100), snapshot.Light())
+	require.Equal(uint64(150), updated.Light())
+
+	require.NoError(m.SetMetadata(netID, nodeID, &ValidatorMetadata{Moniker: "node"}))
+	val, ok := m.Snapshot(netID).Get(nodeID)
+	require.True(ok)
+	require.Equal("node", val.Metadata.Moniker)
+
+	// Extensions are shared with the snapshot but replaced, not modified
+	require.NoError(m.SetExtension(netID, nodeID, "key", []byte{1}))
+	shared := m.Snapshot(netID)
+	require.NoError(m.SetExtension(netID, nodeID, "other", []byte{2}))
+	m.DeleteExtension(netID, nodeID, "key")
+	val, ok = shared.Get(nodeID)
+	require.True(ok)
+	require.Equal(map[string][]byte{"key": {1}}, val.Extensions)
+
+	otherNodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, otherNodeID, nil, ids.Empty, 10))
+	require.ElementsMatch([]ids.NodeID{nodeID, otherNodeID}, m.Snapshot(netID).NodeIDs())
+
+	require.NoError(m.RemoveStaker(netID, otherNodeID))
+	require.NoError(m.RemoveStaker(netID, nodeID))
+	empty := m.Snapshot(netID)
+	require.Zero(empty.Len())
+	_, ok = empty.Get(nodeID)
+	require.False(ok)
+	require.Equal(1, updated.Len())
+}
+
+// TestManagerSnapshotAll tests that snapshots iterate in node ID order
+func TestManagerSnapshotAll(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	for range 8 {
+		require.NoError(m.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 1))
+	}
+
+	snapshot := m.Snapshot(netID)
+	var nodeIDs []ids.NodeID
+	for nodeID, val := range snapshot.All() {
+		require.Equal(nodeID, val.NodeID)
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	require.Equal(snapshot.NodeIDs(), nodeIDs)
+	require.Equal(sortNodeIDs(m.GetValidatorIDs(netID)), nodeIDs)
+	require.Equal(snapshot.Len(), snapshot.Set().Len())
+}
+
+// TestManagerSnapshotOverflow tests that a total light that overflows is
+// reported rather than wrapped, and that weight additions are checked
+func TestManagerSnapshotOverflow(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, math.MaxUint64))
+	require.ErrorIs(m.AddWeight(netID, nodeID, 1), ErrWeightOverflow)
+	require.Equal(uint64(math.MaxUint64), m.GetLight(netID, nodeID))
+
+	require.NoError(m.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 1))
+	snapshot := m.Snapshot(netID)
+	require.Equal(uint64(math.MaxUint64), snapshot.Light())
+	_, err := snapshot.TotalLight()
+	require.ErrorIs(err, ErrWeightOverflow)
+	_, err = m.TotalLight(netID)
+	require.ErrorIs(err, ErrWeightOverflow)
+	_, err = m.GetValidatorIDsPage(netID, ids.EmptyNodeID, 1)
+	require.ErrorIs(err, ErrWeightOverflow)
+
+	set, err := m.GetValidators(netID)
+	require.NoError(err)
+	require.Equal(uint64(math.MaxUint64), set.Light())
+}
+
+// TestManagerMultiNetSnapshot tests that nets are captured at the same
+// instant, never between two halves of a change spanning them
+func TestManagerMultiNetSnapshot(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netA, netB := ids.GenerateTestID(), ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netA, nodeID, nil, ids.Empty, 1))
+
+	// Move the validator between the nets atomically while snapshotting
+	done := make(chan error)
+	go func() {
+		from, to := netA, netB
+		for range 1000 {
+			err := m.WithTransaction(func(tx *Tx) error {
+				if err := tx.RemoveStaker(from, nodeID); err != nil {
+					return err
+				}
+				return tx.AddStaker(to, nodeID, nil, ids.Empty, 1)
+			})
+			if err != nil {
+				done <- err
+				return
+			}
+			from, to = to, from
+		}
+		done <- nil
+	}()
+
+	for running := true; running; {
+		select {
+		case err := <-done:
+			require.NoError(err)
+			running = false
+		default:
+		}
+
+		snapshot := m.MultiNetSnapshot([]ids.ID{netA, netB, netA})
+		require.Equal([]ids.ID{netA, netB}, snapshot.NetIDs())
+		a, ok := snapshot.Net(netA)
+		require.True(ok)
+		b, ok := snapshot.Net(netB)
+		require.True(ok)
+		require.Equal(1, a.Len()+b.Len())
+	}
+
+	_, ok := m.MultiNetSnapshot(nil).Net(netA)
+	require.False(ok)
+}
@@ -0,0 +1,283 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package statediff compares two validators.State implementations, such as a
+// cached and an uncached state or a local state and a remote client, at
+// random heights and nets, and reports every place their validator sets or
+// checksums differ. It is meant to catch cache coherency bugs, in CI and in
+// production canaries.
+package statediff
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+
+	"github.com/luxfi/ids"
+
+	validators "github.com/luxfi/validators"
+)
+
+var (
+	ErrInvalidConfig   = errors.New("invalid statediff config")
+	ErrNoCommonHeights = errors.New("states have no height in common")
+	ErrDiverged        = errors.New("states diverged")
+)
+
+// Kind is the way two states differ
+type Kind uint8
+
+const (
+	// MissingFromA is reported for a validator only state B has
+	MissingFromA Kind = iota + 1
+	// MissingFromB is reported for a validator only state A has
+	MissingFromB
+	// FieldMismatch is reported for a validator both states have with
+	// different keys, light, weight or TxID
+	FieldMismatch
+	// ChecksumMismatch is reported when the checksums of a set differ
+	ChecksumMismatch
+	// ErrorMismatch is reported when only one state fails to return a set
+	ErrorMismatch
+)
+
+// String implements fmt.Stringer
+func (k Kind) String() string {
+	switch k {
+	case MissingFromA:
+		return "missing-from-a"
+	case MissingFromB:
+		return "missing-from-b"
+	case FieldMismatch:
+		return "field-mismatch"
+	case ChecksumMismatch:
+		return "checksum-mismatch"
+	case ErrorMismatch:
+		return "error-mismatch"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(k))
+	}
+}
+
+// Config is what a Differ samples
+type Config struct {
+	// NetIDs are the nets sampled from
+	NetIDs []ids.ID
+	// Samples is the number of (height, net) pairs compared by each Run
+	Samples int
+	// Seed makes the sampled pairs reproducible
+	Seed uint64
+}
+
+// Verify returns an error if nothing can be sampled
+func (c Config) Verify() error {
+	switch {
+	case len(c.NetIDs) == 0:
+		return fmt.Errorf("%w: no nets", ErrInvalidConfig)
+	case c.Samples <= 0:
+		return fmt.Errorf("%w: samples %d must be positive", ErrInvalidConfig, c.Samples)
+	}
+	return nil
+}
+
+// Divergence is one difference between the states at a height and net
+type Divergence struct {
+	Kind   Kind
+	Height uint64
+	NetID  ids.ID
+	// NodeID is the validator that differs, or empty for set level
+	// differences
+	NodeID ids.NodeID
+	Detail string
+}
+
+// String implements fmt.Stringer
+func (d Divergence) String() string {
+	if d.NodeID == ids.EmptyNodeID {
+		return fmt.Sprintf("%s at height %d in %s: %s", d.Kind, d.Height, d.NetID, d.Detail)
+	}
+	return fmt.Sprintf("%s at height %d in %s for %s: %s", d.Kind, d.Height, d.NetID, d.NodeID, d.Detail)
+}
+
+// Report is the result of a Run
+type Report struct {
+	// Samples is the number of (height, net) pairs compared
+	Samples     int
+	Divergences []Divergence
+}
+
+// Err returns ErrDiverged describing the first divergence, or nil if the
+// states agreed
+func (r Report) Err() error {
+	if len(r.Divergences) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %d divergences in %d samples, first %s", ErrDiverged, len(r.Divergences), r.Samples, r.Divergences[0])
+}
+
+// Differ compares two states. Successive runs continue the same random
+// sequence, so a canary running it periodically covers new pairs each time.
+type Differ struct {
+	a, b   validators.State
+	config Config
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// New returns a Differ comparing [a] and [b]
+func New(a, b validators.State, config Config) (*Differ, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	return &Differ{
+		a:      a,
+		b:      b,
+		config: config,
+		rng:    rand.New(rand.NewPCG(config.Seed, config.Seed)),
+	}, nil
+}
+
+// Run compares the states at Samples random pairs of a configured net and a
+// height both states serve. Differences are reported, not returned; an error
+// is only returned if the heights can't be read or [ctx] is done.
+func (d *Differ) Run(ctx context.Context) (Report, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	low, high, err := d.commonHeights(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+
+	var report Report
+	for range d.config.Samples {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		var (
+			height = low + d.rng.Uint64N(high-low+1)
+			netID  = d.config.NetIDs[d.rng.IntN(len(d.config.NetIDs))]
+		)
+		report.Divergences = append(report.Divergences, d.compare(ctx, height, netID)...)
+		report.Samples++
+	}
+	return report, nil
+}
+
+// commonHeights returns the range of heights both states serve
+func (d *Differ) commonHeights(ctx context.Context) (uint64, uint64, error) {
+	var low, high uint64
+	for i, state := range []validators.State{d.a, d.b} {
+		minimum, err := state.GetMinimumHeight(ctx)
+		if err != nil {
+			return 0, 0, fmt.Errorf("couldn't get minimum height: %w", err)
+		}
+		current, err := state.GetCurrentHeight(ctx)
+		if err != nil {
+			return 0, 0, fmt.Errorf("couldn't get current height: %w", err)
+		}
+		if i == 0 {
+			low, high = minimum, current
+			continue
+		}
+		low, high = max(low, minimum), min(high, current)
+	}
+	if low > high {
+		return 0, 0, fmt.Errorf("%w: [%d, %d]", ErrNoCommonHeights, low, high)
+	}
+	return low, high, nil
+}
+
+// compare returns the differences between the states at [height] in [netID]
+func (d *Differ) compare(ctx context.Context, height uint64, netID ids.ID) []Divergence {
+	var divergences []Divergence
+	report := func(kind Kind, nodeID ids.NodeID, format string, args ...any) {
+		divergences = append(divergences, Divergence{
+			Kind:   kind,
+			Height: height,
+			NetID:  netID,
+			NodeID: nodeID,
+			Detail: fmt.Sprintf(format, args...),
+		})
+	}
+
+	setA, errA := d.a.GetValidatorSet(ctx, height, netID)
+	setB, errB := d.b.GetValidatorSet(ctx, height, netID)
+	switch {
+	case errA != nil && errB != nil:
+		// Both states refusing the same pair is agreement
+	case errA != nil || errB != nil:
+		report(ErrorMismatch, ids.EmptyNodeID, "a: %v, b: %v", errA, errB)
+	default:
+		for _, nodeID := range sortedNodeIDs(setA, setB) {
+			vdrA, inA := setA[nodeID]
+			vdrB, inB := setB[nodeID]
+			switch {
+			case !inA:
+				report(MissingFromA, nodeID, "b has light %d", vdrB.Light)
+			case !inB:
+				report(MissingFromB, nodeID, "a has light %d", vdrA.Light)
+			default:
+				if field := mismatchedField(vdrA, vdrB); field != "" {
+					report(FieldMismatch, nodeID, "%s differs", field)
+				}
+			}
+		}
+	}
+
+	// Checksums are compared separately, since states implementing
+	// validators.ChecksumState may serve them from a different cache than
+	// their sets
+	checksumA, errA := validators.GetValidatorSetChecksum(ctx, d.a, height, netID)
+	checksumB, errB := validators.GetValidatorSetChecksum(ctx, d.b, height, netID)
+	switch {
+	case errA != nil && errB != nil:
+	case errA != nil || errB != nil:
+		report(ErrorMismatch, ids.EmptyNodeID, "checksum a: %v, b: %v", errA, errB)
+	case checksumA != checksumB:
+		report(ChecksumMismatch, ids.EmptyNodeID, "a: %s, b: %s", checksumA, checksumB)
+	}
+	return divergences
+}
+
+// mismatchedField returns the name of the first consensus relevant field
+// that differs between [a] and [b], or "" if none do. Local bookkeeping, such
+// as Sequence and connection status, isn't compared.
+func mismatchedField(a, b *validators.GetValidatorOutput) string {
+	switch {
+	case !bytes.Equal(a.PublicKey, b.PublicKey):
+		return "PublicKey"
+	case !bytes.Equal(a.RingtailPubKey, b.RingtailPubKey):
+		return "RingtailPubKey"
+	case a.Light != b.Light:
+		return "Light"
+	case a.Weight != b.Weight:
+		return "Weight"
+	case a.TxID != b.TxID:
+		return "TxID"
+	}
+	return ""
+}
+
+// sortedNodeIDs returns the node IDs in either set, sorted so reports are
+// deterministic
+func sortedNodeIDs(a, b map[ids.NodeID]*validators.GetValidatorOutput) []ids.NodeID {
+	nodeIDs := make([]ids.NodeID, 0, len(a)+len(b))
+	for nodeID := range a {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	for nodeID := range b {
+		if _, ok := a[nodeID]; !ok {
+			nodeIDs = append(nodeIDs, nodeID)
+		}
+	}
+	slices.SortFunc(nodeIDs, func(x, y ids.NodeID) int {
+		return bytes.Compare(x[:], y[:])
+	})
+	return nodeIDs
+}
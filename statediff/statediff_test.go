@@ -0,0 +1,130 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statediff
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+	"github.com/luxfi/validators/validatorstest"
+)
+
+// TestConfigVerify tests that unusable configs are rejected
+func TestConfigVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      Config
+		expectedErr error
+	}{
+		{
+			name:   "valid",
+			config: Config{NetIDs: []ids.ID{ids.GenerateTestID()}, Samples: 1},
+		},
+		{
+			name:        "no nets",
+			config:      Config{Samples: 1},
+			expectedErr: ErrInvalidConfig,
+		},
+		{
+			name:        "no samples",
+			config:      Config{NetIDs: []ids.ID{ids.GenerateTestID()}},
+			expectedErr: ErrInvalidConfig,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.config.Verify(), test.expectedErr)
+		})
+	}
+}
+
+// TestDifferRun tests that equal states agree and that each kind of
+// divergence is reported
+func TestDifferRun(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		ctx    = context.Background()
+		netID  = ids.GenerateTestID()
+		nodeA  = ids.GenerateTestNodeID()
+		nodeB  = ids.GenerateTestNodeID()
+		a      = validatorstest.NewTestState().SetCurrentHeight(10)
+		b      = validatorstest.NewTestState().SetCurrentHeight(20)
+		config = Config{NetIDs: []ids.ID{netID}, Samples: 8, Seed: 1}
+	)
+	for _, state := range []*validatorstest.TestState{a, b} {
+		state.AddValidator(netID, &validators.GetValidatorOutput{NodeID: nodeA, Light: 10, Weight: 10})
+	}
+
+	d, err := New(a, b, config)
+	require.NoError(err)
+	report, err := d.Run(ctx)
+	require.NoError(err)
+	require.Equal(8, report.Samples)
+	require.Empty(report.Divergences)
+	require.NoError(report.Err())
+
+	b.AddValidator(netID, &validators.GetValidatorOutput{NodeID: nodeA, Light: 11, Weight: 10})
+	b.AddValidator(netID, &validators.GetValidatorOutput{NodeID: nodeB, Light: 1, Weight: 1})
+	config.Samples = 1
+	d, err = New(a, b, config)
+	require.NoError(err)
+	report, err = d.Run(ctx)
+	require.NoError(err)
+	require.ErrorIs(report.Err(), ErrDiverged)
+
+	kinds := make(map[Kind]ids.NodeID)
+	for _, divergence := range report.Divergences {
+		require.LessOrEqual(divergence.Height, uint64(10))
+		kinds[divergence.Kind] = divergence.NodeID
+	}
+	require.Equal(map[Kind]ids.NodeID{
+		FieldMismatch:    nodeA,
+		MissingFromA:     nodeB,
+		ChecksumMismatch: ids.EmptyNodeID,
+	}, kinds)
+
+	// A state failing alone is a divergence
+	errUnavailable := errors.New("unavailable")
+	b.GetValidatorSetF = func(context.Context, uint64, ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+		return nil, errUnavailable
+	}
+	report, err = d.Run(ctx)
+	require.NoError(err)
+	require.Len(report.Divergences, 2)
+	for _, divergence := range report.Divergences {
+		require.Equal(ErrorMismatch, divergence.Kind)
+	}
+}
+
+// prunedState is a TestState that has pruned heights below minimum
+type prunedState struct {
+	*validatorstest.TestState
+	minimum uint64
+}
+
+func (s prunedState) GetMinimumHeight(context.Context) (uint64, error) {
+	return s.minimum, nil
+}
+
+// TestDifferNoCommonHeights tests that states without a shared height can't
+// be compared
+func TestDifferNoCommonHeights(t *testing.T) {
+	var (
+		a = validatorstest.NewTestState().SetCurrentHeight(5)
+		b = prunedState{
+			TestState: validatorstest.NewTestState().SetCurrentHeight(10),
+			minimum:   6,
+		}
+	)
+	d, err := New(a, b, Config{NetIDs: []ids.ID{ids.GenerateTestID()}, Samples: 1})
+	require.NoError(t, err)
+	_, err = d.Run(context.Background())
+	require.ErrorIs(t, err, ErrNoCommonHeights)
+}
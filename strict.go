@@ -0,0 +1,57 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"fmt"
+
+	"github.com/luxfi/ids"
+)
+
+// StrictManager makes weight changes to validators that don't exist fail
+// instead of silently doing nothing, so callers can tell no-ops from real
+// updates. In strict mode AddWeight, RemoveWeight, AddBigWeight,
+// RemoveBigWeight, AddLight, RemoveLight, SetEconomicWeight, SetAssetStake
+// and their transaction counterparts return ErrNetNotFound if the net has no
+// validators and ErrValidatorNotFound if it has others.
+type StrictManager interface {
+	SetStrict(strict bool)
+	IsStrict() bool
+}
+
+var _ StrictManager = (*manager)(nil)
+
+// SetStrict enables or disables strict mode
+func (m *manager) SetStrict(strict bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.strict = strict
+}
+
+// IsStrict returns true if strict mode is enabled
+func (m *manager) IsStrict() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.strict
+}
+
+// requireValidator returns an error if [nodeID] isn't in [netID] and strict
+// mode is enabled. It assumes the lock is held.
+func (m *manager) requireValidator(netID ids.ID, nodeID ids.NodeID) error {
+	if !m.strict {
+		return nil
+	}
+	return missingValidator(netID, nodeID, len(m.validators[netID]))
+}
+
+// missingValidator returns the error for [nodeID] missing from [netID], which
+// has [count] validators
+func missingValidator(netID ids.ID, nodeID ids.NodeID, count int) error {
+	if count == 0 {
+		return fmt.Errorf("%w: %s", ErrNetNotFound, netID)
+	}
+	return fmt.Errorf("%w: %s in %s", ErrValidatorNotFound, nodeID, netID)
+}
@@ -0,0 +1,56 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// TestManagerStrict tests that strict mode reports weight changes to missing
+// validators
+func TestManagerStrict(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+
+	// Lenient by default
+	require.False(m.IsStrict())
+	require.NoError(m.AddWeight(netID, nodeID, 1))
+	require.NoError(m.RemoveWeight(netID, nodeID, 1))
+
+	m.SetStrict(true)
+	require.True(m.IsStrict())
+	require.ErrorIs(m.AddWeight(netID, nodeID, 1), ErrNetNotFound)
+	require.ErrorIs(m.RemoveWeight(netID, nodeID, 1), ErrNetNotFound)
+
+	require.NoError(m.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 1))
+	require.ErrorIs(m.AddWeight(netID, nodeID, 1), ErrValidatorNotFound)
+	require.ErrorIs(m.RemoveWeight(netID, nodeID, 1), ErrValidatorNotFound)
+	err := m.WithTransaction(func(tx *Tx) error {
+		return tx.AddWeight(netID, nodeID, 1)
+	})
+	require.ErrorIs(err, ErrValidatorNotFound)
+
+	// Changes to the validator once it was added within the transaction are
+	// allowed
+	err = m.WithTransaction(func(tx *Tx) error {
+		if err := tx.AddStaker(netID, nodeID, nil, ids.Empty, 1); err != nil {
+			return err
+		}
+		return tx.AddWeight(netID, nodeID, 1)
+	})
+	require.NoError(err)
+	require.Equal(uint64(2), m.GetLight(netID, nodeID))
+
+	bigNetID := ids.GenerateTestID()
+	require.NoError(m.SetWeightMode(bigNetID, WeightModeBig))
+	require.ErrorIs(m.AddBigWeight(bigNetID, nodeID, big.NewInt(1)), ErrNetNotFound)
+	require.ErrorIs(m.RemoveBigWeight(bigNetID, nodeID, big.NewInt(1)), ErrNetNotFound)
+}
@@ -0,0 +1,158 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package tally counts stake weighted votes of a net's validators
+package tally
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math"
+
+	validators "github.com/luxfi/validators"
+)
+
+var (
+	ErrNotValidator  = errors.New("voter isn't a validator")
+	ErrDuplicateVote = errors.New("duplicate vote")
+)
+
+// Choice is the stake accumulated by a choice
+type Choice struct {
+	ID    ids.ID
+	Stake uint64
+}
+
+// Tally accumulates votes for choices, weighting every vote by the voter's
+// light at the time it was cast. The first choice whose stake reaches the
+// threshold out of the net's current total light is the decision, and it
+// doesn't change as more votes arrive.
+type Tally struct {
+	manager   validators.Manager
+	netID     ids.ID
+	threshold validators.QuorumConfig
+
+	mu       sync.RWMutex
+	votes    map[ids.NodeID]ids.ID
+	stake    map[ids.ID]uint64
+	voted    uint64
+	decision ids.ID
+	decided  bool
+}
+
+// New returns a tally of votes by the validators of [netID] in [manager] that
+// decides once a choice reaches [threshold]. MinSigners of [threshold] is the
+// minimum number of votes for the decided choice.
+func New(manager validators.Manager, netID ids.ID, threshold validators.QuorumConfig) (*Tally, error) {
+	if err := threshold.Verify(); err != nil {
+		return nil, err
+	}
+	return &Tally{
+		manager:   manager,
+		netID:     netID,
+		threshold: threshold,
+		votes:     make(map[ids.NodeID]ids.ID),
+		stake:     make(map[ids.ID]uint64),
+	}, nil
+}
+
+// Vote records [nodeID]'s vote for [choice] and returns true if the tally is
+// decided. A validator can vote only once.
+func (t *Tally) Vote(nodeID ids.NodeID, choice ids.ID) (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if previous, ok := t.votes[nodeID]; ok {
+		return t.decided, fmt.Errorf("%w: %s already voted for %s", ErrDuplicateVote, nodeID, previous)
+	}
+	light := t.manager.GetLight(t.netID, nodeID)
+	if light == 0 {
+		return t.decided, fmt.Errorf("%w: %s in %s", ErrNotValidator, nodeID, t.netID)
+	}
+	stake, err := math.Add64(t.stake[choice], light)
+	if err != nil {
+		return t.decided, fmt.Errorf("%w: %w", validators.ErrWeightOverflow, err)
+	}
+	voted, err := math.Add64(t.voted, light)
+	if err != nil {
+		return t.decided, fmt.Errorf("%w: %w", validators.ErrWeightOverflow, err)
+	}
+
+	t.votes[nodeID] = choice
+	t.stake[choice] = stake
+	t.voted = voted
+	if t.decided {
+		return true, nil
+	}
+
+	total, err := t.manager.TotalLight(t.netID)
+	if err != nil {
+		return false, err
+	}
+	if t.threshold.Reached(stake, total) && t.numVotes(choice) >= t.threshold.MinSigners {
+		t.decision = choice
+		t.decided = true
+	}
+	return t.decided, nil
+}
+
+// numVotes returns the number of votes for [choice]. It assumes the lock is
+// held.
+func (t *Tally) numVotes(choice ids.ID) int {
+	var n int
+	for _, vote := range t.votes {
+		if vote == choice {
+			n++
+		}
+	}
+	return n
+}
+
+// Decision returns the decided choice, if any
+func (t *Tally) Decision() (ids.ID, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.decision, t.decided
+}
+
+// Stake returns the stake accumulated by [choice]
+func (t *Tally) Stake(choice ids.ID) uint64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.stake[choice]
+}
+
+// Voted returns the stake of every validator that voted
+func (t *Tally) Voted() uint64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.voted
+}
+
+// Choices returns every choice that was voted for, by descending stake and
+// then by ID
+func (t *Tally) Choices() []Choice {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	choices := make([]Choice, 0, len(t.stake))
+	for id, stake := range t.stake {
+		choices = append(choices, Choice{ID: id, Stake: stake})
+	}
+	slices.SortFunc(choices, func(a, b Choice) int {
+		if c := cmp.Compare(b.Stake, a.Stake); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.ID[:], b.ID[:])
+	})
+	return choices
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tally
+
+import (
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+// TestTally tests that the first choice to reach the threshold is decided
+func TestTally(t *testing.T) {
+	require := require.New(t)
+
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	nodeIDs := make([]ids.NodeID, 4)
+	for i, light := range []uint64{40, 30, 20, 10} {
+		nodeIDs[i] = ids.GenerateTestNodeID()
+		require.NoError(m.AddStaker(netID, nodeIDs[i], nil, ids.Empty, light))
+	}
+
+	tally, err := New(m, netID, validators.DefaultQuorumConfig())
+	require.NoError(err)
+
+	yes, no := ids.GenerateTestID(), ids.GenerateTestID()
+	decided, err := tally.Vote(nodeIDs[0], yes)
+	require.NoError(err)
+	require.False(decided)
+
+	decided, err = tally.Vote(nodeIDs[2], no)
+	require.NoError(err)
+	require.False(decided)
+
+	_, err = tally.Vote(nodeIDs[0], no)
+	require.ErrorIs(err, ErrDuplicateVote)
+	_, err = tally.Vote(ids.GenerateTestNodeID(), yes)
+	require.ErrorIs(err, ErrNotValidator)
+
+	// 70/100 reaches 67%
+	decided, err = tally.Vote(nodeIDs[1], yes)
+	require.NoError(err)
+	require.True(decided)
+
+	// Later votes are counted but don't change the decision
+	decided, err = tally.Vote(nodeIDs[3], no)
+	require.NoError(err)
+	require.True(decided)
+
+	decision, ok := tally.Decision()
+	require.True(ok)
+	require.Equal(yes, decision)
+	require.Equal(uint64(70), tally.Stake(yes))
+	require.Equal(uint64(30), tally.Stake(no))
+	require.Equal(uint64(100), tally.Voted())
+	require.Equal([]Choice{{ID: yes, Stake: 70}, {ID: no, Stake: 30}}, tally.Choices())
+}
+
+// TestTallyMinSigners tests that the decided choice needs enough voters
+func TestTallyMinSigners(t *testing.T) {
+	require := require.New(t)
+
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	whale, minnow := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, whale, nil, ids.Empty, 90))
+	require.NoError(m.AddStaker(netID, minnow, nil, ids.Empty, 10))
+
+	threshold := validators.DefaultQuorumConfig()
+	threshold.MinSigners = 2
+	tally, err := New(m, netID, threshold)
+	require.NoError(err)
+
+	choice := ids.GenerateTestID()
+	decided, err := tally.Vote(whale, choice)
+	require.NoError(err)
+	require.False(decided)
+
+	decided, err = tally.Vote(minnow, choice)
+	require.NoError(err)
+	require.True(decided)
+
+	_, err = New(m, netID, validators.QuorumConfig{})
+	require.ErrorIs(err, validators.ErrInvalidQuorum)
+}
@@ -0,0 +1,129 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/luxfi/ids"
+)
+
+// TenantListener is notified of validator changes in every tenant of a
+// Tenants, for process wide metrics and logging
+type TenantListener interface {
+	OnValidatorAdded(tenant string, netID ids.ID, nodeID ids.NodeID, light uint64)
+	OnValidatorRemoved(tenant string, netID ids.ID, nodeID ids.NodeID, light uint64)
+	OnValidatorLightChanged(tenant string, netID ids.ID, nodeID ids.NodeID, oldLight, newLight uint64)
+}
+
+// Tenants hosts isolated managers, one per tenant key, such as the chains or
+// VMs embedded in one node process. Each tenant has its own validators,
+// listeners and limits; TenantListeners observe all of them.
+type Tenants struct {
+	mu            sync.RWMutex
+	defaultLimits Limits
+	tenants       map[string]*manager
+	listeners     []TenantListener
+}
+
+// NewTenants returns an empty Tenants whose tenants start with
+// [defaultLimits]
+func NewTenants(defaultLimits Limits) *Tenants {
+	return &Tenants{
+		defaultLimits: defaultLimits,
+		tenants:       make(map[string]*manager),
+	}
+}
+
+// Get returns the manager of [tenant], creating it on first use. The manager
+// also implements the manager extension interfaces, such as LimitManager.
+func (t *Tenants) Get(tenant string) Manager {
+	t.mu.RLock()
+	m, ok := t.tenants[tenant]
+	t.mu.RUnlock()
+	if ok {
+		return m
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if m, ok := t.tenants[tenant]; ok {
+		return m
+	}
+	m = NewManager()
+	m.limits = t.defaultLimits
+	for _, listener := range t.listeners {
+		m.RegisterCallbackListener(&tenantListener{tenant: tenant, listener: listener})
+	}
+	t.tenants[tenant] = m
+	return m
+}
+
+// Lookup returns the manager of [tenant] if it exists
+func (t *Tenants) Lookup(tenant string) (Manager, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	m, ok := t.tenants[tenant]
+	if !ok {
+		return nil, false
+	}
+	return m, true
+}
+
+// Remove drops [tenant] and all of its state. Listeners aren't notified of
+// the dropped validators.
+func (t *Tenants) Remove(tenant string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, ok := t.tenants[tenant]
+	delete(t.tenants, tenant)
+	return ok
+}
+
+// Keys returns the tenant keys in order
+func (t *Tenants) Keys() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	keys := make([]string, 0, len(t.tenants))
+	for key := range t.tenants {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// RegisterTenantListener registers [listener] with every current and future
+// tenant. Existing validators are reported as added.
+func (t *Tenants) RegisterTenantListener(listener TenantListener) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.listeners = append(t.listeners, listener)
+	for tenant, m := range t.tenants {
+		m.RegisterCallbackListener(&tenantListener{tenant: tenant, listener: listener})
+	}
+}
+
+// tenantListener tags a tenant's notifications with its key
+type tenantListener struct {
+	tenant   string
+	listener TenantListener
+}
+
+func (l *tenantListener) OnValidatorAdded(netID ids.ID, nodeID ids.NodeID, light uint64) {
+	l.listener.OnValidatorAdded(l.tenant, netID, nodeID, light)
+}
+
+func (l *tenantListener) OnValidatorRemoved(netID ids.ID, nodeID ids.NodeID, light uint64) {
+	l.listener.OnValidatorRemoved(l.tenant, netID, nodeID, light)
+}
+
+func (l *tenantListener) OnValidatorLightChanged(netID ids.ID, nodeID ids.NodeID, oldLight, newLight uint64) {
+	l.listener.OnValidatorLightChanged(l.tenant, netID, nodeID, oldLight, newLight)
+}
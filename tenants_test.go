@@ -0,0 +1,66 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+type tenantEvent struct {
+	tenant string
+	nodeID ids.NodeID
+	light  uint64
+}
+
+type testTenantListener struct {
+	added []tenantEvent
+}
+
+func (l *testTenantListener) OnValidatorAdded(tenant string, _ ids.ID, nodeID ids.NodeID, light uint64) {
+	l.added = append(l.added, tenantEvent{tenant: tenant, nodeID: nodeID, light: light})
+}
+
+func (*testTenantListener) OnValidatorRemoved(string, ids.ID, ids.NodeID, uint64) {}
+
+func (*testTenantListener) OnValidatorLightChanged(string, ids.ID, ids.NodeID, uint64, uint64) {}
+
+// TestTenants tests that tenants are isolated but observable together
+func TestTenants(t *testing.T) {
+	require := require.New(t)
+
+	tenants := NewTenants(Limits{MaxValidatorsPerNet: 1})
+	netID := ids.GenerateTestID()
+	nodeID1, nodeID2 := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+
+	cChain := tenants.Get("C")
+	require.NoError(cChain.AddStaker(netID, nodeID1, nil, ids.Empty, 10))
+	require.Same(cChain, tenants.Get("C"))
+
+	listener := &testTenantListener{}
+	tenants.RegisterTenantListener(listener)
+	require.Equal([]tenantEvent{{tenant: "C", nodeID: nodeID1, light: 10}}, listener.added)
+
+	// Tenants share a net ID without sharing validators or limits
+	xChain := tenants.Get("X")
+	require.NoError(xChain.AddStaker(netID, nodeID2, nil, ids.Empty, 20))
+	require.Equal([]ids.NodeID{nodeID1}, cChain.GetValidatorIDs(netID))
+	require.Equal([]ids.NodeID{nodeID2}, xChain.GetValidatorIDs(netID))
+	require.ErrorIs(xChain.AddStaker(netID, nodeID1, nil, ids.Empty, 20), ErrLimitExceeded)
+	require.Equal(tenantEvent{tenant: "X", nodeID: nodeID2, light: 20}, listener.added[1])
+
+	// Limits are per tenant
+	xChain.(LimitManager).SetLimits(Limits{})
+	require.NoError(xChain.AddStaker(netID, nodeID1, nil, ids.Empty, 20))
+	require.ErrorIs(cChain.AddStaker(netID, nodeID2, nil, ids.Empty, 20), ErrLimitExceeded)
+
+	require.Equal([]string{"C", "X"}, tenants.Keys())
+	require.True(tenants.Remove("X"))
+	require.False(tenants.Remove("X"))
+	_, ok := tenants.Lookup("X")
+	require.False(ok)
+	require.Equal([]string{"C"}, tenants.Keys())
+}
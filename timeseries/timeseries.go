@@ -0,0 +1,207 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package timeseries keeps a short history of the validator count and total
+// light of each net in memory, so trends can be shown without an external
+// time-series database
+package timeseries
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/luxfi/ids"
+
+	validators "github.com/luxfi/validators"
+)
+
+var ErrInvalidConfig = errors.New("invalid time-series config")
+
+// Config is the resolution and retention of a Recorder
+type Config struct {
+	// Interval is the resolution of the series. Samples within the same
+	// interval replace each other, so each net keeps at most one point per
+	// interval.
+	Interval time.Duration
+	// Retention is how long points are kept. It is rounded up to a multiple
+	// of Interval.
+	Retention time.Duration
+}
+
+// DefaultConfig keeps a day of points at minute resolution
+func DefaultConfig() Config {
+	return Config{
+		Interval:  time.Minute,
+		Retention: 24 * time.Hour,
+	}
+}
+
+// Verify returns an error if the config can't be recorded
+func (c Config) Verify() error {
+	switch {
+	case c.Interval <= 0:
+		return fmt.Errorf("%w: interval %s must be positive", ErrInvalidConfig, c.Interval)
+	case c.Retention < c.Interval:
+		return fmt.Errorf("%w: retention %s is shorter than interval %s", ErrInvalidConfig, c.Retention, c.Interval)
+	}
+	return nil
+}
+
+// capacity returns the number of points a net keeps
+func (c Config) capacity() int {
+	return int((c.Retention + c.Interval - 1) / c.Interval)
+}
+
+// Point is the state of a net at the end of an interval
+type Point struct {
+	// Time is when the point was sampled
+	Time time.Time
+	// Count is the number of validators
+	Count int
+	// Light is the total light of the validators
+	Light uint64
+}
+
+// series is a ring buffer of the points of a net, oldest first
+type series struct {
+	count  int
+	light  uint64
+	points []Point
+	start  int
+	len    int
+}
+
+// record samples the net at [now], replacing the latest point if it is in
+// the same interval
+func (s *series) record(now time.Time, interval time.Duration) {
+	point := Point{Time: now, Count: s.count, Light: s.light}
+	if s.len > 0 {
+		last := (s.start + s.len - 1) % len(s.points)
+		if now.Truncate(interval).Equal(s.points[last].Time.Truncate(interval)) {
+			s.points[last] = point
+			return
+		}
+	}
+	if s.len < len(s.points) {
+		s.points[(s.start+s.len)%len(s.points)] = point
+		s.len++
+		return
+	}
+	s.points[s.start] = point
+	s.start = (s.start + 1) % len(s.points)
+}
+
+// Recorder records the validator count and total light of every net each time
+// they change and whenever Sample is called. It is a
+// validators.ManagerCallbackListener, so it tracks the nets from the
+// manager's notifications and never calls back into the manager.
+type Recorder struct {
+	config Config
+	now    func() time.Time
+
+	mu   sync.Mutex
+	nets map[ids.ID]*series
+}
+
+var _ validators.ManagerCallbackListener = (*Recorder)(nil)
+
+// New returns a Recorder without history. [now] is the clock; nil uses
+// time.Now. Register it with Manager.RegisterCallbackListener.
+func New(config Config, now func() time.Time) (*Recorder, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &Recorder{
+		config: config,
+		now:    now,
+		nets:   make(map[ids.ID]*series),
+	}, nil
+}
+
+// Sample records the current state of every net, so nets that don't change
+// still have a point in each interval. Call it on a ticker of Config.Interval.
+func (r *Recorder) Sample() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	for _, s := range r.nets {
+		s.record(now, r.config.Interval)
+	}
+}
+
+// Last returns the points of [netID] sampled within [d] of now, oldest first.
+// Points older than the retention are gone, so [d] beyond it returns every
+// point kept.
+func (r *Recorder) Last(netID ids.ID, d time.Duration) []Point {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.nets[netID]
+	if !ok {
+		return nil
+	}
+	since := r.now().Add(-d)
+	var points []Point
+	for i := range s.len {
+		point := s.points[(s.start+i)%len(s.points)]
+		if !point.Time.Before(since) {
+			points = append(points, point)
+		}
+	}
+	return points
+}
+
+// NetIDs returns the nets with history, in ascending order
+func (r *Recorder) NetIDs() []ids.ID {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	netIDs := make([]ids.ID, 0, len(r.nets))
+	for netID := range r.nets {
+		netIDs = append(netIDs, netID)
+	}
+	slices.SortFunc(netIDs, func(a, b ids.ID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	return netIDs
+}
+
+// OnValidatorAdded implements validators.ManagerCallbackListener
+func (r *Recorder) OnValidatorAdded(netID ids.ID, _ ids.NodeID, light uint64) {
+	r.update(netID, 1, light, 0)
+}
+
+// OnValidatorRemoved implements validators.ManagerCallbackListener
+func (r *Recorder) OnValidatorRemoved(netID ids.ID, _ ids.NodeID, light uint64) {
+	r.update(netID, -1, 0, light)
+}
+
+// OnValidatorLightChanged implements validators.ManagerCallbackListener
+func (r *Recorder) OnValidatorLightChanged(netID ids.ID, _ ids.NodeID, oldLight, newLight uint64) {
+	r.update(netID, 0, newLight, oldLight)
+}
+
+// update applies a change to the state of [netID] and records it. The
+// manager keeps the total light of a net within a uint64, so it can't
+// overflow.
+func (r *Recorder) update(netID ids.ID, countDelta int, added, removed uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.nets[netID]
+	if !ok {
+		s = &series{points: make([]Point, r.config.capacity())}
+		r.nets[netID] = s
+	}
+	s.count += countDelta
+	s.light = s.light + added - removed
+	s.record(r.now(), r.config.Interval)
+}
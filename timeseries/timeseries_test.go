@@ -0,0 +1,91 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timeseries
+
+import (
+	"testing"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+// TestConfigVerify tests config validation
+func TestConfigVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		err    error
+	}{
+		{
+			name:   "default",
+			config: DefaultConfig(),
+		},
+		{
+			name:   "zero interval",
+			config: Config{Retention: time.Hour},
+			err:    ErrInvalidConfig,
+		},
+		{
+			name:   "retention shorter than interval",
+			config: Config{Interval: time.Hour, Retention: time.Minute},
+			err:    ErrInvalidConfig,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.config.Verify(), test.err)
+		})
+	}
+}
+
+// TestRecorder tests that changes are recorded once per interval and old
+// points are dropped
+func TestRecorder(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		now    = time.Unix(3600, 0)
+		m      = validators.NewManager()
+		netID  = ids.GenerateTestID()
+		node1  = ids.GenerateTestNodeID()
+		node2  = ids.GenerateTestNodeID()
+		config = Config{Interval: time.Minute, Retention: 3 * time.Minute}
+	)
+	require.NoError(m.AddStaker(netID, node1, nil, ids.Empty, 10))
+
+	r, err := New(config, func() time.Time { return now })
+	require.NoError(err)
+	m.RegisterCallbackListener(r)
+	require.Equal([]ids.ID{netID}, r.NetIDs())
+	require.Equal([]Point{{Time: now, Count: 1, Light: 10}}, r.Last(netID, time.Hour))
+
+	// Changes within an interval replace its point
+	now = now.Add(10 * time.Second)
+	require.NoError(m.AddStaker(netID, node2, nil, ids.Empty, 20))
+	require.NoError(m.AddWeight(netID, node1, 5))
+	require.Equal([]Point{{Time: now, Count: 2, Light: 35}}, r.Last(netID, time.Hour))
+
+	now = now.Add(time.Minute)
+	require.NoError(m.RemoveWeight(netID, node2, 20))
+	now = now.Add(time.Minute)
+	r.Sample()
+	require.Equal([]Point{
+		{Time: now.Add(-2 * time.Minute), Count: 2, Light: 35},
+		{Time: now.Add(-time.Minute), Count: 1, Light: 15},
+		{Time: now, Count: 1, Light: 15},
+	}, r.Last(netID, time.Hour))
+	require.Equal([]Point{{Time: now, Count: 1, Light: 15}}, r.Last(netID, 30*time.Second))
+
+	// The oldest point is dropped once the retention is full
+	now = now.Add(time.Minute)
+	r.Sample()
+	points := r.Last(netID, time.Hour)
+	require.Len(points, 3)
+	require.Equal(Point{Count: 1, Light: 15, Time: now.Add(-2 * time.Minute)}, points[0])
+
+	require.Nil(r.Last(ids.GenerateTestID(), time.Hour))
+}
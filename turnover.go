@@ -0,0 +1,126 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math"
+)
+
+var (
+	ErrInvalidTurnoverBound = errors.New("invalid turnover bound")
+	ErrTurnoverExceeded     = errors.New("validator set turnover exceeded")
+)
+
+// TurnoverBound is the largest share of either set's weight, Numerator /
+// Denominator, that may change between two heights
+type TurnoverBound struct {
+	Numerator   uint64
+	Denominator uint64
+}
+
+// Verify returns an error if the bound isn't a share between 0 and 1
+func (b TurnoverBound) Verify() error {
+	switch {
+	case b.Denominator == 0:
+		return fmt.Errorf("%w: zero denominator", ErrInvalidTurnoverBound)
+	case b.Numerator > b.Denominator:
+		return fmt.Errorf("%w: %d/%d exceeds 1", ErrInvalidTurnoverBound, b.Numerator, b.Denominator)
+	}
+	return nil
+}
+
+// Turnover compares the weight of two validator sets
+type Turnover struct {
+	FromWeight uint64
+	ToWeight   uint64
+	// Overlap is the weight both sets agree on: for every node with the same
+	// public key in both sets, the smaller of its two weights
+	Overlap uint64
+}
+
+// MeasureTurnover returns the Turnover from [from] to [to], weighting
+// validators by their economic weight
+func MeasureTurnover(from, to map[ids.NodeID]*GetValidatorOutput) (Turnover, error) {
+	var (
+		turnover Turnover
+		err      error
+	)
+	for _, vdr := range from {
+		turnover.FromWeight, err = math.Add64(turnover.FromWeight, vdr.Weight)
+		if err != nil {
+			return Turnover{}, fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+		}
+	}
+	for nodeID, vdr := range to {
+		turnover.ToWeight, err = math.Add64(turnover.ToWeight, vdr.Weight)
+		if err != nil {
+			return Turnover{}, fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+		}
+		// A node that rotated its key can't sign for the old set
+		if old, ok := from[nodeID]; ok && bytes.Equal(old.PublicKey, vdr.PublicKey) {
+			// Can't overflow, as the overlap is at most FromWeight
+			turnover.Overlap += min(old.Weight, vdr.Weight)
+		}
+	}
+	return turnover, nil
+}
+
+// TurnoverError is returned by CheckTurnover when the sets at two heights
+// differ by more than the bound. It wraps ErrTurnoverExceeded.
+type TurnoverError struct {
+	FromHeight uint64
+	ToHeight   uint64
+	Turnover   Turnover
+	Bound      TurnoverBound
+}
+
+func (e *TurnoverError) Error() string {
+	return fmt.Sprintf("%s: heights %d -> %d share %d of %d -> %d weight, bound is %d/%d",
+		ErrTurnoverExceeded, e.FromHeight, e.ToHeight,
+		e.Turnover.Overlap, e.Turnover.FromWeight, e.Turnover.ToWeight,
+		e.Bound.Numerator, e.Bound.Denominator,
+	)
+}
+
+func (*TurnoverError) Unwrap() error {
+	return ErrTurnoverExceeded
+}
+
+// CheckTurnover returns a *TurnoverError if more than [bound] of the weight of
+// either [from] or [to] isn't shared by the other set. Light clients trusting
+// the set at [fromHeight] use it to refuse jumps to [toHeight] that the
+// trusted set can't vouch for.
+func CheckTurnover(
+	fromHeight uint64,
+	from map[ids.NodeID]*GetValidatorOutput,
+	toHeight uint64,
+	to map[ids.NodeID]*GetValidatorOutput,
+	bound TurnoverBound,
+) error {
+	if err := bound.Verify(); err != nil {
+		return err
+	}
+	turnover, err := MeasureTurnover(from, to)
+	if err != nil {
+		return err
+	}
+
+	// The overlap must be at least 1 - bound of both sets
+	retained := bound.Denominator - bound.Numerator
+	if !meetsQuorum(turnover.Overlap, turnover.FromWeight, retained, bound.Denominator) ||
+		!meetsQuorum(turnover.Overlap, turnover.ToWeight, retained, bound.Denominator) {
+		return &TurnoverError{
+			FromHeight: fromHeight,
+			ToHeight:   toHeight,
+			Turnover:   turnover,
+			Bound:      bound,
+		}
+	}
+	return nil
+}
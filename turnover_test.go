@@ -0,0 +1,96 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCheckTurnover tests the turnover bound between two sets
+func TestCheckTurnover(t *testing.T) {
+	nodeIDs := []ids.NodeID{ids.GenerateTestNodeID(), ids.GenerateTestNodeID(), ids.GenerateTestNodeID()}
+	vdr := func(i int, pk string, weight uint64) *GetValidatorOutput {
+		return &GetValidatorOutput{NodeID: nodeIDs[i], PublicKey: []byte(pk), Light: weight, Weight: weight}
+	}
+	from := map[ids.NodeID]*GetValidatorOutput{
+		nodeIDs[0]: vdr(0, "a", 50),
+		nodeIDs[1]: vdr(1, "b", 50),
+	}
+	bound := TurnoverBound{Numerator: 1, Denominator: 3}
+
+	tests := []struct {
+		name     string
+		to       map[ids.NodeID]*GetValidatorOutput
+		overlap  uint64
+		exceeded bool
+	}{
+		{
+			name:    "unchanged",
+			to:      from,
+			overlap: 100,
+		},
+		{
+			name: "small change",
+			to: map[ids.NodeID]*GetValidatorOutput{
+				nodeIDs[0]: vdr(0, "a", 40),
+				nodeIDs[1]: vdr(1, "b", 50),
+				nodeIDs[2]: vdr(2, "c", 20),
+			},
+			overlap: 90,
+		},
+		{
+			name: "validator removed",
+			to: map[ids.NodeID]*GetValidatorOutput{
+				nodeIDs[0]: vdr(0, "a", 50),
+			},
+			overlap:  50,
+			exceeded: true,
+		},
+		{
+			name: "diluted by new validator",
+			to: map[ids.NodeID]*GetValidatorOutput{
+				nodeIDs[0]: vdr(0, "a", 50),
+				nodeIDs[1]: vdr(1, "b", 50),
+				nodeIDs[2]: vdr(2, "c", 100),
+			},
+			overlap:  100,
+			exceeded: true,
+		},
+		{
+			name: "key rotated",
+			to: map[ids.NodeID]*GetValidatorOutput{
+				nodeIDs[0]: vdr(0, "a", 50),
+				nodeIDs[1]: vdr(1, "rotated", 50),
+			},
+			overlap:  50,
+			exceeded: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			turnover, err := MeasureTurnover(from, test.to)
+			require.NoError(err)
+			require.Equal(test.overlap, turnover.Overlap)
+
+			err = CheckTurnover(10, from, 11, test.to, bound)
+			if !test.exceeded {
+				require.NoError(err)
+				return
+			}
+			require.ErrorIs(err, ErrTurnoverExceeded)
+			var turnoverErr *TurnoverError
+			require.ErrorAs(err, &turnoverErr)
+			require.Equal(uint64(10), turnoverErr.FromHeight)
+			require.Equal(uint64(11), turnoverErr.ToHeight)
+			require.Equal(turnover, turnoverErr.Turnover)
+		})
+	}
+
+	require.ErrorIs(t, CheckTurnover(0, from, 1, from, TurnoverBound{}), ErrInvalidTurnoverBound)
+}
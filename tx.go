@@ -0,0 +1,303 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/luxfi/ids"
+)
+
+var ErrTxDone = errors.New("transaction already committed or rolled back")
+
+// TxManager groups validator changes so they're applied all together or not
+// at all. Changes are staged on a Tx without touching the manager; Commit
+// checks every change against the state left by the ones before it and only
+// then applies them under a single lock acquisition, so listeners are
+// notified on commit and never of a change that was rolled back.
+type TxManager interface {
+	// Begin starts a transaction
+	Begin() *Tx
+	// WithTransaction commits the changes staged by [f], or rolls them back
+	// if [f] returns an error
+	WithTransaction(f func(tx *Tx) error) error
+}
+
+var _ TxManager = (*manager)(nil)
+
+type txOpKind uint8
+
+const (
+	txAddStaker txOpKind = iota
+	txAddWeight
+	txRemoveWeight
+	txSetWeight
+	txRemoveStaker
+	txSetEconomicWeight
+	txSetMetadata
+	txSetExtension
+	txSetWeightScale
+	txAddLight
+	txRemoveLight
+	txAddDelegator
+)
+
+// txOpNames are the MetricOperations labels of the kinds of changes, if they
+// are counted
+var txOpNames = [...]string{
+	txAddStaker:         OpAddStaker,
+	txAddWeight:         OpAddWeight,
+	txRemoveWeight:      OpRemoveWeight,
+	txSetWeight:         OpSetWeight,
+	txRemoveStaker:      OpRemoveStaker,
+	txSetEconomicWeight: "",
+	txSetMetadata:       "",
+	txSetExtension:      "",
+	txSetWeightScale:    "",
+	txAddLight:          OpAddLight,
+	txRemoveLight:       OpRemoveLight,
+	txAddDelegator:      "",
+}
+
+// txOp is a change staged on a Tx
+type txOp struct {
+	kind      txOpKind
+	netID     ids.ID
+	nodeID    ids.NodeID
+	publicKey []byte
+	txID      ids.ID
+	light     uint64
+	metadata  *ValidatorMetadata
+	key       string
+	value     []byte
+	delegator ids.ShortID
+}
+
+// Tx is a set of changes applied in order by Commit. The methods mirror those
+// of Manager and the extension interfaces of the manager; errors they would
+// return are reported by Commit. A Tx isn't
+// safe for concurrent use.
+type Tx struct {
+	m    *manager
+	ops  []txOp
+	done bool
+}
+
+// Begin starts a transaction
+func (m *manager) Begin() *Tx {
+	return &Tx{m: m}
+}
+
+// WithTransaction runs [f] in a transaction
+func (m *manager) WithTransaction(f func(tx *Tx) error) error {
+	tx := m.Begin()
+	if err := f(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// AddStaker stages Manager.AddStaker
+func (tx *Tx) AddStaker(netID ids.ID, nodeID ids.NodeID, publicKey []byte, txID ids.ID, light uint64) error {
+	return tx.stage(txOp{kind: txAddStaker, netID: netID, nodeID: nodeID, publicKey: publicKey, txID: txID, light: light})
+}
+
+// AddWeight stages Manager.AddWeight
+func (tx *Tx) AddWeight(netID ids.ID, nodeID ids.NodeID, light uint64) error {
+	return tx.stage(txOp{kind: txAddWeight, netID: netID, nodeID: nodeID, light: light})
+}
+
+// RemoveWeight stages Manager.RemoveWeight
+func (tx *Tx) RemoveWeight(netID ids.ID, nodeID ids.NodeID, light uint64) error {
+	return tx.stage(txOp{kind: txRemoveWeight, netID: netID, nodeID: nodeID, light: light})
+}
+
+// SetWeight stages StakerManager.SetWeight
+func (tx *Tx) SetWeight(netID ids.ID, nodeID ids.NodeID, light uint64) error {
+	return tx.stage(txOp{kind: txSetWeight, netID: netID, nodeID: nodeID, light: light})
+}
+
+// RemoveStaker stages StakerManager.RemoveStaker
+func (tx *Tx) RemoveStaker(netID ids.ID, nodeID ids.NodeID) error {
+	return tx.stage(txOp{kind: txRemoveStaker, netID: netID, nodeID: nodeID})
+}
+
+// AddLight stages LightManager.AddLight
+func (tx *Tx) AddLight(netID ids.ID, nodeID ids.NodeID, light uint64) error {
+	return tx.stage(txOp{kind: txAddLight, netID: netID, nodeID: nodeID, light: light})
+}
+
+// RemoveLight stages LightManager.RemoveLight
+func (tx *Tx) RemoveLight(netID ids.ID, nodeID ids.NodeID, light uint64) error {
+	return tx.stage(txOp{kind: txRemoveLight, netID: netID, nodeID: nodeID, light: light})
+}
+
+// SetEconomicWeight stages LightManager.SetEconomicWeight
+func (tx *Tx) SetEconomicWeight(netID ids.ID, nodeID ids.NodeID, weight uint64) error {
+	return tx.stage(txOp{kind: txSetEconomicWeight, netID: netID, nodeID: nodeID, light: weight})
+}
+
+// AddDelegator stages DelegationManager.AddDelegator
+func (tx *Tx) AddDelegator(netID ids.ID, nodeID ids.NodeID, delegatorID ids.ShortID, weight uint64) error {
+	return tx.stage(txOp{kind: txAddDelegator, netID: netID, nodeID: nodeID, delegator: delegatorID, light: weight})
+}
+
+// SetMetadata stages MetadataManager.SetMetadata
+func (tx *Tx) SetMetadata(netID ids.ID, nodeID ids.NodeID, metadata *ValidatorMetadata) error {
+	return tx.stage(txOp{kind: txSetMetadata, netID: netID, nodeID: nodeID, metadata: metadata.Clone()})
+}
+
+// SetExtension stages ExtensionManager.SetExtension
+func (tx *Tx) SetExtension(netID ids.ID, nodeID ids.NodeID, key string, value []byte) error {
+	return tx.stage(txOp{kind: txSetExtension, netID: netID, nodeID: nodeID, key: key, value: slices.Clone(value)})
+}
+
+// SetWeightScale stages WeightScaleManager.SetWeightScale
+func (tx *Tx) SetWeightScale(netID ids.ID, scale uint64) error {
+	return tx.stage(txOp{kind: txSetWeightScale, netID: netID, light: scale})
+}
+
+func (tx *Tx) stage(op txOp) error {
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.ops = append(tx.ops, op)
+	return nil
+}
+
+// Rollback discards the staged changes. Rolling back a finished transaction
+// is a no-op.
+func (tx *Tx) Rollback() {
+	tx.done = true
+	tx.ops = nil
+}
+
+// Commit applies the staged changes. If any change would fail, none are
+// applied and the transaction can't be committed again.
+func (tx *Tx) Commit() error {
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.done = true
+
+	m := tx.m
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// The changes are tried on a copy first, with the same checks that apply
+	// them, so a failing change leaves the manager untouched
+	if _, err := m.scratch(tx.ops).applyTx(tx.ops); err != nil {
+		return err
+	}
+	notify, err := m.applyTx(tx.ops)
+	if err != nil {
+		// Unreachable: the copy holds everything the changes read
+		return fmt.Errorf("couldn't apply tried changes: %w", err)
+	}
+	for _, op := range tx.ops {
+		if name := txOpNames[op.kind]; name != "" {
+			m.metrics.operation(name)
+		}
+	}
+	m.notify(notifyAll(notify))
+	return nil
+}
+
+// applyTx applies [ops] in order and returns their notifications, stopping at
+// the first change that fails. It assumes the lock is held.
+func (m *manager) applyTx(ops []txOp) ([]func(ManagerCallbackListener), error) {
+	notify := make([]func(ManagerCallbackListener), 0, len(ops))
+	for i, op := range ops {
+		var (
+			f   func(ManagerCallbackListener)
+			err error
+		)
+		switch op.kind {
+		case txAddStaker:
+			f, err = m.addStaker(op.netID, op.nodeID, op.publicKey, op.txID, op.light)
+		case txAddWeight:
+			f, err = m.addWeight(op.netID, op.nodeID, op.light)
+		case txRemoveWeight:
+			f, err = m.removeWeight(op.netID, op.nodeID, op.light)
+		case txSetWeight:
+			f, err = m.setWeight(op.netID, op.nodeID, op.light)
+		case txRemoveStaker:
+			f, err = m.removeStaker(op.netID, op.nodeID)
+		case txSetEconomicWeight:
+			err = m.setEconomicWeight(op.netID, op.nodeID, op.light)
+		case txSetMetadata:
+			err = m.setMetadata(op.netID, op.nodeID, op.metadata)
+		case txSetExtension:
+			err = m.setExtension(op.netID, op.nodeID, op.key, op.value)
+		case txSetWeightScale:
+			err = m.setWeightScale(op.netID, op.light)
+		case txAddLight:
+			f, err = m.addLight(op.netID, op.nodeID, op.light)
+		case txRemoveLight:
+			f, err = m.removeLight(op.netID, op.nodeID, op.light)
+		case txAddDelegator:
+			f, err = m.addDelegator(op.netID, op.nodeID, op.delegator, op.light)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("change %d: %w", i, err)
+		}
+		if f != nil {
+			notify = append(notify, f)
+		}
+	}
+	return notify, nil
+}
+
+// scratch returns a manager without listeners, metrics or history holding
+// copies of the state of [m], so [ops] can be tried on it without touching
+// [m]. Every map is copied, but only the records of the nets and the
+// memberships of the nodes [ops] touch, so trying changes doesn't cost as
+// much as copying the whole manager. TestTxScratch fails for fields added to
+// the manager without being copied here. It assumes the lock is held.
+func (m *manager) scratch(ops []txOp) *manager {
+	s := *m
+	s.listeners = &callbackListeners{}
+	s.delegationListeners = nil
+	s.balanceListeners = nil
+	s.assetListeners = nil
+	s.freezeListeners = nil
+	s.keyListeners = nil
+	s.metrics = nil
+	s.logger = nil
+	s.snapshots = newSnapshots()
+	s.history = &history{}
+
+	s.validators = maps.Clone(m.validators)
+	s.memberships = maps.Clone(m.memberships)
+	copied := make(map[ids.ID]struct{})
+	for _, op := range ops {
+		// Nets and nodes are copied once, before any change is tried
+		if _, ok := copied[op.netID]; !ok {
+			copied[op.netID] = struct{}{}
+			if vdrs, ok := m.validators[op.netID]; ok {
+				s.validators[op.netID] = shareValidators(vdrs)
+			}
+		}
+		if nets, ok := m.memberships[op.nodeID]; ok {
+			s.memberships[op.nodeID] = maps.Clone(nets)
+		}
+	}
+	s.txIDs = maps.Clone(m.txIDs)
+	s.delegations = m.delegations.clone()
+	s.assets = m.assets.clone()
+	s.balances = maps.Clone(m.balances)
+	s.feeConfigs = maps.Clone(m.feeConfigs)
+	s.balanceClocks = maps.Clone(m.balanceClocks)
+	s.weightModes = maps.Clone(m.weightModes)
+	s.bigWeights = maps.Clone(m.bigWeights)
+	s.weightScales = maps.Clone(m.weightScales)
+	s.allowlists = maps.Clone(m.allowlists)
+	s.denylists = maps.Clone(m.denylists)
+	s.frozen = maps.Clone(m.frozen)
+	return &s
+}
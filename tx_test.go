@@ -0,0 +1,270 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"errors"
+	"maps"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// TestManagerTxCommit tests that committed changes are applied in order and
+// notified on commit
+func TestManagerTxCommit(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	var (
+		existing = ids.GenerateTestNodeID()
+		added    = ids.GenerateTestNodeID()
+	)
+	require.NoError(m.AddStaker(netID, existing, nil, ids.Empty, 100))
+
+	listener := &testListener{}
+	m.RegisterCallbackListener(listener)
+	listener.added = nil
+
+	tx := m.Begin()
+	require.NoError(tx.AddStaker(netID, added, nil, ids.Empty, 10))
+	require.NoError(tx.AddWeight(netID, added, 5))
+	require.NoError(tx.RemoveWeight(netID, existing, 100))
+	require.Empty(listener.added)
+	require.Equal(uint64(100), m.GetLight(netID, existing))
+
+	require.NoError(tx.Commit())
+	require.Equal([]ids.NodeID{added}, m.GetValidatorIDs(netID))
+	require.Equal(uint64(15), m.GetLight(netID, added))
+	require.Equal([]validatorEvent{{netID, added, 10}}, listener.added)
+	require.Equal([]lightChangedEvent{{netID, added, 10, 15}}, listener.changed)
+	require.Equal([]validatorEvent{{netID, existing, 100}}, listener.removed)
+
+	require.ErrorIs(tx.Commit(), ErrTxDone)
+	require.ErrorIs(tx.AddWeight(netID, added, 1), ErrTxDone)
+}
+
+// TestManagerTxAtomic tests that a transaction with a failing change applies
+// nothing
+func TestManagerTxAtomic(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	m.SetLimits(Limits{MaxValidatorsPerNet: 2})
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+
+	listener := &testListener{}
+	m.RegisterCallbackListener(listener)
+	listener.added = nil
+
+	// The third validator only exceeds the limit given the second
+	err := m.WithTransaction(func(tx *Tx) error {
+		require.NoError(tx.AddWeight(netID, nodeID, 50))
+		require.NoError(tx.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 1))
+		return tx.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 1)
+	})
+	require.ErrorIs(err, ErrLimitExceeded)
+	require.Equal(1, m.Count(netID))
+	require.Equal(uint64(100), m.GetLight(netID, nodeID))
+	require.Empty(listener.added)
+	require.Empty(listener.changed)
+
+	// Validators removed earlier in the transaction free up capacity
+	replacements := []ids.NodeID{ids.GenerateTestNodeID(), ids.GenerateTestNodeID()}
+	require.NoError(m.WithTransaction(func(tx *Tx) error {
+		require.NoError(tx.RemoveWeight(netID, nodeID, 100))
+		for _, replacement := range replacements {
+			require.NoError(tx.AddStaker(netID, replacement, nil, ids.Empty, 1))
+		}
+		return nil
+	}))
+	require.ElementsMatch(replacements, m.GetValidatorIDs(netID))
+
+	// Errors returned by the closure roll the transaction back
+	errTest := errors.New("test")
+	err = m.WithTransaction(func(tx *Tx) error {
+		require.NoError(tx.RemoveStaker(netID, replacements[0]))
+		return errTest
+	})
+	require.ErrorIs(err, errTest)
+	require.Equal(2, m.Count(netID))
+
+	// Changes tried before a failing one leave no trace, including the
+	// delegations of the validators they remove
+	delegationListener := &testDelegationListener{}
+	m.RegisterDelegationListener(delegationListener)
+	require.NoError(m.AddDelegator(netID, replacements[0], ids.GenerateTestShortID(), 10))
+	delegationListener.events = nil
+	err = m.WithTransaction(func(tx *Tx) error {
+		require.NoError(tx.RemoveStaker(netID, replacements[0]))
+		require.NoError(tx.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 1))
+		return tx.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 1)
+	})
+	require.ErrorIs(err, ErrLimitExceeded)
+	require.ElementsMatch(replacements, m.GetValidatorIDs(netID))
+	require.Equal(uint64(10), m.GetDelegatedWeight(netID, replacements[0]))
+	require.Empty(delegationListener.events)
+
+	m.FreezeNet(netID)
+	tx := m.Begin()
+	require.NoError(tx.RemoveStaker(netID, replacements[0]))
+	require.ErrorIs(tx.Commit(), ErrNetFrozen)
+	require.Equal(2, m.Count(netID))
+}
+
+// TestManagerTxMetrics tests that committed changes are counted and their
+// dispatch observed like direct mutations
+func TestManagerTxMetrics(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	registry := newTestRegistry()
+	require.NoError(m.RegisterMetrics(registry))
+
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.WithTransaction(func(tx *Tx) error {
+		require.NoError(tx.AddStaker(netID, nodeID, nil, ids.Empty, 10))
+		return tx.AddWeight(netID, nodeID, 5)
+	}))
+	require.Equal(map[string]float64{
+		OpAddStaker: 1,
+		OpAddWeight: 1,
+	}, registry.values[MetricOperations])
+	require.Len(registry.observations[MetricDispatchDuration], 1)
+	require.Equal(map[string]float64{netID.String(): 15}, registry.values[MetricLight])
+
+	// Failed transactions aren't counted
+	tx := m.Begin()
+	require.NoError(tx.RemoveWeight(netID, nodeID, 1))
+	require.NoError(tx.AddStaker(netID, nodeID, nil, ids.Empty, 1))
+	require.ErrorIs(tx.Commit(), ErrDuplicateValidator)
+	require.Len(registry.observations[MetricDispatchDuration], 1)
+	require.Equal(float64(1), registry.values[MetricOperations][OpAddStaker])
+}
+
+// TestManagerTxRollbackState tests that a failed transaction leaves the
+// delegations, asset stakes and TxID index of the validators it touched
+// unchanged
+func TestManagerTxRollbackState(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		m           = NewManager()
+		netID       = ids.GenerateTestID()
+		nodeID      = ids.GenerateTestNodeID()
+		txID        = ids.GenerateTestID()
+		delegatorID = ids.GenerateTestShortID()
+		assetID     = ids.GenerateTestID()
+	)
+	require.NoError(m.AddStaker(netID, nodeID, nil, txID, 100))
+	require.NoError(m.AddDelegator(netID, nodeID, delegatorID, 10))
+	require.NoError(m.SetAssetRate(netID, assetID, AssetRateDenominator))
+	require.NoError(m.SetAssetStake(netID, nodeID, assetID, 20))
+	expected, ok := m.GetValidator(netID, nodeID)
+	require.True(ok)
+
+	// The validator is removed and re-added before a change fails
+	m.SetStrict(true)
+	err := m.WithTransaction(func(tx *Tx) error {
+		require.NoError(tx.RemoveStaker(netID, nodeID))
+		require.NoError(tx.AddStaker(netID, nodeID, nil, ids.GenerateTestID(), 1))
+		return tx.AddWeight(netID, ids.GenerateTestNodeID(), 1)
+	})
+	require.ErrorIs(err, ErrValidatorNotFound)
+
+	actual, ok := m.GetValidator(netID, nodeID)
+	require.True(ok)
+	require.Equal(expected, actual)
+	require.Equal(map[ids.ShortID]uint64{delegatorID: 10}, m.GetDelegations(netID, nodeID))
+	require.Equal(map[ids.ID]uint64{assetID: 20}, m.GetAssetStakes(netID, nodeID))
+	require.Equal(uint64(20), m.GetAssetWeight(netID, nodeID))
+	gotNetID, gotNodeID, ok := m.GetValidatorByTxID(txID)
+	require.True(ok)
+	require.Equal(netID, gotNetID)
+	require.Equal(nodeID, gotNodeID)
+}
+
+// TestTxScratch tests that the copy changes are tried on shares no mutable
+// state with the manager, so fields added to the manager can't escape a
+// rollback
+func TestTxScratch(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.GenerateTestID(), 100))
+	m.RegisterDelegationListener(&testDelegationListener{})
+	require.NoError(m.RegisterMetrics(newTestRegistry()))
+
+	// Fields the changes only read
+	shared := map[string]bool{
+		"mu":   true,
+		"self": true,
+	}
+	s := m.scratch([]txOp{{netID: netID, nodeID: nodeID}})
+	require.NotSame(m.validators[netID][nodeID], s.validators[netID][nodeID])
+	require.True(maps.Equal(m.memberships[nodeID], s.memberships[nodeID]))
+
+	mValue, sValue := reflect.ValueOf(m).Elem(), reflect.ValueOf(s).Elem()
+	for i := range mValue.NumField() {
+		field := mValue.Type().Field(i)
+		switch field.Type.Kind() {
+		case reflect.Map, reflect.Pointer, reflect.Slice:
+		default:
+			continue
+		}
+		if shared[field.Name] || mValue.Field(i).IsNil() {
+			continue
+		}
+		require.NotEqual(mValue.Field(i).Pointer(), sValue.Field(i).Pointer(), "%s is shared with the manager", field.Name)
+	}
+}
+
+// TestManagerTxLight tests that light changes are staged, notified and
+// counted on commit
+func TestManagerTxLight(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	registry := newTestRegistry()
+	require.NoError(m.RegisterMetrics(registry))
+	listener := &testListener{}
+	m.RegisterCallbackListener(listener)
+
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+
+	require.NoError(m.WithTransaction(func(tx *Tx) error {
+		require.NoError(tx.AddLight(netID, nodeID, 50))
+		require.NoError(tx.RemoveLight(netID, nodeID, 20))
+		return tx.SetEconomicWeight(netID, nodeID, 300)
+	}))
+	vdr, ok := m.GetValidator(netID, nodeID)
+	require.True(ok)
+	require.Equal(uint64(130), vdr.Light)
+	require.Equal(uint64(300), vdr.Weight)
+	require.Equal([]lightChangedEvent{
+		{netID, nodeID, 100, 150},
+		{netID, nodeID, 150, 130},
+	}, listener.changed)
+	require.Equal(float64(1), registry.values[MetricOperations][OpAddLight])
+	require.Equal(float64(1), registry.values[MetricOperations][OpRemoveLight])
+
+	// A failing light change rolls back the others
+	tx := m.Begin()
+	require.NoError(tx.RemoveLight(netID, nodeID, 30))
+	require.NoError(tx.AddLight(netID, nodeID, math.MaxUint64))
+	require.ErrorIs(tx.Commit(), ErrWeightOverflow)
+	require.Equal(uint64(130), m.GetLight(netID, nodeID))
+	require.Len(listener.changed, 2)
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"slices"
+
+	"github.com/luxfi/ids"
+)
+
+// TxIndexManager resolves the transaction that registered a validator to the
+// validator, for transaction processors that only know the registration tx
+type TxIndexManager interface {
+	// GetValidatorByTxID returns the net and node of the validator whose
+	// TxID is [txID], if any. The empty ID is never indexed. If several
+	// validators share a TxID, the latest added that is still present is
+	// returned.
+	GetValidatorByTxID(txID ids.ID) (ids.ID, ids.NodeID, bool)
+
+	// GetValidatorsByTxID returns the node of every validator whose TxID is
+	// [txID], keyed by net. It returns nil if none is.
+	GetValidatorsByTxID(txID ids.ID) map[ids.ID]ids.NodeID
+}
+
+var _ TxIndexManager = (*manager)(nil)
+
+// GetValidatorByTxID returns the validator registered by a tx using the TxID
+// index
+func (m *manager) GetValidatorByTxID(txID ids.ID) (ids.ID, ids.NodeID, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := m.txIDs[txID]
+	if len(keys) == 0 {
+		return ids.Empty, ids.EmptyNodeID, false
+	}
+	key := keys[len(keys)-1]
+	return key.netID, key.nodeID, true
+}
+
+// GetValidatorsByTxID returns every validator registered by a tx using the
+// TxID index
+func (m *manager) GetValidatorsByTxID(txID ids.ID) map[ids.ID]ids.NodeID {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := m.txIDs[txID]
+	if len(keys) == 0 {
+		return nil
+	}
+	vdrs := make(map[ids.ID]ids.NodeID, len(keys))
+	for _, key := range keys {
+		vdrs[key.netID] = key.nodeID
+	}
+	return vdrs
+}
+
+// indexTxID adds [key] to the validators of [txID]. The slice is replaced
+// rather than appended to in place, so a scratch copy of the index never
+// shares writes with the manager. It assumes the lock is held.
+func (m *manager) indexTxID(txID ids.ID, key validatorKey) {
+	if txID == ids.Empty {
+		return
+	}
+	m.txIDs[txID] = append(slices.Clip(m.txIDs[txID]), key)
+}
+
+// unindexTxID removes [key] from the validators of [txID], so a validator
+// that leaves doesn't drop the entries of others sharing its TxID. It assumes
+// the lock is held.
+func (m *manager) unindexTxID(txID ids.ID, key validatorKey) {
+	keys := m.txIDs[txID]
+	i := slices.Index(keys, key)
+	if i < 0 {
+		return
+	}
+	if len(keys) == 1 {
+		delete(m.txIDs, txID)
+		return
+	}
+	m.txIDs[txID] = slices.Delete(slices.Clone(keys), i, i+1)
+}
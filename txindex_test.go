@@ -0,0 +1,88 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// TestManagerGetValidatorByTxID tests that the TxID index follows validators
+// across nets
+func TestManagerGetValidatorByTxID(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID1, netID2 := ids.GenerateTestID(), ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	txID1, txID2, txID3 := ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()
+	require.NoError(m.AddStaker(netID1, nodeID, nil, txID1, 100))
+	require.NoError(m.AddStaker(netID2, nodeID, nil, txID2, 100))
+	require.NoError(m.AddStaker(netID2, ids.GenerateTestNodeID(), nil, ids.Empty, 100))
+
+	netID, foundNodeID, ok := m.GetValidatorByTxID(txID2)
+	require.True(ok)
+	require.Equal(netID2, netID)
+	require.Equal(nodeID, foundNodeID)
+
+	// The empty ID isn't indexed
+	_, _, ok = m.GetValidatorByTxID(ids.Empty)
+	require.False(ok)
+
+	// Changes that keep the record keep its entry
+	require.NoError(m.AddWeight(netID1, nodeID, 10))
+	netID, _, ok = m.GetValidatorByTxID(txID1)
+	require.True(ok)
+	require.Equal(netID1, netID)
+
+	// Replacing the registration moves the entry to the new TxID
+	require.NoError(m.SetDuplicatePolicy(DuplicateReplace))
+	require.NoError(m.AddStaker(netID1, nodeID, nil, txID3, 50))
+	_, _, ok = m.GetValidatorByTxID(txID1)
+	require.False(ok)
+	netID, _, ok = m.GetValidatorByTxID(txID3)
+	require.True(ok)
+	require.Equal(netID1, netID)
+
+	require.NoError(m.RemoveStaker(netID2, nodeID))
+	_, _, ok = m.GetValidatorByTxID(txID2)
+	require.False(ok)
+}
+
+// TestManagerGetValidatorsByTxID tests that validators sharing a TxID across
+// nets are all indexed, and that one leaving keeps the others
+func TestManagerGetValidatorsByTxID(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID1, netID2 := ids.GenerateTestID(), ids.GenerateTestID()
+	nodeID1, nodeID2 := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	txID := ids.GenerateTestID()
+	require.NoError(m.AddStaker(netID1, nodeID1, nil, txID, 100))
+	require.NoError(m.AddStaker(netID2, nodeID2, nil, txID, 100))
+
+	require.Equal(map[ids.ID]ids.NodeID{
+		netID1: nodeID1,
+		netID2: nodeID2,
+	}, m.GetValidatorsByTxID(txID))
+	netID, nodeID, ok := m.GetValidatorByTxID(txID)
+	require.True(ok)
+	require.Equal(netID2, netID)
+	require.Equal(nodeID2, nodeID)
+
+	// The latest added leaving falls back to the one still present
+	require.NoError(m.RemoveStaker(netID2, nodeID2))
+	netID, nodeID, ok = m.GetValidatorByTxID(txID)
+	require.True(ok)
+	require.Equal(netID1, netID)
+	require.Equal(nodeID1, nodeID)
+	require.Equal(map[ids.ID]ids.NodeID{netID1: nodeID1}, m.GetValidatorsByTxID(txID))
+
+	require.NoError(m.RemoveStaker(netID1, nodeID1))
+	require.Nil(m.GetValidatorsByTxID(txID))
+	_, _, ok = m.GetValidatorByTxID(txID)
+	require.False(ok)
+}
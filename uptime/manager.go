@@ -0,0 +1,141 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package uptime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/luxfi/ids"
+
+	validators "github.com/luxfi/validators"
+)
+
+var ErrClosed = validators.NewCategoryError("uptime manager is closed", validators.ErrManagerClosed)
+
+type validatorKey struct {
+	nodeID ids.NodeID
+	netID  ids.ID
+}
+
+// Manager tracks when validators connect and disconnect and adds the time
+// they were connected to the uptime in a State. Uptime is written when a
+// validator disconnects, so the time of validators still connected is only
+// kept if the Manager is closed before the node shuts down.
+type Manager struct {
+	state State
+	now   func() time.Time
+
+	mu        sync.Mutex
+	closed    bool
+	connected map[validatorKey]time.Time
+}
+
+// NewManager returns a Manager writing to [state]. [now] is the clock; nil
+// uses time.Now.
+func NewManager(state State, now func() time.Time) *Manager {
+	if now == nil {
+		now = time.Now
+	}
+	return &Manager{
+		state:     state,
+		now:       now,
+		connected: make(map[validatorKey]time.Time),
+	}
+}
+
+// Connect marks [nodeID] as connected in [netID]. Connecting a connected
+// validator does nothing.
+func (m *Manager) Connect(nodeID ids.NodeID, netID ids.ID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return ErrClosed
+	}
+	key := validatorKey{nodeID: nodeID, netID: netID}
+	if _, ok := m.connected[key]; !ok {
+		m.connected[key] = m.now()
+	}
+	return nil
+}
+
+// IsConnected returns true if [nodeID] is connected in [netID]
+func (m *Manager) IsConnected(nodeID ids.NodeID, netID ids.ID) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.connected[validatorKey{nodeID: nodeID, netID: netID}]
+	return ok
+}
+
+// Disconnect marks [nodeID] as disconnected in [netID] and writes its uptime.
+// If the write fails, the validator stays connected. Validators the State
+// doesn't track fail with validators.ErrValidatorNotFound.
+func (m *Manager) Disconnect(nodeID ids.NodeID, netID ids.ID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := validatorKey{nodeID: nodeID, netID: netID}
+	if _, ok := m.connected[key]; !ok {
+		return nil
+	}
+	return m.write(key)
+}
+
+// Close writes the uptime of every connected validator, in node then net
+// order, stopping early if [ctx] is done. Afterwards Connect fails with
+// ErrClosed. Validators whose write failed stay connected, so closing again
+// retries them; once all are written, closing again returns nil.
+func (m *Manager) Close(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.closed = true
+	keys := make([]validatorKey, 0, len(m.connected))
+	for key := range m.connected {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b validatorKey) int {
+		if c := bytes.Compare(a.nodeID[:], b.nodeID[:]); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.netID[:], b.netID[:])
+	})
+
+	var errs []error
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, fmt.Errorf("couldn't write %d uptimes: %w", len(m.connected), err))
+			break
+		}
+		if err := m.write(key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// write adds the time [key] has been connected to its uptime and marks it as
+// disconnected. It assumes the lock is held.
+func (m *Manager) write(key validatorKey) error {
+	uptime, _, err := m.state.GetUptime(key.nodeID, key.netID)
+	if err != nil {
+		return fmt.Errorf("couldn't get uptime of %s in %s: %w", key.nodeID, key.netID, err)
+	}
+	now := m.now()
+	if connectedAt := m.connected[key]; now.After(connectedAt) {
+		uptime += now.Sub(connectedAt)
+	}
+	if err := m.state.SetUptime(key.nodeID, key.netID, uptime, now); err != nil {
+		return fmt.Errorf("couldn't set uptime of %s in %s: %w", key.nodeID, key.netID, err)
+	}
+	delete(m.connected, key)
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package uptime
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+type uptimeRecord struct {
+	uptime      time.Duration
+	lastUpdated time.Time
+}
+
+type testState struct {
+	records map[validatorKey]uptimeRecord
+}
+
+func (s *testState) GetUptime(nodeID ids.NodeID, netID ids.ID) (time.Duration, time.Duration, error) {
+	record := s.records[validatorKey{nodeID: nodeID, netID: netID}]
+	return record.uptime, 0, nil
+}
+
+func (s *testState) SetUptime(nodeID ids.NodeID, netID ids.ID, uptime time.Duration, lastUpdated time.Time) error {
+	s.records[validatorKey{nodeID: nodeID, netID: netID}] = uptimeRecord{uptime: uptime, lastUpdated: lastUpdated}
+	return nil
+}
+
+func (*testState) GetStartTime(ids.NodeID, ids.ID) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+// TestManagerClose tests that closing writes the uptime of connected
+// validators and stops new connections
+func TestManagerClose(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		state = &testState{records: make(map[validatorKey]uptimeRecord)}
+		now   = time.Unix(1000, 0)
+		m     = NewManager(state, func() time.Time { return now })
+		node1 = ids.GenerateTestNodeID()
+		node2 = ids.GenerateTestNodeID()
+		netID = ids.GenerateTestID()
+	)
+	require.NoError(state.SetUptime(node1, netID, time.Minute, now))
+	require.NoError(m.Connect(node1, netID))
+	require.NoError(m.Connect(node2, netID))
+	require.True(m.IsConnected(node1, netID))
+
+	now = now.Add(10 * time.Second)
+	require.NoError(m.Disconnect(node2, netID))
+	require.False(m.IsConnected(node2, netID))
+	require.Equal(uptimeRecord{uptime: 10 * time.Second, lastUpdated: now}, state.records[validatorKey{nodeID: node2, netID: netID}])
+
+	now = now.Add(20 * time.Second)
+	require.NoError(m.Close(context.Background()))
+	require.Equal(uptimeRecord{uptime: time.Minute + 30*time.Second, lastUpdated: now}, state.records[validatorKey{nodeID: node1, netID: netID}])
+	require.False(m.IsConnected(node1, netID))
+
+	err := m.Connect(node1, netID)
+	require.ErrorIs(err, ErrClosed)
+	require.ErrorIs(err, validators.ErrManagerClosed)
+	require.NoError(m.Close(context.Background()))
+}
+
+// untrackedState is a State that tracks no validators
+type untrackedState struct {
+	testState
+}
+
+func (*untrackedState) GetUptime(nodeID ids.NodeID, netID ids.ID) (time.Duration, time.Duration, error) {
+	return 0, 0, fmt.Errorf("%w: %s in %s", validators.ErrValidatorNotFound, nodeID, netID)
+}
+
+// TestManagerUntracked tests that writing the uptime of a validator the State
+// doesn't track fails with ErrValidatorNotFound and keeps it connected
+func TestManagerUntracked(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		m      = NewManager(&untrackedState{}, nil)
+		nodeID = ids.GenerateTestNodeID()
+		netID  = ids.GenerateTestID()
+	)
+	require.NoError(m.Connect(nodeID, netID))
+	require.ErrorIs(m.Disconnect(nodeID, netID), validators.ErrValidatorNotFound)
+	require.True(m.IsConnected(nodeID, netID))
+}
@@ -9,7 +9,11 @@ import (
 	"github.com/luxfi/ids"
 )
 
-// State tracks validator uptime
+// State tracks validator uptime.
+//
+// Reads of a validator the State doesn't track return an error wrapping
+// validators.ErrValidatorNotFound, so callers can tell them apart from
+// storage failures.
 type State interface {
 	// GetUptime returns the uptime for a validator
 	GetUptime(nodeID ids.NodeID, netID ids.ID) (time.Duration, time.Duration, error)
@@ -4,6 +4,7 @@ package validators
 
 import (
 	"context"
+	"time"
 
 	"github.com/luxfi/ids"
 	"github.com/luxfi/math/set"
@@ -12,10 +13,15 @@ import (
 
 // State provides validator state management
 type State interface {
-	// GetValidatorSet returns validators at a specific height for a network
+	// GetValidatorSet returns the validators of a network pinned at a
+	// height. The set returned for a height never changes.
 	GetValidatorSet(ctx context.Context, height uint64, netID ids.ID) (map[ids.NodeID]*GetValidatorOutput, error)
 
-	// GetCurrentValidators returns current validators
+	// GetCurrentValidators returns the live validators of a network,
+	// including changes not yet pinned at an accepted height, with their
+	// Pending and Connected status. Implementations that don't track live
+	// state may ignore the height. See NewLegacyCurrentState for callers
+	// that relied on it matching GetValidatorSet.
 	GetCurrentValidators(ctx context.Context, height uint64, netID ids.ID) (map[ids.NodeID]*GetValidatorOutput, error)
 
 	// GetCurrentHeight returns the current height
@@ -45,8 +51,22 @@ type GetValidatorOutput struct {
 	PublicKey      []byte // BLS public key (classical)
 	RingtailPubKey []byte // Ringtail public key (post-quantum)
 	Light          uint64
-	Weight         uint64 // Alias for Light for backward compatibility
-	TxID           ids.ID // Transaction ID that added this validator
+	Weight         uint64             // Alias for Light for backward compatibility
+	TxID           ids.ID             // Transaction ID that added this validator
+	Metadata       *ValidatorMetadata // Operator-supplied metadata, if any
+	Extensions     map[string][]byte  // Chain-specific data, see ExtensionManager
+	// KeyExpiry is when PublicKey stops being usable, or zero if it doesn't
+	// expire. See KeyExpiryManager.
+	KeyExpiry time.Time
+	// Sequence increases on every change to the record. Sequences are drawn
+	// from a single counter per manager, so they keep increasing when a
+	// validator is removed and re-added.
+	Sequence uint64
+	// Pending and Connected are only reported by GetCurrentValidators.
+	// Pending validators changed after the latest accepted height, so the
+	// change isn't in any height-pinned set yet.
+	Pending   bool
+	Connected bool
 }
 
 // WarpValidator represents a Warp validator with BLS and Ringtail keys
@@ -61,6 +81,9 @@ type WarpValidator struct {
 type WarpSet struct {
 	Height     uint64
 	Validators map[ids.NodeID]*WarpValidator
+	// Commitment is the optional hash of the set's canonical encoding, see
+	// ComputeCommitment. It is ids.Empty if the producer didn't commit.
+	Commitment ids.ID
 }
 
 // Set represents a set of validators
@@ -120,6 +143,31 @@ type Manager interface {
 	RegisterSetCallbackListener(netID ids.ID, listener SetCallbackListener)
 }
 
+// StakerManager sets a validator's self-stake outright and removes validators
+// regardless of their weight, where Manager only moves self-stake by an
+// amount.
+type StakerManager interface {
+	// SetWeight sets the light and weight of [nodeID] to [light]. Setting 0
+	// removes the validator.
+	SetWeight(netID ids.ID, nodeID ids.NodeID, light uint64) error
+	// RemoveStaker removes [nodeID] from [netID]. Removing a validator that
+	// isn't in the net is a no-op.
+	RemoveStaker(netID ids.ID, nodeID ids.NodeID) error
+}
+
+// NetManager lists the nets with validators
+type NetManager interface {
+	// GetNetIDs returns the nets with validators, sorted by ID
+	GetNetIDs() []ids.ID
+	// TotalCount returns the number of validators across all nets
+	TotalCount() int
+}
+
+var (
+	_ StakerManager = (*manager)(nil)
+	_ NetManager    = (*manager)(nil)
+)
+
 // SetCallbackListener listens to validator set changes
 type SetCallbackListener interface {
 	OnValidatorAdded(nodeID ids.NodeID, light uint64)
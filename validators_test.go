@@ -150,11 +150,10 @@ func (m *mockSet) Sample(size int) ([]ids.NodeID, error) {
 	if m.sampleErr != nil {
 		return nil, m.sampleErr
 	}
-	if size > len(m.validators) {
-		return nil, errors.New("sample size too large")
-	}
-	if size < 0 {
-		return nil, errors.New("negative sample size")
+	// The mock follows the contract of SampleStrict
+	size, err := sampleSize(SampleStrict, size, len(m.validators))
+	if err != nil {
+		return nil, err
 	}
 
 	result := make([]ids.NodeID, 0, size)
@@ -290,14 +289,17 @@ func (m *mockManager) Sample(netID ids.ID, size int) ([]ids.NodeID, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
+	vals := m.validators[netID]
+	size, err := sampleSize(SampleTruncate, size, len(vals))
+	if err != nil {
+		return nil, err
+	}
 	nodeIDs := make([]ids.NodeID, 0, size)
-	if vals, ok := m.validators[netID]; ok {
-		for nodeID := range vals {
-			if len(nodeIDs) >= size {
-				break
-			}
-			nodeIDs = append(nodeIDs, nodeID)
+	for nodeID := range vals {
+		if len(nodeIDs) >= size {
+			break
 		}
+		nodeIDs = append(nodeIDs, nodeID)
 	}
 	return nodeIDs, nil
 }
@@ -479,11 +481,11 @@ func TestSet(t *testing.T) {
 
 		// Sample too many
 		_, err = set.Sample(10)
-		require.Error(t, err)
+		require.ErrorIs(t, err, ErrInsufficientValidators)
 
 		// Sample negative
 		_, err = set.Sample(-1)
-		require.Error(t, err)
+		require.ErrorIs(t, err, ErrNegativeSampleSize)
 
 		// Test error case
 		set.sampleErr = errors.New("sample error")
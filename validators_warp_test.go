@@ -13,6 +13,7 @@ import (
 
 	validators "github.com/luxfi/validators"
 	"github.com/luxfi/validators/validatorsmock"
+	"github.com/luxfi/validators/validatorstest"
 )
 
 func TestWarpValidatorTypes(t *testing.T) {
@@ -222,3 +223,18 @@ func TestGetWarpValidatorSets_EmptySets(t *testing.T) {
 	require.NotNil(result)
 	require.Empty(result)
 }
+
+func TestFlattenGeneratedValidatorSet(t *testing.T) {
+	require := require.New(t)
+
+	fixture, err := validatorstest.GenerateValidatorSet(1, 16, validatorstest.UniformWeights(1, 1000))
+	require.NoError(err)
+
+	result, err := validators.FlattenValidatorSet(fixture.GetValidatorOutputs())
+	require.NoError(err)
+	require.Len(result.Validators, 16)
+	require.Equal(fixture.TotalWeight(), result.TotalWeight)
+	for i := 1; i < len(result.Validators); i++ {
+		require.Negative(result.Validators[i-1].Compare(result.Validators[i]))
+	}
+}
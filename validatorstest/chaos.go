@@ -0,0 +1,184 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorstest
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/luxfi/ids"
+
+	validators "github.com/luxfi/validators"
+)
+
+// ErrChaos is returned by ChaosState when it injects a failure
+var ErrChaos = errors.New("chaos: injected failure")
+
+var _ validators.State = (*ChaosState)(nil)
+
+// ChaosConfig configures the failures injected by ChaosState. Rates are
+// probabilities in [0, 1] evaluated independently on every call.
+type ChaosConfig struct {
+	// Seed makes the injected failures reproducible for a given call order
+	Seed uint64
+	// ErrorRate is the probability a call fails with ErrChaos
+	ErrorRate float64
+	// MinLatency and MaxLatency bound the delay added to context-aware calls
+	MinLatency time.Duration
+	MaxLatency time.Duration
+	// StaleRate is the probability a height-based call is answered as of an
+	// older height
+	StaleRate float64
+	// MaxStaleness is the maximum number of blocks a stale response lags by
+	MaxStaleness uint64
+}
+
+// ChaosState wraps a validators.State and injects errors, latency, and stale
+// height responses into its calls
+type ChaosState struct {
+	inner  validators.State
+	config ChaosConfig
+
+	lock sync.Mutex
+	rng  *rand.Rand
+}
+
+// NewChaosState returns a State that forwards to [inner] while injecting the
+// failures described by [config]
+func NewChaosState(inner validators.State, config ChaosConfig) *ChaosState {
+	return &ChaosState{
+		inner:  inner,
+		config: config,
+		rng:    rand.New(rand.NewPCG(config.Seed, config.Seed)),
+	}
+}
+
+// GetValidatorSet implements validators.State
+func (c *ChaosState) GetValidatorSet(ctx context.Context, height uint64, netID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+	if err := c.disrupt(ctx); err != nil {
+		return nil, err
+	}
+	return c.inner.GetValidatorSet(ctx, c.staleHeight(height), netID)
+}
+
+// GetCurrentValidators implements validators.State
+func (c *ChaosState) GetCurrentValidators(ctx context.Context, height uint64, netID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+	if err := c.disrupt(ctx); err != nil {
+		return nil, err
+	}
+	return c.inner.GetCurrentValidators(ctx, c.staleHeight(height), netID)
+}
+
+// GetCurrentHeight implements validators.State
+func (c *ChaosState) GetCurrentHeight(ctx context.Context) (uint64, error) {
+	if err := c.disrupt(ctx); err != nil {
+		return 0, err
+	}
+	height, err := c.inner.GetCurrentHeight(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return c.staleHeight(height), nil
+}
+
+// GetMinimumHeight implements validators.State
+func (c *ChaosState) GetMinimumHeight(ctx context.Context) (uint64, error) {
+	if err := c.disrupt(ctx); err != nil {
+		return 0, err
+	}
+	return c.inner.GetMinimumHeight(ctx)
+}
+
+// GetChainID implements validators.State
+func (c *ChaosState) GetChainID(netID ids.ID) (ids.ID, error) {
+	if c.shouldFail() {
+		return ids.Empty, ErrChaos
+	}
+	return c.inner.GetChainID(netID)
+}
+
+// GetNetworkID implements validators.State
+func (c *ChaosState) GetNetworkID(chainID ids.ID) (ids.ID, error) {
+	if c.shouldFail() {
+		return ids.Empty, ErrChaos
+	}
+	return c.inner.GetNetworkID(chainID)
+}
+
+// GetWarpValidatorSets implements validators.State
+func (c *ChaosState) GetWarpValidatorSets(ctx context.Context, heights []uint64, netIDs []ids.ID) (map[ids.ID]map[uint64]*validators.WarpSet, error) {
+	if err := c.disrupt(ctx); err != nil {
+		return nil, err
+	}
+	return c.inner.GetWarpValidatorSets(ctx, heights, netIDs)
+}
+
+// GetWarpValidatorSet implements validators.State
+func (c *ChaosState) GetWarpValidatorSet(ctx context.Context, height uint64, netID ids.ID) (*validators.WarpSet, error) {
+	if err := c.disrupt(ctx); err != nil {
+		return nil, err
+	}
+	return c.inner.GetWarpValidatorSet(ctx, c.staleHeight(height), netID)
+}
+
+// disrupt delays the call and then decides whether it fails. The delay is
+// cut short if [ctx] is done.
+func (c *ChaosState) disrupt(ctx context.Context) error {
+	if delay := c.latency(); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if c.shouldFail() {
+		return ErrChaos
+	}
+	return nil
+}
+
+func (c *ChaosState) latency() time.Duration {
+	if c.config.MaxLatency <= c.config.MinLatency {
+		return c.config.MinLatency
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	span := c.config.MaxLatency - c.config.MinLatency
+	return c.config.MinLatency + time.Duration(c.rng.Int64N(int64(span)+1))
+}
+
+func (c *ChaosState) shouldFail() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.rng.Float64() < c.config.ErrorRate
+}
+
+// staleHeight returns [height], or with probability StaleRate, a height up to
+// MaxStaleness blocks lower
+func (c *ChaosState) staleHeight(height uint64) uint64 {
+	if c.config.MaxStaleness == 0 {
+		return height
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.rng.Float64() >= c.config.StaleRate {
+		return height
+	}
+	lag := c.rng.Uint64N(c.config.MaxStaleness) + 1
+	if lag > height {
+		return 0
+	}
+	return height - lag
+}
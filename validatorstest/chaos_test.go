@@ -0,0 +1,100 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorstest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// TestChaosStatePassthrough tests that a zero config forwards every call
+func TestChaosStatePassthrough(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	netID := ids.GenerateTestID()
+	inner := NewTestState().SetCurrentHeight(100)
+	state := NewChaosState(inner, ChaosConfig{})
+
+	height, err := state.GetCurrentHeight(ctx)
+	require.NoError(err)
+	require.Equal(uint64(100), height)
+
+	chainID, err := state.GetChainID(netID)
+	require.NoError(err)
+	require.Equal(netID, chainID)
+
+	warpSet, err := state.GetWarpValidatorSet(ctx, height, netID)
+	require.NoError(err)
+	require.Equal(height, warpSet.Height)
+}
+
+// TestChaosStateErrors tests error injection
+func TestChaosStateErrors(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	state := NewChaosState(NewTestState(), ChaosConfig{ErrorRate: 1})
+	_, err := state.GetValidatorSet(ctx, 0, ids.GenerateTestID())
+	require.ErrorIs(err, ErrChaos)
+	_, err = state.GetNetworkID(ids.GenerateTestID())
+	require.ErrorIs(err, ErrChaos)
+
+	// A partial error rate fails some, but not all, calls
+	state = NewChaosState(NewTestState(), ChaosConfig{Seed: 1, ErrorRate: 0.5})
+	var failures int
+	for i := 0; i < 100; i++ {
+		if _, err := state.GetMinimumHeight(ctx); err != nil {
+			require.ErrorIs(err, ErrChaos)
+			failures++
+		}
+	}
+	require.Positive(failures)
+	require.Less(failures, 100)
+}
+
+// TestChaosStateStaleHeights tests stale height responses
+func TestChaosStateStaleHeights(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	state := NewChaosState(NewTestState().SetCurrentHeight(100), ChaosConfig{
+		StaleRate:    1,
+		MaxStaleness: 10,
+	})
+	for i := 0; i < 20; i++ {
+		height, err := state.GetCurrentHeight(ctx)
+		require.NoError(err)
+		require.GreaterOrEqual(height, uint64(90))
+		require.Less(height, uint64(100))
+
+		warpSet, err := state.GetWarpValidatorSet(ctx, 5, ids.GenerateTestID())
+		require.NoError(err)
+		require.Less(warpSet.Height, uint64(5))
+	}
+}
+
+// TestChaosStateLatency tests that latency respects context cancellation
+func TestChaosStateLatency(t *testing.T) {
+	require := require.New(t)
+
+	state := NewChaosState(NewTestState(), ChaosConfig{
+		MinLatency: 10 * time.Millisecond,
+		MaxLatency: 20 * time.Millisecond,
+	})
+
+	start := time.Now()
+	_, err := state.GetCurrentHeight(context.Background())
+	require.NoError(err)
+	require.GreaterOrEqual(time.Since(start), 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = state.GetCurrentHeight(ctx)
+	require.ErrorIs(err, context.Canceled)
+}
@@ -0,0 +1,185 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorstest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+// conformanceHeight is the current height of the backing state used by
+// RunStateConformance
+const conformanceHeight = 100
+
+// StateFactory returns the validators.State under test, serving the contents
+// of [backing]. Any cleanup should be registered with [t].
+type StateFactory func(t testing.TB, backing *TestState) validators.State
+
+// RunStateConformance checks that the State returned by [newState] faithfully
+// reproduces its backing state, including context deadline propagation.
+//
+// The deadline and serialization checks only bite across a transport: run
+// the suite through NewWireState, or through a real gRPC server and client
+// wired up by [newState]. This module has no gRPC State server or client of
+// its own.
+func RunStateConformance(t *testing.T, newState StateFactory) {
+	fixture, err := GenerateValidatorSet(1, 4, UniformWeights(1, 1000), WithRingtailKeys())
+	require.NoError(t, err)
+
+	netID := ids.GenerateTestID()
+	otherNetID := ids.GenerateTestID()
+	pendingNodeID := ids.GenerateTestNodeID()
+	newBacking := func() *TestState {
+		backing := NewTestState().SetCurrentHeight(conformanceHeight)
+		for _, vdr := range fixture.GetValidatorOutputs() {
+			backing.AddValidator(netID, vdr)
+		}
+		backing.SetWarpSet(netID, fixture.WarpSet(conformanceHeight-1))
+		backing.AddPendingValidator(netID, &validators.GetValidatorOutput{
+			NodeID: pendingNodeID,
+			Light:  1,
+			Weight: 1,
+		})
+		backing.SetConnected(fixture.Validators[0].NodeID, true)
+		return backing
+	}
+
+	t.Run("GetCurrentHeight", func(t *testing.T) {
+		state := newState(t, newBacking())
+		height, err := state.GetCurrentHeight(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, uint64(conformanceHeight), height)
+	})
+
+	t.Run("GetValidatorSet", func(t *testing.T) {
+		require := require.New(t)
+		ctx := context.Background()
+		backing := newBacking()
+		state := newState(t, backing)
+
+		expected, err := backing.GetValidatorSet(ctx, conformanceHeight, netID)
+		require.NoError(err)
+		vdrs, err := state.GetValidatorSet(ctx, conformanceHeight, netID)
+		require.NoError(err)
+		require.Equal(expected, vdrs)
+
+		require.NotContains(vdrs, pendingNodeID)
+
+		// The current set includes pending validators and connection status
+		expected, err = backing.GetCurrentValidators(ctx, conformanceHeight, netID)
+		require.NoError(err)
+		vdrs, err = state.GetCurrentValidators(ctx, conformanceHeight, netID)
+		require.NoError(err)
+		require.Equal(expected, vdrs)
+		require.True(vdrs[pendingNodeID].Pending)
+		require.True(vdrs[fixture.Validators[0].NodeID].Connected)
+
+		vdrs, err = state.GetValidatorSet(ctx, conformanceHeight, otherNetID)
+		require.NoError(err)
+		require.Empty(vdrs)
+	})
+
+	t.Run("GetWarpValidatorSet", func(t *testing.T) {
+		require := require.New(t)
+		ctx := context.Background()
+		backing := newBacking()
+		state := newState(t, backing)
+
+		for _, height := range []uint64{conformanceHeight - 1, conformanceHeight} {
+			expected, err := backing.GetWarpValidatorSet(ctx, height, netID)
+			require.NoError(err)
+			warpSet, err := state.GetWarpValidatorSet(ctx, height, netID)
+			require.NoError(err)
+			require.Equal(expected, warpSet)
+		}
+
+		heights := []uint64{conformanceHeight - 1, conformanceHeight}
+		netIDs := []ids.ID{netID, otherNetID}
+		expected, err := backing.GetWarpValidatorSets(ctx, heights, netIDs)
+		require.NoError(err)
+		warpSets, err := state.GetWarpValidatorSets(ctx, heights, netIDs)
+		require.NoError(err)
+		require.Equal(expected, warpSets)
+	})
+
+	t.Run("ChainAndNetworkIDs", func(t *testing.T) {
+		require := require.New(t)
+		backing := newBacking()
+		state := newState(t, backing)
+
+		expectedChainID, err := backing.GetChainID(netID)
+		require.NoError(err)
+		chainID, err := state.GetChainID(netID)
+		require.NoError(err)
+		require.Equal(expectedChainID, chainID)
+
+		expectedNetID, err := backing.GetNetworkID(chainID)
+		require.NoError(err)
+		gotNetID, err := state.GetNetworkID(chainID)
+		require.NoError(err)
+		require.Equal(expectedNetID, gotNetID)
+	})
+
+	t.Run("DeadlinePropagation", func(t *testing.T) {
+		require := require.New(t)
+		backing := newBacking()
+
+		var (
+			sawDeadline bool
+			deadline    = time.Now().Add(time.Minute)
+		)
+		backing.GetCurrentHeightF = func(ctx context.Context) (uint64, error) {
+			got, ok := ctx.Deadline()
+			// Transports may round the deadline, so allow for some skew
+			sawDeadline = ok && !got.After(deadline.Add(time.Second))
+			return conformanceHeight, nil
+		}
+		state := newState(t, backing)
+
+		ctx, cancel := context.WithDeadline(context.Background(), deadline)
+		defer cancel()
+		_, err := state.GetCurrentHeight(ctx)
+		require.NoError(err)
+		require.True(sawDeadline, "deadline wasn't propagated to the backing state")
+	})
+
+	t.Run("ErrorPropagation", func(t *testing.T) {
+		backing := newBacking()
+		backing.GetValidatorSetF = func(context.Context, uint64, ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+			return nil, ErrChaos
+		}
+		state := newState(t, backing)
+
+		_, err := state.GetValidatorSet(context.Background(), conformanceHeight, netID)
+		require.Error(t, err)
+	})
+
+	// Errors keep their category, so callers can branch on them with
+	// errors.Is whatever the transport
+	for _, category := range []error{
+		validators.ErrValidatorNotFound,
+		validators.ErrNetNotFound,
+		validators.ErrDuplicateValidator,
+		validators.ErrInvalidWeight,
+		validators.ErrManagerClosed,
+	} {
+		t.Run("ErrorCategory/"+category.Error(), func(t *testing.T) {
+			backing := newBacking()
+			backing.GetWarpValidatorSetF = func(context.Context, uint64, ids.ID) (*validators.WarpSet, error) {
+				return nil, fmt.Errorf("%w: at %d", category, conformanceHeight)
+			}
+			state := newState(t, backing)
+
+			_, err := state.GetWarpValidatorSet(context.Background(), conformanceHeight, netID)
+			require.ErrorIs(t, err, category)
+		})
+	}
+}
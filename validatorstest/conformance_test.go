@@ -0,0 +1,33 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorstest
+
+import (
+	"testing"
+
+	validators "github.com/luxfi/validators"
+)
+
+// TestStateConformanceInProcess runs the conformance suite against the test
+// state itself
+func TestStateConformanceInProcess(t *testing.T) {
+	RunStateConformance(t, func(_ testing.TB, backing *TestState) validators.State {
+		return backing
+	})
+}
+
+// TestStateConformanceChaos runs the conformance suite through a ChaosState
+// that injects no failures
+func TestStateConformanceChaos(t *testing.T) {
+	RunStateConformance(t, func(_ testing.TB, backing *TestState) validators.State {
+		return NewChaosState(backing, ChaosConfig{})
+	})
+}
+
+// TestStateConformanceWire runs the conformance suite across the wire
+func TestStateConformanceWire(t *testing.T) {
+	RunStateConformance(t, func(t testing.TB, backing *TestState) validators.State {
+		return NewWireState(t, backing)
+	})
+}
@@ -0,0 +1,133 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package fixture generates deterministic validators with real BLS keys. It
+// doesn't depend on package validators, so that package's own tests can use
+// it; other tests use validatorstest.GenerateValidatorSet, which builds on it.
+package fixture
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+)
+
+// RingtailPubKeyLen is the length of the Ringtail public keys produced by
+// Generate. The keys are opaque to this package.
+const RingtailPubKeyLen = 32
+
+// blsSeedLen is the number of seed bytes used to derive each BLS secret key
+const blsSeedLen = 32
+
+var ErrNonPositiveCount = errors.New("validator count must be positive")
+
+// WeightDistribution returns the weight of the i-th generated validator.
+// Implementations must only draw randomness from [r] so that generation stays
+// reproducible for a given seed.
+type WeightDistribution func(r *rand.Rand, i int) uint64
+
+// ConstantWeights gives every validator the same weight
+func ConstantWeights(weight uint64) WeightDistribution {
+	return func(*rand.Rand, int) uint64 {
+		return weight
+	}
+}
+
+// UniformWeights draws weights uniformly from [minWeight, maxWeight]
+func UniformWeights(minWeight, maxWeight uint64) WeightDistribution {
+	if maxWeight < minWeight {
+		minWeight, maxWeight = maxWeight, minWeight
+	}
+	span := maxWeight - minWeight
+	return func(r *rand.Rand, _ int) uint64 {
+		if span == math.MaxUint64 {
+			return r.Uint64()
+		}
+		return minWeight + r.Uint64N(span+1)
+	}
+}
+
+// ExponentialWeights draws weights from an exponential distribution with the
+// provided mean. Every validator is given a weight of at least 1.
+func ExponentialWeights(mean uint64) WeightDistribution {
+	return func(r *rand.Rand, _ int) uint64 {
+		return max(uint64(r.ExpFloat64()*float64(mean)), 1)
+	}
+}
+
+// Option configures Generate
+type Option func(*config)
+
+type config struct {
+	ringtail bool
+}
+
+// WithRingtailKeys also populates a deterministic Ringtail public key for each
+// generated validator
+func WithRingtailKeys() Option {
+	return func(c *config) {
+		c.ringtail = true
+	}
+}
+
+// Validator is a generated validator along with its signing key
+type Validator struct {
+	NodeID         ids.NodeID
+	SecretKey      *bls.SecretKey
+	PublicKey      *bls.PublicKey
+	PublicKeyBytes []byte // Compressed BLS public key
+	RingtailPubKey []byte
+	Weight         uint64
+}
+
+// Generate produces [n] validators with deterministic BLS keys and node IDs
+// derived from [seed]. Weights are drawn from [weightDist]. Calling it twice
+// with the same arguments returns identical validators, in generation order.
+func Generate(seed uint64, n int, weightDist WeightDistribution, opts ...Option) ([]*Validator, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrNonPositiveCount, n)
+	}
+
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	var chachaSeed [32]byte
+	binary.BigEndian.PutUint64(chachaSeed[:], seed)
+	source := rand.NewChaCha8(chachaSeed)
+	r := rand.New(source)
+
+	vdrs := make([]*Validator, n)
+	for i := range vdrs {
+		var nodeID ids.NodeID
+		_, _ = source.Read(nodeID[:])
+
+		skSeed := make([]byte, blsSeedLen)
+		_, _ = source.Read(skSeed)
+		sk, err := bls.SecretKeyFromSeed(skSeed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive BLS key %d: %w", i, err)
+		}
+		pk := sk.PublicKey()
+
+		vdr := &Validator{
+			NodeID:         nodeID,
+			SecretKey:      sk,
+			PublicKey:      pk,
+			PublicKeyBytes: bls.PublicKeyToCompressedBytes(pk),
+			Weight:         weightDist(r, i),
+		}
+		if c.ringtail {
+			vdr.RingtailPubKey = make([]byte, RingtailPubKeyLen)
+			_, _ = source.Read(vdr.RingtailPubKey)
+		}
+		vdrs[i] = vdr
+	}
+	return vdrs, nil
+}
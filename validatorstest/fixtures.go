@@ -0,0 +1,106 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorstest
+
+import (
+	"github.com/luxfi/ids"
+
+	validators "github.com/luxfi/validators"
+	"github.com/luxfi/validators/validatorstest/fixture"
+)
+
+// RingtailPubKeyLen is the length of the Ringtail public keys produced by
+// GenerateValidatorSet. The keys are opaque to this package.
+const RingtailPubKeyLen = fixture.RingtailPubKeyLen
+
+// WeightDistribution returns the weight of the i-th generated validator, see
+// fixture.WeightDistribution
+type WeightDistribution = fixture.WeightDistribution
+
+// ConstantWeights gives every validator the same weight
+func ConstantWeights(weight uint64) WeightDistribution {
+	return fixture.ConstantWeights(weight)
+}
+
+// UniformWeights draws weights uniformly from [minWeight, maxWeight]
+func UniformWeights(minWeight, maxWeight uint64) WeightDistribution {
+	return fixture.UniformWeights(minWeight, maxWeight)
+}
+
+// ExponentialWeights draws weights from an exponential distribution with the
+// provided mean. Every validator is given a weight of at least 1.
+func ExponentialWeights(mean uint64) WeightDistribution {
+	return fixture.ExponentialWeights(mean)
+}
+
+// GenerateOption configures GenerateValidatorSet
+type GenerateOption = fixture.Option
+
+// WithRingtailKeys also populates a deterministic Ringtail public key for each
+// generated validator
+func WithRingtailKeys() GenerateOption {
+	return fixture.WithRingtailKeys()
+}
+
+// Validator is a generated validator along with its signing key
+type Validator = fixture.Validator
+
+// ValidatorSet is a deterministic set of validators produced by
+// GenerateValidatorSet. Validators are kept in generation order.
+type ValidatorSet struct {
+	Validators []*Validator
+}
+
+// GenerateValidatorSet produces [n] validators with deterministic BLS keys and
+// node IDs derived from [seed]. Weights are drawn from [weightDist]. Calling
+// it twice with the same arguments returns identical validator sets.
+func GenerateValidatorSet(seed uint64, n int, weightDist WeightDistribution, opts ...GenerateOption) (*ValidatorSet, error) {
+	vdrs, err := fixture.Generate(seed, n, weightDist, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatorSet{Validators: vdrs}, nil
+}
+
+// GetValidatorOutputs returns the set in the format returned by
+// validators.State.GetValidatorSet
+func (s *ValidatorSet) GetValidatorOutputs() map[ids.NodeID]*validators.GetValidatorOutput {
+	outputs := make(map[ids.NodeID]*validators.GetValidatorOutput, len(s.Validators))
+	for _, vdr := range s.Validators {
+		outputs[vdr.NodeID] = &validators.GetValidatorOutput{
+			NodeID:         vdr.NodeID,
+			PublicKey:      vdr.PublicKeyBytes,
+			RingtailPubKey: vdr.RingtailPubKey,
+			Light:          vdr.Weight,
+			Weight:         vdr.Weight,
+		}
+	}
+	return outputs
+}
+
+// WarpSet returns the set as a validators.WarpSet at [height]
+func (s *ValidatorSet) WarpSet(height uint64) *validators.WarpSet {
+	warpVdrs := make(map[ids.NodeID]*validators.WarpValidator, len(s.Validators))
+	for _, vdr := range s.Validators {
+		warpVdrs[vdr.NodeID] = &validators.WarpValidator{
+			NodeID:         vdr.NodeID,
+			PublicKey:      vdr.PublicKeyBytes,
+			RingtailPubKey: vdr.RingtailPubKey,
+			Weight:         vdr.Weight,
+		}
+	}
+	return &validators.WarpSet{
+		Height:     height,
+		Validators: warpVdrs,
+	}
+}
+
+// TotalWeight returns the sum of the weights of the validators in the set
+func (s *ValidatorSet) TotalWeight() uint64 {
+	var total uint64
+	for _, vdr := range s.Validators {
+		total += vdr.Weight
+	}
+	return total
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorstest
+
+import (
+	"testing"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/validators/validatorstest/fixture"
+)
+
+// TestGenerateValidatorSetDeterministic tests that the same seed produces the same set
+func TestGenerateValidatorSetDeterministic(t *testing.T) {
+	require := require.New(t)
+
+	set1, err := GenerateValidatorSet(42, 5, UniformWeights(1, 1000), WithRingtailKeys())
+	require.NoError(err)
+	set2, err := GenerateValidatorSet(42, 5, UniformWeights(1, 1000), WithRingtailKeys())
+	require.NoError(err)
+
+	require.Len(set1.Validators, 5)
+	for i, vdr := range set1.Validators {
+		other := set2.Validators[i]
+		require.Equal(vdr.NodeID, other.NodeID)
+		require.Equal(vdr.PublicKeyBytes, other.PublicKeyBytes)
+		require.Equal(vdr.RingtailPubKey, other.RingtailPubKey)
+		require.Equal(vdr.Weight, other.Weight)
+	}
+}
+
+// TestGenerateValidatorSetDifferentSeeds tests that different seeds produce different keys
+func TestGenerateValidatorSetDifferentSeeds(t *testing.T) {
+	require := require.New(t)
+
+	set1, err := GenerateValidatorSet(1, 3, ConstantWeights(10))
+	require.NoError(err)
+	set2, err := GenerateValidatorSet(2, 3, ConstantWeights(10))
+	require.NoError(err)
+
+	require.NotEqual(set1.Validators[0].NodeID, set2.Validators[0].NodeID)
+	require.NotEqual(set1.Validators[0].PublicKeyBytes, set2.Validators[0].PublicKeyBytes)
+}
+
+// TestGenerateValidatorSetValidKeys tests that generated keys can sign and verify
+func TestGenerateValidatorSetValidKeys(t *testing.T) {
+	require := require.New(t)
+
+	set, err := GenerateValidatorSet(7, 4, ConstantWeights(100))
+	require.NoError(err)
+
+	msg := []byte("warp message")
+	for _, vdr := range set.Validators {
+		pk, err := bls.PublicKeyFromCompressedBytes(vdr.PublicKeyBytes)
+		require.NoError(err)
+
+		sig, err := vdr.SecretKey.Sign(msg)
+		require.NoError(err)
+		require.True(bls.Verify(pk, sig, msg))
+		require.Nil(vdr.RingtailPubKey)
+	}
+	require.Equal(uint64(400), set.TotalWeight())
+}
+
+// TestGenerateValidatorSetInvalidCount tests that a non-positive count is rejected
+func TestGenerateValidatorSetInvalidCount(t *testing.T) {
+	_, err := GenerateValidatorSet(1, 0, ConstantWeights(1))
+	require.ErrorIs(t, err, fixture.ErrNonPositiveCount)
+}
+
+// TestWeightDistributions tests the bounds of the provided distributions
+func TestWeightDistributions(t *testing.T) {
+	require := require.New(t)
+
+	uniform, err := GenerateValidatorSet(3, 50, UniformWeights(100, 10))
+	require.NoError(err)
+	for _, vdr := range uniform.Validators {
+		require.GreaterOrEqual(vdr.Weight, uint64(10))
+		require.LessOrEqual(vdr.Weight, uint64(100))
+	}
+
+	exponential, err := GenerateValidatorSet(3, 50, ExponentialWeights(1000))
+	require.NoError(err)
+	for _, vdr := range exponential.Validators {
+		require.Positive(vdr.Weight)
+	}
+}
+
+// TestValidatorSetConversions tests GetValidatorOutputs and WarpSet
+func TestValidatorSetConversions(t *testing.T) {
+	require := require.New(t)
+
+	set, err := GenerateValidatorSet(9, 3, UniformWeights(1, 10), WithRingtailKeys())
+	require.NoError(err)
+
+	outputs := set.GetValidatorOutputs()
+	warpSet := set.WarpSet(123)
+	require.Len(outputs, 3)
+	require.Len(warpSet.Validators, 3)
+	require.Equal(uint64(123), warpSet.Height)
+
+	for _, vdr := range set.Validators {
+		output := outputs[vdr.NodeID]
+		require.Equal(vdr.PublicKeyBytes, output.PublicKey)
+		require.Equal(vdr.RingtailPubKey, output.RingtailPubKey)
+		require.Equal(vdr.Weight, output.Weight)
+		require.Equal(vdr.Weight, output.Light)
+
+		warpVdr := warpSet.Validators[vdr.NodeID]
+		require.Equal(vdr.PublicKeyBytes, warpVdr.PublicKey)
+		require.Len(warpVdr.RingtailPubKey, RingtailPubKeyLen)
+		require.Equal(vdr.Weight, warpVdr.Weight)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorstest
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math/set"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+// CanonicalGolden is a versioned set of golden vectors for canonical
+// validator set flattening and public key aggregation.
+//
+// Golden files must never be edited in place. Any intentional change to the
+// canonical form must be introduced as a new version.
+type CanonicalGolden struct {
+	Version int                   `json:"version"`
+	Cases   []CanonicalGoldenCase `json:"cases"`
+}
+
+// CanonicalGoldenCase is a single input validator set and its expected
+// canonical form
+type CanonicalGoldenCase struct {
+	Name       string                     `json:"name"`
+	Validators []GoldenValidator          `json:"validators"`
+	Expected   CanonicalGoldenExpectation `json:"expected"`
+}
+
+// GoldenValidator is an input validator. NodeID and PublicKey are hex encoded;
+// PublicKey is the compressed BLS public key and may be empty or invalid.
+type GoldenValidator struct {
+	NodeID    string `json:"nodeID"`
+	PublicKey string `json:"publicKey"`
+	Weight    uint64 `json:"weight"`
+}
+
+// CanonicalGoldenExpectation is the expected result of flattening a case
+type CanonicalGoldenExpectation struct {
+	// Error is a substring of the expected error, if flattening must fail
+	Error              string                     `json:"error,omitempty"`
+	TotalWeight        uint64                     `json:"totalWeight"`
+	Validators         []GoldenCanonicalValidator `json:"validators"`
+	AggregatePublicKey string                     `json:"aggregatePublicKey,omitempty"`
+	Subsets            []GoldenSubset             `json:"subsets,omitempty"`
+}
+
+// GoldenCanonicalValidator is an expected entry of the canonical ordering.
+// NodeIDs are in ascending order.
+type GoldenCanonicalValidator struct {
+	PublicKey string   `json:"publicKey"`
+	Weight    uint64   `json:"weight"`
+	NodeIDs   []string `json:"nodeIDs"`
+}
+
+// GoldenSubset is an expected signer subset, identified by canonical indices
+type GoldenSubset struct {
+	Signers            []int  `json:"signers"`
+	Weight             uint64 `json:"weight"`
+	AggregatePublicKey string `json:"aggregatePublicKey"`
+}
+
+// LoadCanonicalGolden reads the golden vectors stored at [path]
+func LoadCanonicalGolden(path string) (*CanonicalGolden, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	golden := &CanonicalGolden{}
+	if err := json.Unmarshal(data, golden); err != nil {
+		return nil, fmt.Errorf("failed to parse golden file %s: %w", path, err)
+	}
+	return golden, nil
+}
+
+// GetValidatorOutputs returns the case inputs in the format returned by
+// validators.State.GetValidatorSet
+func (c *CanonicalGoldenCase) GetValidatorOutputs() (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+	outputs := make(map[ids.NodeID]*validators.GetValidatorOutput, len(c.Validators))
+	for _, vdr := range c.Validators {
+		nodeID, err := parseGoldenNodeID(vdr.NodeID)
+		if err != nil {
+			return nil, err
+		}
+		pk, err := hex.DecodeString(vdr.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key of %s: %w", vdr.NodeID, err)
+		}
+		if len(pk) == 0 {
+			pk = nil
+		}
+		outputs[nodeID] = &validators.GetValidatorOutput{
+			NodeID:    nodeID,
+			PublicKey: pk,
+			Light:     vdr.Weight,
+			Weight:    vdr.Weight,
+		}
+	}
+	return outputs, nil
+}
+
+// RequireCanonicalGolden verifies that the canonical ordering, weights, and
+// aggregate public keys produced by this package match every case of the
+// golden file at [path].
+func RequireCanonicalGolden(t testing.TB, path string) {
+	t.Helper()
+
+	golden, err := LoadCanonicalGolden(path)
+	require.NoError(t, err)
+	require.NotEmpty(t, golden.Cases, "golden file %s has no cases", path)
+
+	for _, c := range golden.Cases {
+		requireCanonicalGoldenCase(t, golden.Version, &c)
+	}
+}
+
+func requireCanonicalGoldenCase(t testing.TB, version int, c *CanonicalGoldenCase) {
+	t.Helper()
+	require := require.New(t)
+	msg := fmt.Sprintf("golden v%d case %q", version, c.Name)
+
+	vdrSet, err := c.GetValidatorOutputs()
+	require.NoError(err, msg)
+
+	canonical, err := validators.FlattenValidatorSet(vdrSet)
+	if c.Expected.Error != "" {
+		require.ErrorContains(err, c.Expected.Error, msg)
+		return
+	}
+	require.NoError(err, msg)
+	require.Equal(c.Expected.TotalWeight, canonical.TotalWeight, msg)
+	require.Len(canonical.Validators, len(c.Expected.Validators), msg)
+
+	for i, expected := range c.Expected.Validators {
+		vdr := canonical.Validators[i]
+		require.Equal(expected.PublicKey, hex.EncodeToString(bls.PublicKeyToCompressedBytes(vdr.PublicKey)), "%s: validator %d", msg, i)
+		require.Equal(expected.Weight, vdr.Weight, "%s: validator %d", msg, i)
+
+		nodeIDs := make([]string, len(vdr.NodeIDs))
+		for j, nodeID := range vdr.NodeIDs {
+			nodeIDs[j] = hex.EncodeToString(nodeID[:])
+		}
+		require.Equal(expected.NodeIDs, nodeIDs, "%s: validator %d", msg, i)
+	}
+
+	if c.Expected.AggregatePublicKey != "" {
+		aggPK, err := validators.AggregatePublicKeys(canonical.Validators)
+		require.NoError(err, msg)
+		require.Equal(c.Expected.AggregatePublicKey, hex.EncodeToString(bls.PublicKeyToCompressedBytes(aggPK)), msg)
+	}
+
+	for _, subset := range c.Expected.Subsets {
+		signers, err := validators.FilterValidators(set.NewBits(subset.Signers...), canonical.Validators)
+		require.NoError(err, "%s: signers %v", msg, subset.Signers)
+
+		weight, err := validators.SumWeight(signers)
+		require.NoError(err, "%s: signers %v", msg, subset.Signers)
+		require.Equal(subset.Weight, weight, "%s: signers %v", msg, subset.Signers)
+
+		aggPK, err := validators.AggregatePublicKeys(signers)
+		require.NoError(err, "%s: signers %v", msg, subset.Signers)
+		require.Equal(subset.AggregatePublicKey, hex.EncodeToString(bls.PublicKeyToCompressedBytes(aggPK)), "%s: signers %v", msg, subset.Signers)
+	}
+}
+
+func parseGoldenNodeID(s string) (ids.NodeID, error) {
+	var nodeID ids.NodeID
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nodeID, fmt.Errorf("invalid nodeID %q: %w", s, err)
+	}
+	if len(b) != len(nodeID) {
+		return nodeID, fmt.Errorf("invalid nodeID %q: expected %d bytes but got %d", s, len(nodeID), len(b))
+	}
+	copy(nodeID[:], b)
+	return nodeID, nil
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorstest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadCanonicalGolden tests loading the golden vectors
+func TestLoadCanonicalGolden(t *testing.T) {
+	require := require.New(t)
+
+	golden, err := LoadCanonicalGolden("../testdata/canonical_v1.json")
+	require.NoError(err)
+	require.Equal(1, golden.Version)
+	require.NotEmpty(golden.Cases)
+
+	for _, c := range golden.Cases {
+		outputs, err := c.GetValidatorOutputs()
+		require.NoError(err, c.Name)
+		require.Len(outputs, len(c.Validators), c.Name)
+	}
+}
+
+// TestLoadCanonicalGoldenInvalid tests loading malformed golden files
+func TestLoadCanonicalGoldenInvalid(t *testing.T) {
+	require := require.New(t)
+
+	_, err := LoadCanonicalGolden(filepath.Join(t.TempDir(), "missing.json"))
+	require.ErrorIs(err, os.ErrNotExist)
+
+	path := filepath.Join(t.TempDir(), "invalid.json")
+	require.NoError(os.WriteFile(path, []byte("{"), 0o600))
+	_, err = LoadCanonicalGolden(path)
+	require.Error(err)
+}
+
+// TestGoldenCaseInvalidNodeID tests that malformed node IDs are rejected
+func TestGoldenCaseInvalidNodeID(t *testing.T) {
+	c := CanonicalGoldenCase{
+		Validators: []GoldenValidator{{NodeID: "abcd", Weight: 1}},
+	}
+	_, err := c.GetValidatorOutputs()
+	require.Error(t, err)
+}
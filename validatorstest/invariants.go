@@ -0,0 +1,294 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorstest
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+var (
+	errTotalMismatch    = errors.New("total light doesn't equal the sum of validator lights")
+	errCountMismatch    = errors.New("validator counts are inconsistent")
+	errLightMismatch    = errors.New("validator light is inconsistent")
+	errZeroLight        = errors.New("validator with zero light is tracked")
+	errLightUnderflow   = errors.New("validator light exceeds the light ever added")
+	errNumNetsMismatch  = errors.New("number of nets is inconsistent")
+	errListenerMismatch = errors.New("listener events don't match manager state")
+)
+
+// InvariantConfig configures RunManagerInvariants
+type InvariantConfig struct {
+	// Seed makes the operation sequence reproducible
+	Seed uint64
+	// Steps is the number of random operations to apply
+	Steps int
+	// NumNets is the number of distinct nets operated on
+	NumNets int
+	// NumNodes is the number of distinct node IDs operated on
+	NumNodes int
+	// MaxLight bounds the light of each individual operation. It must be small
+	// enough that the accumulated light of a validator can't overflow.
+	MaxLight uint64
+}
+
+// DefaultInvariantConfig returns a config that exercises a manager quickly
+func DefaultInvariantConfig() InvariantConfig {
+	return InvariantConfig{
+		Seed:     1,
+		Steps:    2000,
+		NumNets:  3,
+		NumNodes: 16,
+		MaxLight: 1_000_000,
+	}
+}
+
+// RunManagerInvariants applies a random sequence of AddStaker, AddWeight,
+// RemoveWeight and, if supported, SetWeight operations to the manager
+// returned by [newManager], checking the manager invariants after every
+// operation.
+//
+// It can be used to validate any validators.Manager implementation.
+func RunManagerInvariants(t testing.TB, newManager func() validators.Manager, config InvariantConfig) {
+	t.Helper()
+	require := require.New(t)
+
+	r := rand.New(rand.NewPCG(config.Seed, config.Seed))
+	netIDs := make([]ids.ID, config.NumNets)
+	for i := range netIDs {
+		netIDs[i] = randomID(r)
+	}
+	nodeIDs := make([]ids.NodeID, config.NumNodes)
+	for i := range nodeIDs {
+		nodeIDs[i] = randomNodeID(r)
+	}
+
+	m := newManager()
+	listener := NewInvariantListener()
+	m.RegisterCallbackListener(listener)
+
+	setter, canSetWeight := m.(validators.StakerManager)
+	numOps := 3
+	if canSetWeight {
+		numOps++
+	}
+
+	// bounds tracks the most light each validator could have since it was
+	// last added, which lets underflows be detected
+	bounds := make(map[ids.ID]map[ids.NodeID]uint64)
+	for step := 0; step < config.Steps; step++ {
+		var (
+			netID  = netIDs[r.IntN(len(netIDs))]
+			nodeID = nodeIDs[r.IntN(len(nodeIDs))]
+			light  = r.Uint64N(config.MaxLight) + 1
+			op     = r.IntN(numOps)
+			_, ok  = m.GetValidator(netID, nodeID)
+			err    error
+			opName string
+		)
+		if bounds[netID] == nil {
+			bounds[netID] = make(map[ids.NodeID]uint64)
+		}
+		if !ok {
+			delete(bounds[netID], nodeID)
+		}
+
+		switch op {
+		case 0:
+			opName = "AddStaker"
+			err = m.AddStaker(netID, nodeID, nil, ids.Empty, light)
+			switch {
+			case ok && errors.Is(err, validators.ErrDuplicateValidator):
+				err = nil // The validator is left as it was
+			case err == nil:
+				bounds[netID][nodeID] = light
+			}
+		case 1:
+			opName = "AddWeight"
+			err = m.AddWeight(netID, nodeID, light)
+			if ok {
+				bounds[netID][nodeID] += light
+			}
+		case 2:
+			opName = "RemoveWeight"
+			err = m.RemoveWeight(netID, nodeID, light)
+		case 3:
+			opName = "SetWeight"
+			err = setter.SetWeight(netID, nodeID, light)
+			bounds[netID][nodeID] = light
+		}
+		require.NoError(err, "step %d: %s(%s, %s, %d)", step, opName, netID, nodeID, light)
+		require.NoError(
+			CheckManagerInvariants(m, netIDs, bounds, listener),
+			"step %d: %s(%s, %s, %d)", step, opName, netID, nodeID, light,
+		)
+	}
+}
+
+// CheckManagerInvariants verifies the invariants of [m] for [netIDs].
+//
+// If [bounds] is non-nil, every validator's light must not exceed its bound.
+// If [listener] is non-nil, the state reconstructed from the listener's events
+// must match the manager's state.
+func CheckManagerInvariants(
+	m validators.Manager,
+	netIDs []ids.ID,
+	bounds map[ids.ID]map[ids.NodeID]uint64,
+	listener *InvariantListener,
+) error {
+	numNets := 0
+	for _, netID := range netIDs {
+		vdrs := m.GetMap(netID)
+		if len(vdrs) > 0 {
+			numNets++
+		}
+
+		var sum uint64
+		for nodeID, vdr := range vdrs {
+			if vdr.Light == 0 {
+				return fmt.Errorf("%w: %s in %s", errZeroLight, nodeID, netID)
+			}
+			if vdr.Weight != vdr.Light || m.GetLight(netID, nodeID) != vdr.Light || m.GetWeight(netID, nodeID) != vdr.Light {
+				return fmt.Errorf("%w: %s in %s", errLightMismatch, nodeID, netID)
+			}
+			if bound, ok := bounds[netID][nodeID]; bounds != nil && (!ok || vdr.Light > bound) {
+				return fmt.Errorf("%w: %s in %s has %d > %d", errLightUnderflow, nodeID, netID, vdr.Light, bound)
+			}
+			sum += vdr.Light
+		}
+
+		total, err := m.TotalLight(netID)
+		if err != nil {
+			return err
+		}
+		if total != sum {
+			return fmt.Errorf("%w: %s has total %d but sum %d", errTotalMismatch, netID, total, sum)
+		}
+
+		set, err := m.GetValidators(netID)
+		if err != nil {
+			return err
+		}
+		if set.Light() != sum {
+			return fmt.Errorf("%w: %s has set light %d but sum %d", errTotalMismatch, netID, set.Light(), sum)
+		}
+		if count := len(vdrs); m.Count(netID) != count || m.NumValidators(netID) != count ||
+			len(m.GetValidatorIDs(netID)) != count || set.Len() != count {
+			return fmt.Errorf("%w: %s", errCountMismatch, netID)
+		}
+
+		if listener != nil {
+			if err := listener.check(netID, vdrs); err != nil {
+				return err
+			}
+		}
+	}
+	if m.NumNets() != numNets {
+		return fmt.Errorf("%w: expected %d but got %d", errNumNetsMismatch, numNets, m.NumNets())
+	}
+	return nil
+}
+
+// InvariantListener reconstructs the validator lights from manager events so
+// they can be compared against the manager's state
+type InvariantListener struct {
+	mu     sync.Mutex
+	lights map[ids.ID]map[ids.NodeID]uint64
+	err    error
+}
+
+// NewInvariantListener returns a listener with no tracked validators
+func NewInvariantListener() *InvariantListener {
+	return &InvariantListener{
+		lights: make(map[ids.ID]map[ids.NodeID]uint64),
+	}
+}
+
+// OnValidatorAdded implements validators.ManagerCallbackListener
+func (l *InvariantListener) OnValidatorAdded(netID ids.ID, nodeID ids.NodeID, light uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.lights[netID] == nil {
+		l.lights[netID] = make(map[ids.NodeID]uint64)
+	}
+	l.lights[netID][nodeID] = light
+}
+
+// OnValidatorRemoved implements validators.ManagerCallbackListener
+func (l *InvariantListener) OnValidatorRemoved(netID ids.ID, nodeID ids.NodeID, light uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if current, ok := l.lights[netID][nodeID]; !ok || current != light {
+		l.fail(fmt.Errorf("%w: removed %s from %s with light %d but tracked %d", errListenerMismatch, nodeID, netID, light, current))
+	}
+	delete(l.lights[netID], nodeID)
+}
+
+// OnValidatorLightChanged implements validators.ManagerCallbackListener
+func (l *InvariantListener) OnValidatorLightChanged(netID ids.ID, nodeID ids.NodeID, oldLight, newLight uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if current, ok := l.lights[netID][nodeID]; !ok || current != oldLight {
+		l.fail(fmt.Errorf("%w: changed %s in %s from %d but tracked %d", errListenerMismatch, nodeID, netID, oldLight, current))
+	}
+	if l.lights[netID] == nil {
+		l.lights[netID] = make(map[ids.NodeID]uint64)
+	}
+	l.lights[netID][nodeID] = newLight
+}
+
+// fail records the first error reported by the listener
+func (l *InvariantListener) fail(err error) {
+	if l.err == nil {
+		l.err = err
+	}
+}
+
+func (l *InvariantListener) check(netID ids.ID, vdrs map[ids.NodeID]*validators.GetValidatorOutput) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.err != nil {
+		return l.err
+	}
+	lights := l.lights[netID]
+	if len(lights) != len(vdrs) {
+		return fmt.Errorf("%w: %s has %d validators but listener tracked %d", errListenerMismatch, netID, len(vdrs), len(lights))
+	}
+	for nodeID, vdr := range vdrs {
+		if light, ok := lights[nodeID]; !ok || light != vdr.Light {
+			return fmt.Errorf("%w: %s in %s has light %d but listener tracked %d", errListenerMismatch, nodeID, netID, vdr.Light, light)
+		}
+	}
+	return nil
+}
+
+var _ validators.ManagerCallbackListener = (*InvariantListener)(nil)
+
+func randomID(r *rand.Rand) ids.ID {
+	var id ids.ID
+	for i := range id {
+		id[i] = byte(r.Uint32())
+	}
+	return id
+}
+
+func randomNodeID(r *rand.Rand) ids.NodeID {
+	var nodeID ids.NodeID
+	for i := range nodeID {
+		nodeID[i] = byte(r.Uint32())
+	}
+	return nodeID
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorstest
+
+import (
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+func newManager() validators.Manager {
+	return validators.NewManager()
+}
+
+// TestManagerInvariants runs the invariant harness against the default manager
+func TestManagerInvariants(t *testing.T) {
+	for _, seed := range []uint64{1, 2, 3} {
+		config := DefaultInvariantConfig()
+		config.Seed = seed
+		RunManagerInvariants(t, newManager, config)
+	}
+}
+
+// TestCheckManagerInvariantsListenerMismatch tests that missed events are detected
+func TestCheckManagerInvariantsListenerMismatch(t *testing.T) {
+	require := require.New(t)
+
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	require.NoError(m.AddStaker(netID, ids.GenerateTestNodeID(), nil, ids.Empty, 100))
+
+	// The listener was never registered, so it didn't observe the addition
+	listener := NewInvariantListener()
+	err := CheckManagerInvariants(m, []ids.ID{netID}, nil, listener)
+	require.ErrorIs(err, errListenerMismatch)
+
+	m.RegisterCallbackListener(listener)
+	require.NoError(CheckManagerInvariants(m, []ids.ID{netID}, nil, listener))
+}
+
+// TestCheckManagerInvariantsUnderflow tests that lights above their bound are detected
+func TestCheckManagerInvariantsUnderflow(t *testing.T) {
+	require := require.New(t)
+
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID, nil, ids.Empty, 100))
+
+	bounds := map[ids.ID]map[ids.NodeID]uint64{
+		netID: {nodeID: 50},
+	}
+	err := CheckManagerInvariants(m, []ids.ID{netID}, bounds, nil)
+	require.ErrorIs(err, errLightUnderflow)
+
+	bounds[netID][nodeID] = 100
+	require.NoError(CheckManagerInvariants(m, []ids.ID{netID}, bounds, nil))
+}
+
+// TestInvariantListenerUnknownRemoval tests that removing an untracked validator is reported
+func TestInvariantListenerUnknownRemoval(t *testing.T) {
+	listener := NewInvariantListener()
+	netID := ids.GenerateTestID()
+	listener.OnValidatorRemoved(netID, ids.GenerateTestNodeID(), 10)
+	require.ErrorIs(t, listener.check(netID, nil), errListenerMismatch)
+}
@@ -0,0 +1,260 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorstest
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/luxfi/ids"
+
+	validators "github.com/luxfi/validators"
+)
+
+var errInvalidLoadConfig = errors.New("invalid load config")
+
+// initialLoadLight is the light every validator starts with. Writes move the
+// light by at most 1, so validators are never removed during a run.
+const initialLoadLight = 1_000_000
+
+// LoadConfig describes the mutation/query mix driven against a Manager
+type LoadConfig struct {
+	// Seed makes the operation sequence reproducible
+	Seed uint64
+	// NumNets is the number of nets the validators are spread over
+	NumNets int
+	// NumValidators is the number of validators in each net
+	NumValidators int
+	// Ops is the total number of operations to issue
+	Ops int
+	// Concurrency is the number of goroutines issuing operations
+	Concurrency int
+	// ReadRatio is the fraction of operations that are queries
+	ReadRatio float64
+	// SetReadRatio is the fraction of queries that read a whole validator set
+	// rather than a single validator
+	SetReadRatio float64
+}
+
+// DefaultLoadConfig returns a read-heavy mix of 95% reads and 5% weight
+// changes over 10k validators
+func DefaultLoadConfig() LoadConfig {
+	return LoadConfig{
+		Seed:          1,
+		NumNets:       1,
+		NumValidators: 10_000,
+		Ops:           100_000,
+		Concurrency:   runtime.GOMAXPROCS(0),
+		ReadRatio:     0.95,
+		SetReadRatio:  0.01,
+	}
+}
+
+// LatencySummary summarizes the latencies of one kind of operation
+type LatencySummary struct {
+	Count int
+	Mean  time.Duration
+	P50   time.Duration
+	P99   time.Duration
+	Max   time.Duration
+}
+
+// LoadReport is the result of a load run
+type LoadReport struct {
+	Elapsed      time.Duration
+	Throughput   float64 // operations per second
+	Reads        LatencySummary
+	Writes       LatencySummary
+	AllocsPerOp  float64
+	BytesPerOp   float64
+	ErroredCalls int
+}
+
+// String implements fmt.Stringer
+func (r LoadReport) String() string {
+	return fmt.Sprintf(
+		"%.0f ops/s, reads p50=%s p99=%s, writes p50=%s p99=%s, %.1f allocs/op, %.0f B/op, %d errors",
+		r.Throughput,
+		r.Reads.P50, r.Reads.P99,
+		r.Writes.P50, r.Writes.P99,
+		r.AllocsPerOp, r.BytesPerOp,
+		r.ErroredCalls,
+	)
+}
+
+// PopulateLoad adds the validators described by [config] to [m] and returns
+// the IDs that were used
+func PopulateLoad(m validators.Manager, config LoadConfig) ([]ids.ID, []ids.NodeID, error) {
+	r := rand.New(rand.NewPCG(config.Seed, config.Seed))
+	netIDs := make([]ids.ID, config.NumNets)
+	for i := range netIDs {
+		netIDs[i] = randomID(r)
+	}
+	nodeIDs := make([]ids.NodeID, config.NumValidators)
+	for i := range nodeIDs {
+		nodeIDs[i] = randomNodeID(r)
+	}
+	for _, netID := range netIDs {
+		for _, nodeID := range nodeIDs {
+			if err := m.AddStaker(netID, nodeID, nil, ids.Empty, initialLoadLight); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return netIDs, nodeIDs, nil
+}
+
+// RunLoad populates [m] and then issues the configured operation mix against
+// it, measuring throughput, per-operation latency, and allocations
+func RunLoad(m validators.Manager, config LoadConfig) (LoadReport, error) {
+	if config.NumNets <= 0 || config.NumValidators <= 0 || config.Concurrency <= 0 || config.Ops < 0 {
+		return LoadReport{}, fmt.Errorf("%w: %+v", errInvalidLoadConfig, config)
+	}
+
+	netIDs, nodeIDs, err := PopulateLoad(m, config)
+	if err != nil {
+		return LoadReport{}, err
+	}
+	return driveLoad(m, config, netIDs, nodeIDs), nil
+}
+
+func driveLoad(m validators.Manager, config LoadConfig, netIDs []ids.ID, nodeIDs []ids.NodeID) LoadReport {
+	type workerResult struct {
+		reads  []time.Duration
+		writes []time.Duration
+		errs   int
+	}
+	results := make([]workerResult, config.Concurrency)
+
+	// Latency buffers are allocated up front so they aren't attributed to the
+	// manager
+	numOps := make([]int, config.Concurrency)
+	for w := range results {
+		numOps[w] = config.Ops / config.Concurrency
+		if w == 0 {
+			numOps[w] += config.Ops % config.Concurrency
+		}
+		results[w].reads = make([]time.Duration, 0, numOps[w])
+		results[w].writes = make([]time.Duration, 0, numOps[w])
+	}
+
+	var (
+		wg     sync.WaitGroup
+		before runtime.MemStats
+		after  runtime.MemStats
+	)
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for w := range results {
+		seed := config.Seed + uint64(w) + 1
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			r := rand.New(rand.NewPCG(seed, seed))
+			result := &results[w]
+			for i := 0; i < numOps[w]; i++ {
+				netID := netIDs[r.IntN(len(netIDs))]
+				nodeID := nodeIDs[r.IntN(len(nodeIDs))]
+
+				opStart := time.Now()
+				if r.Float64() < config.ReadRatio {
+					if r.Float64() < config.SetReadRatio {
+						if _, err := m.TotalLight(netID); err != nil {
+							result.errs++
+						}
+					} else {
+						_ = m.GetLight(netID, nodeID)
+					}
+					result.reads = append(result.reads, time.Since(opStart))
+					continue
+				}
+
+				var err error
+				if r.IntN(2) == 0 {
+					err = m.AddWeight(netID, nodeID, 1)
+				} else {
+					err = m.RemoveWeight(netID, nodeID, 1)
+				}
+				if err != nil {
+					result.errs++
+				}
+				result.writes = append(result.writes, time.Since(opStart))
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	var (
+		reads  []time.Duration
+		writes []time.Duration
+		report = LoadReport{Elapsed: elapsed}
+	)
+	for _, result := range results {
+		reads = append(reads, result.reads...)
+		writes = append(writes, result.writes...)
+		report.ErroredCalls += result.errs
+	}
+	report.Reads = summarizeLatencies(reads)
+	report.Writes = summarizeLatencies(writes)
+	if config.Ops > 0 {
+		report.Throughput = float64(config.Ops) / elapsed.Seconds()
+		report.AllocsPerOp = float64(after.Mallocs-before.Mallocs) / float64(config.Ops)
+		report.BytesPerOp = float64(after.TotalAlloc-before.TotalAlloc) / float64(config.Ops)
+	}
+	return report
+}
+
+func summarizeLatencies(latencies []time.Duration) LatencySummary {
+	if len(latencies) == 0 {
+		return LatencySummary{}
+	}
+	slices.Sort(latencies)
+
+	var total time.Duration
+	for _, latency := range latencies {
+		total += latency
+	}
+	return LatencySummary{
+		Count: len(latencies),
+		Mean:  total / time.Duration(len(latencies)),
+		P50:   latencies[len(latencies)/2],
+		P99:   latencies[len(latencies)*99/100],
+		Max:   latencies[len(latencies)-1],
+	}
+}
+
+// BenchmarkManagerLoad populates a manager returned by [newManager] outside of
+// the timed region and then runs b.N operations of the configured mix,
+// reporting throughput and latency percentiles as custom metrics
+func BenchmarkManagerLoad(b *testing.B, newManager func() validators.Manager, config LoadConfig) {
+	m := newManager()
+	netIDs, nodeIDs, err := PopulateLoad(m, config)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	config.Ops = b.N
+	b.ReportAllocs()
+	b.ResetTimer()
+	report := driveLoad(m, config, netIDs, nodeIDs)
+	b.StopTimer()
+
+	if report.ErroredCalls > 0 {
+		b.Fatalf("%d operations failed", report.ErroredCalls)
+	}
+	b.ReportMetric(report.Throughput, "ops/s")
+	b.ReportMetric(float64(report.Reads.P99.Nanoseconds()), "read-p99-ns")
+	b.ReportMetric(float64(report.Writes.P99.Nanoseconds()), "write-p99-ns")
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorstest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+// TestRunLoad tests that a small load run reports every operation
+func TestRunLoad(t *testing.T) {
+	require := require.New(t)
+
+	config := DefaultLoadConfig()
+	config.NumValidators = 100
+	config.Ops = 1_000
+	config.Concurrency = 3
+
+	m := validators.NewManager()
+	report, err := RunLoad(m, config)
+	require.NoError(err)
+	require.Zero(report.ErroredCalls)
+	require.Equal(config.Ops, report.Reads.Count+report.Writes.Count)
+	require.Positive(report.Reads.Count)
+	require.Positive(report.Throughput)
+	require.LessOrEqual(report.Reads.P50, report.Reads.P99)
+	require.LessOrEqual(report.Reads.P99, report.Reads.Max)
+	require.Equal(config.NumNets, m.NumNets())
+}
+
+// TestRunLoadInvalidConfig tests that invalid configs are rejected
+func TestRunLoadInvalidConfig(t *testing.T) {
+	config := DefaultLoadConfig()
+	config.Concurrency = 0
+	_, err := RunLoad(validators.NewManager(), config)
+	require.ErrorIs(t, err, errInvalidLoadConfig)
+}
+
+func BenchmarkManagerReadHeavy(b *testing.B) {
+	BenchmarkManagerLoad(b, func() validators.Manager {
+		return validators.NewManager()
+	}, DefaultLoadConfig())
+}
+
+func BenchmarkManagerWriteHeavy(b *testing.B) {
+	config := DefaultLoadConfig()
+	config.ReadRatio = 0.5
+	BenchmarkManagerLoad(b, func() validators.Manager {
+		return validators.NewManager()
+	}, config)
+}
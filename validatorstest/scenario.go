@@ -0,0 +1,228 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorstest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+var (
+	errUnexpectedLight = errors.New("unexpected validator light")
+	errUnexpectedCount = errors.New("unexpected validator count")
+	errUnexpectedTotal = errors.New("unexpected total light")
+	errUnexpectedFound = errors.New("unexpected validator presence")
+)
+
+// ScenarioContext is the state a scenario's steps operate on
+type ScenarioContext struct {
+	Manager validators.Manager
+	Height  uint64
+	Time    time.Time
+
+	advanceHooks []func(*ScenarioContext) error
+}
+
+// OnAdvance registers [hook] to be called after every height or time
+// advancement. Hooks are how lifecycle rules such as epoch rotation, expiry,
+// or churn limits are plugged into a scenario.
+func (c *ScenarioContext) OnAdvance(hook func(*ScenarioContext) error) {
+	c.advanceHooks = append(c.advanceHooks, hook)
+}
+
+func (c *ScenarioContext) advanced() error {
+	for _, hook := range c.advanceHooks {
+		if err := hook(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Step is a single named action of a scenario
+type Step struct {
+	Name string
+	Do   func(*ScenarioContext) error
+}
+
+// Scenario is an ordered list of steps applied to a manager
+type Scenario struct {
+	Name  string
+	Steps []Step
+}
+
+// Run applies every step of the scenario to [m], starting at [startHeight] and
+// [startTime]. It returns the first step error, annotated with its index and
+// name.
+func (s Scenario) Run(m validators.Manager, startHeight uint64, startTime time.Time) error {
+	ctx := &ScenarioContext{
+		Manager: m,
+		Height:  startHeight,
+		Time:    startTime,
+	}
+	for i, step := range s.Steps {
+		if err := step.Do(ctx); err != nil {
+			return fmt.Errorf("scenario %q step %d (%s): %w", s.Name, i, step.Name, err)
+		}
+	}
+	return nil
+}
+
+// RunScenario runs [steps] against [m] from height 0 and the unix epoch,
+// failing [t] on the first step error
+func RunScenario(t testing.TB, m validators.Manager, steps ...Step) {
+	t.Helper()
+
+	scenario := Scenario{
+		Name:  t.Name(),
+		Steps: steps,
+	}
+	require.NoError(t, scenario.Run(m, 0, time.Unix(0, 0)))
+}
+
+// Hook registers an advancement hook, see ScenarioContext.OnAdvance
+func Hook(name string, hook func(*ScenarioContext) error) Step {
+	return Step{
+		Name: "hook " + name,
+		Do: func(c *ScenarioContext) error {
+			c.OnAdvance(hook)
+			return nil
+		},
+	}
+}
+
+// Add adds [nodeID] to [netID] with [light]
+func Add(netID ids.ID, nodeID ids.NodeID, light uint64) Step {
+	return Step{
+		Name: fmt.Sprintf("add %s to %s with %d", nodeID, netID, light),
+		Do: func(c *ScenarioContext) error {
+			return c.Manager.AddStaker(netID, nodeID, nil, ids.Empty, light)
+		},
+	}
+}
+
+// Remove removes all of [nodeID]'s light from [netID]
+func Remove(netID ids.ID, nodeID ids.NodeID) Step {
+	return Step{
+		Name: fmt.Sprintf("remove %s from %s", nodeID, netID),
+		Do: func(c *ScenarioContext) error {
+			return c.Manager.RemoveWeight(netID, nodeID, c.Manager.GetLight(netID, nodeID))
+		},
+	}
+}
+
+// Reweight sets the light of [nodeID] in [netID] to [light]
+func Reweight(netID ids.ID, nodeID ids.NodeID, light uint64) Step {
+	return Step{
+		Name: fmt.Sprintf("reweight %s in %s to %d", nodeID, netID, light),
+		Do: func(c *ScenarioContext) error {
+			if setter, ok := c.Manager.(validators.StakerManager); ok {
+				return setter.SetWeight(netID, nodeID, light)
+			}
+
+			current := c.Manager.GetLight(netID, nodeID)
+			switch {
+			case light > current:
+				return c.Manager.AddWeight(netID, nodeID, light-current)
+			case light < current:
+				return c.Manager.RemoveWeight(netID, nodeID, current-light)
+			default:
+				return nil
+			}
+		},
+	}
+}
+
+// AdvanceHeight increases the scenario height by [blocks]
+func AdvanceHeight(blocks uint64) Step {
+	return Step{
+		Name: fmt.Sprintf("advance height by %d", blocks),
+		Do: func(c *ScenarioContext) error {
+			c.Height += blocks
+			return c.advanced()
+		},
+	}
+}
+
+// AdvanceTime increases the scenario time by [d]
+func AdvanceTime(d time.Duration) Step {
+	return Step{
+		Name: fmt.Sprintf("advance time by %s", d),
+		Do: func(c *ScenarioContext) error {
+			c.Time = c.Time.Add(d)
+			return c.advanced()
+		},
+	}
+}
+
+// Assert runs an arbitrary check against the scenario
+func Assert(name string, check func(*ScenarioContext) error) Step {
+	return Step{
+		Name: "assert " + name,
+		Do:   check,
+	}
+}
+
+// AssertLight checks that [nodeID] has [light] in [netID]
+func AssertLight(netID ids.ID, nodeID ids.NodeID, light uint64) Step {
+	return Assert(
+		fmt.Sprintf("%s in %s has light %d", nodeID, netID, light),
+		func(c *ScenarioContext) error {
+			if got := c.Manager.GetLight(netID, nodeID); got != light {
+				return fmt.Errorf("%w: expected %d but got %d", errUnexpectedLight, light, got)
+			}
+			return nil
+		},
+	)
+}
+
+// AssertPresent checks whether [nodeID] is a validator of [netID]
+func AssertPresent(netID ids.ID, nodeID ids.NodeID, present bool) Step {
+	return Assert(
+		fmt.Sprintf("%s in %s is present=%t", nodeID, netID, present),
+		func(c *ScenarioContext) error {
+			if _, ok := c.Manager.GetValidator(netID, nodeID); ok != present {
+				return fmt.Errorf("%w: expected %t but got %t", errUnexpectedFound, present, ok)
+			}
+			return nil
+		},
+	)
+}
+
+// AssertCount checks that [netID] has [count] validators
+func AssertCount(netID ids.ID, count int) Step {
+	return Assert(
+		fmt.Sprintf("%s has %d validators", netID, count),
+		func(c *ScenarioContext) error {
+			if got := c.Manager.Count(netID); got != count {
+				return fmt.Errorf("%w: expected %d but got %d", errUnexpectedCount, count, got)
+			}
+			return nil
+		},
+	)
+}
+
+// AssertTotalLight checks that the validators of [netID] have [light] in total
+func AssertTotalLight(netID ids.ID, light uint64) Step {
+	return Assert(
+		fmt.Sprintf("%s has total light %d", netID, light),
+		func(c *ScenarioContext) error {
+			got, err := c.Manager.TotalLight(netID)
+			if err != nil {
+				return err
+			}
+			if got != light {
+				return fmt.Errorf("%w: expected %d but got %d", errUnexpectedTotal, light, got)
+			}
+			return nil
+		},
+	)
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorstest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+// TestScenarioLifecycle tests a basic add, reweight, remove lifecycle
+func TestScenarioLifecycle(t *testing.T) {
+	netID := ids.GenerateTestID()
+	nodeID1 := ids.GenerateTestNodeID()
+	nodeID2 := ids.GenerateTestNodeID()
+
+	RunScenario(t, validators.NewManager(),
+		Add(netID, nodeID1, 100),
+		Add(netID, nodeID2, 200),
+		AssertCount(netID, 2),
+		AssertTotalLight(netID, 300),
+		Reweight(netID, nodeID1, 150),
+		AssertLight(netID, nodeID1, 150),
+		Reweight(netID, nodeID2, 50),
+		AssertLight(netID, nodeID2, 50),
+		Remove(netID, nodeID1),
+		AssertPresent(netID, nodeID1, false),
+		AssertPresent(netID, nodeID2, true),
+		AssertTotalLight(netID, 50),
+	)
+}
+
+// TestScenarioExpiryHook tests that hooks run as the scenario advances
+func TestScenarioExpiryHook(t *testing.T) {
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	const expiryHeight = 10
+
+	RunScenario(t, validators.NewManager(),
+		Hook("expire at height 10", func(c *ScenarioContext) error {
+			if c.Height < expiryHeight {
+				return nil
+			}
+			return c.Manager.RemoveWeight(netID, nodeID, c.Manager.GetLight(netID, nodeID))
+		}),
+		Add(netID, nodeID, 100),
+		AdvanceHeight(5),
+		AssertPresent(netID, nodeID, true),
+		AdvanceTime(time.Hour),
+		AdvanceHeight(5),
+		AssertPresent(netID, nodeID, false),
+		Assert("height and time advanced", func(c *ScenarioContext) error {
+			require.Equal(t, uint64(10), c.Height)
+			require.Equal(t, time.Unix(0, 0).Add(time.Hour), c.Time)
+			return nil
+		}),
+	)
+}
+
+// TestScenarioFailure tests that failing steps are reported
+func TestScenarioFailure(t *testing.T) {
+	require := require.New(t)
+
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	scenario := Scenario{
+		Name: "failure",
+		Steps: []Step{
+			Add(netID, nodeID, 100),
+			AssertLight(netID, nodeID, 200),
+		},
+	}
+	err := scenario.Run(validators.NewManager(), 0, time.Unix(0, 0))
+	require.ErrorIs(err, errUnexpectedLight)
+	require.ErrorContains(err, "step 1")
+}
@@ -0,0 +1,263 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorstest
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+// UpdateGoldenEnv is the environment variable that, when set to a non-empty
+// value, makes RequireGoldenSnapshot rewrite golden files instead of
+// comparing against them
+const UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+// ManagerSnapshot is a canonical, human readable encoding of a manager's
+// validator sets. Nets and validators are sorted by ID.
+type ManagerSnapshot struct {
+	Nets []NetSnapshot `json:"nets"`
+}
+
+// NetSnapshot is the canonical encoding of a single net's validators and, for
+// managers that implement validators.AccessManager, its access lists
+type NetSnapshot struct {
+	NetID      string              `json:"netID"`
+	Validators []ValidatorSnapshot `json:"validators"`
+
+	Permissioned bool     `json:"permissioned,omitempty"`
+	Allowlist    []string `json:"allowlist,omitempty"`
+	Denylist     []string `json:"denylist,omitempty"`
+}
+
+// ValidatorSnapshot is the canonical encoding of a single validator
+type ValidatorSnapshot struct {
+	NodeID         string `json:"nodeID"`
+	PublicKey      string `json:"publicKey,omitempty"`
+	RingtailPubKey string `json:"ringtailPubKey,omitempty"`
+	Light          uint64 `json:"light"`
+	Weight         uint64 `json:"weight"`
+	TxID           string `json:"txID"`
+
+	Metadata   *validators.ValidatorMetadata `json:"metadata,omitempty"`
+	Extensions map[string]string             `json:"extensions,omitempty"`
+}
+
+// SnapshotManager returns the canonical snapshot of the validators of
+// [netIDs] in [m]. Nets without validators or access lists are omitted.
+func SnapshotManager(m validators.Manager, netIDs []ids.ID) ManagerSnapshot {
+	snapshot := ManagerSnapshot{
+		Nets: make([]NetSnapshot, 0, len(netIDs)),
+	}
+	access, hasAccess := m.(validators.AccessManager)
+	for _, netID := range netIDs {
+		vdrs := m.GetMap(netID)
+		net := NetSnapshot{
+			NetID:      netID.String(),
+			Validators: make([]ValidatorSnapshot, 0, len(vdrs)),
+		}
+		if hasAccess {
+			var allowlist []ids.NodeID
+			allowlist, net.Permissioned = access.GetAllowlist(netID)
+			net.Allowlist = nodeIDStrings(allowlist)
+			net.Denylist = nodeIDStrings(access.GetDenylist(netID))
+		}
+		if len(vdrs) == 0 && !net.Permissioned && len(net.Denylist) == 0 {
+			continue
+		}
+
+		for nodeID, vdr := range vdrs {
+			net.Validators = append(net.Validators, ValidatorSnapshot{
+				NodeID:         nodeID.String(),
+				PublicKey:      hex.EncodeToString(vdr.PublicKey),
+				RingtailPubKey: hex.EncodeToString(vdr.RingtailPubKey),
+				Light:          vdr.Light,
+				Weight:         vdr.Weight,
+				TxID:           vdr.TxID.String(),
+				Metadata:       vdr.Metadata,
+				Extensions:     hexExtensions(vdr.Extensions),
+			})
+		}
+		slices.SortFunc(net.Validators, func(a, b ValidatorSnapshot) int {
+			return strings.Compare(a.NodeID, b.NodeID)
+		})
+		snapshot.Nets = append(snapshot.Nets, net)
+	}
+	slices.SortFunc(snapshot.Nets, func(a, b NetSnapshot) int {
+		return strings.Compare(a.NetID, b.NetID)
+	})
+	return snapshot
+}
+
+func nodeIDStrings(nodeIDs []ids.NodeID) []string {
+	if len(nodeIDs) == 0 {
+		return nil
+	}
+	strs := make([]string, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		strs[i] = nodeID.String()
+	}
+	slices.Sort(strs)
+	return strs
+}
+
+// Marshal returns the canonical encoding of the snapshot
+func (s ManagerSnapshot) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// DiffSnapshots returns one line per difference between [expected] and
+// [actual]. It returns nil if the snapshots are equal.
+func DiffSnapshots(expected, actual ManagerSnapshot) []string {
+	var (
+		diffs        []string
+		expectedMap  = snapshotIndex(expected)
+		actualMap    = snapshotIndex(actual)
+		expectedNets = netIndex(expected)
+		actualNets   = netIndex(actual)
+	)
+	for _, netID := range sortedKeys(expectedMap, actualMap) {
+		diffs = append(diffs, diffAccess(netID, expectedNets[netID], actualNets[netID])...)
+
+		expectedVdrs, actualVdrs := expectedMap[netID], actualMap[netID]
+		for _, nodeID := range sortedKeys(expectedVdrs, actualVdrs) {
+			expectedVdr, expectedOK := expectedVdrs[nodeID]
+			actualVdr, actualOK := actualVdrs[nodeID]
+			prefix := fmt.Sprintf("net %s: validator %s", netID, nodeID)
+			switch {
+			case !actualOK:
+				diffs = append(diffs, fmt.Sprintf("%s: missing (expected light %d)", prefix, expectedVdr.Light))
+			case !expectedOK:
+				diffs = append(diffs, fmt.Sprintf("%s: unexpected (light %d)", prefix, actualVdr.Light))
+			default:
+				diffs = append(diffs, diffValidator(prefix, expectedVdr, actualVdr)...)
+			}
+		}
+	}
+	return diffs
+}
+
+func diffValidator(prefix string, expected, actual ValidatorSnapshot) []string {
+	var diffs []string
+	field := func(name string, expected, actual any) {
+		if expected != actual {
+			diffs = append(diffs, fmt.Sprintf("%s: %s %v -> %v", prefix, name, expected, actual))
+		}
+	}
+	field("light", expected.Light, actual.Light)
+	field("weight", expected.Weight, actual.Weight)
+	field("publicKey", expected.PublicKey, actual.PublicKey)
+	field("ringtailPubKey", expected.RingtailPubKey, actual.RingtailPubKey)
+	field("txID", expected.TxID, actual.TxID)
+	if !reflect.DeepEqual(expected.Metadata, actual.Metadata) {
+		diffs = append(diffs, fmt.Sprintf("%s: metadata %+v -> %+v", prefix, expected.Metadata, actual.Metadata))
+	}
+	if !maps.Equal(expected.Extensions, actual.Extensions) {
+		diffs = append(diffs, fmt.Sprintf("%s: extensions %v -> %v", prefix, expected.Extensions, actual.Extensions))
+	}
+	return diffs
+}
+
+// hexExtensions returns [extensions] with hex encoded values
+func hexExtensions(extensions map[string][]byte) map[string]string {
+	if len(extensions) == 0 {
+		return nil
+	}
+	encoded := make(map[string]string, len(extensions))
+	for key, value := range extensions {
+		encoded[key] = hex.EncodeToString(value)
+	}
+	return encoded
+}
+
+func diffAccess(netID string, expected, actual NetSnapshot) []string {
+	var diffs []string
+	if expected.Permissioned != actual.Permissioned {
+		diffs = append(diffs, fmt.Sprintf("net %s: permissioned %t -> %t", netID, expected.Permissioned, actual.Permissioned))
+	}
+	if !slices.Equal(expected.Allowlist, actual.Allowlist) {
+		diffs = append(diffs, fmt.Sprintf("net %s: allowlist %v -> %v", netID, expected.Allowlist, actual.Allowlist))
+	}
+	if !slices.Equal(expected.Denylist, actual.Denylist) {
+		diffs = append(diffs, fmt.Sprintf("net %s: denylist %v -> %v", netID, expected.Denylist, actual.Denylist))
+	}
+	return diffs
+}
+
+func netIndex(s ManagerSnapshot) map[string]NetSnapshot {
+	index := make(map[string]NetSnapshot, len(s.Nets))
+	for _, net := range s.Nets {
+		index[net.NetID] = net
+	}
+	return index
+}
+
+func snapshotIndex(s ManagerSnapshot) map[string]map[string]ValidatorSnapshot {
+	index := make(map[string]map[string]ValidatorSnapshot, len(s.Nets))
+	for _, net := range s.Nets {
+		vdrs := make(map[string]ValidatorSnapshot, len(net.Validators))
+		for _, vdr := range net.Validators {
+			vdrs[vdr.NodeID] = vdr
+		}
+		index[net.NetID] = vdrs
+	}
+	return index
+}
+
+// sortedKeys returns the union of the keys of [a] and [b] in sorted order
+func sortedKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// RequireGoldenSnapshot compares the snapshot of [m] against the golden file
+// at [path], failing [t] with a per-validator diff if they differ. If
+// UpdateGoldenEnv is set, the golden file is rewritten instead.
+func RequireGoldenSnapshot(t testing.TB, m validators.Manager, netIDs []ids.ID, path string) {
+	t.Helper()
+
+	actual := SnapshotManager(m, netIDs)
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		data, err := actual.Marshal()
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, data, 0o644))
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err, "golden snapshot missing, run with %s=1 to create it", UpdateGoldenEnv)
+
+	var expected ManagerSnapshot
+	require.NoError(t, json.Unmarshal(data, &expected))
+
+	if diffs := DiffSnapshots(expected, actual); len(diffs) > 0 {
+		t.Fatalf("manager doesn't match golden snapshot %s:\n%s", path, strings.Join(diffs, "\n"))
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorstest
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+)
+
+// TestSnapshotManagerCanonical tests that snapshots are sorted and skip empty nets
+func TestSnapshotManagerCanonical(t *testing.T) {
+	require := require.New(t)
+
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	for i := 0; i < 5; i++ {
+		require.NoError(m.AddStaker(netID, ids.GenerateTestNodeID(), []byte{byte(i)}, ids.GenerateTestID(), uint64(i+1)))
+	}
+
+	snapshot := SnapshotManager(m, []ids.ID{netID, ids.GenerateTestID()})
+	require.Len(snapshot.Nets, 1)
+	require.Len(snapshot.Nets[0].Validators, 5)
+	require.True(slices.IsSortedFunc(snapshot.Nets[0].Validators, func(a, b ValidatorSnapshot) int {
+		return strings.Compare(a.NodeID, b.NodeID)
+	}))
+
+	data1, err := snapshot.Marshal()
+	require.NoError(err)
+	data2, err := SnapshotManager(m, []ids.ID{netID}).Marshal()
+	require.NoError(err)
+	require.Equal(data1, data2)
+}
+
+// TestDiffSnapshots tests the per-validator diff output
+func TestDiffSnapshots(t *testing.T) {
+	require := require.New(t)
+
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	nodeID1 := ids.GenerateTestNodeID()
+	nodeID2 := ids.GenerateTestNodeID()
+	nodeID3 := ids.GenerateTestNodeID()
+	require.NoError(m.AddStaker(netID, nodeID1, nil, ids.Empty, 100))
+	require.NoError(m.AddStaker(netID, nodeID2, nil, ids.Empty, 200))
+	before := SnapshotManager(m, []ids.ID{netID})
+	require.Empty(DiffSnapshots(before, before))
+
+	require.NoError(m.AddWeight(netID, nodeID1, 50))
+	require.NoError(m.RemoveWeight(netID, nodeID2, 200))
+	require.NoError(m.AddStaker(netID, nodeID3, nil, ids.Empty, 300))
+	after := SnapshotManager(m, []ids.ID{netID})
+
+	require.ElementsMatch([]string{
+		"net " + netID.String() + ": validator " + nodeID1.String() + ": light 100 -> 150",
+		"net " + netID.String() + ": validator " + nodeID1.String() + ": weight 100 -> 150",
+		"net " + netID.String() + ": validator " + nodeID2.String() + ": missing (expected light 200)",
+		"net " + netID.String() + ": validator " + nodeID3.String() + ": unexpected (light 300)",
+	}, DiffSnapshots(before, after))
+}
+
+// TestRequireGoldenSnapshot tests writing and comparing golden snapshots
+func TestRequireGoldenSnapshot(t *testing.T) {
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	require.NoError(t, m.AddStaker(netID, nodeID, []byte("key"), ids.GenerateTestID(), 100))
+	require.NoError(t, m.SetMetadata(netID, nodeID, &validators.ValidatorMetadata{
+		Moniker:    "lux-0",
+		RegionTags: []string{"eu-west"},
+	}))
+
+	path := filepath.Join(t.TempDir(), "golden", "manager.json")
+	t.Setenv(UpdateGoldenEnv, "1")
+	RequireGoldenSnapshot(t, m, []ids.ID{netID}, path)
+
+	t.Setenv(UpdateGoldenEnv, "")
+	RequireGoldenSnapshot(t, m, []ids.ID{netID}, path)
+}
+
+// TestSnapshotAccessLists tests that access lists are snapshotted and diffed
+func TestSnapshotAccessLists(t *testing.T) {
+	require := require.New(t)
+
+	m := validators.NewManager()
+	netID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	before := SnapshotManager(m, []ids.ID{netID})
+	require.Empty(before.Nets)
+
+	m.SetAllowlist(netID, []ids.NodeID{nodeID})
+	m.Deny(netID, ids.GenerateTestNodeID())
+	after := SnapshotManager(m, []ids.ID{netID})
+	require.Len(after.Nets, 1)
+	require.True(after.Nets[0].Permissioned)
+	require.Equal([]string{nodeID.String()}, after.Nets[0].Allowlist)
+	require.Len(after.Nets[0].Denylist, 1)
+
+	diffs := DiffSnapshots(before, after)
+	require.Len(diffs, 3)
+	require.Equal("net "+netID.String()+": permissioned false -> true", diffs[0])
+}
@@ -0,0 +1,211 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorstest
+
+import (
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+	"github.com/luxfi/validators/uptime"
+)
+
+// StressConfig configures the stress helpers. They are intended to be run
+// with -race so that unsynchronized accesses are reported.
+type StressConfig struct {
+	// Readers is the number of goroutines issuing queries
+	Readers int
+	// Writers is the number of goroutines issuing mutations
+	Writers int
+	// Registrars is the number of goroutines registering listeners
+	Registrars int
+	// Duration is how long the goroutines are run for
+	Duration time.Duration
+	// NumNets is the number of distinct nets operated on
+	NumNets int
+	// NumNodes is the number of distinct node IDs operated on
+	NumNodes int
+}
+
+// DefaultStressConfig returns a config suitable for running in unit tests
+func DefaultStressConfig() StressConfig {
+	return StressConfig{
+		Readers:    8,
+		Writers:    4,
+		Registrars: 2,
+		Duration:   100 * time.Millisecond,
+		NumNets:    2,
+		NumNodes:   32,
+	}
+}
+
+// maxListenersPerRegistrar bounds the number of listeners each registrar adds
+// so that long runs don't degrade into measuring listener fan-out
+const maxListenersPerRegistrar = 64
+
+// StressManager concurrently queries and mutates [m] and registers listeners
+// on it for the configured duration. Afterwards the manager invariants are
+// checked.
+func StressManager(t testing.TB, m validators.Manager, config StressConfig) {
+	t.Helper()
+
+	netIDs, nodeIDs := stressIDs(config)
+	deadline := time.Now().Add(config.Duration)
+	run := func(seed uint64, worker func(r *rand.Rand, netID ids.ID, nodeID ids.NodeID, i int)) func() {
+		return func() {
+			r := rand.New(rand.NewPCG(seed, seed))
+			for i := 0; ; i++ {
+				worker(r, netIDs[r.IntN(len(netIDs))], nodeIDs[r.IntN(len(nodeIDs))], i)
+				if i%16 == 0 && !time.Now().Before(deadline) {
+					return
+				}
+			}
+		}
+	}
+
+	var (
+		errsLock sync.Mutex
+		errs     []error
+	)
+	recordErr := func(err error) {
+		if err == nil {
+			return
+		}
+		errsLock.Lock()
+		defer errsLock.Unlock()
+		errs = append(errs, err)
+	}
+
+	workers := make([]func(), 0, config.Readers+config.Writers+config.Registrars)
+	for i := 0; i < config.Readers; i++ {
+		workers = append(workers, run(uint64(i), func(r *rand.Rand, netID ids.ID, nodeID ids.NodeID, _ int) {
+			switch r.IntN(6) {
+			case 0:
+				if vdr, ok := m.GetValidator(netID, nodeID); ok {
+					_ = vdr.Light
+				}
+			case 1:
+				_, err := m.TotalLight(netID)
+				recordErr(err)
+			case 2:
+				set, err := m.GetValidators(netID)
+				recordErr(err)
+				if set != nil {
+					_ = set.Light()
+					_ = set.List()
+				}
+			case 3:
+				for _, vdr := range m.GetMap(netID) {
+					_ = vdr.Light
+				}
+			case 4:
+				_, err := m.Sample(netID, 4)
+				recordErr(err)
+			default:
+				_ = m.GetValidatorIDs(netID)
+				_ = m.Count(netID)
+				_ = m.NumNets()
+			}
+		}))
+	}
+	for i := 0; i < config.Writers; i++ {
+		workers = append(workers, run(uint64(config.Readers+i), func(r *rand.Rand, netID ids.ID, nodeID ids.NodeID, _ int) {
+			light := r.Uint64N(1000) + 1
+			switch r.IntN(3) {
+			case 0:
+				if err := m.AddStaker(netID, nodeID, nil, ids.Empty, light); !errors.Is(err, validators.ErrDuplicateValidator) {
+					recordErr(err)
+				}
+			case 1:
+				recordErr(m.AddWeight(netID, nodeID, light))
+			default:
+				recordErr(m.RemoveWeight(netID, nodeID, light))
+			}
+		}))
+	}
+	for i := 0; i < config.Registrars; i++ {
+		workers = append(workers, run(uint64(config.Readers+config.Writers+i), func(_ *rand.Rand, _ ids.ID, _ ids.NodeID, i int) {
+			if i < maxListenersPerRegistrar {
+				m.RegisterCallbackListener(NewInvariantListener())
+			}
+		}))
+	}
+
+	runStress(workers)
+	require.Empty(t, errs)
+	require.NoError(t, CheckManagerInvariants(m, netIDs, nil, nil))
+}
+
+// StressCalculator concurrently queries [calc] and replaces its per-net
+// calculators for the configured duration
+func StressCalculator(t testing.TB, calc uptime.Calculator, config StressConfig) {
+	t.Helper()
+
+	netIDs, nodeIDs := stressIDs(config)
+	deadline := time.Now().Add(config.Duration)
+	calculators := []uptime.Calculator{
+		uptime.NoOpCalculator{},
+		uptime.ZeroUptimeCalculator{},
+	}
+
+	workers := make([]func(), 0, config.Readers+config.Writers)
+	for i := 0; i < config.Readers; i++ {
+		seed := uint64(i)
+		workers = append(workers, func() {
+			r := rand.New(rand.NewPCG(seed, seed))
+			for time.Now().Before(deadline) {
+				netID := netIDs[r.IntN(len(netIDs))]
+				nodeID := nodeIDs[r.IntN(len(nodeIDs))]
+				_, _, _ = calc.CalculateUptime(nodeID, netID)
+				_, _ = calc.CalculateUptimePercent(nodeID, netID)
+				_, _ = calc.CalculateUptimePercentFrom(nodeID, netID, time.Now())
+			}
+		})
+	}
+	for i := 0; i < config.Writers; i++ {
+		seed := uint64(config.Readers + i)
+		workers = append(workers, func() {
+			r := rand.New(rand.NewPCG(seed, seed))
+			for time.Now().Before(deadline) {
+				netID := netIDs[r.IntN(len(netIDs))]
+				_ = calc.SetCalculator(netID, calculators[r.IntN(len(calculators))])
+			}
+		})
+	}
+
+	runStress(workers)
+}
+
+// runStress runs every worker in its own goroutine and waits for all of them
+// to return
+func runStress(workers []func()) {
+	var wg sync.WaitGroup
+	for _, worker := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker()
+		}()
+	}
+	wg.Wait()
+}
+
+func stressIDs(config StressConfig) ([]ids.ID, []ids.NodeID) {
+	r := rand.New(rand.NewPCG(0, 0))
+	netIDs := make([]ids.ID, max(config.NumNets, 1))
+	for i := range netIDs {
+		netIDs[i] = randomID(r)
+	}
+	nodeIDs := make([]ids.NodeID, max(config.NumNodes, 1))
+	for i := range nodeIDs {
+		nodeIDs[i] = randomNodeID(r)
+	}
+	return netIDs, nodeIDs
+}
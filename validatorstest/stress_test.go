@@ -0,0 +1,21 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorstest
+
+import (
+	"testing"
+
+	validators "github.com/luxfi/validators"
+	"github.com/luxfi/validators/uptime"
+)
+
+// TestStressManager hammers the default manager with concurrent operations
+func TestStressManager(t *testing.T) {
+	StressManager(t, validators.NewManager(), DefaultStressConfig())
+}
+
+// TestStressLockedCalculator hammers the locked uptime calculator
+func TestStressLockedCalculator(t *testing.T) {
+	StressCalculator(t, uptime.NewLockedCalculator(), DefaultStressConfig())
+}
@@ -2,21 +2,34 @@ package validatorstest
 
 import (
 	"context"
+	"maps"
+	"sync"
 
 	"github.com/luxfi/ids"
+	"github.com/luxfi/math/set"
 	validators "github.com/luxfi/validators"
 )
 
 // State is an alias for TestState for backward compatibility
 type State = TestState
 
-// TestState is a test implementation of validators.State
+// TestState is a test implementation of validators.State.
+//
+// Function fields take precedence over the state configured through the
+// builder methods. TestState is safe for concurrent use as long as the
+// function fields aren't modified concurrently.
 type TestState struct {
-	validators map[ids.ID]validators.Set
+	mu            sync.RWMutex
+	validators    map[ids.ID]map[ids.NodeID]*validators.GetValidatorOutput
+	pending       map[ids.ID]map[ids.NodeID]*validators.GetValidatorOutput
+	connected     set.Set[ids.NodeID]
+	warpSets      map[ids.ID]map[uint64]*validators.WarpSet
+	currentHeight uint64
 
 	// Function fields for test customization
 	GetCurrentHeightF     func(context.Context) (uint64, error)
 	GetValidatorSetF      func(context.Context, uint64, ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error)
+	GetCurrentValidatorsF func(context.Context, uint64, ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error)
 	GetWarpValidatorSetF  func(context.Context, uint64, ids.ID) (*validators.WarpSet, error)
 	GetWarpValidatorSetsF func(context.Context, []uint64, []ids.ID) (map[ids.ID]map[uint64]*validators.WarpSet, error)
 }
@@ -24,13 +37,109 @@ type TestState struct {
 // NewTestState creates a new test state
 func NewTestState() *TestState {
 	return &TestState{
-		validators: make(map[ids.ID]validators.Set),
+		validators: make(map[ids.ID]map[ids.NodeID]*validators.GetValidatorOutput),
+		pending:    make(map[ids.ID]map[ids.NodeID]*validators.GetValidatorOutput),
+		warpSets:   make(map[ids.ID]map[uint64]*validators.WarpSet),
+		connected:  set.NewSet[ids.NodeID](0),
 	}
 }
 
-// GetCurrentValidators returns current validators
+// AddValidator adds [output] to the validators of [netID], replacing any
+// validator with the same NodeID. The validator is returned at every height.
+func (s *TestState) AddValidator(netID ids.ID, output *validators.GetValidatorOutput) *TestState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.validators[netID] == nil {
+		s.validators[netID] = make(map[ids.NodeID]*validators.GetValidatorOutput)
+	}
+	outputCopy := *output
+	s.validators[netID][output.NodeID] = &outputCopy
+	return s
+}
+
+// AddPendingValidator adds [output] to the validators of [netID] returned by
+// GetCurrentValidators, marked Pending, replacing any validator with the same
+// NodeID there. It isn't part of any height-pinned set.
+func (s *TestState) AddPendingValidator(netID ids.ID, output *validators.GetValidatorOutput) *TestState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pending[netID] == nil {
+		s.pending[netID] = make(map[ids.NodeID]*validators.GetValidatorOutput)
+	}
+	outputCopy := *output
+	outputCopy.Pending = true
+	s.pending[netID][output.NodeID] = &outputCopy
+	return s
+}
+
+// SetConnected sets whether GetCurrentValidators reports [nodeID] as
+// Connected
+func (s *TestState) SetConnected(nodeID ids.NodeID, connected bool) *TestState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if connected {
+		s.connected.Add(nodeID)
+	} else {
+		s.connected.Remove(nodeID)
+	}
+	return s
+}
+
+// SetCurrentHeight sets the height returned by GetCurrentHeight
+func (s *TestState) SetCurrentHeight(height uint64) *TestState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.currentHeight = height
+	return s
+}
+
+// SetWarpSet sets the Warp validator set of [netID] at [warpSet.Height].
+// Heights without an explicit Warp set are derived from the validators that
+// have a BLS public key and carry a commitment.
+func (s *TestState) SetWarpSet(netID ids.ID, warpSet *validators.WarpSet) *TestState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.warpSets[netID] == nil {
+		s.warpSets[netID] = make(map[uint64]*validators.WarpSet)
+	}
+	s.warpSets[netID][warpSet.Height] = warpSet
+	return s
+}
+
+// GetCurrentValidators returns the validator set at [height] together with
+// the pending validators, with Connected set for connected nodes
 func (s *TestState) GetCurrentValidators(ctx context.Context, height uint64, netID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
-	return s.GetValidatorSet(ctx, height, netID)
+	if s.GetCurrentValidatorsF != nil {
+		return s.GetCurrentValidatorsF(ctx, height, netID)
+	}
+
+	vdrs, err := s.GetValidatorSet(ctx, height, netID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[ids.NodeID]*validators.GetValidatorOutput, len(vdrs)+len(s.pending[netID]))
+	maps.Copy(result, vdrs)
+	for nodeID, vdr := range s.pending[netID] {
+		vdrCopy := *vdr
+		result[nodeID] = &vdrCopy
+	}
+	for nodeID, vdr := range result {
+		if s.connected.Contains(nodeID) {
+			vdrCopy := *vdr
+			vdrCopy.Connected = true
+			result[nodeID] = &vdrCopy
+		}
+	}
+	return result, nil
 }
 
 // GetValidatorSet returns a validator set
@@ -38,7 +147,16 @@ func (s *TestState) GetValidatorSet(ctx context.Context, height uint64, netID id
 	if s.GetValidatorSetF != nil {
 		return s.GetValidatorSetF(ctx, height, netID)
 	}
-	return make(map[ids.NodeID]*validators.GetValidatorOutput), nil
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[ids.NodeID]*validators.GetValidatorOutput, len(s.validators[netID]))
+	for nodeID, vdr := range s.validators[netID] {
+		vdrCopy := *vdr
+		result[nodeID] = &vdrCopy
+	}
+	return result, nil
 }
 
 // GetCurrentHeight returns the current height
@@ -46,7 +164,11 @@ func (s *TestState) GetCurrentHeight(ctx context.Context) (uint64, error) {
 	if s.GetCurrentHeightF != nil {
 		return s.GetCurrentHeightF(ctx)
 	}
-	return 0, nil
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.currentHeight, nil
 }
 
 // GetWarpValidatorSet returns the Warp validator set for a specific height and netID
@@ -54,10 +176,11 @@ func (s *TestState) GetWarpValidatorSet(ctx context.Context, height uint64, netI
 	if s.GetWarpValidatorSetF != nil {
 		return s.GetWarpValidatorSetF(ctx, height, netID)
 	}
-	return &validators.WarpSet{
-		Height:     height,
-		Validators: make(map[ids.NodeID]*validators.WarpValidator),
-	}, nil
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.warpSet(height, netID), nil
 }
 
 // GetWarpValidatorSets returns Warp validator sets for the requested heights and netIDs
@@ -65,19 +188,46 @@ func (s *TestState) GetWarpValidatorSets(ctx context.Context, heights []uint64,
 	if s.GetWarpValidatorSetsF != nil {
 		return s.GetWarpValidatorSetsF(ctx, heights, netIDs)
 	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	result := make(map[ids.ID]map[uint64]*validators.WarpSet)
 	for _, netID := range netIDs {
 		result[netID] = make(map[uint64]*validators.WarpSet)
 		for _, height := range heights {
-			result[netID][height] = &validators.WarpSet{
-				Height:     height,
-				Validators: make(map[ids.NodeID]*validators.WarpValidator),
-			}
+			result[netID][height] = s.warpSet(height, netID)
 		}
 	}
 	return result, nil
 }
 
+// warpSet returns the Warp set of [netID] at [height]. It assumes the lock is
+// held.
+func (s *TestState) warpSet(height uint64, netID ids.ID) *validators.WarpSet {
+	if warpSet, ok := s.warpSets[netID][height]; ok {
+		return warpSet
+	}
+
+	warpVdrs := make(map[ids.NodeID]*validators.WarpValidator)
+	for nodeID, vdr := range s.validators[netID] {
+		if len(vdr.PublicKey) == 0 {
+			continue
+		}
+		warpVdrs[nodeID] = &validators.WarpValidator{
+			NodeID:         nodeID,
+			PublicKey:      vdr.PublicKey,
+			RingtailPubKey: vdr.RingtailPubKey,
+			Weight:         validators.EconomicWeight.Of(vdr),
+		}
+	}
+	warpSet := &validators.WarpSet{
+		Height:     height,
+		Validators: warpVdrs,
+	}
+	warpSet.Commit()
+	return warpSet
+}
+
 // GetMinimumHeight returns the minimum acceptable height
 func (s *TestState) GetMinimumHeight(ctx context.Context) (uint64, error) {
 	return 0, nil
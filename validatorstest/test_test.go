@@ -6,6 +6,7 @@ package validatorstest
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 
 	"github.com/luxfi/ids"
@@ -274,3 +275,103 @@ func TestTestStateImplementsInterface(t *testing.T) {
 	var _ validators.State = (*TestState)(nil)
 	var _ validators.State = NewTestState()
 }
+
+// TestTestStateBuilders tests the builder methods
+func TestTestStateBuilders(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	netID := ids.GenerateTestID()
+	nodeID1 := ids.GenerateTestNodeID()
+	nodeID2 := ids.GenerateTestNodeID()
+
+	state := NewTestState().
+		SetCurrentHeight(42).
+		AddValidator(netID, &validators.GetValidatorOutput{
+			NodeID:    nodeID1,
+			PublicKey: []byte("bls-key"),
+			Light:     100,
+			Weight:    100,
+		}).
+		AddValidator(netID, &validators.GetValidatorOutput{
+			NodeID: nodeID2,
+			Light:  50,
+			Weight: 50,
+		})
+
+	height, err := state.GetCurrentHeight(ctx)
+	require.NoError(err)
+	require.Equal(uint64(42), height)
+
+	vdrs, err := state.GetValidatorSet(ctx, height, netID)
+	require.NoError(err)
+	require.Len(vdrs, 2)
+	require.Equal(uint64(100), vdrs[nodeID1].Weight)
+
+	// Returned validators are copies
+	vdrs[nodeID1].Weight = 1
+	vdrs, err = state.GetCurrentValidators(ctx, height, netID)
+	require.NoError(err)
+	require.Equal(uint64(100), vdrs[nodeID1].Weight)
+
+	// Warp sets are derived from validators with BLS keys
+	warpSet, err := state.GetWarpValidatorSet(ctx, height, netID)
+	require.NoError(err)
+	require.Equal(height, warpSet.Height)
+	require.Len(warpSet.Validators, 1)
+	require.Equal(uint64(100), warpSet.Validators[nodeID1].Weight)
+
+	// Explicit warp sets take precedence
+	explicit := &validators.WarpSet{
+		Height:     height,
+		Validators: map[ids.NodeID]*validators.WarpValidator{},
+	}
+	state.SetWarpSet(netID, explicit)
+	warpSets, err := state.GetWarpValidatorSets(ctx, []uint64{height, height + 1}, []ids.ID{netID})
+	require.NoError(err)
+	require.Same(explicit, warpSets[netID][height])
+	require.Len(warpSets[netID][height+1].Validators, 1)
+
+	// Function fields take precedence over builders
+	state.GetCurrentHeightF = func(context.Context) (uint64, error) {
+		return 7, nil
+	}
+	height, err = state.GetCurrentHeight(ctx)
+	require.NoError(err)
+	require.Equal(uint64(7), height)
+}
+
+// TestTestStateConcurrentAccess tests that builders and getters can race safely
+func TestTestStateConcurrentAccess(t *testing.T) {
+	ctx := context.Background()
+	state := NewTestState()
+	netID := ids.GenerateTestID()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				state.AddValidator(netID, &validators.GetValidatorOutput{
+					NodeID: ids.GenerateTestNodeID(),
+					Weight: uint64(j + 1),
+				})
+				state.SetCurrentHeight(uint64(j))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_, _ = state.GetValidatorSet(ctx, 0, netID)
+				_, _ = state.GetCurrentHeight(ctx)
+				_, _ = state.GetWarpValidatorSet(ctx, 0, netID)
+			}
+		}()
+	}
+	wg.Wait()
+
+	vdrs, err := state.GetValidatorSet(ctx, 0, netID)
+	require.NoError(t, err)
+	require.Len(t, vdrs, 400)
+}
@@ -0,0 +1,334 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorstest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luxfi/ids"
+
+	validators "github.com/luxfi/validators"
+)
+
+var (
+	_ validators.State = (*wireState)(nil)
+
+	errUnknownWireMethod = errors.New("unknown wire method")
+)
+
+// wireErrors are the sentinels errors are sent across the wire as, by code,
+// so the client's errors match them with errors.Is as the backing state's
+// did. Errors matching none of them only keep their message.
+var wireErrors = []struct {
+	code string
+	err  error
+}{
+	{code: "validator-not-found", err: validators.ErrValidatorNotFound},
+	{code: "net-not-found", err: validators.ErrNetNotFound},
+	{code: "duplicate-validator", err: validators.ErrDuplicateValidator},
+	{code: "invalid-weight", err: validators.ErrInvalidWeight},
+	{code: "manager-closed", err: validators.ErrManagerClosed},
+	{code: "canceled", err: context.Canceled},
+	{code: "deadline-exceeded", err: context.DeadlineExceeded},
+}
+
+// wireErrorCode returns the code of the sentinel [err] matches, if any
+func wireErrorCode(err error) string {
+	for _, wireErr := range wireErrors {
+		if errors.Is(err, wireErr.err) {
+			return wireErr.code
+		}
+	}
+	return ""
+}
+
+// wireError returns the error the server sent for [method], matching the
+// sentinel of [code]
+func wireError(method, msg, code string) error {
+	msg = fmt.Sprintf("%s: %s", method, msg)
+	for _, wireErr := range wireErrors {
+		if wireErr.code == code {
+			return validators.NewCategoryError(msg, wireErr.err)
+		}
+	}
+	return errors.New(msg)
+}
+
+// NewWireState serves [backing] over HTTP on a random loopback port and
+// returns a State that calls it across the wire, so RunStateConformance
+// catches serialization and deadline propagation bugs the in-process suite
+// can't. Validators and Warp sets are sent with their JSON encodings, and
+// the deadline of each call's context is sent along and applied to the
+// backing call, as gRPC does. The server is closed when [t] finishes.
+func NewWireState(t testing.TB, backing validators.State) validators.State {
+	server := httptest.NewServer(&wireServer{state: backing})
+	t.Cleanup(server.Close)
+	return &wireState{
+		url:    server.URL,
+		client: server.Client(),
+	}
+}
+
+// wireRequest is the body of every call. Only the fields used by the called
+// method are set.
+type wireRequest struct {
+	Deadline *time.Time `json:"deadline,omitempty"`
+	Height   uint64     `json:"height,omitempty"`
+	Heights  []uint64   `json:"heights,omitempty"`
+	NetID    ids.ID     `json:"netID"`
+	NetIDs   []ids.ID   `json:"netIDs,omitempty"`
+	ChainID  ids.ID     `json:"chainID"`
+}
+
+// wireResponse is the body of every response. A null list decodes to a nil
+// map, so nil and empty results survive the trip. Errors are sent with the
+// code of their sentinel, see wireErrors.
+type wireResponse struct {
+	Error      string                           `json:"error,omitempty"`
+	ErrorCode  string                           `json:"errorCode,omitempty"`
+	Height     uint64                           `json:"height,omitempty"`
+	ID         ids.ID                           `json:"id"`
+	Validators []*validators.GetValidatorOutput `json:"validators"`
+	WarpSet    *validators.WarpSet              `json:"warpSet"`
+	WarpSets   []wireNetWarpSets                `json:"warpSets"`
+}
+
+type wireNetWarpSets struct {
+	NetID ids.ID         `json:"netID"`
+	Sets  []wireWarpSets `json:"sets"`
+}
+
+type wireWarpSets struct {
+	Height uint64              `json:"height"`
+	Set    *validators.WarpSet `json:"set"`
+}
+
+// wireServer serves a State, one method per path
+type wireServer struct {
+	state validators.State
+}
+
+func (s *wireServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req wireRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	if req.Deadline != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, *req.Deadline)
+		defer cancel()
+	}
+
+	resp, err := s.call(ctx, strings.TrimPrefix(r.URL.Path, "/"), &req)
+	if err != nil {
+		resp = &wireResponse{
+			Error:     err.Error(),
+			ErrorCode: wireErrorCode(err),
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (s *wireServer) call(ctx context.Context, method string, req *wireRequest) (*wireResponse, error) {
+	switch method {
+	case "GetValidatorSet":
+		vdrs, err := s.state.GetValidatorSet(ctx, req.Height, req.NetID)
+		return &wireResponse{Validators: validatorList(vdrs)}, err
+	case "GetCurrentValidators":
+		vdrs, err := s.state.GetCurrentValidators(ctx, req.Height, req.NetID)
+		return &wireResponse{Validators: validatorList(vdrs)}, err
+	case "GetCurrentHeight":
+		height, err := s.state.GetCurrentHeight(ctx)
+		return &wireResponse{Height: height}, err
+	case "GetMinimumHeight":
+		height, err := s.state.GetMinimumHeight(ctx)
+		return &wireResponse{Height: height}, err
+	case "GetChainID":
+		chainID, err := s.state.GetChainID(req.NetID)
+		return &wireResponse{ID: chainID}, err
+	case "GetNetworkID":
+		netID, err := s.state.GetNetworkID(req.ChainID)
+		return &wireResponse{ID: netID}, err
+	case "GetWarpValidatorSets":
+		warpSets, err := s.state.GetWarpValidatorSets(ctx, req.Heights, req.NetIDs)
+		if err != nil {
+			return nil, err
+		}
+		resp := &wireResponse{}
+		if warpSets != nil {
+			resp.WarpSets = make([]wireNetWarpSets, 0, len(warpSets))
+		}
+		for netID, sets := range warpSets {
+			netSets := wireNetWarpSets{
+				NetID: netID,
+				Sets:  make([]wireWarpSets, 0, len(sets)),
+			}
+			for height, set := range sets {
+				netSets.Sets = append(netSets.Sets, wireWarpSets{
+					Height: height,
+					Set:    set,
+				})
+			}
+			resp.WarpSets = append(resp.WarpSets, netSets)
+		}
+		return resp, nil
+	case "GetWarpValidatorSet":
+		warpSet, err := s.state.GetWarpValidatorSet(ctx, req.Height, req.NetID)
+		return &wireResponse{WarpSet: warpSet}, err
+	default:
+		return nil, fmt.Errorf("%w: %q", errUnknownWireMethod, method)
+	}
+}
+
+// validatorList returns the records of [vdrs], or nil if [vdrs] is nil
+func validatorList(vdrs map[ids.NodeID]*validators.GetValidatorOutput) []*validators.GetValidatorOutput {
+	if vdrs == nil {
+		return nil
+	}
+	list := make([]*validators.GetValidatorOutput, 0, len(vdrs))
+	for _, vdr := range vdrs {
+		list = append(list, vdr)
+	}
+	return list
+}
+
+// wireState is the client side of wireServer
+type wireState struct {
+	url    string
+	client *http.Client
+}
+
+func (s *wireState) call(ctx context.Context, method string, req wireRequest) (*wireResponse, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		req.Deadline = &deadline
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpResp, err := s.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(httpResp.Body)
+		return nil, fmt.Errorf("%s: %s: %s", method, httpResp.Status, strings.TrimSpace(string(msg)))
+	}
+	var resp wireResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("%s: couldn't decode response: %w", method, err)
+	}
+	if resp.Error != "" {
+		return nil, wireError(method, resp.Error, resp.ErrorCode)
+	}
+	return &resp, nil
+}
+
+// validatorMap returns the validators of [resp] keyed by node ID, or nil if
+// the server sent none
+func validatorMap(resp *wireResponse) map[ids.NodeID]*validators.GetValidatorOutput {
+	if resp.Validators == nil {
+		return nil
+	}
+	vdrs := make(map[ids.NodeID]*validators.GetValidatorOutput, len(resp.Validators))
+	for _, vdr := range resp.Validators {
+		vdrs[vdr.NodeID] = vdr
+	}
+	return vdrs
+}
+
+func (s *wireState) GetValidatorSet(ctx context.Context, height uint64, netID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+	resp, err := s.call(ctx, "GetValidatorSet", wireRequest{Height: height, NetID: netID})
+	if err != nil {
+		return nil, err
+	}
+	return validatorMap(resp), nil
+}
+
+func (s *wireState) GetCurrentValidators(ctx context.Context, height uint64, netID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+	resp, err := s.call(ctx, "GetCurrentValidators", wireRequest{Height: height, NetID: netID})
+	if err != nil {
+		return nil, err
+	}
+	return validatorMap(resp), nil
+}
+
+func (s *wireState) GetCurrentHeight(ctx context.Context) (uint64, error) {
+	resp, err := s.call(ctx, "GetCurrentHeight", wireRequest{})
+	if err != nil {
+		return 0, err
+	}
+	return resp.Height, nil
+}
+
+func (s *wireState) GetMinimumHeight(ctx context.Context) (uint64, error) {
+	resp, err := s.call(ctx, "GetMinimumHeight", wireRequest{})
+	if err != nil {
+		return 0, err
+	}
+	return resp.Height, nil
+}
+
+func (s *wireState) GetChainID(netID ids.ID) (ids.ID, error) {
+	resp, err := s.call(context.Background(), "GetChainID", wireRequest{NetID: netID})
+	if err != nil {
+		return ids.Empty, err
+	}
+	return resp.ID, nil
+}
+
+func (s *wireState) GetNetworkID(chainID ids.ID) (ids.ID, error) {
+	resp, err := s.call(context.Background(), "GetNetworkID", wireRequest{ChainID: chainID})
+	if err != nil {
+		return ids.Empty, err
+	}
+	return resp.ID, nil
+}
+
+func (s *wireState) GetWarpValidatorSets(ctx context.Context, heights []uint64, netIDs []ids.ID) (map[ids.ID]map[uint64]*validators.WarpSet, error) {
+	resp, err := s.call(ctx, "GetWarpValidatorSets", wireRequest{Heights: heights, NetIDs: netIDs})
+	if err != nil {
+		return nil, err
+	}
+	if resp.WarpSets == nil {
+		return nil, nil
+	}
+	warpSets := make(map[ids.ID]map[uint64]*validators.WarpSet, len(resp.WarpSets))
+	for _, netSets := range resp.WarpSets {
+		sets := make(map[uint64]*validators.WarpSet, len(netSets.Sets))
+		for _, set := range netSets.Sets {
+			sets[set.Height] = set.Set
+		}
+		warpSets[netSets.NetID] = sets
+	}
+	return warpSets, nil
+}
+
+func (s *wireState) GetWarpValidatorSet(ctx context.Context, height uint64, netID ids.ID) (*validators.WarpSet, error) {
+	resp, err := s.call(ctx, "GetWarpValidatorSet", wireRequest{Height: height, NetID: netID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.WarpSet, nil
+}
@@ -0,0 +1,258 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package verifier verifies aggregate BLS signatures of validator sets on a
+// bounded pool of workers, so bursts of Warp messages queue up instead of
+// taking every core from block processing.
+package verifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math/set"
+
+	validators "github.com/luxfi/validators"
+)
+
+var (
+	ErrInvalidConfig    = errors.New("invalid verifier config")
+	ErrQueueFull        = errors.New("verification queue is full")
+	ErrClosed           = errors.New("verifier is closed")
+	ErrInvalidSignature = errors.New("invalid aggregate signature")
+)
+
+// Config bounds the resources of a Pool
+type Config struct {
+	// Workers is the number of signatures verified concurrently
+	Workers int
+	// QueueSize is the number of requests each net may have waiting. Requests
+	// beyond it are rejected with ErrQueueFull.
+	QueueSize int
+}
+
+// DefaultConfig leaves half of the cores to block processing
+func DefaultConfig() Config {
+	return Config{
+		Workers:   max(1, runtime.NumCPU()/2),
+		QueueSize: 1024,
+	}
+}
+
+// Verify returns an error if the config can't make progress
+func (c Config) Verify() error {
+	switch {
+	case c.Workers <= 0:
+		return fmt.Errorf("%w: workers %d must be positive", ErrInvalidConfig, c.Workers)
+	case c.QueueSize <= 0:
+		return fmt.Errorf("%w: queue size %d must be positive", ErrInvalidConfig, c.QueueSize)
+	}
+	return nil
+}
+
+// Request is a message signed by the validators of Validators marked in
+// Signers
+type Request struct {
+	NetID      ids.ID
+	Validators validators.CanonicalValidatorSet
+	Signers    set.Bits
+	Message    []byte
+	Signature  *bls.Signature
+}
+
+type job struct {
+	request Request
+	result  chan error
+}
+
+// Pool verifies requests on a fixed number of workers. Nets with a higher
+// priority are always served first; nets with the same priority take turns,
+// one request at a time, so a burst on one net doesn't delay the others.
+type Pool struct {
+	quorum *validators.QuorumRegistry
+	config Config
+
+	mu         sync.Mutex
+	cond       *sync.Cond
+	closed     bool
+	priorities map[ids.ID]int
+	queues     map[ids.ID][]*job
+	// turns holds the nets with queued requests in the order they are served
+	// within a priority
+	turns []ids.ID
+
+	workers sync.WaitGroup
+}
+
+// New returns a running Pool checking signers against [quorum]
+func New(quorum *validators.QuorumRegistry, config Config) (*Pool, error) {
+	p, err := newPool(quorum, config)
+	if err != nil {
+		return nil, err
+	}
+	for range config.Workers {
+		p.workers.Add(1)
+		go p.work()
+	}
+	return p, nil
+}
+
+// newPool returns a Pool without workers
+func newPool(quorum *validators.QuorumRegistry, config Config) (*Pool, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	p := &Pool{
+		quorum:     quorum,
+		config:     config,
+		priorities: make(map[ids.ID]int),
+		queues:     make(map[ids.ID][]*job),
+	}
+	p.cond = sync.NewCond(&p.mu)
+	return p, nil
+}
+
+// SetPriority sets the priority of [netID]. Nets default to priority 0.
+func (p *Pool) SetPriority(netID ids.ID, priority int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if priority == 0 {
+		delete(p.priorities, netID)
+	} else {
+		p.priorities[netID] = priority
+	}
+}
+
+// Pending returns the number of queued requests of [netID]
+func (p *Pool) Pending(netID ids.ID) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.queues[netID])
+}
+
+// Submit queues [request] without blocking. The returned channel receives
+// the result of the verification.
+func (p *Pool) Submit(request Request) (<-chan error, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, ErrClosed
+	}
+	queue := p.queues[request.NetID]
+	if len(queue) >= p.config.QueueSize {
+		return nil, fmt.Errorf("%w: net %s has %d pending", ErrQueueFull, request.NetID, len(queue))
+	}
+	if len(queue) == 0 {
+		p.turns = append(p.turns, request.NetID)
+	}
+	j := &job{
+		request: request,
+		result:  make(chan error, 1),
+	}
+	p.queues[request.NetID] = append(queue, j)
+	p.cond.Signal()
+	return j.result, nil
+}
+
+// Verify queues [request] and waits for its result. If [ctx] is done first,
+// the request is still verified but the result is dropped.
+func (p *Pool) Verify(ctx context.Context, request Request) error {
+	result, err := p.Submit(request)
+	if err != nil {
+		return err
+	}
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops the workers after their current request. Queued requests fail
+// with ErrClosed.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	p.closed = true
+	for _, queue := range p.queues {
+		for _, j := range queue {
+			j.result <- ErrClosed
+		}
+	}
+	clear(p.queues)
+	p.turns = nil
+	p.cond.Broadcast()
+	p.mu.Unlock()
+
+	p.workers.Wait()
+}
+
+func (p *Pool) work() {
+	defer p.workers.Done()
+
+	for {
+		j := p.next()
+		if j == nil {
+			return
+		}
+		j.result <- p.verify(j.request)
+	}
+}
+
+// next blocks until a request is queued and dequeues the one to serve next.
+// It returns nil once the pool is closed.
+func (p *Pool) next() *job {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for len(p.turns) == 0 && !p.closed {
+		p.cond.Wait()
+	}
+	if p.closed {
+		return nil
+	}
+
+	turn := 0
+	for i, netID := range p.turns {
+		if p.priorities[netID] > p.priorities[p.turns[turn]] {
+			turn = i
+		}
+	}
+	netID := p.turns[turn]
+	p.turns = append(p.turns[:turn], p.turns[turn+1:]...)
+
+	queue := p.queues[netID]
+	j := queue[0]
+	queue[0] = nil
+	if queue = queue[1:]; len(queue) == 0 {
+		delete(p.queues, netID)
+	} else {
+		p.queues[netID] = queue
+		p.turns = append(p.turns, netID)
+	}
+	return j
+}
+
+// verify returns nil if [request] is signed by a quorum of its validators
+func (p *Pool) verify(request Request) error {
+	signers, err := p.quorum.VerifySigners(request.NetID, request.Validators, request.Signers)
+	if err != nil {
+		return err
+	}
+	aggregateKey, err := validators.AggregatePublicKeys(signers)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	if request.Signature == nil || !bls.Verify(aggregateKey, request.Signature, request.Message) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package verifier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math/set"
+	"github.com/stretchr/testify/require"
+
+	validators "github.com/luxfi/validators"
+	"github.com/luxfi/validators/validatorstest"
+)
+
+func newTestQuorum(t *testing.T) *validators.QuorumRegistry {
+	quorum, err := validators.NewQuorumRegistry(validators.DefaultQuorumConfig())
+	require.NoError(t, err)
+	return quorum
+}
+
+// TestPoolVerify tests that requests are checked for quorum and signature
+func TestPoolVerify(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		sks    = make(map[string]*bls.SecretKey)
+		vdrSet = make(map[ids.NodeID]*validators.GetValidatorOutput)
+	)
+	generated, err := validatorstest.GenerateValidatorSet(1, 3, validatorstest.ConstantWeights(100))
+	require.NoError(err)
+	for _, vdr := range generated.Validators {
+		sks[string(bls.PublicKeyToUncompressedBytes(vdr.PublicKey))] = vdr.SecretKey
+		vdrSet[vdr.NodeID] = &validators.GetValidatorOutput{
+			NodeID:    vdr.NodeID,
+			PublicKey: vdr.PublicKeyBytes,
+			Light:     vdr.Weight,
+			Weight:    vdr.Weight,
+		}
+	}
+	canonical, err := validators.FlattenValidatorSet(vdrSet)
+	require.NoError(err)
+
+	// sign returns the aggregate signature of the canonical validators at
+	// [indices]
+	msg := []byte("warp message")
+	sign := func(indices ...int) *bls.Signature {
+		sigs := make([]*bls.Signature, len(indices))
+		for i, index := range indices {
+			sk := sks[string(canonical.Validators[index].PublicKeyBytes)]
+			sig, err := sk.Sign(msg)
+			require.NoError(err)
+			sigs[i] = sig
+		}
+		sig, err := bls.AggregateSignatures(sigs)
+		require.NoError(err)
+		return sig
+	}
+
+	p, err := New(newTestQuorum(t), Config{Workers: 2, QueueSize: 4})
+	require.NoError(err)
+	defer p.Close()
+
+	ctx := context.Background()
+	netID := ids.GenerateTestID()
+	request := Request{
+		NetID:      netID,
+		Validators: canonical,
+		Signers:    set.NewBits(0, 1, 2),
+		Message:    msg,
+		Signature:  sign(0, 1, 2),
+	}
+	require.NoError(p.Verify(ctx, request))
+
+	// A different message doesn't verify
+	forged := request
+	forged.Message = []byte("other message")
+	require.ErrorIs(p.Verify(ctx, forged), ErrInvalidSignature)
+
+	// One of three validators isn't a quorum
+	request.Signers = set.NewBits(0)
+	request.Signature = sign(0)
+	require.ErrorIs(p.Verify(ctx, request), validators.ErrInsufficientWeight)
+
+	// The signature must match the claimed signers
+	request.Signers = set.NewBits(0, 1, 2)
+	require.ErrorIs(p.Verify(ctx, request), ErrInvalidSignature)
+}
+
+// TestPoolScheduling tests priorities, turn taking and backpressure
+func TestPoolScheduling(t *testing.T) {
+	require := require.New(t)
+
+	_, err := newPool(newTestQuorum(t), Config{QueueSize: 1})
+	require.ErrorIs(err, ErrInvalidConfig)
+
+	p, err := newPool(newTestQuorum(t), Config{Workers: 1, QueueSize: 2})
+	require.NoError(err)
+
+	var (
+		busyNetID   = ids.GenerateTestID()
+		quietNetID  = ids.GenerateTestID()
+		urgentNetID = ids.GenerateTestID()
+	)
+	p.SetPriority(urgentNetID, 1)
+
+	submit := func(netID ids.ID, msg string) {
+		_, err := p.Submit(Request{NetID: netID, Message: []byte(msg)})
+		require.NoError(err)
+	}
+	submit(busyNetID, "busy-0")
+	submit(busyNetID, "busy-1")
+	_, err = p.Submit(Request{NetID: busyNetID})
+	require.ErrorIs(err, ErrQueueFull)
+	submit(quietNetID, "quiet-0")
+	submit(urgentNetID, "urgent-0")
+	require.Equal(2, p.Pending(busyNetID))
+
+	var served []string
+	for range 4 {
+		served = append(served, string(p.next().request.Message))
+	}
+	require.Equal([]string{"urgent-0", "busy-0", "quiet-0", "busy-1"}, served)
+	require.Zero(p.Pending(busyNetID))
+
+	submit(quietNetID, "quiet-1")
+	p.Close()
+	_, err = p.Submit(Request{NetID: quietNetID})
+	require.ErrorIs(err, ErrClosed)
+	require.Nil(p.next())
+}
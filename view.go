@@ -0,0 +1,82 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"sync"
+
+	"github.com/luxfi/ids"
+)
+
+// ViewManager serves views of a net's validators that don't copy the records
+type ViewManager interface {
+	// View returns a live view of the validators of [netID]
+	View(netID ids.ID) *ValidatorView
+	// ViewAt returns a frozen view of the validators of [netID]
+	ViewAt(netID ids.ID) *FrozenView
+}
+
+var _ ViewManager = (*manager)(nil)
+
+// ValidatorView reads the live validators of a net without copying them, for
+// hot paths that only look validators up. Each call reads the net as it is
+// at that moment, so successive calls may see different validators; take a
+// Snapshot to read a consistent set, or GetMap for a copy the caller owns.
+//
+// Records are returned by value, but their slices and maps are shared with
+// the manager and must not be modified.
+type ValidatorView struct {
+	mu         *sync.RWMutex
+	netID      ids.ID
+	validators map[ids.ID]map[ids.NodeID]*GetValidatorOutput
+}
+
+// View returns a view of the validators of [netID]. The view keeps tracking
+// the net as validators join and leave.
+func (m *manager) View(netID ids.ID) *ValidatorView {
+	return &ValidatorView{
+		mu:         m.mu,
+		netID:      netID,
+		validators: m.validators,
+	}
+}
+
+// NetID returns the net the view reads
+func (v *ValidatorView) NetID() ids.ID {
+	return v.netID
+}
+
+// Get returns the record of [nodeID], if it's a validator
+func (v *ValidatorView) Get(nodeID ids.NodeID) (GetValidatorOutput, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	val, ok := v.validators[v.netID][nodeID]
+	if !ok {
+		return GetValidatorOutput{}, false
+	}
+	return *val, true
+}
+
+// Len returns the number of validators
+func (v *ValidatorView) Len() int {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return len(v.validators[v.netID])
+}
+
+// Range calls [f] with each validator, in no particular order, until it
+// returns false. The manager is read locked for the whole iteration, so [f]
+// must not call the manager.
+func (v *ValidatorView) Range(f func(nodeID ids.NodeID, val GetValidatorOutput) bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	for nodeID, val := range v.validators[v.netID] {
+		if !f(nodeID, *val) {
+			return
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// TestManagerView tests that a view reads the live validators of a net
+func TestManagerView(t *testing.T) {
+	require := require.New(t)
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	nodeID1, nodeID2 := ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+
+	// Views of empty nets see validators added later
+	view := m.View(netID)
+	require.Equal(netID, view.NetID())
+	require.Zero(view.Len())
+	_, ok := view.Get(nodeID1)
+	require.False(ok)
+
+	require.NoError(m.AddStaker(netID, nodeID1, nil, ids.Empty, 100))
+	require.NoError(m.AddStaker(netID, nodeID2, nil, ids.Empty, 200))
+	require.Equal(2, view.Len())
+
+	val, ok := view.Get(nodeID1)
+	require.True(ok)
+	require.Equal(uint64(100), val.Light)
+
+	// Records are returned by value
+	val.Light = 1
+	require.Equal(uint64(100), m.GetLight(netID, nodeID1))
+
+	require.NoError(m.SetWeight(netID, nodeID1, 150))
+	val, ok = view.Get(nodeID1)
+	require.True(ok)
+	require.Equal(uint64(150), val.Light)
+
+	lights := make(map[ids.NodeID]uint64)
+	view.Range(func(nodeID ids.NodeID, val GetValidatorOutput) bool {
+		lights[nodeID] = val.Light
+		return true
+	})
+	require.Equal(map[ids.NodeID]uint64{nodeID1: 150, nodeID2: 200}, lights)
+
+	// Range stops when f returns false
+	var calls int
+	view.Range(func(ids.NodeID, GetValidatorOutput) bool {
+		calls++
+		return false
+	})
+	require.Equal(1, calls)
+
+	require.NoError(m.RemoveStaker(netID, nodeID1))
+	require.NoError(m.RemoveStaker(netID, nodeID2))
+	require.Zero(view.Len())
+}
@@ -22,8 +22,10 @@ type Sortable[T any] interface {
 }
 
 var (
-	ErrUnknownValidator = errors.New("unknown validator")
-	ErrWeightOverflow   = errors.New("weight overflowed")
+	ErrUnknownValidator = NewCategoryError("unknown validator", ErrValidatorNotFound)
+	ErrWeightOverflow   = NewCategoryError("weight overflowed", ErrInvalidWeight)
+
+	ErrConflictingRingtailKey = errors.New("validators sharing a BLS key have different Ringtail keys")
 )
 
 // CanonicalValidatorSet represents a validator set in canonical ordering
@@ -38,34 +40,85 @@ type CanonicalValidatorSet struct {
 type CanonicalValidator struct {
 	PublicKey      *bls.PublicKey
 	PublicKeyBytes []byte // Uncompressed bytes for canonical ordering
+	RingtailPubKey []byte // Ringtail public key, only set by hybrid flattening
 	Weight         uint64
-	NodeIDs        []ids.NodeID // Can have multiple NodeIDs with same public key
+	// NodeIDs are the nodes sharing the public key, in ascending order, so
+	// every node derives the same encoding from the same validators
+	NodeIDs []ids.NodeID
 }
 
-// Compare implements utils.Sortable for canonical ordering
+// Compare implements utils.Sortable for canonical ordering. Validators are
+// ordered by BLS key, then by Ringtail key.
 func (v *CanonicalValidator) Compare(o *CanonicalValidator) int {
-	return bytes.Compare(v.PublicKeyBytes, o.PublicKeyBytes)
+	if c := bytes.Compare(v.PublicKeyBytes, o.PublicKeyBytes); c != 0 {
+		return c
+	}
+	return bytes.Compare(v.RingtailPubKey, o.RingtailPubKey)
 }
 
 var _ Sortable[*CanonicalValidator] = (*CanonicalValidator)(nil)
 
+// WeightSource selects which value of a validator canonical and warp sets are
+// weighted by
+type WeightSource uint8
+
+const (
+	// EconomicWeight weights validators by GetValidatorOutput.Weight
+	EconomicWeight WeightSource = iota
+	// ConsensusLight weights validators by GetValidatorOutput.Light
+	ConsensusLight
+)
+
+// Of returns the value of [vdr] selected by the source
+func (s WeightSource) Of(vdr *GetValidatorOutput) uint64 {
+	if s == ConsensusLight {
+		return vdr.Light
+	}
+	return vdr.Weight
+}
+
 // FlattenValidatorSet converts the provided [vdrSet] into a canonical utils.
-// Also returns the total weight of the validator set.
+// Also returns the total weight of the validator set. Validators are weighted
+// by their economic Weight.
 func FlattenValidatorSet(vdrSet map[ids.NodeID]*GetValidatorOutput) (CanonicalValidatorSet, error) {
+	return FlattenValidatorSetBy(vdrSet, EconomicWeight)
+}
+
+// FlattenValidatorSetBy is FlattenValidatorSet with validators weighted by
+// [source]
+func FlattenValidatorSetBy(vdrSet map[ids.NodeID]*GetValidatorOutput, source WeightSource) (CanonicalValidatorSet, error) {
+	return flattenValidatorSet(vdrSet, source, false)
+}
+
+// FlattenHybridValidatorSet is FlattenValidatorSetBy for signing paths that
+// use both BLS and Ringtail keys. Only validators with both keys are listed,
+// each carrying its Ringtail key; the weight of the others still counts
+// towards the total. Validators sharing a BLS key must share a Ringtail key,
+// otherwise ErrConflictingRingtailKey is returned.
+func FlattenHybridValidatorSet(vdrSet map[ids.NodeID]*GetValidatorOutput, source WeightSource) (CanonicalValidatorSet, error) {
+	return flattenValidatorSet(vdrSet, source, true)
+}
+
+func flattenValidatorSet(vdrSet map[ids.NodeID]*GetValidatorOutput, source WeightSource, hybrid bool) (CanonicalValidatorSet, error) {
 	var (
 		// Map public keys to validators to handle duplicates
 		pkToValidator = make(map[string]*CanonicalValidator)
 		totalWeight   uint64
 		err           error
 	)
-	for _, vdr := range vdrSet {
-		totalWeight, err = math.Add64(totalWeight, vdr.Weight)
+	// Validators are visited in node ID order, so merged validators list
+	// their node IDs in ascending order and errors don't depend on map
+	// iteration order
+	for _, nodeID := range sortNodeIDs(slices.Collect(maps.Keys(vdrSet))) {
+		vdr := vdrSet[nodeID]
+		weight := source.Of(vdr)
+		totalWeight, err = math.Add64(totalWeight, weight)
 		if err != nil {
 			return CanonicalValidatorSet{}, fmt.Errorf("%w: %w", ErrWeightOverflow, err)
 		}
 
 		// Skip validators without public keys
-		if len(vdr.PublicKey) == 0 {
+		if len(vdr.PublicKey) == 0 || (hybrid && len(vdr.RingtailPubKey) == 0) {
 			continue
 		}
 
@@ -81,8 +134,11 @@ func FlattenValidatorSet(vdrSet map[ids.NodeID]*GetValidatorOutput) (CanonicalVa
 
 		// Check if we already have a validator with this public key
 		if existingVdr, exists := pkToValidator[pkKey]; exists {
+			if hybrid && !bytes.Equal(existingVdr.RingtailPubKey, vdr.RingtailPubKey) {
+				return CanonicalValidatorSet{}, fmt.Errorf("%w: %s and %s", ErrConflictingRingtailKey, existingVdr.NodeIDs[0], vdr.NodeID)
+			}
 			// Merge validators with duplicate public keys
-			existingVdr.Weight, err = math.Add64(existingVdr.Weight, vdr.Weight)
+			existingVdr.Weight, err = math.Add64(existingVdr.Weight, weight)
 			if err != nil {
 				return CanonicalValidatorSet{}, fmt.Errorf("%w: %w", ErrWeightOverflow, err)
 			}
@@ -92,9 +148,12 @@ func FlattenValidatorSet(vdrSet map[ids.NodeID]*GetValidatorOutput) (CanonicalVa
 			newVdr := &CanonicalValidator{
 				PublicKey:      blsPK,
 				PublicKeyBytes: pkBytes,
-				Weight:         vdr.Weight,
+				Weight:         weight,
 				NodeIDs:        []ids.NodeID{vdr.NodeID},
 			}
+			if hybrid {
+				newVdr.RingtailPubKey = slices.Clone(vdr.RingtailPubKey)
+			}
 			pkToValidator[pkKey] = newVdr
 		}
 	}
@@ -105,6 +164,35 @@ func FlattenValidatorSet(vdrSet map[ids.NodeID]*GetValidatorOutput) (CanonicalVa
 	return CanonicalValidatorSet{Validators: vdrList, TotalWeight: totalWeight}, nil
 }
 
+// BitsFor returns the signer bitset marking the validators of [nodeIDs].
+// Node IDs sharing a public key map to the bit of their shared validator.
+//
+// Returns an error wrapping ErrUnknownValidator if a node ID isn't in the
+// set, including validators that only count towards the total weight because
+// they have no public key.
+func (s *CanonicalValidatorSet) BitsFor(nodeIDs set.Set[ids.NodeID]) (set.Bits, error) {
+	var (
+		bits  = set.NewBits()
+		found = set.NewSet[ids.NodeID](nodeIDs.Len())
+	)
+	for i, vdr := range s.Validators {
+		for _, nodeID := range vdr.NodeIDs {
+			if nodeIDs.Contains(nodeID) {
+				bits.Add(i)
+				found.Add(nodeID)
+			}
+		}
+	}
+	if found.Len() != nodeIDs.Len() {
+		for _, nodeID := range sortNodeIDs(nodeIDs.List()) {
+			if !found.Contains(nodeID) {
+				return set.Bits{}, fmt.Errorf("%w: %s", ErrUnknownValidator, nodeID)
+			}
+		}
+	}
+	return bits, nil
+}
+
 // FilterValidators returns the validators in [vdrs] whose bit is set to 1 in
 // [indices].
 //
@@ -134,6 +222,38 @@ func FilterValidators(
 	return filteredVdrs, nil
 }
 
+// FilterValidatorsWithComplement is FilterValidators that also sums the
+// weight of the validators in [vdrs] that are included and of those that
+// aren't.
+//
+// Returns an error if [indices] references an unknown validator or either
+// weight overflows.
+func FilterValidatorsWithComplement(
+	indices set.Bits,
+	vdrs []*CanonicalValidator,
+) ([]*CanonicalValidator, uint64, uint64, error) {
+	filteredVdrs, err := FilterValidators(indices, vdrs)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	includedWeight, err := SumWeight(filteredVdrs)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	var excludedWeight uint64
+	for i, vdr := range vdrs {
+		if indices.Contains(i) {
+			continue
+		}
+		excludedWeight, err = math.Add64(excludedWeight, vdr.Weight)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+		}
+	}
+	return filteredVdrs, includedWeight, excludedWeight, nil
+}
+
 // SumWeight returns the total weight of the provided validators.
 func SumWeight(vdrs []*CanonicalValidator) (uint64, error) {
 	var (
@@ -0,0 +1,179 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"testing"
+
+	"github.com/luxfi/ids"
+	mathset "github.com/luxfi/math/set"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/validators/validatorstest/fixture"
+)
+
+// fuzzPublicKeys returns [n] valid compressed BLS public keys to seed the
+// corpus
+func fuzzPublicKeys(f *testing.F, n int) [][]byte {
+	generated, err := fixture.Generate(1, n, fixture.ConstantWeights(1))
+	require.NoError(f, err)
+	keys := make([][]byte, n)
+	for i, vdr := range generated {
+		keys[i] = vdr.PublicKeyBytes
+	}
+	return keys
+}
+
+// FuzzFlattenValidatorSet checks that flattening arbitrary validator sets,
+// including malformed and duplicate keys, never panics, produces sorted
+// output, conserves weight, and reports overflows.
+func FuzzFlattenValidatorSet(f *testing.F) {
+	keys := fuzzPublicKeys(f, 2)
+	pk1, pk2 := keys[0], keys[1]
+	f.Add(pk1, pk2, uint64(1), uint64(2), uint64(3))
+	f.Add(pk1, pk1, uint64(100), uint64(200), uint64(300))
+	f.Add([]byte{}, pk2, uint64(0), uint64(0), uint64(0))
+	f.Add([]byte("invalid"), pk2, ^uint64(0), uint64(1), uint64(0))
+	f.Add(pk1, pk2, ^uint64(0), uint64(0), uint64(1))
+
+	f.Fuzz(func(t *testing.T, pk1, pk2 []byte, w1, w2, w3 uint64) {
+		require := require.New(t)
+
+		// The third validator reuses the first key to exercise merging
+		inputs := []struct {
+			pk     []byte
+			weight uint64
+		}{
+			{pk1, w1},
+			{pk2, w2},
+			{pk1, w3},
+		}
+		vdrSet := make(map[ids.NodeID]*GetValidatorOutput, len(inputs))
+		var (
+			expectedTotal uint64
+			overflowed    bool
+		)
+		for i, input := range inputs {
+			nodeID := ids.NodeID{byte(i + 1)}
+			vdrSet[nodeID] = &GetValidatorOutput{
+				NodeID:    nodeID,
+				PublicKey: input.pk,
+				Weight:    input.weight,
+			}
+			var carry uint64
+			expectedTotal, carry = bits.Add64(expectedTotal, input.weight, 0)
+			overflowed = overflowed || carry != 0
+		}
+
+		result, err := FlattenValidatorSet(vdrSet)
+		if overflowed {
+			require.ErrorIs(err, ErrWeightOverflow)
+			return
+		}
+		require.NoError(err)
+		require.Equal(expectedTotal, result.TotalWeight)
+
+		var (
+			canonicalWeight uint64
+			numNodeIDs      int
+		)
+		for i, vdr := range result.Validators {
+			if i > 0 {
+				require.Negative(result.Validators[i-1].Compare(vdr))
+			}
+			require.NotNil(vdr.PublicKey)
+			require.NotEmpty(vdr.NodeIDs)
+
+			var weight uint64
+			for _, nodeID := range vdr.NodeIDs {
+				weight += vdrSet[nodeID].Weight
+			}
+			require.Equal(weight, vdr.Weight)
+
+			canonicalWeight += vdr.Weight
+			numNodeIDs += len(vdr.NodeIDs)
+		}
+		require.LessOrEqual(canonicalWeight, result.TotalWeight)
+		require.LessOrEqual(numNodeIDs, len(vdrSet))
+	})
+}
+
+// FuzzFilterValidators checks that filtering never panics, rejects unknown
+// indices, and returns the selected validators in canonical order.
+func FuzzFilterValidators(f *testing.F) {
+	f.Add([]byte{}, uint8(0))
+	f.Add([]byte{0, 2}, uint8(4))
+	f.Add([]byte{0, 1, 2}, uint8(3))
+	f.Add([]byte{5}, uint8(2))
+	f.Add([]byte{255, 0}, uint8(255))
+
+	f.Fuzz(func(t *testing.T, rawIndices []byte, numVdrs uint8) {
+		require := require.New(t)
+
+		vdrs := make([]*CanonicalValidator, numVdrs)
+		for i := range vdrs {
+			vdrs[i] = &CanonicalValidator{Weight: uint64(i)}
+		}
+
+		indices := make([]int, len(rawIndices))
+		maxIndex := -1
+		for i, index := range rawIndices {
+			indices[i] = int(index)
+			maxIndex = max(maxIndex, int(index))
+		}
+		bitSet := mathset.NewBits(indices...)
+
+		filtered, err := FilterValidators(bitSet, vdrs)
+		if maxIndex >= len(vdrs) {
+			require.ErrorIs(err, ErrUnknownValidator)
+			return
+		}
+		require.NoError(err)
+
+		expected := make([]*CanonicalValidator, 0, len(vdrs))
+		for i, vdr := range vdrs {
+			if bitSet.Contains(i) {
+				expected = append(expected, vdr)
+			}
+		}
+		require.Equal(expected, filtered)
+	})
+}
+
+// FuzzSumWeight checks that SumWeight matches a carry-tracking reference sum
+// and reports overflow instead of wrapping.
+func FuzzSumWeight(f *testing.F) {
+	f.Add([]byte{})
+	f.Add(binary.BigEndian.AppendUint64(nil, 100))
+	f.Add(binary.BigEndian.AppendUint64(binary.BigEndian.AppendUint64(nil, ^uint64(0)), 1))
+
+	f.Fuzz(func(t *testing.T, rawWeights []byte) {
+		require := require.New(t)
+
+		var (
+			vdrs       []*CanonicalValidator
+			expected   uint64
+			overflowed bool
+		)
+		for len(rawWeights) >= 8 {
+			weight := binary.BigEndian.Uint64(rawWeights)
+			rawWeights = rawWeights[8:]
+
+			vdrs = append(vdrs, &CanonicalValidator{Weight: weight})
+			var carry uint64
+			expected, carry = bits.Add64(expected, weight, 0)
+			overflowed = overflowed || carry != 0
+		}
+
+		weight, err := SumWeight(vdrs)
+		if overflowed {
+			require.ErrorIs(err, ErrWeightOverflow)
+			return
+		}
+		require.NoError(err)
+		require.Equal(expected, weight)
+	})
+}
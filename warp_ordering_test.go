@@ -11,6 +11,8 @@ import (
 	"github.com/luxfi/ids"
 	mathset "github.com/luxfi/math/set"
 	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/validators/validatorstest/fixture"
 )
 
 // TestCanonicalValidatorCompare tests the Compare method
@@ -119,8 +121,9 @@ func TestFlattenValidatorSetValidPubKey(t *testing.T) {
 	require := require.New(t)
 
 	// Generate a valid BLS key pair
-	sk, err := bls.NewSecretKey()
+	generated, err := fixture.Generate(1, 1, fixture.ConstantWeights(1))
 	require.NoError(err)
+	sk := generated[0].SecretKey
 
 	pk := sk.PublicKey()
 	pkBytes := bls.PublicKeyToCompressedBytes(pk)
@@ -147,8 +150,9 @@ func TestFlattenValidatorSetDuplicatePubKey(t *testing.T) {
 	require := require.New(t)
 
 	// Generate a valid BLS key pair
-	sk, err := bls.NewSecretKey()
+	generated, err := fixture.Generate(1, 1, fixture.ConstantWeights(1))
 	require.NoError(err)
+	sk := generated[0].SecretKey
 
 	pk := sk.PublicKey()
 	pkBytes := bls.PublicKeyToCompressedBytes(pk)
@@ -177,6 +181,47 @@ func TestFlattenValidatorSetDuplicatePubKey(t *testing.T) {
 	require.Len(result.Validators[0].NodeIDs, 2)
 }
 
+// TestFlattenValidatorSetDeterministic tests that validators sharing a key
+// list their node IDs in ascending order, so every node encodes the same set
+// regardless of map iteration order
+func TestFlattenValidatorSetDeterministic(t *testing.T) {
+	require := require.New(t)
+
+	generated, err := fixture.Generate(1, 1, fixture.ConstantWeights(1))
+	require.NoError(err)
+	sk := generated[0].SecretKey
+	pkBytes := bls.PublicKeyToCompressedBytes(sk.PublicKey())
+
+	vdrSet := make(map[ids.NodeID]*GetValidatorOutput)
+	for range 16 {
+		nodeID := ids.GenerateTestNodeID()
+		vdrSet[nodeID] = &GetValidatorOutput{
+			NodeID:    nodeID,
+			PublicKey: pkBytes,
+			Weight:    10,
+		}
+	}
+
+	var expected []byte
+	for range 10 {
+		result, err := FlattenValidatorSet(vdrSet)
+		require.NoError(err)
+		require.Len(result.Validators, 1)
+		require.True(nodeIDsAscending(result.Validators[0].NodeIDs))
+		require.Len(result.Validators[0].NodeIDs, len(vdrSet))
+
+		b, err := result.Marshal()
+		require.NoError(err)
+		if expected == nil {
+			expected = b
+		}
+		require.Equal(expected, b)
+
+		bigSet := FlattenBigValidatorSet(vdrSet, nil)
+		require.Equal(result.Validators[0].NodeIDs, bigSet.Validators[0].NodeIDs)
+	}
+}
+
 // TestFlattenValidatorSetWeightOverflow tests weight overflow
 func TestFlattenValidatorSetWeightOverflow(t *testing.T) {
 	require := require.New(t)
@@ -204,8 +249,9 @@ func TestFlattenValidatorSetDuplicateKeyWeightOverflow(t *testing.T) {
 	require := require.New(t)
 
 	// Generate a valid BLS key pair
-	sk, err := bls.NewSecretKey()
+	generated, err := fixture.Generate(1, 1, fixture.ConstantWeights(1))
 	require.NoError(err)
+	sk := generated[0].SecretKey
 
 	pk := sk.PublicKey()
 	pkBytes := bls.PublicKeyToCompressedBytes(pk)
@@ -235,24 +281,14 @@ func TestFlattenValidatorSetSorting(t *testing.T) {
 	require := require.New(t)
 
 	// Generate multiple valid BLS key pairs
-	sks := make([]*bls.SecretKey, 3)
-	pks := make([]*bls.PublicKey, 3)
-	pkBytes := make([][]byte, 3)
-
-	for i := range sks {
-		var err error
-		sks[i], err = bls.NewSecretKey()
-		require.NoError(err)
-		pks[i] = sks[i].PublicKey()
-		pkBytes[i] = bls.PublicKeyToCompressedBytes(pks[i])
-	}
+	generated, err := fixture.Generate(1, 3, fixture.ConstantWeights(1))
+	require.NoError(err)
 
 	vdrSet := make(map[ids.NodeID]*GetValidatorOutput)
-	for i := range sks {
-		nodeID := ids.GenerateTestNodeID()
-		vdrSet[nodeID] = &GetValidatorOutput{
-			NodeID:    nodeID,
-			PublicKey: pkBytes[i],
+	for i, vdr := range generated {
+		vdrSet[vdr.NodeID] = &GetValidatorOutput{
+			NodeID:    vdr.NodeID,
+			PublicKey: vdr.PublicKeyBytes,
 			Weight:    uint64((i + 1) * 100),
 		}
 	}
@@ -269,6 +305,58 @@ func TestFlattenValidatorSetSorting(t *testing.T) {
 	}
 }
 
+// TestFlattenHybridValidatorSet tests that hybrid flattening lists only
+// validators with both keys and carries their Ringtail keys
+func TestFlattenHybridValidatorSet(t *testing.T) {
+	require := require.New(t)
+
+	keys, err := fixture.Generate(1, 3, fixture.ConstantWeights(1))
+	require.NoError(err)
+	var (
+		sharedKey = keys[0].PublicKeyBytes
+		nodeID1   = ids.GenerateTestNodeID()
+		nodeID2   = ids.GenerateTestNodeID()
+		nodeID3   = ids.GenerateTestNodeID()
+		nodeID4   = ids.GenerateTestNodeID()
+		vdrSet    = map[ids.NodeID]*GetValidatorOutput{
+			nodeID1: {NodeID: nodeID1, PublicKey: sharedKey, RingtailPubKey: []byte{1}, Weight: 10},
+			nodeID2: {NodeID: nodeID2, PublicKey: sharedKey, RingtailPubKey: []byte{1}, Weight: 20},
+			nodeID3: {NodeID: nodeID3, PublicKey: keys[1].PublicKeyBytes, RingtailPubKey: []byte{2}, Weight: 35},
+			nodeID4: {NodeID: nodeID4, PublicKey: keys[2].PublicKeyBytes, Weight: 40},
+		}
+	)
+
+	result, err := FlattenHybridValidatorSet(vdrSet, EconomicWeight)
+	require.NoError(err)
+	require.Equal(uint64(105), result.TotalWeight)
+	require.Len(result.Validators, 2)
+	for i, vdr := range result.Validators {
+		require.NotEmpty(vdr.RingtailPubKey)
+		if i > 0 {
+			require.Negative(result.Validators[i-1].Compare(vdr))
+		}
+		if vdr.Weight == 35 {
+			require.Equal([]byte{2}, vdr.RingtailPubKey)
+		} else {
+			require.Equal(uint64(30), vdr.Weight)
+			require.Equal([]byte{1}, vdr.RingtailPubKey)
+			require.ElementsMatch([]ids.NodeID{nodeID1, nodeID2}, vdr.NodeIDs)
+		}
+	}
+
+	// BLS only flattening ignores Ringtail keys
+	result, err = FlattenValidatorSet(vdrSet)
+	require.NoError(err)
+	require.Len(result.Validators, 3)
+	for _, vdr := range result.Validators {
+		require.Empty(vdr.RingtailPubKey)
+	}
+
+	vdrSet[nodeID2].RingtailPubKey = []byte{3}
+	_, err = FlattenHybridValidatorSet(vdrSet, EconomicWeight)
+	require.ErrorIs(err, ErrConflictingRingtailKey)
+}
+
 // TestFilterValidatorsEmpty tests with empty inputs
 func TestFilterValidatorsEmpty(t *testing.T) {
 	require := require.New(t)
@@ -343,6 +431,69 @@ func TestFilterValidatorsOutOfBounds(t *testing.T) {
 	require.ErrorIs(err, ErrUnknownValidator)
 }
 
+// TestCanonicalValidatorSetBitsFor tests mapping node IDs to signer bits
+func TestCanonicalValidatorSetBitsFor(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		shared1 = ids.GenerateTestNodeID()
+		shared2 = ids.GenerateTestNodeID()
+		single  = ids.GenerateTestNodeID()
+		vdrSet  = CanonicalValidatorSet{
+			Validators: []*CanonicalValidator{
+				{Weight: 100, NodeIDs: []ids.NodeID{single}},
+				{Weight: 200, NodeIDs: []ids.NodeID{shared1, shared2}},
+			},
+			TotalWeight: 300,
+		}
+	)
+
+	bits, err := vdrSet.BitsFor(mathset.Of(shared2))
+	require.NoError(err)
+	require.Equal(mathset.NewBits(1).Bytes(), bits.Bytes())
+
+	bits, err = vdrSet.BitsFor(mathset.Of(single, shared1, shared2))
+	require.NoError(err)
+	require.Equal(mathset.NewBits(0, 1).Bytes(), bits.Bytes())
+
+	bits, err = vdrSet.BitsFor(mathset.Of[ids.NodeID]())
+	require.NoError(err)
+	require.Zero(bits.Len())
+
+	_, err = vdrSet.BitsFor(mathset.Of(single, ids.GenerateTestNodeID()))
+	require.ErrorIs(err, ErrUnknownValidator)
+	require.ErrorIs(err, ErrValidatorNotFound)
+}
+
+// TestFilterValidatorsWithComplement tests that the weights of the included
+// and excluded validators are summed
+func TestFilterValidatorsWithComplement(t *testing.T) {
+	require := require.New(t)
+
+	vdrs := []*CanonicalValidator{
+		{Weight: 100},
+		{Weight: 200},
+		{Weight: 300},
+		{Weight: 400},
+	}
+
+	result, included, excluded, err := FilterValidatorsWithComplement(mathset.NewBits(0, 2), vdrs)
+	require.NoError(err)
+	require.Equal([]*CanonicalValidator{vdrs[0], vdrs[2]}, result)
+	require.Equal(uint64(400), included)
+	require.Equal(uint64(600), excluded)
+
+	_, _, _, err = FilterValidatorsWithComplement(mathset.NewBits(4), vdrs)
+	require.ErrorIs(err, ErrUnknownValidator)
+	require.ErrorIs(err, ErrValidatorNotFound)
+
+	overflowing := []*CanonicalValidator{{Weight: math.MaxUint64}, {Weight: 1}, {Weight: 1}}
+	_, _, _, err = FilterValidatorsWithComplement(mathset.NewBits(0), overflowing)
+	require.NoError(err)
+	_, _, _, err = FilterValidatorsWithComplement(mathset.NewBits(0, 1), overflowing)
+	require.ErrorIs(err, ErrWeightOverflow)
+}
+
 // TestSumWeightEmpty tests with empty input
 func TestSumWeightEmpty(t *testing.T) {
 	require := require.New(t)
@@ -396,8 +547,9 @@ func TestAggregatePublicKeysEmpty(t *testing.T) {
 func TestAggregatePublicKeysSingle(t *testing.T) {
 	require := require.New(t)
 
-	sk, err := bls.NewSecretKey()
+	generated, err := fixture.Generate(1, 1, fixture.ConstantWeights(1))
 	require.NoError(err)
+	sk := generated[0].SecretKey
 	pk := sk.PublicKey()
 
 	vdrs := []*CanonicalValidator{
@@ -413,13 +565,12 @@ func TestAggregatePublicKeysSingle(t *testing.T) {
 func TestAggregatePublicKeysMultiple(t *testing.T) {
 	require := require.New(t)
 
+	generated, err := fixture.Generate(1, 3, fixture.ConstantWeights(1))
+	require.NoError(err)
 	var vdrs []*CanonicalValidator
-	for i := 0; i < 3; i++ {
-		sk, err := bls.NewSecretKey()
-		require.NoError(err)
-		pk := sk.PublicKey()
+	for i, vdr := range generated {
 		vdrs = append(vdrs, &CanonicalValidator{
-			PublicKey: pk,
+			PublicKey: vdr.PublicKey,
 			Weight:    uint64((i + 1) * 100),
 		})
 	}
@@ -0,0 +1,51 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"slices"
+	"time"
+
+	"github.com/luxfi/ids"
+)
+
+// WarpSetManager builds Warp sets from the manager's validators
+type WarpSetManager interface {
+	// GetWarpSet returns the Warp set of the current validators of [netID]
+	GetWarpSet(netID ids.ID) *WarpSet
+}
+
+var _ WarpSetManager = (*manager)(nil)
+
+// GetWarpSet returns the Warp set of the current validators of a net. The
+// set isn't pinned to a height: its Height is zero and it has no commitment.
+// Callers pinning it to a height set Height and call Commit. Validators whose
+// key expired as of the key time are left out, see KeyExpiryManager.
+func (m *manager) GetWarpSet(netID ids.ID) *WarpSet {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return buildWarpSet(m.validators[netID], m.keyTime)
+}
+
+// buildWarpSet returns the Warp set of the validators of [vdrs] that have a
+// BLS public key that hasn't expired as of [keyTime], weighted by economic
+// weight. Keys are copied.
+func buildWarpSet(vdrs map[ids.NodeID]*GetValidatorOutput, keyTime time.Time) *WarpSet {
+	warpVdrs := make(map[ids.NodeID]*WarpValidator)
+	for nodeID, vdr := range vdrs {
+		if len(vdr.PublicKey) == 0 || keyExpired(vdr, keyTime) {
+			continue
+		}
+		warpVdrs[nodeID] = &WarpValidator{
+			NodeID:         nodeID,
+			PublicKey:      slices.Clone(vdr.PublicKey),
+			RingtailPubKey: slices.Clone(vdr.RingtailPubKey),
+			Weight:         EconomicWeight.Of(vdr),
+		}
+	}
+	return &WarpSet{
+		Validators: warpVdrs,
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"testing"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/validators/validatorstest/fixture"
+)
+
+// TestManagerGetWarpSet tests that Warp sets carry the validators with BLS
+// keys along with their Ringtail keys
+func TestManagerGetWarpSet(t *testing.T) {
+	require := require.New(t)
+
+	generated, err := fixture.Generate(1, 1, fixture.ConstantWeights(1))
+	require.NoError(err)
+	sk := generated[0].SecretKey
+	pk := bls.PublicKeyToCompressedBytes(sk.PublicKey())
+
+	m := NewManager()
+	netID := ids.GenerateTestID()
+	var (
+		signer  = ids.GenerateTestNodeID()
+		keyless = ids.GenerateTestNodeID()
+	)
+	m.loadValidators(netID, []*GetValidatorOutput{
+		{
+			NodeID:         signer,
+			PublicKey:      pk,
+			RingtailPubKey: []byte("ringtail"),
+			Light:          100,
+			Weight:         150,
+		},
+		{
+			NodeID: keyless,
+			Light:  100,
+			Weight: 100,
+		},
+	})
+
+	warpSet := m.GetWarpSet(netID)
+	require.Len(warpSet.Validators, 1)
+	vdr := warpSet.Validators[signer]
+	require.Equal(signer, vdr.NodeID)
+	require.Equal(pk, vdr.PublicKey)
+	require.Equal([]byte("ringtail"), vdr.RingtailPubKey)
+	require.Equal(uint64(150), vdr.Weight)
+
+	// The set doesn't share keys with the manager
+	warpSet.Validators[signer].RingtailPubKey[0] = 0
+	require.Equal([]byte("ringtail"), m.GetWarpSet(netID).Validators[signer].RingtailPubKey)
+
+	require.Empty(m.GetWarpSet(ids.GenerateTestID()).Validators)
+}
@@ -0,0 +1,101 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"fmt"
+	"maps"
+	"math/bits"
+	"slices"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math"
+)
+
+// WarpSetSource is a Warp set weighted into a union. Each validator of Set
+// counts with Numerator/Denominator of its weight, rounded down, so sets of
+// nets whose stake is denominated differently can be balanced against each
+// other.
+type WarpSetSource struct {
+	Set         *WarpSet
+	Numerator   uint64
+	Denominator uint64
+}
+
+// UnionWarpSets returns the canonical set of the validators of every source,
+// for messages that must be attested by several validator sets at once.
+//
+// Validators are deduplicated by BLS public key: a key registered in several
+// sets, whether by the same node or not, is a single canonical validator
+// weighing the sum of its scaled weights, with the union of its node IDs in
+// node ID order. Validators whose scaled weight is zero are left out. The
+// total weight is the sum of the scaled weights.
+func UnionWarpSets(sources ...WarpSetSource) (CanonicalValidatorSet, error) {
+	var (
+		byKey       = make(map[string]*CanonicalValidator)
+		nodeIDs     = make(map[string]map[ids.NodeID]struct{})
+		totalWeight uint64
+	)
+	for i, source := range sources {
+		if source.Denominator == 0 {
+			return CanonicalValidatorSet{}, fmt.Errorf("%w: source %d has a zero denominator", ErrInvalidScale, i)
+		}
+		if source.Set == nil {
+			continue
+		}
+		for nodeID, vdr := range source.Set.Validators {
+			weight, err := scaleWeight(vdr.Weight, source.Numerator, source.Denominator)
+			if err != nil {
+				return CanonicalValidatorSet{}, fmt.Errorf("couldn't scale %s of source %d: %w", nodeID, i, err)
+			}
+			if weight == 0 {
+				continue
+			}
+			publicKey, err := vdr.BLSPublicKey()
+			if err != nil {
+				return CanonicalValidatorSet{}, fmt.Errorf("%w: %s of source %d: %w", ErrInvalidPublicKey, nodeID, i, err)
+			}
+			totalWeight, err = math.Add64(totalWeight, weight)
+			if err != nil {
+				return CanonicalValidatorSet{}, fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+			}
+
+			pkBytes := bls.PublicKeyToUncompressedBytes(publicKey)
+			key := string(pkBytes)
+			canonical, ok := byKey[key]
+			if !ok {
+				canonical = &CanonicalValidator{
+					PublicKey:      publicKey,
+					PublicKeyBytes: pkBytes,
+				}
+				byKey[key] = canonical
+				nodeIDs[key] = make(map[ids.NodeID]struct{})
+			}
+			// Can't overflow, since the total weight didn't
+			canonical.Weight += weight
+			nodeIDs[key][nodeID] = struct{}{}
+		}
+	}
+
+	for key, canonical := range byKey {
+		canonical.NodeIDs = sortNodeIDs(slices.Collect(maps.Keys(nodeIDs[key])))
+	}
+	vdrs := slices.Collect(maps.Values(byKey))
+	slices.SortFunc(vdrs, (*CanonicalValidator).Compare)
+	return CanonicalValidatorSet{
+		Validators:  vdrs,
+		TotalWeight: totalWeight,
+	}, nil
+}
+
+// scaleWeight returns [weight]*[num]/[den], rounded down
+func scaleWeight(weight, num, den uint64) (uint64, error) {
+	hi, lo := bits.Mul64(weight, num)
+	if hi >= den {
+		return 0, fmt.Errorf("%w: %d*%d/%d", ErrWeightOverflow, weight, num, den)
+	}
+	scaled, _ := bits.Div64(hi, lo, den)
+	return scaled, nil
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/validators/validatorstest/fixture"
+)
+
+// TestUnionWarpSets tests that overlapping sets are merged by key with their
+// weights scaled
+func TestUnionWarpSets(t *testing.T) {
+	require := require.New(t)
+
+	keys, err := fixture.Generate(1, 3, fixture.ConstantWeights(1))
+	require.NoError(err)
+	var (
+		shared = ids.GenerateTestNodeID()
+		l1Only = ids.GenerateTestNodeID()
+		pOnly  = ids.GenerateTestNodeID()
+		// The key of shared, and a second node registering the same key
+		sharedKey = keys[0].PublicKeyBytes
+		alias     = ids.GenerateTestNodeID()
+	)
+	l1 := &WarpSet{Validators: map[ids.NodeID]*WarpValidator{
+		shared: {NodeID: shared, PublicKey: sharedKey, Weight: 10},
+		l1Only: {NodeID: l1Only, PublicKey: keys[1].PublicKeyBytes, Weight: 20},
+	}}
+	primary := &WarpSet{Validators: map[ids.NodeID]*WarpValidator{
+		shared: {NodeID: shared, PublicKey: sharedKey, Weight: 1_000},
+		alias:  {NodeID: alias, PublicKey: sharedKey, Weight: 500},
+		pOnly:  {NodeID: pOnly, PublicKey: keys[2].PublicKeyBytes, Weight: 1},
+	}}
+
+	// The primary network's stake counts at 1/100
+	vdrSet, err := UnionWarpSets(
+		WarpSetSource{Set: l1, Numerator: 1, Denominator: 1},
+		WarpSetSource{Set: primary, Numerator: 1, Denominator: 100},
+	)
+	require.NoError(err)
+	// pOnly rounds down to zero and is left out
+	require.Len(vdrSet.Validators, 2)
+	require.Equal(uint64(10+20+10+5), vdrSet.TotalWeight)
+	for i := 1; i < len(vdrSet.Validators); i++ {
+		require.Negative(vdrSet.Validators[i-1].Compare(vdrSet.Validators[i]))
+	}
+
+	sharedIndex, weight, ok := vdrSet.CanSign(alias)
+	require.True(ok)
+	require.Equal(uint64(25), weight)
+	require.Equal(sortNodeIDs([]ids.NodeID{shared, alias}), vdrSet.Validators[sharedIndex].NodeIDs)
+	_, _, ok = vdrSet.CanSign(pOnly)
+	require.False(ok)
+
+	_, err = UnionWarpSets(WarpSetSource{Set: l1, Numerator: 1})
+	require.ErrorIs(err, ErrInvalidScale)
+	_, err = UnionWarpSets(WarpSetSource{Set: primary, Numerator: ^uint64(0), Denominator: 1})
+	require.ErrorIs(err, ErrWeightOverflow)
+}
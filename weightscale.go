@@ -0,0 +1,125 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"fmt"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math"
+)
+
+var ErrInvalidScale = NewCategoryError("invalid weight scale", ErrInvalidWeight)
+
+// WeightScaleManager sets the unit each net's light is sampled in, for nets
+// whose stake is stored in a finer unit than it is sampled in, such as nLUX
+// stored and whole LUX sampled.
+//
+// The scale of a net is the number of stored units per sampled unit. It is
+// applied by ScaledLight, ScaledTotalLight and the gossip package's Selector,
+// which divide light by the scale, rounding down, so validators with less
+// than one sampled unit are never drawn, and written to dumps. Everything
+// else stays in stored units: records and totals, canonical sets and the
+// committees drawn from them, which every node must derive alike, and key
+// coverage, fairness quotas, alarms and time series, whose shares don't
+// depend on the unit. The default scale is 1.
+type WeightScaleManager interface {
+	// SetWeightScale sets the scale of [netID]. A scale of 1 resets it.
+	SetWeightScale(netID ids.ID, scale uint64) error
+	// GetWeightScale returns the scale of [netID]
+	GetWeightScale(netID ids.ID) uint64
+	// ScaledLight returns the light of [nodeID] in [netID] in sampled units
+	ScaledLight(netID ids.ID, nodeID ids.NodeID) uint64
+	// ScaledTotalLight returns the sum of the scaled light of the validators
+	// of [netID]. It can be less than the scaled total light, since each
+	// validator is rounded down separately.
+	ScaledTotalLight(netID ids.ID) (uint64, error)
+}
+
+var _ WeightScaleManager = (*manager)(nil)
+
+// ScaleDown returns [weight] in units of [scale], rounded down
+func ScaleDown(weight, scale uint64) (uint64, error) {
+	if scale == 0 {
+		return 0, fmt.Errorf("%w: zero scale", ErrInvalidScale)
+	}
+	return weight / scale, nil
+}
+
+// WeightScaleOf returns the scale of [netID] in [m], which is 1 if [m]
+// doesn't implement WeightScaleManager
+func WeightScaleOf(m Manager, netID ids.ID) uint64 {
+	if scaler, ok := m.(WeightScaleManager); ok {
+		return scaler.GetWeightScale(netID)
+	}
+	return 1
+}
+
+// SetWeightScale sets the scale of a net
+func (m *manager) SetWeightScale(netID ids.ID, scale uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.setWeightScale(netID, scale)
+}
+
+// setWeightScale is SetWeightScale without locking. It assumes the lock is
+// held.
+func (m *manager) setWeightScale(netID ids.ID, scale uint64) error {
+	if scale == 0 {
+		return fmt.Errorf("%w: zero scale", ErrInvalidScale)
+	}
+	if scale == 1 {
+		delete(m.weightScales, netID)
+		return nil
+	}
+	m.weightScales[netID] = scale
+	return nil
+}
+
+// GetWeightScale returns the scale of a net
+func (m *manager) GetWeightScale(netID ids.ID) uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.weightScale(netID)
+}
+
+// ScaledLight returns the light of a validator in sampled units
+func (m *manager) ScaledLight(netID ids.ID, nodeID ids.NodeID) uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if val, ok := m.validators[netID][nodeID]; ok {
+		return val.Light / m.weightScale(netID)
+	}
+	return 0
+}
+
+// ScaledTotalLight returns the scaled light of a net
+func (m *manager) ScaledTotalLight(netID ids.ID) (uint64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var (
+		scale = m.weightScale(netID)
+		total uint64
+		err   error
+	)
+	for _, val := range m.validators[netID] {
+		total, err = math.Add64(total, val.Light/scale)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", ErrWeightOverflow, err)
+		}
+	}
+	return total, nil
+}
+
+// weightScale returns the scale of [netID]. It assumes the lock is held.
+func (m *manager) weightScale(netID ids.ID) uint64 {
+	if scale, ok := m.weightScales[netID]; ok {
+		return scale
+	}
+	return 1
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2019-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// TestScaleWeights tests the exact scaling helper
+func TestScaleWeights(t *testing.T) {
+	require := require.New(t)
+
+	scaled, err := ScaleDown(2_999_999_999, 1_000_000_000)
+	require.NoError(err)
+	require.Equal(uint64(2), scaled)
+	_, err = ScaleDown(1, 0)
+	require.ErrorIs(err, ErrInvalidScale)
+	require.ErrorIs(err, ErrInvalidWeight)
+}
+
+// TestManagerWeightScale tests per-net scales and that dumps carry them
+func TestManagerWeightScale(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		m      = NewManager()
+		netID  = ids.GenerateTestID()
+		nodeA  = ids.GenerateTestNodeID()
+		nodeB  = ids.GenerateTestNodeID()
+		nLUX   = uint64(1_000_000_000)
+		stored = 5*nLUX + 1
+	)
+	require.NoError(m.AddStaker(netID, nodeA, nil, ids.Empty, stored))
+	require.NoError(m.AddStaker(netID, nodeB, nil, ids.Empty, nLUX-1))
+
+	require.Equal(uint64(1), m.GetWeightScale(netID))
+	require.Equal(stored, m.ScaledLight(netID, nodeA))
+
+	require.ErrorIs(m.SetWeightScale(netID, 0), ErrInvalidScale)
+	require.NoError(m.SetWeightScale(netID, nLUX))
+	require.Equal(nLUX, WeightScaleOf(m, netID))
+	require.Equal(uint64(5), m.ScaledLight(netID, nodeA))
+	require.Zero(m.ScaledLight(netID, nodeB))
+	total, err := m.ScaledTotalLight(netID)
+	require.NoError(err)
+	require.Equal(uint64(5), total)
+	// Stored light is unchanged
+	require.Equal(stored, m.GetLight(netID, nodeA))
+
+	var buf bytes.Buffer
+	require.NoError(DumpValidators(&buf, m, DumpOptions{NetIDs: []ids.ID{netID}}))
+	require.Contains(buf.String(), " scale=1000000000 checksum=")
+
+	restored := NewManager()
+	require.NoError(RestoreValidators(strings.NewReader(buf.String()), restored))
+	require.Equal(nLUX, restored.GetWeightScale(netID))
+	require.Equal(stored, restored.GetLight(netID, nodeA))
+
+	require.ErrorIs(RestoreValidators(strings.NewReader(buf.String()), &mockManager{}), ErrUnsupportedRestore)
+
+	require.NoError(m.SetWeightScale(netID, 1))
+	require.Equal(uint64(1), m.GetWeightScale(netID))
+}